@@ -3,16 +3,12 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 
-	"github.com/wormhole-demo/relayer/internal"
 	"github.com/wormhole-demo/relayer/internal/clients"
 	"github.com/wormhole-demo/relayer/internal/submitter"
 )
@@ -24,6 +20,9 @@ const (
 	DefaultAztecTargetContract    = "0x0848d2af89dfd7c0e171238f9216399e61e908cd31b0222a920f1bf621a16ed6"
 	DefaultVerificationServiceURL = "http://localhost:8080"
 
+	// Aztec proofs can take many minutes, so submissions get a generous deadline
+	DefaultAztecSubmissionTimeout = 15 * time.Minute
+
 	// Wormhole chain ID for Aztec
 	AztecDestinationChainID uint16 = 56
 )
@@ -40,7 +39,7 @@ var aztecCmd = &cobra.Command{
 This command monitors the Wormhole network for messages from EVM chains, Solana,
 or other configured chains and submits them to the Aztec network.`,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		printBanner()
+		printBanner(cmd)
 		configureLogging(cmd, args)
 	},
 	RunE: runAztecRelay,
@@ -49,96 +48,193 @@ or other configured chains and submits them to the Aztec network.`,
 func init() {
 	rootCmd.AddCommand(aztecCmd)
 
-	// Aztec-specific flags
-	aztecCmd.Flags().String(
+	registerAztecFlags(aztecCmd)
+
+	registerRelayFlags(aztecCmd, DefaultAztecSourceChains, DefaultAztecSubmissionTimeout,
+		"Source chain IDs to listen for (Arbitrum=10003, Solana=1, Base=10004)",
+		"Source emitter address to filter (hex, e.g., EVM bridge address)")
+
+	// Bind flags to viper
+	bindAztecFlags(aztecCmd)
+}
+
+// registerAztecFlags registers the Aztec destination flags on cmd
+func registerAztecFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
 		"aztec-pxe-url",
 		DefaultAztecPXEURL,
 		"PXE URL for Aztec")
 
-	aztecCmd.Flags().String(
+	cmd.Flags().String(
 		"aztec-wallet-address",
 		DefaultAztecWalletAddress,
 		"Aztec wallet address to use")
 
-	aztecCmd.Flags().String(
+	cmd.Flags().String(
 		"aztec-target-contract",
 		DefaultAztecTargetContract,
 		"Target contract on Aztec to send VAAs to")
 
-	aztecCmd.Flags().String(
+	cmd.Flags().String(
 		"verification-service-url",
 		DefaultVerificationServiceURL,
 		"Verification service URL (optional)")
 
-	aztecCmd.Flags().IntSlice(
-		"chain-ids",
-		DefaultAztecSourceChains,
-		"Source chain IDs to listen for (Arbitrum=10003, Solana=1, Base=10004)")
+	cmd.Flags().Int(
+		"verification-retries",
+		clients.DefaultRetryConfig().MaxAttempts,
+		"Attempts per verification service request; 5xx responses and timeouts are retried with backoff")
 
-	aztecCmd.Flags().String(
-		"emitter-address",
-		"",
-		"Source emitter address to filter (hex, e.g., EVM bridge address)")
+	cmd.Flags().Bool(
+		"verification-service-gzip",
+		false,
+		"Gzip requests to the verification service once it advertises support (Accept-Encoding: gzip)")
 
-	// Bind flags to viper
-	viper.BindPFlag("aztec_pxe_url", aztecCmd.Flags().Lookup("aztec-pxe-url"))
-	viper.BindPFlag("aztec_wallet_address", aztecCmd.Flags().Lookup("aztec-wallet-address"))
-	viper.BindPFlag("aztec_target_contract", aztecCmd.Flags().Lookup("aztec-target-contract"))
-	viper.BindPFlag("verification_service_url", aztecCmd.Flags().Lookup("verification-service-url"))
-	viper.BindPFlag("chain_ids", aztecCmd.Flags().Lookup("chain-ids"))
-	viper.BindPFlag("emitter_address", aztecCmd.Flags().Lookup("emitter-address"))
+	cmd.Flags().Int64(
+		"verification-service-max-response-bytes",
+		clients.DefaultMaxResponseBytes,
+		"Largest verification service response body read, in bytes; larger responses fail the request")
+
+	cmd.Flags().StringToString(
+		"verification-service-header",
+		nil,
+		"Static headers sent with every verification service request, e.g. X-Api-Key=... (bearer token: WORMHOLE_RELAYER_VERIFICATION_SERVICE_TOKEN)")
+
+	cmd.Flags().Bool(
+		"aztec-confirm-inclusion",
+		false,
+		"Wait for each Aztec transaction to be included in a block (requires the PXE)")
+
+	cmd.Flags().Duration(
+		"aztec-confirm-interval",
+		clients.DefaultAztecConfirmationConfig().PollInterval,
+		"With --aztec-confirm-inclusion, how often to poll the node for the transaction receipt")
+
+	cmd.Flags().Duration(
+		"aztec-confirm-timeout",
+		clients.DefaultAztecConfirmationConfig().Timeout,
+		"With --aztec-confirm-inclusion, how long to wait for inclusion before failing")
+}
+
+// bindAztecFlags binds the Aztec destination flags of cmd to viper
+func bindAztecFlags(cmd *cobra.Command) {
+	viper.BindPFlag("aztec_pxe_url", cmd.Flags().Lookup("aztec-pxe-url"))
+	viper.BindPFlag("aztec_wallet_address", cmd.Flags().Lookup("aztec-wallet-address"))
+	viper.BindPFlag("aztec_target_contract", cmd.Flags().Lookup("aztec-target-contract"))
+	viper.BindPFlag("verification_service_url", cmd.Flags().Lookup("verification-service-url"))
+	viper.BindPFlag("verification_service_gzip", cmd.Flags().Lookup("verification-service-gzip"))
+	viper.BindPFlag("verification_service_max_response_bytes", cmd.Flags().Lookup("verification-service-max-response-bytes"))
+	viper.BindPFlag("verification_service_headers", cmd.Flags().Lookup("verification-service-header"))
+	// Note: verification_service_token is read from env WORMHOLE_RELAYER_VERIFICATION_SERVICE_TOKEN
 }
 
 type AztecConfig struct {
-	SpyRPCHost             string   // Wormhole spy service endpoint
-	ChainIDs               []uint16 // Source chain IDs to listen for
-	AztecPXEURL            string   // PXE URL for Aztec
-	AztecWalletAddress     string   // Aztec wallet address to use
-	AztecTargetContract    string   // Target contract on Aztec
-	VerificationServiceURL string   // Optional verification service URL
-	EmitterAddress         string   // Source emitter address to filter
+	AztecPXEURL            string              `mapstructure:"pxe_url"`                   // PXE URL for Aztec
+	AztecWalletAddress     string              `mapstructure:"wallet_address"`            // Aztec wallet address to use
+	AztecTargetContract    string              `mapstructure:"target_contract"`           // Target contract on Aztec
+	VerificationServiceURL string              `mapstructure:"verification_service_url"`  // Optional verification service URL
+	VerificationRetry      clients.RetryConfig `mapstructure:"verification_retry"`        // Retry policy for verification service requests
+	VerificationGzip       bool                `mapstructure:"verification_service_gzip"` // Gzip verification requests once the service advertises support
+	// Largest verification service response body read, in bytes (0 = clients.DefaultMaxResponseBytes)
+	VerificationMaxResponse int64 `mapstructure:"verification_service_max_response_bytes"`
+	// Bearer token and static headers sent with every verification service request
+	VerificationToken   string            `mapstructure:"verification_service_token"`
+	VerificationHeaders map[string]string `mapstructure:"verification_service_headers"`
+	// Inclusion confirmation via node receipts (nil = report success once the tx is sent)
+	Confirmation *clients.AztecConfirmationConfig `mapstructure:"confirmation"`
 }
 
 func runAztecRelay(cmd *cobra.Command, args []string) error {
 	logger := configureLogging(cmd, args)
 	logger.Info("Starting Aztec relayer")
 
-	// Get flags directly from command (viper bindings conflict across commands)
-	emitterAddress, _ := cmd.Flags().GetString("emitter-address")
-	chainIDsInt, _ := cmd.Flags().GetIntSlice("chain-ids")
-
-	// Convert chain IDs from []int to []uint16
-	chainIDs := make([]uint16, len(chainIDsInt))
-	for i, id := range chainIDsInt {
-		chainIDs[i] = uint16(id)
-	}
-
-	config := AztecConfig{
-		SpyRPCHost:             viper.GetString("spy_rpc_host"),
-		ChainIDs:               chainIDs,
-		AztecPXEURL:            viper.GetString("aztec_pxe_url"),
-		AztecWalletAddress:     viper.GetString("aztec_wallet_address"),
-		AztecTargetContract:    viper.GetString("aztec_target_contract"),
-		VerificationServiceURL: viper.GetString("verification_service_url"),
-		EmitterAddress:         emitterAddress,
+	config, err := readAztecConfig(cmd)
+	if err != nil {
+		return err
 	}
 
 	logger.Info("Configuration",
-		zap.String("spyRPC", config.SpyRPCHost),
-		zap.Any("chainIds", config.ChainIDs),
 		zap.String("aztecPXE", config.AztecPXEURL),
 		zap.String("aztecWallet", config.AztecWalletAddress),
 		zap.String("aztecTarget", config.AztecTargetContract),
 		zap.String("verificationService", config.VerificationServiceURL),
-		zap.String("emitterFilter", config.EmitterAddress))
+		zap.Stringer("verificationServiceAuth", verificationAuth(config)),
+		zap.Bool("confirmInclusion", config.Confirmation != nil))
 
-	spyClient, err := clients.NewSpyClient(logger, config.SpyRPCHost)
-	if err != nil {
-		return fmt.Errorf("failed to create spy client: %v", err)
+	relayConfig := readRelayConfig(cmd, DefaultAztecSourceChains)
+	if config.Confirmation != nil && config.Confirmation.Timeout >= relayConfig.SubmissionTimeout {
+		// The submission deadline cancels the wait first, so a dropped tx is reported as a timeout
+		logger.Warn("--aztec-confirm-timeout is not shorter than --submission-timeout; the submission deadline will end confirmation first",
+			zap.Duration("confirmTimeout", config.Confirmation.Timeout),
+			zap.Duration("submissionTimeout", relayConfig.SubmissionTimeout))
+	}
+
+	return runRelay(logger, relayConfig, AztecDestinationChainID,
+		func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+			return buildAztecSubmitter(logger, config)
+		})
+}
+
+// readAztecConfig reads and validates the Aztec-specific configuration
+func readAztecConfig(cmd *cobra.Command) (AztecConfig, error) {
+	config := AztecConfig{
+		AztecPXEURL:             viper.GetString("aztec_pxe_url"),
+		AztecWalletAddress:      viper.GetString("aztec_wallet_address"),
+		AztecTargetContract:     viper.GetString("aztec_target_contract"),
+		VerificationServiceURL:  viper.GetString("verification_service_url"),
+		VerificationRetry:       clients.DefaultRetryConfig(),
+		VerificationGzip:        viper.GetBool("verification_service_gzip"),
+		VerificationMaxResponse: viper.GetInt64("verification_service_max_response_bytes"),
+		VerificationToken:       viper.GetString("verification_service_token"),
+		VerificationHeaders:     viper.GetStringMapString("verification_service_headers"),
+	}
+
+	// Get flags directly from command (viper bindings conflict across commands)
+	config.VerificationRetry.MaxAttempts, _ = cmd.Flags().GetInt("verification-retries")
+	if confirm, _ := cmd.Flags().GetBool("aztec-confirm-inclusion"); confirm {
+		interval, _ := cmd.Flags().GetDuration("aztec-confirm-interval")
+		timeout, _ := cmd.Flags().GetDuration("aztec-confirm-timeout")
+		config.Confirmation = &clients.AztecConfirmationConfig{PollInterval: interval, Timeout: timeout}
+	}
+
+	return config, validateAztecConfig(config)
+}
+
+// validateAztecConfig checks the retry and inclusion confirmation settings of an Aztec destination
+func validateAztecConfig(config AztecConfig) error {
+	if config.VerificationRetry.MaxAttempts < 1 {
+		return fmt.Errorf("--verification-retries must be at least 1")
+	}
+	if config.VerificationMaxResponse < 0 {
+		return fmt.Errorf("--verification-service-max-response-bytes must not be negative")
+	}
+	if _, err := serviceAuth("--verification-service-header", config.VerificationToken, config.VerificationHeaders); err != nil {
+		return err
 	}
+	if config.Confirmation != nil && (config.Confirmation.PollInterval <= 0 || config.Confirmation.Timeout <= 0) {
+		return fmt.Errorf("--aztec-confirm-interval and --aztec-confirm-timeout must be positive")
+	}
+	return nil
+}
 
+// verificationAuth returns the credentials sent to the verification service of config, which
+// validateAztecConfig has checked
+func verificationAuth(config AztecConfig) clients.ServiceAuth {
+	auth, _ := serviceAuth("--verification-service-header", config.VerificationToken, config.VerificationHeaders)
+	return auth
+}
+
+// buildAztecSubmitter creates the Aztec submitter, requiring at least one of the
+// verification service and the PXE to be reachable
+func buildAztecSubmitter(logger *zap.Logger, config AztecConfig) (submitter.VAASubmitter, error) {
 	// Check verification service health first
-	verificationService := clients.NewVerificationServiceClient(logger, config.VerificationServiceURL)
+	verificationService := clients.NewVerificationServiceClientWithConfig(logger, clients.VerificationServiceConfig{
+		URL:              config.VerificationServiceURL,
+		Retry:            config.VerificationRetry,
+		GzipRequests:     config.VerificationGzip,
+		MaxResponseBytes: config.VerificationMaxResponse,
+		Auth:             verificationAuth(config),
+	})
 	healthCtx, healthCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	verificationHealthy := false
 	if err := verificationService.CheckHealth(healthCtx); err != nil {
@@ -149,53 +245,25 @@ func runAztecRelay(cmd *cobra.Command, args []string) error {
 	}
 	healthCancel()
 
-	// PXE client is optional if verification service is healthy, required otherwise
-	var pxeClient *clients.AztecPXEClient
-	pxeClient, err = clients.NewAztecPXEClient(
-		logger, config.AztecPXEURL, config.AztecWalletAddress)
-	if err != nil {
-		if verificationHealthy {
-			logger.Warn("PXE client not available, using verification service only", zap.Error(err))
-			pxeClient = nil
-		} else {
-			return fmt.Errorf("failed to create PXE client and verification service is not healthy: %v", err)
-		}
+	// PXE client is optional if verification service is healthy, required otherwise.
+	// pxeClient stays a nil interface (not a nil *AztecPXEClient) when the PXE is unavailable.
+	var pxeClient submitter.AztecRelayer
+	if client, err := clients.NewAztecPXEClient(logger, config.AztecPXEURL, config.AztecWalletAddress); err == nil {
+		pxeClient = client
+	} else if verificationHealthy {
+		logger.Warn("PXE client not available, using verification service only", zap.Error(err))
+	} else {
+		return nil, fmt.Errorf("failed to create PXE client and verification service is not healthy: %v", err)
 	}
 
-	aztecSubmitter := submitter.NewAztecSubmitter(logger,
-		config.AztecTargetContract, pxeClient, verificationService)
-	vaaProcessor := internal.NewDefaultVAAProcessor(logger,
-		internal.VAAProcessorConfig{
-			ChainIDs:           config.ChainIDs,
-			EmitterAddress:     config.EmitterAddress,
-			DestinationChainID: AztecDestinationChainID,
-		},
-		aztecSubmitter)
-
-	// Create and start relayer
-	relayer, err := internal.NewRelayer(logger, spyClient, vaaProcessor)
-	if err != nil {
-		return fmt.Errorf("failed to initialize relayer: %v", err)
-	}
-	defer relayer.Close()
-
-	// Setup context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		logger.Info("Received shutdown signal")
-		cancel()
-	}()
-
-	// Start the relayer
-	if err := relayer.Start(ctx); err != nil {
-		return fmt.Errorf("relayer stopped with error: %v", err)
+	if config.Confirmation != nil {
+		if pxeClient == nil {
+			return nil, fmt.Errorf("--aztec-confirm-inclusion requires a reachable PXE")
+		}
+		return submitter.NewAztecSubmitterWithConfirmation(logger,
+			config.AztecTargetContract, pxeClient, verificationService, *config.Confirmation), nil
 	}
 
-	return nil
+	return submitter.NewAztecSubmitter(logger,
+		config.AztecTargetContract, pxeClient, verificationService), nil
 }
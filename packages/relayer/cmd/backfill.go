@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/buildinfo"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// backfillCmd relays a range of already-signed VAAs fetched from Wormholescan
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Relay a range of sequences from one emitter, fetched from Wormholescan",
+	Long: `Catches up after downtime: fetches each VAA of one emitter from --from-seq to
+--to-seq (inclusive) from the Wormholescan API and submits it to --chain, in sequence
+order. VAAs the destination already processed are reported as such, not as failures.
+
+The command stops at the first sequence that fails unless --continue-on-error is set,
+then prints the outcome of every attempted sequence and exits non-zero if any failed.
+Pass the same destination flags as the relay command. With --chain solana, --batch-size
+delivers several sequences per transaction.`,
+	Example:      `  wormhole-relayer backfill --chain base --source-chain 56 --emitter 0x... --from-seq 120 --to-seq 135 --private-key-file key.hex --evm-target-contract 0x...`,
+	SilenceUsage: true,
+	RunE:         runBackfill,
+}
+
+func init() {
+	rootCmd.AddCommand(backfillCmd)
+
+	backfillCmd.Flags().String(
+		"chain",
+		"",
+		"Destination to relay to (aztec, solana, cosmos, arbitrum, base)")
+
+	backfillCmd.Flags().Int(
+		"source-chain",
+		0,
+		"Wormhole chain ID of the emitter")
+
+	backfillCmd.Flags().String(
+		"emitter",
+		"",
+		"Emitter address whose VAAs to relay (32-byte hex, or a 20-byte EVM address)")
+
+	backfillCmd.Flags().Uint64(
+		"from-seq",
+		0,
+		"First sequence to relay")
+
+	backfillCmd.Flags().Uint64(
+		"to-seq",
+		0,
+		"Last sequence to relay (inclusive)")
+
+	backfillCmd.Flags().Bool(
+		"continue-on-error",
+		false,
+		"Keep going after a sequence fails instead of stopping")
+
+	backfillCmd.Flags().Int(
+		"batch-size",
+		1,
+		"Sequences submitted together in as few transactions as fit (solana only; 1 = one at a time)")
+
+	backfillCmd.Flags().String(
+		"wormholescan-url",
+		clients.DefaultWormholescanURL,
+		"Wormholescan API to fetch VAAs from")
+
+	backfillCmd.Flags().Duration(
+		"submission-timeout",
+		0,
+		"Deadline for submitting a single VAA (0 = the destination's relay command default)")
+
+	backfillCmd.Flags().Float64(
+		"rate-limit",
+		0,
+		"Maximum submissions per second to the destination (0 = unlimited)")
+
+	backfillCmd.Flags().Int(
+		"rate-burst",
+		1,
+		"Submissions allowed in a burst above --rate-limit")
+
+	// Every destination's flags are accepted; only those of --chain are used
+	registerAztecFlags(backfillCmd)
+	registerSolanaFlags(backfillCmd)
+	registerCosmosFlags(backfillCmd)
+	registerEVMFlags(backfillCmd)
+
+	backfillCmd.MarkFlagRequired("chain")
+	backfillCmd.MarkFlagRequired("source-chain")
+	backfillCmd.MarkFlagRequired("emitter")
+	backfillCmd.MarkFlagRequired("from-seq")
+	backfillCmd.MarkFlagRequired("to-seq")
+}
+
+func runBackfill(cmd *cobra.Command, args []string) error {
+	logger := configureLogging(cmd, args)
+	logger.Info("Starting backfill", buildinfo.Get().Fields()...)
+
+	chain, _ := cmd.Flags().GetString("chain")
+	sourceChain, _ := cmd.Flags().GetInt("source-chain")
+	emitterAddress, _ := cmd.Flags().GetString("emitter")
+	fromSeq, _ := cmd.Flags().GetUint64("from-seq")
+	toSeq, _ := cmd.Flags().GetUint64("to-seq")
+	continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	wormholescanURL, _ := cmd.Flags().GetString("wormholescan-url")
+	submissionTimeout, _ := cmd.Flags().GetDuration("submission-timeout")
+	rateLimit, _ := cmd.Flags().GetFloat64("rate-limit")
+	rateBurst, _ := cmd.Flags().GetInt("rate-burst")
+
+	// Validate the range before connecting to anything
+	if sourceChain <= 0 || sourceChain > 0xFFFF {
+		return fmt.Errorf("invalid --source-chain: %d is not a Wormhole chain ID", sourceChain)
+	}
+	emitter, err := internal.ValidateEmitterAddress(emitterAddress)
+	if err != nil {
+		return fmt.Errorf("invalid --emitter: %v", err)
+	}
+	if fromSeq > toSeq {
+		return fmt.Errorf("--from-seq %d is after --to-seq %d", fromSeq, toSeq)
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("invalid --batch-size: must be at least 1, got %d", batchSize)
+	}
+
+	target, err := backfillTarget(cmd, chain)
+	if err != nil {
+		return err
+	}
+	if submissionTimeout == 0 {
+		submissionTimeout = target.defaultTimeout
+	}
+
+	logger.Info("Backfill configuration",
+		zap.String("destination", chain),
+		zap.Uint16("destinationChainID", target.chainID),
+		zap.Int("sourceChain", sourceChain),
+		zap.String("emitter", emitter),
+		zap.Uint64("fromSequence", fromSeq),
+		zap.Uint64("toSequence", toSeq),
+		zap.Bool("continueOnError", continueOnError),
+		zap.Int("batchSize", batchSize),
+		zap.String("wormholescan", wormholescanURL),
+		zap.Duration("submissionTimeout", submissionTimeout),
+		zap.Float64("rateLimit", rateLimit))
+
+	vaaSubmitter, err := target.build(logger)
+	if err != nil {
+		return err
+	}
+	if _, ok := vaaSubmitter.(submitter.VAABatchSubmitter); batchSize > 1 && !ok {
+		logger.Warn("Destination cannot submit VAAs in batches; --batch-size is ignored", zap.String("destination", chain))
+	}
+
+	// Only the requested emitter's VAAs for this destination are submitted
+	processor, err := internal.NewDefaultVAAProcessor(logger,
+		internal.VAAProcessorConfig{
+			ChainIDs:           []uint16{uint16(sourceChain)},
+			EmitterAddress:     emitter,
+			DestinationChainID: target.chainID,
+			SubmissionTimeout:  submissionTimeout,
+			RateLimit:          rateLimit,
+			RateBurst:          rateBurst,
+			ShortPayloads:      shortPayloadPolicy(target.chainID),
+		},
+		vaaSubmitter)
+	if err != nil {
+		return fmt.Errorf("invalid VAA processor configuration: %v", err)
+	}
+
+	// SIGINT or SIGTERM stops after the current sequence; the summary is still printed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fetcher := clients.NewWormholescanClient(logger, clients.WormholescanConfig{URL: wormholescanURL})
+	config := internal.BackfillConfig{
+		ChainID:         uint16(sourceChain),
+		Emitter:         emitter,
+		FromSequence:    fromSeq,
+		ToSequence:      toSeq,
+		ContinueOnError: continueOnError,
+		BatchSize:       batchSize,
+	}
+	results := internal.Backfill(ctx, logger, fetcher, processor, config)
+
+	if failed := printBackfillSummary(cmd.OutOrStdout(), results, config); failed > 0 {
+		return fmt.Errorf("%d of %d attempted sequences failed", failed, len(results))
+	}
+	if uint64(len(results)) < toSeq-fromSeq+1 {
+		return fmt.Errorf("backfill interrupted")
+	}
+	return nil
+}
+
+// backfillTarget reads and validates the configuration of the given destination.
+// Only one command runs per process, so the destination's flags are bound to viper here.
+func backfillTarget(cmd *cobra.Command, chain string) (routeTarget, error) {
+	spec := RouteSpec{Destination: chain}
+	var err error
+	switch chain {
+	case "aztec":
+		bindAztecFlags(cmd)
+		spec.Aztec, err = readAztecConfig(cmd)
+	case "solana":
+		bindSolanaFlags(cmd)
+		spec.Solana, err = readSolanaConfig()
+	case "cosmos":
+		bindCosmosFlags(cmd)
+		spec.Cosmos, err = readCosmosConfig(cmd)
+	default:
+		chainConfig, ok := EVMChainConfigs[chain]
+		if !ok {
+			return routeTarget{}, fmt.Errorf("unsupported chain: %s (valid: aztec, solana, cosmos, arbitrum, base)", chain)
+		}
+		bindEVMFlags(cmd)
+		spec.EVM, err = readEVMConfig(cmd, chain, chainConfig)
+	}
+	if err != nil {
+		return routeTarget{}, err
+	}
+	return resolveRouteTarget(spec)
+}
+
+// printBackfillSummary writes the outcome of every attempted sequence as an aligned table,
+// followed by the totals, and returns the number of failed sequences
+func printBackfillSummary(w io.Writer, results []internal.BackfillResult, config internal.BackfillConfig) int {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEQUENCE\tRESULT\tDETAIL")
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Decision]++
+		detail := result.TxHash
+		if result.Err != nil {
+			detail = result.Err.Error()
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", result.Sequence, result.Decision, detail)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\n%d submitted, %d already processed, %d filtered, %d failed\n",
+		counts[internal.DecisionSubmitted], counts[internal.DecisionAlreadyProcessed],
+		counts[internal.DecisionFiltered], counts[internal.DecisionFailed])
+	if len(results) == 0 {
+		fmt.Fprintf(w, "No sequences attempted; %d-%d not relayed\n", config.FromSequence, config.ToSequence)
+	} else if last := results[len(results)-1].Sequence; last < config.ToSequence {
+		fmt.Fprintf(w, "Stopped after sequence %d; %d-%d not attempted\n", last, last+1, config.ToSequence)
+	}
+	return counts[internal.DecisionFailed]
+}
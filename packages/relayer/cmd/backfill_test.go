@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal"
+)
+
+func TestPrintBackfillSummary(t *testing.T) {
+	config := internal.BackfillConfig{FromSequence: 10, ToSequence: 15}
+	results := []internal.BackfillResult{
+		{Sequence: 10, Decision: internal.DecisionSubmitted, TxHash: "0xabc"},
+		{Sequence: 11, Decision: internal.DecisionAlreadyProcessed},
+		{Sequence: 12, Decision: internal.DecisionFailed, Err: errors.New("fetch VAA: VAA not found")},
+	}
+
+	var out bytes.Buffer
+	if failed := printBackfillSummary(&out, results, config); failed != 1 {
+		t.Errorf("expected 1 failed sequence, got %d", failed)
+	}
+	summary := out.String()
+	for _, want := range []string{
+		"SEQUENCE",
+		"10        submitted          0xabc",
+		"12        failed             fetch VAA: VAA not found",
+		"1 submitted, 1 already processed, 0 filtered, 1 failed",
+		"Stopped after sequence 12; 13-15 not attempted",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected summary to contain %q, got:\n%s", want, summary)
+		}
+	}
+
+	out.Reset()
+	printBackfillSummary(&out, nil, config)
+	if !strings.Contains(out.String(), "No sequences attempted; 10-15 not relayed") {
+		t.Errorf("unexpected summary for an interrupted backfill:\n%s", out.String())
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+const (
+	// Default configuration values for the Wormhole gateway (Wormchain)
+	DefaultCosmosLCDURL       = "http://localhost:1317"
+	DefaultCosmosBech32Prefix = "wormhole"
+	DefaultCosmosFeeDenom     = "uworm"
+	DefaultCosmosGasLimit     = 2000000
+
+	// Wormchain blocks take seconds; this covers the broadcast and polling for the tx to be included
+	DefaultCosmosSubmissionTimeout = 2 * time.Minute
+
+	// Wormhole chain ID for Wormchain (the Wormhole gateway)
+	CosmosDestinationChainID uint16 = 3104
+)
+
+// Default source chains for Cosmos destination (Arbitrum=10003, Aztec=56, Solana=1, Base=10004)
+var DefaultCosmosSourceChains = []int{10003, 56, 1, 10004}
+
+// cosmosCmd represents the command to relay VAAs to a Cosmos chain
+var cosmosCmd = &cobra.Command{
+	Use:   "cosmos",
+	Short: "Relay Wormhole VAAs to a Cosmos chain via the Wormhole gateway",
+	Long: `Listens for Wormhole VAAs from configured source chains and relays them to a Cosmos chain.
+
+This command monitors the Wormhole network for messages from EVM chains, Aztec,
+Solana, or other configured chains and submits them to a CosmWasm contract
+using MsgExecuteContract.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		printBanner(cmd)
+		configureLogging(cmd, args)
+	},
+	RunE: runCosmosRelay,
+}
+
+func init() {
+	rootCmd.AddCommand(cosmosCmd)
+
+	registerCosmosFlags(cosmosCmd)
+
+	registerRelayFlags(cosmosCmd, DefaultCosmosSourceChains, DefaultCosmosSubmissionTimeout,
+		"Source chain IDs to listen for (Arbitrum=10003, Aztec=56, Solana=1, Base=10004)",
+		"Source emitter address to filter (hex)")
+
+	// Mark required flags
+	cosmosCmd.MarkFlagRequired("cosmos-private-key")
+	cosmosCmd.MarkFlagRequired("cosmos-target-contract")
+
+	// Bind flags to viper
+	bindCosmosFlags(cosmosCmd)
+}
+
+// registerCosmosFlags registers the Cosmos destination flags on cmd
+func registerCosmosFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"cosmos-lcd-url",
+		DefaultCosmosLCDURL,
+		"REST (LCD) URL of the Cosmos chain")
+
+	cmd.Flags().String(
+		"cosmos-chain-id",
+		"",
+		"Cosmos chain ID (fetched from the node when empty)")
+
+	cmd.Flags().String(
+		"cosmos-private-key",
+		"",
+		"Hex-encoded secp256k1 private key for Cosmos transactions (required)")
+
+	cmd.Flags().String(
+		"cosmos-target-contract",
+		"",
+		"Bech32 address of the CosmWasm contract to send VAAs to (required)")
+
+	cmd.Flags().String(
+		"cosmos-bech32-prefix",
+		DefaultCosmosBech32Prefix,
+		"Bech32 address prefix of the Cosmos chain")
+
+	cmd.Flags().Uint64(
+		"cosmos-gas-limit",
+		DefaultCosmosGasLimit,
+		"Gas limit for each Cosmos transaction")
+
+	cmd.Flags().String(
+		"cosmos-fee-amount",
+		"0",
+		"Fee amount paid per transaction (in --cosmos-fee-denom)")
+
+	cmd.Flags().String(
+		"cosmos-fee-denom",
+		DefaultCosmosFeeDenom,
+		"Fee denomination")
+
+	cmd.Flags().String(
+		"cosmos-execute-msg-key",
+		submitter.DefaultCosmosExecuteMsgKey,
+		"Execute message variant carrying the VAA ({\"<key>\": {\"vaa\": \"<base64>\"}})")
+}
+
+// bindCosmosFlags binds the Cosmos destination flags of cmd to viper
+func bindCosmosFlags(cmd *cobra.Command) {
+	viper.BindPFlag("cosmos_lcd_url", cmd.Flags().Lookup("cosmos-lcd-url"))
+	viper.BindPFlag("cosmos_chain_id", cmd.Flags().Lookup("cosmos-chain-id"))
+	viper.BindPFlag("cosmos_private_key", cmd.Flags().Lookup("cosmos-private-key"))
+	viper.BindPFlag("cosmos_target_contract", cmd.Flags().Lookup("cosmos-target-contract"))
+}
+
+type CosmosConfig struct {
+	CosmosLCDURL         string `mapstructure:"lcd_url"`         // REST (LCD) URL of the Cosmos chain
+	CosmosChainID        string `mapstructure:"chain_id"`        // Cosmos chain ID
+	CosmosPrivateKey     string `mapstructure:"private_key"`     // Hex-encoded secp256k1 private key
+	CosmosTargetContract string `mapstructure:"target_contract"` // Target CosmWasm contract
+	CosmosBech32Prefix   string `mapstructure:"bech32_prefix"`   // Bech32 address prefix
+	CosmosGasLimit       uint64 `mapstructure:"gas_limit"`       // Gas limit per transaction
+	CosmosFeeAmount      string `mapstructure:"fee_amount"`      // Fee amount per transaction
+	CosmosFeeDenom       string `mapstructure:"fee_denom"`       // Fee denomination
+	CosmosExecuteMsgKey  string `mapstructure:"execute_msg_key"` // Execute message variant carrying the VAA
+}
+
+func runCosmosRelay(cmd *cobra.Command, args []string) error {
+	logger := configureLogging(cmd, args)
+	logger.Info("Starting Cosmos relayer")
+
+	config, err := readCosmosConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Configuration",
+		zap.String("cosmosLCD", config.CosmosLCDURL),
+		zap.String("cosmosChainID", config.CosmosChainID),
+		zap.String("cosmosTarget", config.CosmosTargetContract),
+		zap.String("executeMsgKey", config.CosmosExecuteMsgKey))
+
+	return runRelay(logger, readRelayConfig(cmd, DefaultCosmosSourceChains), CosmosDestinationChainID,
+		func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+			return buildCosmosSubmitter(logger, config)
+		})
+}
+
+// readCosmosConfig reads and validates the Cosmos-specific flags
+func readCosmosConfig(cmd *cobra.Command) (CosmosConfig, error) {
+	// Get flags directly from command (viper bindings conflict across commands)
+	bech32Prefix, _ := cmd.Flags().GetString("cosmos-bech32-prefix")
+	gasLimit, _ := cmd.Flags().GetUint64("cosmos-gas-limit")
+	feeAmount, _ := cmd.Flags().GetString("cosmos-fee-amount")
+	feeDenom, _ := cmd.Flags().GetString("cosmos-fee-denom")
+	executeMsgKey, _ := cmd.Flags().GetString("cosmos-execute-msg-key")
+
+	config := CosmosConfig{
+		CosmosLCDURL:         viper.GetString("cosmos_lcd_url"),
+		CosmosChainID:        viper.GetString("cosmos_chain_id"),
+		CosmosPrivateKey:     viper.GetString("cosmos_private_key"),
+		CosmosTargetContract: viper.GetString("cosmos_target_contract"),
+		CosmosBech32Prefix:   bech32Prefix,
+		CosmosGasLimit:       gasLimit,
+		CosmosFeeAmount:      feeAmount,
+		CosmosFeeDenom:       feeDenom,
+		CosmosExecuteMsgKey:  executeMsgKey,
+	}
+
+	return config, validateCosmosConfig(config)
+}
+
+// validateCosmosConfig checks the key and target contract of a Cosmos destination
+func validateCosmosConfig(config CosmosConfig) error {
+	if config.CosmosPrivateKey == "" {
+		return fmt.Errorf("Cosmos private key is required")
+	}
+	if err := internal.ValidateCosmosAddress(config.CosmosTargetContract, config.CosmosBech32Prefix); err != nil {
+		return fmt.Errorf("invalid --cosmos-target-contract: %v", err)
+	}
+
+	return nil
+}
+
+// buildCosmosSubmitter creates the Cosmos client and submitter
+func buildCosmosSubmitter(logger *zap.Logger, config CosmosConfig) (submitter.VAASubmitter, error) {
+	cosmosClient, err := clients.NewCosmosClient(logger, clients.CosmosClientConfig{
+		LCDURL:       config.CosmosLCDURL,
+		ChainID:      config.CosmosChainID,
+		PrivateKey:   config.CosmosPrivateKey,
+		Bech32Prefix: config.CosmosBech32Prefix,
+		GasLimit:     config.CosmosGasLimit,
+		FeeAmount:    config.CosmosFeeAmount,
+		FeeDenom:     config.CosmosFeeDenom,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cosmos client: %v", err)
+	}
+
+	logger.Info("Connected to Cosmos",
+		zap.String("sender", cosmosClient.GetAddress()))
+
+	return submitter.NewCosmosSubmitter(logger,
+		config.CosmosTargetContract, config.CosmosExecuteMsgKey, cosmosClient), nil
+}
@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -16,24 +16,36 @@ import (
 	"github.com/wormhole-demo/relayer/internal/submitter"
 )
 
+// DefaultEVMSubmissionTimeout bounds sending a transaction and waiting for its receipt
+const DefaultEVMSubmissionTimeout = 60 * time.Second
+
+// evmDefaultPriorityFeeGwei is the default static priority fee, clients.DefaultPriorityFee in gwei
+const evmDefaultPriorityFeeGwei = 0.1
+
+// evmMinGasLimit is the lowest valid --evm-gas-limit, the intrinsic gas of any transaction
+const evmMinGasLimit = 21_000
+
 // EVMChainConfig holds chain-specific configuration
 type EVMChainConfig struct {
-	DestinationChainID uint16
-	DefaultRPCURL      string
+	DestinationChainID  uint16
+	EVMChainID          uint64 // Chain ID the RPC endpoint must report (eth_chainId)
+	DefaultRPCURL       string
 	DefaultSourceChains []int
-	DisplayName        string
+	DisplayName         string
 }
 
 // Supported EVM chains
 var EVMChainConfigs = map[string]EVMChainConfig{
 	"arbitrum": {
 		DestinationChainID:  10003,
+		EVMChainID:          421614,
 		DefaultRPCURL:       "https://sepolia-rollup.arbitrum.io/rpc",
 		DefaultSourceChains: []int{56, 1, 10004}, // Aztec, Solana, Base
 		DisplayName:         "Arbitrum Sepolia",
 	},
 	"base": {
 		DestinationChainID:  10004,
+		EVMChainID:          84532,
 		DefaultRPCURL:       "https://sepolia.base.org",
 		DefaultSourceChains: []int{56, 1, 10003}, // Aztec, Solana, Arbitrum
 		DisplayName:         "Base Sepolia",
@@ -51,7 +63,7 @@ or other configured chains and submits them to the specified EVM chain.
 
 Use --chain to specify the target chain (arbitrum or base).`,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		printBanner()
+		printBanner(cmd)
 		configureLogging(cmd, args)
 	},
 	RunE: runEVMRelay,
@@ -66,53 +78,194 @@ func init() {
 		"arbitrum",
 		"Target EVM chain (arbitrum, base)")
 
-	// EVM-specific flags
-	evmCmd.Flags().String(
+	registerEVMFlags(evmCmd)
+
+	registerRelayFlags(evmCmd, nil, DefaultEVMSubmissionTimeout,
+		"Source chain IDs to listen for (defaults based on --chain)",
+		"Source emitter address to filter (hex, e.g., Aztec bridge address)")
+
+	// The key comes from --private-key, --private-key-file, --keystore-file or --evm-remote-signer-url
+	// (validated at startup, like the target contract)
+	evmCmd.MarkFlagsMutuallyExclusive("private-key", "private-key-file", "keystore-file", "evm-remote-signer-url")
+	evmCmd.MarkFlagsMutuallyExclusive("keystore-password", "keystore-password-file")
+
+	// Bind flags to viper
+	viper.BindPFlag("chain", evmCmd.Flags().Lookup("chain"))
+	bindEVMFlags(evmCmd)
+}
+
+// registerEVMFlags registers the EVM destination flags on cmd
+func registerEVMFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
 		"evm-rpc-url",
 		"",
-		"RPC URL for EVM chain (defaults based on --chain)")
+		"RPC URL for EVM chain (defaults based on --chain); comma-separate several http(s) URLs to fail over between them")
 
-	evmCmd.Flags().String(
+	cmd.Flags().String(
 		"private-key",
 		"",
-		"Private key for EVM transactions (required)")
+		"Private key for EVM transactions (hex; prefer --private-key-file)")
 
-	evmCmd.Flags().String(
+	cmd.Flags().String(
+		"private-key-file",
+		"",
+		"Path to a file holding the hex-encoded private key for EVM transactions")
+
+	cmd.Flags().String(
+		"keystore-file",
+		"",
+		"Path to a go-ethereum V3 keystore file holding the key for EVM transactions")
+
+	cmd.Flags().String(
+		"keystore-password",
+		"",
+		"Password for --keystore-file (prefer --keystore-password-file)")
+
+	cmd.Flags().String(
+		"keystore-password-file",
+		"",
+		"Path to a file holding the password for --keystore-file")
+
+	cmd.Flags().String(
+		"evm-remote-signer-url",
+		"",
+		"Remote signer (e.g. a KMS or HSM gateway) signing as --evm-signer-address instead of a local key")
+
+	cmd.Flags().String(
+		"evm-signer-address",
+		"",
+		"Account the --evm-remote-signer-url signs for (0x-prefixed address)")
+
+	cmd.Flags().String(
 		"evm-target-contract",
 		"",
-		"Target contract on EVM chain to send VAAs to (required)")
+		"Target contract on EVM chain to send VAAs to (required unless --evm-target-routes covers all destinations)")
 
-	evmCmd.Flags().IntSlice(
-		"chain-ids",
+	cmd.Flags().StringToString(
+		"evm-target-routes",
 		nil,
-		"Source chain IDs to listen for (defaults based on --chain)")
+		"Per-destination target contracts, keyed by the payload's destination chain ID (e.g. 10004=0xabc...,10003=0xdef...)")
+
+	cmd.Flags().Int(
+		"evm-rpc-retries",
+		clients.DefaultRetryConfig().MaxAttempts,
+		"Attempts per EVM RPC call on transient errors (429, 5xx, timeouts)")
+
+	cmd.Flags().Duration(
+		"evm-rpc-backoff",
+		clients.DefaultRetryConfig().InitialBackoff,
+		"Initial backoff between EVM RPC retries, doubled on each retry")
+
+	cmd.Flags().Duration(
+		"evm-rpc-max-backoff",
+		clients.DefaultRetryConfig().MaxBackoff,
+		"Maximum backoff between EVM RPC retries")
+
+	cmd.Flags().Int(
+		"evm-rpc-read-concurrency",
+		0,
+		"Maximum header, nonce and contract call reads in flight across all VAAs, for rate-limited RPCs (0 = unlimited)")
+
+	cmd.Flags().Bool(
+		"evm-skip-emitter-check",
+		false,
+		"Skip checking the VAA's emitter is registered with the target contract (registeredEmitters) before sending it")
+
+	cmd.Flags().String(
+		"evm-processed-check",
+		"",
+		"Signature of a view function on the target contract returning whether a VAA was already processed, e.g. isProcessed(uint16,bytes32,uint64); called before each send to skip consumed VAAs (empty = always send)")
+
+	cmd.Flags().StringSlice(
+		"evm-processed-check-args",
+		clients.DefaultProcessedCheckArgs,
+		"What is passed for each parameter of --evm-processed-check, in order: chain, emitter, sequence or hash (the VAA digest)")
+
+	cmd.Flags().Float64(
+		"evm-priority-fee",
+		evmDefaultPriorityFeeGwei,
+		"Priority fee (tip) in gwei, used when the node cannot suggest one with eth_maxPriorityFeePerGas")
+
+	cmd.Flags().Float64(
+		"evm-min-priority-fee",
+		0,
+		"Lowest priority fee in gwei, whether suggested or static (0 = no minimum)")
+
+	cmd.Flags().Float64(
+		"evm-max-priority-fee",
+		0,
+		"Highest priority fee in gwei, whether suggested or static (0 = no maximum)")
+
+	cmd.Flags().Float64(
+		"evm-max-fee",
+		0,
+		"Fee cap (max fee per gas) in gwei, replacing the multiplied base fee plus the priority fee (0 = computed from the base fee)")
+
+	cmd.Flags().Uint64(
+		"evm-base-fee-multiplier",
+		clients.DefaultBaseFeeMultiplier,
+		"How many times the latest base fee the computed fee cap covers, on top of the priority fee")
+
+	cmd.Flags().Uint64(
+		"evm-gas-limit",
+		clients.DefaultEVMGasLimit,
+		"Gas limit of each transaction")
+
+	cmd.Flags().String(
+		"evm-fee-model",
+		"",
+		"How the chain charges for transactions: generic, arbitrum or op-stack (default: chosen from the chain ID the RPC reports)")
 
-	evmCmd.Flags().String(
-		"emitter-address",
+	cmd.Flags().String(
+		"evm-fee-oracle-url",
 		"",
-		"Source emitter address to filter (hex, e.g., Aztec bridge address)")
+		"RPC endpoint queried with eth_maxPriorityFeePerGas for the priority fee instead of --evm-rpc-url")
 
-	// Mark private key and target contract as required
-	evmCmd.MarkFlagRequired("private-key")
-	evmCmd.MarkFlagRequired("evm-target-contract")
+	cmd.Flags().Uint64(
+		"evm-confirmations",
+		1,
+		"Blocks deep a transaction's block must be before its VAA counts as delivered (1 = once included)")
+}
 
-	// Bind flags to viper
-	viper.BindPFlag("chain", evmCmd.Flags().Lookup("chain"))
-	viper.BindPFlag("evm_rpc_url", evmCmd.Flags().Lookup("evm-rpc-url"))
-	viper.BindPFlag("private_key", evmCmd.Flags().Lookup("private-key"))
-	viper.BindPFlag("evm_target_contract", evmCmd.Flags().Lookup("evm-target-contract"))
-	viper.BindPFlag("chain_ids", evmCmd.Flags().Lookup("chain-ids"))
-	viper.BindPFlag("emitter_address", evmCmd.Flags().Lookup("emitter-address"))
+// bindEVMFlags binds the EVM destination flags of cmd to viper
+func bindEVMFlags(cmd *cobra.Command) {
+	viper.BindPFlag("evm_rpc_url", cmd.Flags().Lookup("evm-rpc-url"))
+	viper.BindPFlag("private_key", cmd.Flags().Lookup("private-key"))
+	viper.BindPFlag("private_key_file", cmd.Flags().Lookup("private-key-file"))
+	viper.BindPFlag("keystore_file", cmd.Flags().Lookup("keystore-file"))
+	viper.BindPFlag("keystore_password", cmd.Flags().Lookup("keystore-password"))
+	viper.BindPFlag("keystore_password_file", cmd.Flags().Lookup("keystore-password-file"))
+	viper.BindPFlag("evm_remote_signer_url", cmd.Flags().Lookup("evm-remote-signer-url"))
+	viper.BindPFlag("evm_signer_address", cmd.Flags().Lookup("evm-signer-address"))
+	viper.BindPFlag("evm_target_contract", cmd.Flags().Lookup("evm-target-contract"))
 }
 
 type EVMConfig struct {
-	ChainName         string   // Target chain name (arbitrum, base)
-	SpyRPCHost        string   // Wormhole spy service endpoint
-	ChainIDs          []uint16 // Source chain IDs to listen for
-	EVMRPCURL         string   // RPC URL for EVM chain
-	PrivateKey        string   // Private key for EVM transactions
-	EVMTargetContract string   // Target contract on EVM
-	EmitterAddress    string   // Source emitter address to filter
+	ChainName            string              `mapstructure:"-"`                      // Target chain name (arbitrum, base)
+	EVMRPCURL            string              `mapstructure:"rpc_url"`                // RPC URL for EVM chain
+	PrivateKey           string              `mapstructure:"private_key"`            // Private key for EVM transactions
+	PrivateKeyFile       string              `mapstructure:"private_key_file"`       // Path to a file holding the hex-encoded private key
+	KeystoreFile         string              `mapstructure:"keystore_file"`          // Path to a go-ethereum V3 keystore file
+	KeystorePassword     string              `mapstructure:"keystore_password"`      // Password for the keystore
+	KeystorePasswordFile string              `mapstructure:"keystore_password_file"` // Path to a file holding the keystore password
+	EVMTargetContract    string              `mapstructure:"target_contract"`        // Target contract on EVM
+	EVMTargetRoutes      map[uint16]string   `mapstructure:"target_routes"`          // Per-destination target contracts
+	RPCRetry             clients.RetryConfig `mapstructure:"rpc_retry"`              // Retry policy for transient EVM RPC errors
+	RPCReadConcurrency   int                 `mapstructure:"rpc_read_concurrency"`   // Maximum RPC reads in flight (0 = unlimited)
+	SkipEmitterCheck     bool                `mapstructure:"skip_emitter_check"`     // Skip checking the emitter is registered before sending
+	ProcessedCheck       string              `mapstructure:"processed_check"`        // View function reporting processed VAAs (optional)
+	ProcessedCheckArgs   []string            `mapstructure:"processed_check_args"`   // Arguments passed to the processed check, in order
+	PriorityFeeGwei      float64             `mapstructure:"priority_fee_gwei"`      // Static priority fee, used when none is suggested
+	MinPriorityFeeGwei   float64             `mapstructure:"min_priority_fee_gwei"`  // Lowest priority fee (0 = no minimum)
+	MaxPriorityFeeGwei   float64             `mapstructure:"max_priority_fee_gwei"`  // Highest priority fee (0 = no maximum)
+	MaxFeeGwei           float64             `mapstructure:"max_fee_gwei"`           // Fee cap per gas (0 = multiplied base fee plus the tip)
+	BaseFeeMultiplier    uint64              `mapstructure:"base_fee_multiplier"`    // Base fees covered by the computed fee cap (0 = default)
+	GasLimit             uint64              `mapstructure:"gas_limit"`              // Gas limit of each transaction
+	FeeModel             string              `mapstructure:"fee_model"`              // Fee model of the chain (empty = chosen from the chain ID)
+	FeeOracleURL         string              `mapstructure:"fee_oracle_url"`         // RPC endpoint suggesting the priority fee (optional)
+	Confirmations        uint64              `mapstructure:"confirmations"`          // Blocks deep a transaction must be to count as delivered
+	RemoteSignerURL      string              `mapstructure:"remote_signer_url"`      // Remote signer replacing the local key (optional)
+	SignerAddress        string              `mapstructure:"signer_address"`         // Account the remote signer signs for
 }
 
 func runEVMRelay(cmd *cobra.Command, args []string) error {
@@ -127,20 +280,43 @@ func runEVMRelay(cmd *cobra.Command, args []string) error {
 
 	logger.Info(fmt.Sprintf("Starting %s relayer", chainConfig.DisplayName))
 
-	// Get flags directly from command (viper bindings conflict across commands)
-	emitterAddress, _ := cmd.Flags().GetString("emitter-address")
-	chainIDsInt, _ := cmd.Flags().GetIntSlice("chain-ids")
-
-	// Use default source chains if not specified
-	if len(chainIDsInt) == 0 {
-		chainIDsInt = chainConfig.DefaultSourceChains
+	config, err := readEVMConfig(cmd, chainName, chainConfig)
+	if err != nil {
+		return err
 	}
 
-	// Convert chain IDs from []int to []uint16
-	chainIDs := make([]uint16, len(chainIDsInt))
-	for i, id := range chainIDsInt {
-		chainIDs[i] = uint16(id)
-	}
+	logger.Info("Configuration",
+		zap.String("chain", chainConfig.DisplayName),
+		zap.String("evmRPC", config.EVMRPCURL),
+		zap.String("evmTarget", config.EVMTargetContract),
+		zap.Any("evmTargetRoutes", config.EVMTargetRoutes))
+
+	return runRelay(logger, readRelayConfig(cmd, chainConfig.DefaultSourceChains), chainConfig.DestinationChainID,
+		func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+			return buildEVMSubmitter(logger, config)
+		})
+}
+
+// readEVMConfig reads and validates the EVM-specific flags
+func readEVMConfig(cmd *cobra.Command, chainName string, chainConfig EVMChainConfig) (EVMConfig, error) {
+	// Get flags directly from command (viper bindings conflict across commands)
+	targetRoutesRaw, _ := cmd.Flags().GetStringToString("evm-target-routes")
+	rpcRetries, _ := cmd.Flags().GetInt("evm-rpc-retries")
+	rpcBackoff, _ := cmd.Flags().GetDuration("evm-rpc-backoff")
+	rpcMaxBackoff, _ := cmd.Flags().GetDuration("evm-rpc-max-backoff")
+	rpcReadConcurrency, _ := cmd.Flags().GetInt("evm-rpc-read-concurrency")
+	skipEmitterCheck, _ := cmd.Flags().GetBool("evm-skip-emitter-check")
+	processedCheck, _ := cmd.Flags().GetString("evm-processed-check")
+	processedCheckArgs, _ := cmd.Flags().GetStringSlice("evm-processed-check-args")
+	priorityFee, _ := cmd.Flags().GetFloat64("evm-priority-fee")
+	minPriorityFee, _ := cmd.Flags().GetFloat64("evm-min-priority-fee")
+	maxPriorityFee, _ := cmd.Flags().GetFloat64("evm-max-priority-fee")
+	maxFee, _ := cmd.Flags().GetFloat64("evm-max-fee")
+	baseFeeMultiplier, _ := cmd.Flags().GetUint64("evm-base-fee-multiplier")
+	gasLimit, _ := cmd.Flags().GetUint64("evm-gas-limit")
+	feeModel, _ := cmd.Flags().GetString("evm-fee-model")
+	feeOracleURL, _ := cmd.Flags().GetString("evm-fee-oracle-url")
+	confirmations, _ := cmd.Flags().GetUint64("evm-confirmations")
 
 	// Get RPC URL, use default if not specified
 	rpcURL := viper.GetString("evm_rpc_url")
@@ -149,80 +325,204 @@ func runEVMRelay(cmd *cobra.Command, args []string) error {
 	}
 
 	config := EVMConfig{
-		ChainName:         chainName,
-		SpyRPCHost:        viper.GetString("spy_rpc_host"),
-		ChainIDs:          chainIDs,
-		EVMRPCURL:         rpcURL,
-		PrivateKey:        viper.GetString("private_key"),
-		EVMTargetContract: viper.GetString("evm_target_contract"),
-		EmitterAddress:    emitterAddress,
+		ChainName:            chainName,
+		EVMRPCURL:            rpcURL,
+		PrivateKey:           viper.GetString("private_key"),
+		PrivateKeyFile:       viper.GetString("private_key_file"),
+		KeystoreFile:         viper.GetString("keystore_file"),
+		KeystorePassword:     viper.GetString("keystore_password"),
+		KeystorePasswordFile: viper.GetString("keystore_password_file"),
+		RemoteSignerURL:      viper.GetString("evm_remote_signer_url"),
+		SignerAddress:        viper.GetString("evm_signer_address"),
+		EVMTargetContract:    viper.GetString("evm_target_contract"),
+		EVMTargetRoutes:      make(map[uint16]string),
+		RPCRetry: clients.RetryConfig{
+			MaxAttempts:    rpcRetries,
+			InitialBackoff: rpcBackoff,
+			MaxBackoff:     rpcMaxBackoff,
+		},
+		RPCReadConcurrency: rpcReadConcurrency,
+		SkipEmitterCheck:   skipEmitterCheck,
+		ProcessedCheck:     processedCheck,
+		ProcessedCheckArgs: processedCheckArgs,
+		PriorityFeeGwei:    priorityFee,
+		MinPriorityFeeGwei: minPriorityFee,
+		MaxPriorityFeeGwei: maxPriorityFee,
+		MaxFeeGwei:         maxFee,
+		BaseFeeMultiplier:  baseFeeMultiplier,
+		GasLimit:           gasLimit,
+		FeeModel:           feeModel,
+		FeeOracleURL:       feeOracleURL,
+		Confirmations:      confirmations,
 	}
 
-	// Validate private key is provided
-	if config.PrivateKey == "" {
-		return fmt.Errorf("private key is required for EVM transactions")
+	for chain, target := range targetRoutesRaw {
+		chainID, err := strconv.ParseUint(chain, 10, 16)
+		if err != nil {
+			return config, fmt.Errorf("invalid --evm-target-routes chain ID %q: %v", chain, err)
+		}
+		config.EVMTargetRoutes[uint16(chainID)] = target
 	}
 
-	logger.Info("Configuration",
-		zap.String("chain", chainConfig.DisplayName),
-		zap.Uint16("destinationChainID", chainConfig.DestinationChainID),
-		zap.String("spyRPC", config.SpyRPCHost),
-		zap.Any("sourceChainIds", config.ChainIDs),
-		zap.String("evmRPC", config.EVMRPCURL),
-		zap.String("evmTarget", config.EVMTargetContract),
-		zap.String("emitterFilter", config.EmitterAddress))
+	return config, validateEVMConfig(config)
+}
 
-	// Create spy client
-	spyClient, err := clients.NewSpyClient(logger, config.SpyRPCHost)
-	if err != nil {
-		return fmt.Errorf("failed to create spy client: %v", err)
+// validateEVMConfig checks the key source and target contracts of an EVM destination
+func validateEVMConfig(config EVMConfig) error {
+	// Validate exactly one private key source is provided
+	keySources := 0
+	for _, source := range []string{config.PrivateKey, config.PrivateKeyFile, config.KeystoreFile, config.RemoteSignerURL} {
+		if source != "" {
+			keySources++
+		}
 	}
-
-	// Create EVM client
-	evmClient, err := clients.NewEVMClient(logger, config.EVMRPCURL, config.PrivateKey)
-	if err != nil {
-		return fmt.Errorf("failed to create EVM client: %v", err)
+	if keySources == 0 {
+		return fmt.Errorf("--private-key, --private-key-file, --keystore-file or --evm-remote-signer-url is required for EVM transactions")
 	}
+	if keySources > 1 {
+		return fmt.Errorf("--private-key, --private-key-file, --keystore-file and --evm-remote-signer-url are mutually exclusive")
+	}
+	if config.RemoteSignerURL != "" {
+		if err := internal.ValidateEVMAddress(config.SignerAddress); err != nil {
+			return fmt.Errorf("invalid --evm-signer-address (required with --evm-remote-signer-url): %v", err)
+		}
+	} else if config.SignerAddress != "" {
+		return fmt.Errorf("--evm-signer-address requires --evm-remote-signer-url")
+	}
+	if config.KeystoreFile != "" && config.KeystorePassword == "" && config.KeystorePasswordFile == "" {
+		return fmt.Errorf("--keystore-password or --keystore-password-file is required with --keystore-file")
+	}
+	if config.EVMTargetContract == "" && len(config.EVMTargetRoutes) == 0 {
+		return fmt.Errorf("--evm-target-contract or --evm-target-routes is required")
+	}
+	if config.EVMTargetContract != "" {
+		if err := internal.ValidateEVMAddress(config.EVMTargetContract); err != nil {
+			return fmt.Errorf("invalid --evm-target-contract: %v", err)
+		}
+	}
+	for chainID, target := range config.EVMTargetRoutes {
+		if err := internal.ValidateEVMAddress(target); err != nil {
+			return fmt.Errorf("invalid --evm-target-routes target for chain %d: %v", chainID, err)
+		}
+	}
+	for flag, gwei := range map[string]float64{
+		"--evm-priority-fee":     config.PriorityFeeGwei,
+		"--evm-min-priority-fee": config.MinPriorityFeeGwei,
+		"--evm-max-priority-fee": config.MaxPriorityFeeGwei,
+		"--evm-max-fee":          config.MaxFeeGwei,
+	} {
+		if gwei < 0 || math.IsNaN(gwei) || math.IsInf(gwei, 0) {
+			return fmt.Errorf("invalid %s: %v is not a non-negative amount of gwei", flag, gwei)
+		}
+	}
+	if config.MaxPriorityFeeGwei > 0 && config.MinPriorityFeeGwei > config.MaxPriorityFeeGwei {
+		return fmt.Errorf("--evm-min-priority-fee %v is above --evm-max-priority-fee %v", config.MinPriorityFeeGwei, config.MaxPriorityFeeGwei)
+	}
+	if config.MaxFeeGwei > 0 && config.MinPriorityFeeGwei > config.MaxFeeGwei {
+		return fmt.Errorf("--evm-min-priority-fee %v is above --evm-max-fee %v", config.MinPriorityFeeGwei, config.MaxFeeGwei)
+	}
+	if config.GasLimit != 0 && config.GasLimit < evmMinGasLimit {
+		return fmt.Errorf("invalid --evm-gas-limit: %d is below the %d gas of a plain transfer", config.GasLimit, evmMinGasLimit)
+	}
+	if config.RPCReadConcurrency < 0 {
+		return fmt.Errorf("--evm-rpc-read-concurrency must not be negative")
+	}
+	if _, err := clients.ParseFeeModel(config.FeeModel); err != nil {
+		return fmt.Errorf("invalid --evm-fee-model: %v", err)
+	}
+	return nil
+}
 
-	logger.Info("Connected to EVM",
-		zap.String("address", evmClient.GetAddress().Hex()))
-
-	// Create EVM submitter
-	evmSubmitter := submitter.NewEVMSubmitter(logger, config.EVMTargetContract, evmClient)
+// evmSigner returns the remote signer of config, or nil if the key is local
+func evmSigner(config EVMConfig) (clients.EVMSigner, error) {
+	if config.RemoteSignerURL == "" {
+		return nil, nil
+	}
+	return clients.NewRemoteEVMSigner(remoteSignerConfig(config.RemoteSignerURL), config.SignerAddress)
+}
 
-	// Create VAA processor
-	vaaProcessor := internal.NewDefaultVAAProcessor(logger,
-		internal.VAAProcessorConfig{
-			ChainIDs:           config.ChainIDs,
-			EmitterAddress:     config.EmitterAddress,
-			DestinationChainID: chainConfig.DestinationChainID,
-		},
-		evmSubmitter)
+// evmFeeModel returns the configured fee model of config, or the one of its chain without one
+func evmFeeModel(config EVMConfig) clients.FeeModel {
+	if config.FeeModel != "" {
+		return clients.FeeModel(config.FeeModel)
+	}
+	return clients.FeeModelForChain(EVMChainConfigs[config.ChainName].EVMChainID)
+}
 
-	// Create and start relayer
-	relayer, err := internal.NewRelayer(logger, spyClient, vaaProcessor)
+// buildEVMSubmitter connects to the EVM chain and creates the EVM submitter
+func buildEVMSubmitter(logger *zap.Logger, config EVMConfig) (submitter.VAASubmitter, error) {
+	signer, err := evmSigner(config)
+	if err != nil {
+		return nil, err
+	}
+	evmClient, err := clients.NewEVMClient(logger, clients.EVMClientConfig{
+		RPCURL:               config.EVMRPCURL,
+		PrivateKey:           config.PrivateKey,
+		PrivateKeyFile:       config.PrivateKeyFile,
+		KeystoreFile:         config.KeystoreFile,
+		KeystorePassword:     config.KeystorePassword,
+		KeystorePasswordFile: config.KeystorePasswordFile,
+		Signer:               signer,
+		Retry:                config.RPCRetry,
+		ReadConcurrency:      config.RPCReadConcurrency,
+		PriorityFee:          priorityFeeConfig(config),
+		Confirmations:        config.Confirmations,
+		GasLimit:             config.GasLimit,
+		MaxFee:               maxFeeWei(config),
+		BaseFeeMultiplier:    config.BaseFeeMultiplier,
+		FeeModel:             clients.FeeModel(config.FeeModel),
+		ProcessedCheck:       processedCheckConfig(config),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to initialize relayer: %v", err)
+		return nil, fmt.Errorf("failed to create EVM client: %v", err)
 	}
-	defer relayer.Close()
 
-	// Setup context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	logger.Info("Connected to EVM",
+		zap.String("address", evmClient.GetAddress().Hex()),
+		zap.String("confirmationMode", evmClient.GetConfirmationMode()),
+		zap.Uint64("confirmations", config.Confirmations),
+		zap.String("feeModel", string(evmFeeModel(config))),
+		zap.String("feeOracle", config.FeeOracleURL),
+		zap.String("processedCheck", config.ProcessedCheck))
+
+	evmSubmitter := submitter.NewEVMSubmitterWithRoutes(logger, config.EVMTargetContract, config.EVMTargetRoutes, evmClient)
+	evmSubmitter.SetEmitterCheck(!config.SkipEmitterCheck)
+	return evmSubmitter, nil
+}
 
-	// Handle graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		logger.Info("Received shutdown signal")
-		cancel()
-	}()
+// processedCheckConfig returns the processed check of config, or nil if none is configured
+func processedCheckConfig(config EVMConfig) *clients.ProcessedCheckConfig {
+	if config.ProcessedCheck == "" {
+		return nil
+	}
+	return &clients.ProcessedCheckConfig{Signature: config.ProcessedCheck, Args: config.ProcessedCheckArgs}
+}
 
-	// Start the relayer
-	if err := relayer.Start(ctx); err != nil {
-		return fmt.Errorf("relayer stopped with error: %v", err)
+// priorityFeeConfig converts the gwei priority fee settings of config to the client's, in wei.
+// A zero minimum or maximum is no bound.
+func priorityFeeConfig(config EVMConfig) clients.PriorityFeeConfig {
+	fees := clients.PriorityFeeConfig{
+		Static:    gweiToWei(config.PriorityFeeGwei),
+		OracleURL: config.FeeOracleURL,
+	}
+	if config.MinPriorityFeeGwei > 0 {
+		fees.Min = gweiToWei(config.MinPriorityFeeGwei)
+	}
+	if config.MaxPriorityFeeGwei > 0 {
+		fees.Max = gweiToWei(config.MaxPriorityFeeGwei)
 	}
+	return fees
+}
 
-	return nil
+// maxFeeWei returns the fee cap of config in wei, or nil if it is computed from the base fee
+func maxFeeWei(config EVMConfig) *big.Int {
+	if config.MaxFeeGwei <= 0 {
+		return nil
+	}
+	return gweiToWei(config.MaxFeeGwei)
+}
+
+// gweiToWei converts an amount of gwei, as given in flags, to wei
+func gweiToWei(gwei float64) *big.Int {
+	return big.NewInt(int64(math.Round(gwei * 1e9)))
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestPriorityFeeConfig(t *testing.T) {
+	fees := priorityFeeConfig(EVMConfig{PriorityFeeGwei: 0.1, MaxPriorityFeeGwei: 2.5, FeeOracleURL: "http://oracle"})
+	if fees.Static.Cmp(big.NewInt(100_000_000)) != 0 {
+		t.Errorf("expected a static tip of 0.1 gwei, got %s wei", fees.Static)
+	}
+	if fees.Min != nil {
+		t.Errorf("expected no minimum, got %s wei", fees.Min)
+	}
+	if fees.Max == nil || fees.Max.Cmp(big.NewInt(2_500_000_000)) != 0 {
+		t.Errorf("expected a maximum of 2.5 gwei, got %v wei", fees.Max)
+	}
+	if fees.OracleURL != "http://oracle" {
+		t.Errorf("expected the fee oracle to be passed through, got %q", fees.OracleURL)
+	}
+}
+
+func TestValidateEVMConfigPriorityFees(t *testing.T) {
+	base := EVMConfig{PrivateKey: "0x01", EVMTargetContract: "0x1111111111111111111111111111111111111111"}
+
+	valid := base
+	valid.PriorityFeeGwei, valid.MinPriorityFeeGwei, valid.MaxPriorityFeeGwei = 0.1, 0.05, 3
+	if err := validateEVMConfig(valid); err != nil {
+		t.Errorf("expected valid priority fees, got %v", err)
+	}
+
+	negative := base
+	negative.PriorityFeeGwei = -1
+	if err := validateEVMConfig(negative); err == nil {
+		t.Error("expected a negative priority fee to be rejected")
+	}
+
+	inverted := base
+	inverted.MinPriorityFeeGwei, inverted.MaxPriorityFeeGwei = 5, 1
+	if err := validateEVMConfig(inverted); err == nil {
+		t.Error("expected a minimum above the maximum to be rejected")
+	}
+}
+
+func TestValidateEVMConfigRemoteSigner(t *testing.T) {
+	base := EVMConfig{EVMTargetContract: "0x1111111111111111111111111111111111111111"}
+
+	remote := base
+	remote.RemoteSignerURL, remote.SignerAddress = "https://kms.example/sign", "0x2222222222222222222222222222222222222222"
+	if err := validateEVMConfig(remote); err != nil {
+		t.Errorf("expected a remote signer to stand in for the key, got %v", err)
+	}
+
+	both := remote
+	both.PrivateKey = "0x01"
+	if err := validateEVMConfig(both); err == nil {
+		t.Error("expected a remote signer with a private key to be rejected")
+	}
+
+	noAddress := remote
+	noAddress.SignerAddress = ""
+	if err := validateEVMConfig(noAddress); err == nil {
+		t.Error("expected a remote signer without --evm-signer-address to be rejected")
+	}
+
+	addressOnly := base
+	addressOnly.PrivateKey, addressOnly.SignerAddress = "0x01", remote.SignerAddress
+	if err := validateEVMConfig(addressOnly); err == nil {
+		t.Error("expected --evm-signer-address without a remote signer to be rejected")
+	}
+}
+
+func TestValidateEVMConfigGasOverrides(t *testing.T) {
+	base := EVMConfig{PrivateKey: "0x01", EVMTargetContract: "0x1111111111111111111111111111111111111111"}
+
+	valid := base
+	valid.GasLimit, valid.MaxFeeGwei, valid.FeeModel = 500_000, 2, "op-stack"
+	if err := validateEVMConfig(valid); err != nil {
+		t.Errorf("expected valid gas overrides, got %v", err)
+	}
+	if fee := maxFeeWei(valid); fee == nil || fee.Cmp(big.NewInt(2_000_000_000)) != 0 {
+		t.Errorf("expected a fee cap of 2 gwei, got %v wei", fee)
+	}
+	if fee := maxFeeWei(base); fee != nil {
+		t.Errorf("expected no fee cap by default, got %s wei", fee)
+	}
+
+	// Without a configured fee model, the chain's is used
+	for chain, want := range map[string]clients.FeeModel{"arbitrum": clients.FeeModelArbitrum, "base": clients.FeeModelOPStack} {
+		if got := evmFeeModel(EVMConfig{ChainName: chain}); got != want {
+			t.Errorf("%s: expected fee model %s, got %s", chain, want, got)
+		}
+	}
+	if got := evmFeeModel(valid); got != clients.FeeModelOPStack {
+		t.Errorf("expected the configured fee model, got %s", got)
+	}
+
+	for name, config := range map[string]EVMConfig{
+		"gas limit below a transfer":    {GasLimit: 20_000},
+		"negative fee cap":              {MaxFeeGwei: -1},
+		"minimum tip above the fee cap": {MaxFeeGwei: 1, MinPriorityFeeGwei: 2},
+		"unknown fee model":             {FeeModel: "optimism"},
+	} {
+		config.PrivateKey, config.EVMTargetContract = base.PrivateKey, base.EVMTargetContract
+		if err := validateEVMConfig(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
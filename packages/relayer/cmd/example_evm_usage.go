@@ -22,7 +22,11 @@ func ExampleEVMSubmitterUsage() {
 	targetContract := "0x248EC2E5595480fF371031698ae3a4099b8dC229"
 
 	// Create EVM client
-	evmClient, err := clients.NewEVMClient(logger, evmRPCURL, privateKey)
+	evmClient, err := clients.NewEVMClient(logger, clients.EVMClientConfig{
+		RPCURL:     evmRPCURL,
+		PrivateKey: privateKey,
+		Retry:      clients.DefaultRetryConfig(),
+	})
 	if err != nil {
 		panic(fmt.Errorf("failed to create EVM client: %v", err))
 	}
@@ -46,4 +50,4 @@ func ExampleEVMSubmitterUsage() {
 	}
 
 	logger.Info("VAA submitted successfully", zap.String("txHash", txHash))
-}
\ No newline at end of file
+}
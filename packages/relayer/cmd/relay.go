@@ -0,0 +1,850 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/buildinfo"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// RelayConfig holds the source-side configuration shared by every relay command
+type RelayConfig struct {
+	SpyRPCHost          string        // Wormhole spy service endpoint
+	SpyMaxBackoff       time.Duration // Cap on the delay between spy reconnect attempts
+	SpySubscription     string        // Which VAAs to subscribe to (clients.SpySubscriptionAll or SpySubscriptionEmitters)
+	ChainIDs            []uint16      // Source chain IDs to listen for
+	EmitterAddress      string        // Source emitter address to filter
+	EmitterAllowlist    string        // File of additional emitter addresses to accept
+	EmitterDenylist     string        // File of emitter addresses to reject, reloaded on SIGHUP
+	SubmissionTimeout   time.Duration // Deadline for submitting a single VAA, derived from the relayer's context
+	MetricsAddr         string        // Address to serve Prometheus metrics on; disabled when empty
+	AdminToken          string        // Bearer token for the /dedupe admin endpoints; disabled when empty
+	RecentVAAsSize      int           // Number of recent VAAs served on /recent (0 disables)
+	OrderedDelivery     bool          // Process each emitter's VAAs one at a time, in sequence order
+	OrderingGapTimeout  time.Duration // How long an out-of-order VAA waits for its predecessor
+	MinConsistencyLevel uint8         // Minimum VAA consistency level to relay (0 = no filter)
+	AllowSameChain      bool          // Relay VAAs emitted on the destination chain itself
+	MaxVAAAge           time.Duration // Skip VAAs older than this (0 = no limit)
+	RateLimit           float64       // Maximum submissions per second to the destination (0 = unlimited)
+	RateBurst           int           // Submissions allowed in a burst above RateLimit
+	BreakerThreshold    int           // Consecutive failures that open the destination's circuit breaker (0 = no breaker)
+	BreakerCooldown     time.Duration // How long an open circuit breaker holds submissions before probing
+	MinValue            string        // Minimum payload value to relay (decimal or 0x hex; empty = no bound)
+	MaxValue            string        // Maximum payload value to relay (decimal or 0x hex; empty = no bound)
+	PayloadDestinations []int         // Known payload destination chains; others are rejected (empty = no rule)
+	PayloadLengths      []int         // Accepted payload lengths; others are rejected (empty = no rule)
+	PayloadRequireValue bool          // Reject payloads without a value or with a zero value
+	AuditLog            string        // JSONL file recording every handled VAA ("-" = stdout; empty disables)
+	DeliveryCache       string        // File persisting delivered VAAs across restarts (empty disables)
+	DeliveryCacheSize   int           // Maximum number of delivered VAAs kept in the delivery cache
+	DeliveryCacheTTL    time.Duration // How long a delivered VAA is remembered
+	DedupeTTL           time.Duration // How long replays of a settled VAA are skipped in memory
+	GapBackfill         bool          // After a spy reconnect, fetch the VAAs missed during the gap from Wormholescan
+	GapBackfillMax      int           // Maximum sequences fetched per emitter after a reconnect
+	WormholescanURL     string        // Wormholescan API the gap backfill fetches VAAs from
+	Resume              bool          // Persist each emitter's last processed sequence and resume from it on start
+	ResumeBackfill      bool          // With Resume, backfill the sequences produced while the relayer was stopped
+	SequenceStore       string        // File the sequences are persisted to (empty = beside the delivery cache)
+	LogVAAOnFailure     bool          // Log every field and the raw hex of each VAA that fails
+	Once                bool          // Exit after the first VAA is delivered
+	OnceTimeout         time.Duration // With Once, how long to wait for a delivery (0 = no limit)
+	SelfTest            bool          // Round-trip synthetic VAAs through the parser before starting
+	HealthCheckInterval time.Duration // Time between dependency health checks (0 disables them)
+	PauseUnhealthyAfter time.Duration // Pause submissions once a dependency has been unhealthy this long (0 = never)
+	ShardIndex          int           // Shard handled by this instance
+	ShardCount          int           // Number of instances splitting VAAs by sequence (1 = no sharding)
+}
+
+// DefaultHealthCheckInterval is the time between dependency health checks of a relay command
+const DefaultHealthCheckInterval = 30 * time.Second
+
+// submitterBuilder constructs the destination submitter for a relay command
+type submitterBuilder func(logger *zap.Logger) (submitter.VAASubmitter, error)
+
+// registerRelayFlags registers the source-side flags shared by every relay command.
+// They are read from the command directly, since viper bindings conflict across commands.
+func registerRelayFlags(cmd *cobra.Command, defaultChainIDs []int, defaultSubmissionTimeout time.Duration, chainIDsUsage, emitterUsage string) {
+	cmd.Flags().IntSlice(
+		"chain-ids",
+		defaultChainIDs,
+		chainIDsUsage)
+
+	cmd.Flags().String(
+		"emitter-address",
+		"",
+		emitterUsage)
+
+	cmd.Flags().String(
+		"emitter-allowlist-file",
+		"",
+		"File of emitter addresses to accept (one hex address per line), merged with --emitter-address; reloaded on SIGHUP")
+
+	cmd.Flags().String(
+		"emitter-denylist-file",
+		"",
+		"File of emitter addresses to reject (one hex address per line), taking precedence over the allowlist; reloaded on SIGHUP")
+
+	cmd.Flags().Duration(
+		"submission-timeout",
+		defaultSubmissionTimeout,
+		"Deadline for submitting a single VAA to the destination chain")
+
+	cmd.Flags().Uint8(
+		"min-consistency-level",
+		0,
+		"Skip VAAs whose consistency level is below this value (0 relays every level)")
+
+	cmd.Flags().Duration(
+		"max-vaa-age",
+		0,
+		"Skip VAAs whose timestamp is older than this, e.g. 24h to ignore the backlog of a long outage (0 = no limit)")
+
+	cmd.Flags().Bool(
+		"allow-same-chain",
+		false,
+		"Relay VAAs emitted on the destination chain itself (skipped by default to prevent loops)")
+
+	cmd.Flags().Float64(
+		"rate-limit",
+		0,
+		"Maximum submissions per second to the destination chain (0 = unlimited)")
+
+	cmd.Flags().Int(
+		"rate-burst",
+		1,
+		"With --rate-limit, how many submissions may be sent in a burst")
+
+	cmd.Flags().Int(
+		"circuit-breaker-threshold",
+		0,
+		"Hold submissions to the destination chain after this many consecutive failures (0 disables the circuit breaker)")
+
+	cmd.Flags().Duration(
+		"circuit-breaker-cooldown",
+		internal.DefaultBreakerCooldown,
+		"How long an open circuit breaker holds submissions before a probe checks the destination has recovered")
+
+	cmd.Flags().String(
+		"min-value",
+		"",
+		"Skip VAAs whose payload value (uint128, decimal or 0x hex) is below this")
+
+	cmd.Flags().String(
+		"max-value",
+		"",
+		"Skip VAAs whose payload value (uint128, decimal or 0x hex) is above this")
+
+	cmd.Flags().IntSlice(
+		"payload-destinations",
+		nil,
+		"Reject VAAs whose payload destination chain is not in this list (e.g. 10003,10004)")
+
+	cmd.Flags().IntSlice(
+		"payload-lengths",
+		nil,
+		"Reject VAAs whose payload length is not in this list (e.g. 18,50)")
+
+	cmd.Flags().Bool(
+		"payload-require-value",
+		false,
+		"Reject VAAs whose payload has no value or a zero value")
+
+	registerRelayerFlags(cmd)
+}
+
+// registerRelayerFlags registers the flags for the relayer process itself, independent of
+// the sources and destinations it relays between
+func registerRelayerFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(
+		"recent-vaas",
+		internal.DefaultRecentVAAsSize,
+		"Number of recently handled VAAs served on /recent of the metrics server (0 disables)")
+
+	cmd.Flags().Bool(
+		"ordered-delivery",
+		false,
+		"Process VAAs from each emitter strictly in sequence order (reduces throughput)")
+
+	cmd.Flags().Duration(
+		"ordering-gap-timeout",
+		internal.DefaultOrderingGapTimeout,
+		"With --ordered-delivery, how long a VAA waits for its predecessor before being processed anyway")
+
+	cmd.Flags().String(
+		"audit-log",
+		"",
+		"Append a JSON line for every handled VAA to this file (\"-\" for stdout; empty disables)")
+
+	cmd.Flags().String(
+		"delivery-cache",
+		"",
+		"Persist delivered VAAs to this file so a restarted relayer skips replays without re-submitting (empty disables)")
+
+	cmd.Flags().Int(
+		"delivery-cache-size",
+		internal.DefaultDeliveryCacheSize,
+		"Maximum number of delivered VAAs kept in --delivery-cache; the oldest are evicted first")
+
+	cmd.Flags().Duration(
+		"delivery-cache-ttl",
+		internal.DefaultDeliveryCacheTTL,
+		"How long a delivered VAA is remembered in --delivery-cache")
+
+	cmd.Flags().Duration(
+		"dedupe-ttl",
+		internal.DefaultDedupeTTL,
+		"How long replays of a settled VAA are skipped; raised to the longest submission timeout if shorter")
+
+	cmd.Flags().Bool(
+		"gap-backfill",
+		false,
+		"After a spy reconnect, fetch the VAAs relayed emitters produced during the outage from Wormholescan")
+
+	cmd.Flags().Int(
+		"gap-backfill-max-sequences",
+		internal.DefaultGapBackfillMaxSequences,
+		"With --gap-backfill, the most sequences fetched per emitter after one reconnect")
+
+	cmd.Flags().String(
+		"wormholescan-url",
+		clients.DefaultWormholescanURL,
+		"Wormholescan API the --gap-backfill fetches VAAs from")
+
+	cmd.Flags().Bool(
+		"resume",
+		false,
+		"Persist the last processed sequence of each relayed emitter and log where each resumes on start")
+
+	cmd.Flags().Bool(
+		"resume-backfill",
+		false,
+		"With --resume and --gap-backfill, relay the VAAs those emitters produced while the relayer was stopped")
+
+	cmd.Flags().String(
+		"sequence-store",
+		"",
+		"File --resume persists sequences to (empty = --delivery-cache with a "+internal.SequenceStoreSuffix+" suffix)")
+
+	cmd.Flags().Bool(
+		"log-vaa-on-failure",
+		false,
+		"Log every field and the raw hex of each VAA that fails to parse or submit")
+
+	cmd.Flags().Bool(
+		"once",
+		false,
+		"Process VAAs one at a time and exit after the first one is delivered")
+
+	cmd.Flags().Duration(
+		"once-timeout",
+		0,
+		"With --once, exit with an error if no VAA is delivered within this long (0 = no limit)")
+
+	cmd.Flags().Bool(
+		"self-test",
+		false,
+		"Round-trip synthetic VAAs through the VAA parser and payload decoder before starting, and fail on a mismatch")
+
+	cmd.Flags().Duration(
+		"health-check-interval",
+		DefaultHealthCheckInterval,
+		"Time between health checks of the spy and the destination, served on /readyz of the metrics server (0 disables)")
+
+	cmd.Flags().Duration(
+		"pause-unhealthy-after",
+		0,
+		"Hold VAAs back from submission once a dependency has failed its health checks for this long, until it recovers (0 = never pause)")
+}
+
+// readRelayConfig reads the shared relay flags, using defaultChainIDs when --chain-ids is empty
+func readRelayConfig(cmd *cobra.Command, defaultChainIDs []int) RelayConfig {
+	emitterAddress, _ := cmd.Flags().GetString("emitter-address")
+	emitterAllowlist, _ := cmd.Flags().GetString("emitter-allowlist-file")
+	emitterDenylist, _ := cmd.Flags().GetString("emitter-denylist-file")
+	chainIDsInt, _ := cmd.Flags().GetIntSlice("chain-ids")
+	submissionTimeout, _ := cmd.Flags().GetDuration("submission-timeout")
+	recentVAAsSize, _ := cmd.Flags().GetInt("recent-vaas")
+	orderedDelivery, _ := cmd.Flags().GetBool("ordered-delivery")
+	orderingGapTimeout, _ := cmd.Flags().GetDuration("ordering-gap-timeout")
+	minConsistencyLevel, _ := cmd.Flags().GetUint8("min-consistency-level")
+	allowSameChain, _ := cmd.Flags().GetBool("allow-same-chain")
+	maxVAAAge, _ := cmd.Flags().GetDuration("max-vaa-age")
+	rateLimit, _ := cmd.Flags().GetFloat64("rate-limit")
+	rateBurst, _ := cmd.Flags().GetInt("rate-burst")
+	breakerThreshold, _ := cmd.Flags().GetInt("circuit-breaker-threshold")
+	breakerCooldown, _ := cmd.Flags().GetDuration("circuit-breaker-cooldown")
+	minValue, _ := cmd.Flags().GetString("min-value")
+	maxValue, _ := cmd.Flags().GetString("max-value")
+	payloadDestinations, _ := cmd.Flags().GetIntSlice("payload-destinations")
+	payloadLengths, _ := cmd.Flags().GetIntSlice("payload-lengths")
+	payloadRequireValue, _ := cmd.Flags().GetBool("payload-require-value")
+	auditLog, _ := cmd.Flags().GetString("audit-log")
+	deliveryCache, _ := cmd.Flags().GetString("delivery-cache")
+	deliveryCacheSize, _ := cmd.Flags().GetInt("delivery-cache-size")
+	deliveryCacheTTL, _ := cmd.Flags().GetDuration("delivery-cache-ttl")
+	dedupeTTL, _ := cmd.Flags().GetDuration("dedupe-ttl")
+	gapBackfill, _ := cmd.Flags().GetBool("gap-backfill")
+	gapBackfillMax, _ := cmd.Flags().GetInt("gap-backfill-max-sequences")
+	wormholescanURL, _ := cmd.Flags().GetString("wormholescan-url")
+	resume, _ := cmd.Flags().GetBool("resume")
+	resumeBackfill, _ := cmd.Flags().GetBool("resume-backfill")
+	sequenceStore, _ := cmd.Flags().GetString("sequence-store")
+	logVAAOnFailure, _ := cmd.Flags().GetBool("log-vaa-on-failure")
+	once, _ := cmd.Flags().GetBool("once")
+	onceTimeout, _ := cmd.Flags().GetDuration("once-timeout")
+	selfTest, _ := cmd.Flags().GetBool("self-test")
+	healthCheckInterval, _ := cmd.Flags().GetDuration("health-check-interval")
+	pauseUnhealthyAfter, _ := cmd.Flags().GetDuration("pause-unhealthy-after")
+	if len(chainIDsInt) == 0 {
+		chainIDsInt = defaultChainIDs
+	}
+
+	return RelayConfig{
+		SpyRPCHost:          viper.GetString("spy_rpc_host"),
+		SpyMaxBackoff:       viper.GetDuration("spy_max_backoff"),
+		SpySubscription:     viper.GetString("spy_subscription_mode"),
+		ChainIDs:            toChainIDs(chainIDsInt),
+		EmitterAddress:      emitterAddress,
+		EmitterAllowlist:    emitterAllowlist,
+		EmitterDenylist:     emitterDenylist,
+		SubmissionTimeout:   submissionTimeout,
+		MetricsAddr:         viper.GetString("metrics_addr"),
+		AdminToken:          viper.GetString("admin_token"),
+		RecentVAAsSize:      recentVAAsSize,
+		OrderedDelivery:     orderedDelivery,
+		OrderingGapTimeout:  orderingGapTimeout,
+		MinConsistencyLevel: minConsistencyLevel,
+		AllowSameChain:      allowSameChain,
+		MaxVAAAge:           maxVAAAge,
+		RateLimit:           rateLimit,
+		RateBurst:           rateBurst,
+		BreakerThreshold:    breakerThreshold,
+		BreakerCooldown:     breakerCooldown,
+		MinValue:            minValue,
+		MaxValue:            maxValue,
+		PayloadDestinations: payloadDestinations,
+		PayloadLengths:      payloadLengths,
+		PayloadRequireValue: payloadRequireValue,
+		AuditLog:            auditLog,
+		DeliveryCache:       deliveryCache,
+		DeliveryCacheSize:   deliveryCacheSize,
+		DeliveryCacheTTL:    deliveryCacheTTL,
+		DedupeTTL:           dedupeTTL,
+		GapBackfill:         gapBackfill,
+		GapBackfillMax:      gapBackfillMax,
+		WormholescanURL:     wormholescanURL,
+		Resume:              resume,
+		ResumeBackfill:      resumeBackfill,
+		SequenceStore:       sequenceStore,
+		LogVAAOnFailure:     logVAAOnFailure,
+		Once:                once,
+		OnceTimeout:         onceTimeout,
+		SelfTest:            selfTest,
+		HealthCheckInterval: healthCheckInterval,
+		PauseUnhealthyAfter: pauseUnhealthyAfter,
+		ShardIndex:          viper.GetInt("shard_index"),
+		ShardCount:          viper.GetInt("shard_count"),
+	}
+}
+
+// sequenceStorePath returns the file --resume persists sequences to: --sequence-store, or the
+// delivery cache path with SequenceStoreSuffix so one path locates both stores
+func sequenceStorePath(config RelayConfig) (string, error) {
+	if config.ResumeBackfill && !config.Resume {
+		return "", fmt.Errorf("--resume-backfill requires --resume")
+	}
+	if config.ResumeBackfill && !config.GapBackfill {
+		return "", fmt.Errorf("--resume-backfill requires --gap-backfill to fetch VAAs from Wormholescan")
+	}
+	if config.SequenceStore != "" {
+		return config.SequenceStore, nil
+	}
+	if config.DeliveryCache == "" {
+		return "", fmt.Errorf("--resume requires --sequence-store or --delivery-cache to locate the sequence store")
+	}
+	return config.DeliveryCache + internal.SequenceStoreSuffix, nil
+}
+
+// toChainIDs converts chain IDs from []int, as read from flags, to []uint16
+func toChainIDs(ids []int) []uint16 {
+	chainIDs := make([]uint16, len(ids))
+	for i, id := range ids {
+		chainIDs[i] = uint16(id)
+	}
+	return chainIDs
+}
+
+// remoteSignerConfig returns the settings of the remote signer at url. The bearer token is only
+// read from WORMHOLE_RELAYER_REMOTE_SIGNER_TOKEN, to keep it out of process listings.
+func remoteSignerConfig(url string) clients.RemoteSignerConfig {
+	return clients.RemoteSignerConfig{URL: url, Token: viper.GetString("remote_signer_token")}
+}
+
+// serviceAuth returns the credentials sent to an HTTP service: token as a bearer token and the
+// static headers of flag. The token is only read from the environment or the secrets file, to
+// keep it out of process listings; neither it nor the header values are ever logged.
+func serviceAuth(flag, token string, headers map[string]string) (clients.ServiceAuth, error) {
+	for name := range headers {
+		if name == "" || strings.ContainsAny(name, " \t:\r\n") {
+			return clients.ServiceAuth{}, fmt.Errorf("invalid %s: %q is not a header name", flag, name)
+		}
+		if token != "" && http.CanonicalHeaderKey(name) == "Authorization" {
+			return clients.ServiceAuth{}, fmt.Errorf("invalid %s: Authorization is already set by the service token", flag)
+		}
+	}
+	return clients.ServiceAuth{BearerToken: token, Headers: headers}, nil
+}
+
+// parseValueBound parses an optional payload value bound flag (empty = no bound)
+func parseValueBound(flag, value string) (*big.Int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	bound, err := internal.ParseUint128(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", flag, err)
+	}
+	return bound, nil
+}
+
+// buildPayloadRules converts the payload validation flags into processor rules
+func buildPayloadRules(config RelayConfig) ([]internal.PayloadRule, error) {
+	var rules []internal.PayloadRule
+	if len(config.PayloadLengths) > 0 {
+		for _, length := range config.PayloadLengths {
+			if length <= 0 {
+				return nil, fmt.Errorf("invalid --payload-lengths: %d is not a positive length", length)
+			}
+		}
+		rules = append(rules, internal.RequirePayloadLength(config.PayloadLengths...))
+	}
+	if len(config.PayloadDestinations) > 0 {
+		chainIDs := make([]uint16, len(config.PayloadDestinations))
+		for i, id := range config.PayloadDestinations {
+			if id < 0 || id > math.MaxUint16 {
+				return nil, fmt.Errorf("invalid --payload-destinations: %d is not a Wormhole chain ID", id)
+			}
+			chainIDs[i] = uint16(id)
+		}
+		rules = append(rules, internal.RequireDestinationIn(chainIDs...))
+	}
+	if config.PayloadRequireValue {
+		rules = append(rules, internal.RequireNonZeroValue())
+	}
+	return rules, nil
+}
+
+// shortPayloadPolicy is what the relay to destChainID does with a VAA whose payload is too short
+// to carry a destination. The EVM, Solana and Aztec receivers only decode the demo's payload
+// layouts, so a short payload from a monitored emitter is a bug at the source and fails; the
+// Cosmos gateway also carries other applications' messages, so there it is skipped.
+func shortPayloadPolicy(destChainID uint16) internal.ShortPayloadPolicy {
+	if destChainID == CosmosDestinationChainID {
+		return internal.ShortPayloadSkip
+	}
+	return internal.ShortPayloadFail
+}
+
+// runRelay builds the destination submitter, wires it into a spy-driven relayer
+// and runs until the relayer fails or a shutdown signal is received
+func runRelay(logger *zap.Logger, config RelayConfig, destChainID uint16, buildSubmitter submitterBuilder) error {
+	logger.Info("Starting relayer", buildinfo.Get().Fields()...)
+	logger.Info("Relay configuration",
+		zap.String("spyRPC", config.SpyRPCHost),
+		zap.Duration("spyMaxBackoff", config.SpyMaxBackoff),
+		zap.String("spySubscription", config.SpySubscription),
+		zap.Any("sourceChainIds", config.ChainIDs),
+		zap.Uint16("destinationChainID", destChainID),
+		zap.String("shortPayloads", string(shortPayloadPolicy(destChainID))),
+		zap.String("emitterFilter", config.EmitterAddress),
+		zap.String("emitterAllowlist", config.EmitterAllowlist),
+		zap.String("emitterDenylist", config.EmitterDenylist),
+		zap.Duration("submissionTimeout", config.SubmissionTimeout),
+		zap.Bool("orderedDelivery", config.OrderedDelivery),
+		zap.Uint8("minConsistencyLevel", config.MinConsistencyLevel),
+		zap.Bool("allowSameChain", config.AllowSameChain),
+		zap.Duration("maxVAAAge", config.MaxVAAAge),
+		zap.Float64("rateLimit", config.RateLimit),
+		zap.Int("circuitBreakerThreshold", config.BreakerThreshold),
+		zap.String("minValue", config.MinValue),
+		zap.String("maxValue", config.MaxValue),
+		zap.Ints("payloadDestinations", config.PayloadDestinations),
+		zap.Ints("payloadLengths", config.PayloadLengths),
+		zap.Bool("payloadRequireValue", config.PayloadRequireValue),
+		zap.String("auditLog", config.AuditLog),
+		zap.String("deliveryCache", config.DeliveryCache))
+
+	// Parse the payload value bounds before connecting to anything
+	minValue, err := parseValueBound("--min-value", config.MinValue)
+	if err != nil {
+		return err
+	}
+	maxValue, err := parseValueBound("--max-value", config.MaxValue)
+	if err != nil {
+		return err
+	}
+	payloadRules, err := buildPayloadRules(config)
+	if err != nil {
+		return err
+	}
+	processorConfig := internal.VAAProcessorConfig{
+		ChainIDs:             config.ChainIDs,
+		EmitterAddress:       config.EmitterAddress,
+		EmitterAllowlistFile: config.EmitterAllowlist,
+		EmitterDenylistFile:  config.EmitterDenylist,
+		DestinationChainID:   destChainID,
+		SubmissionTimeout:    config.SubmissionTimeout,
+		MinConsistencyLevel:  config.MinConsistencyLevel,
+		AllowSameChain:       config.AllowSameChain,
+		MaxVAAAge:            config.MaxVAAAge,
+		RateLimit:            config.RateLimit,
+		RateBurst:            config.RateBurst,
+		BreakerThreshold:     config.BreakerThreshold,
+		BreakerCooldown:      config.BreakerCooldown,
+		MinValue:             minValue,
+		MaxValue:             maxValue,
+		PayloadRules:         payloadRules,
+		ShardIndex:           config.ShardIndex,
+		ShardCount:           config.ShardCount,
+		ShortPayloads:        shortPayloadPolicy(destChainID),
+	}
+	spyEmitters, err := spyEmitterFilter(config.SpySubscription, processorConfig)
+	if err != nil {
+		return err
+	}
+
+	// Create destination submitter
+	vaaSubmitter, err := buildSubmitter(logger)
+	if err != nil {
+		return err
+	}
+
+	// Create VAA processor
+	vaaProcessor, err := internal.NewDefaultVAAProcessor(logger, processorConfig, vaaSubmitter)
+	if err != nil {
+		return fmt.Errorf("invalid VAA processor configuration: %v", err)
+	}
+
+	return serveRelayer(logger, config, vaaProcessor, spyEmitters, submitterHealthChecks("destination", vaaSubmitter))
+}
+
+// reconcileDedupeTTL validates ttl against longestSubmission, the largest submission timeout
+// across destinations. A TTL shorter than a submission may take is raised to longestSubmission
+// with a warning (see internal.ValidateDedupeTTL); a TTL that is not positive is an error.
+func reconcileDedupeTTL(logger *zap.Logger, ttl, longestSubmission time.Duration) (time.Duration, error) {
+	err := internal.ValidateDedupeTTL(ttl, longestSubmission)
+	if errors.Is(err, internal.ErrDedupeTTLTooShort) {
+		logger.Warn("--dedupe-ttl is shorter than the submission timeout; raising it so replays are not submitted again",
+			zap.Duration("dedupeTTL", ttl),
+			zap.Duration("submissionTimeout", longestSubmission))
+		return longestSubmission, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid --dedupe-ttl: %v", err)
+	}
+	return ttl, nil
+}
+
+// spyEmitterFilter returns the emitters the spy subscription asks for in mode: none, meaning
+// every VAA, for SpySubscriptionAll, or each allowlisted emitter on each source chain of
+// sources for SpySubscriptionEmitters
+func spyEmitterFilter(mode string, sources ...internal.VAAProcessorConfig) ([]clients.SpyEmitter, error) {
+	switch mode {
+	case clients.SpySubscriptionAll:
+		return nil, nil
+	case clients.SpySubscriptionEmitters:
+	default:
+		return nil, fmt.Errorf("invalid --spy-subscription-mode %q (valid: %s, %s)", mode, clients.SpySubscriptionAll, clients.SpySubscriptionEmitters)
+	}
+
+	var emitters []clients.SpyEmitter
+	seen := make(map[clients.SpyEmitter]bool)
+	for _, source := range sources {
+		if len(source.ChainIDs) == 0 {
+			return nil, fmt.Errorf("--spy-subscription-mode %s needs the source chain IDs to subscribe to", mode)
+		}
+		filter, err := internal.NewEmitterFilter(source.EmitterAddress, source.EmitterAllowlistFile, source.EmitterDenylistFile)
+		if err != nil {
+			return nil, err
+		}
+		allowlist := filter.Allowlist()
+		if len(allowlist) == 0 {
+			return nil, fmt.Errorf("--spy-subscription-mode %s needs an emitter address or allowlist file naming the emitters to subscribe to", mode)
+		}
+		for _, chainID := range source.ChainIDs {
+			for _, address := range allowlist {
+				emitter := clients.SpyEmitter{ChainID: chainID, Address: address}
+				if !seen[emitter] {
+					seen[emitter] = true
+					emitters = append(emitters, emitter)
+				}
+			}
+		}
+	}
+	return emitters, nil
+}
+
+// submitterHealthChecks returns the health check of s under name, if it supports one
+func submitterHealthChecks(name string, s submitter.VAASubmitter) []internal.HealthCheck {
+	checker, ok := s.(submitter.HealthChecker)
+	if !ok {
+		return nil
+	}
+	return []internal.HealthCheck{{Name: name, Check: checker.CheckHealth}}
+}
+
+// relayProcessor is a VAA processor whose emitter lists can be reloaded on SIGHUP
+type relayProcessor interface {
+	internal.VAAProcessor
+	ReloadEmitterLists() error
+}
+
+// healthGatedProcessor holds each VAA back while the health monitor has paused submissions
+type healthGatedProcessor struct {
+	relayProcessor
+	monitor *internal.HealthMonitor
+}
+
+func (p healthGatedProcessor) ProcessVAA(ctx context.Context, vaaData internal.VAAData) (string, error) {
+	if err := p.monitor.WaitHealthy(ctx); err != nil {
+		return "", fmt.Errorf("waiting for dependencies to recover: %w", err)
+	}
+	return p.relayProcessor.ProcessVAA(ctx, vaaData)
+}
+
+// serveRelayer feeds VAAs from the spy into processor, serving metrics and recording the
+// audit log as configured, until the relayer fails or a shutdown signal is received.
+// The spy subscription asks for the VAAs of spyEmitters only, unless it is empty.
+// destinationChecks are run periodically, with a check of the spy, if health checks are enabled.
+func serveRelayer(logger *zap.Logger, config RelayConfig, processor relayProcessor, spyEmitters []clients.SpyEmitter, destinationChecks []internal.HealthCheck) error {
+	// Check the VAA parser before relaying anything with it
+	if config.SelfTest {
+		if err := internal.SelfTest(logger); err != nil {
+			return fmt.Errorf("VAA parser self-test failed: %v", err)
+		}
+		logger.Info("VAA parser self-test passed")
+	}
+
+	if config.ShardCount > 1 {
+		logger.Info("Shard assignment",
+			zap.Int("shardIndex", config.ShardIndex),
+			zap.Int("shardCount", config.ShardCount))
+		if config.OrderedDelivery {
+			logger.Warn("Ordered delivery only orders the VAAs within this shard; " +
+				"consecutive sequences from one emitter are relayed by different instances")
+		}
+	}
+
+	// Create spy client
+	spyClient, err := clients.NewSpyClientWithBackoff(logger, config.SpyRPCHost, config.SpyMaxBackoff)
+	if err != nil {
+		return fmt.Errorf("failed to create spy client: %v", err)
+	}
+	if len(spyEmitters) > 0 {
+		spyClient.SetEmitterFilter(spyEmitters)
+		logger.Info("Subscribing to the VAAs of the configured emitters only",
+			zap.Int("emitterFilters", len(spyEmitters)))
+	}
+
+	// Check the spy and the destinations periodically rather than only at startup if requested
+	var healthMonitor *internal.HealthMonitor
+	if config.HealthCheckInterval < 0 {
+		return fmt.Errorf("invalid --health-check-interval: must not be negative, got %v", config.HealthCheckInterval)
+	}
+	if config.PauseUnhealthyAfter < 0 {
+		return fmt.Errorf("invalid --pause-unhealthy-after: must not be negative, got %v", config.PauseUnhealthyAfter)
+	}
+	if config.HealthCheckInterval > 0 {
+		checks := append([]internal.HealthCheck{{Name: "spy", Check: spyClient.CheckSubscription}}, destinationChecks...)
+		healthMonitor = internal.NewHealthMonitor(logger, checks, internal.HealthMonitorConfig{
+			Interval:   config.HealthCheckInterval,
+			PauseAfter: config.PauseUnhealthyAfter,
+		})
+		if config.PauseUnhealthyAfter > 0 {
+			processor = healthGatedProcessor{relayProcessor: processor, monitor: healthMonitor}
+		}
+		logger.Info("Periodic health checks enabled",
+			zap.Duration("interval", config.HealthCheckInterval),
+			zap.Duration("pauseUnhealthyAfter", config.PauseUnhealthyAfter),
+			zap.Int("checks", len(checks)))
+	} else if config.PauseUnhealthyAfter > 0 {
+		logger.Warn("--pause-unhealthy-after has no effect without --health-check-interval")
+	}
+
+	// Create and start relayer
+	var relayer *internal.Relayer
+	if config.OrderedDelivery {
+		relayer, err = internal.NewRelayerWithOrdering(logger, spyClient, processor, config.OrderingGapTimeout)
+	} else {
+		relayer, err = internal.NewRelayer(logger, spyClient, processor)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize relayer: %v", err)
+	}
+	defer relayer.Close()
+
+	// The dedup cache must outlast a submission, or a slow VAA could be relayed twice
+	if config.DedupeTTL, err = reconcileDedupeTTL(logger, config.DedupeTTL, config.SubmissionTimeout); err != nil {
+		return err
+	}
+	relayer.SetDedupeTTL(config.DedupeTTL)
+	relayer.SetLogVAAOnFailure(config.LogVAAOnFailure)
+
+	// Exit after the first delivery if requested
+	if config.Once {
+		if config.OnceTimeout < 0 {
+			return fmt.Errorf("invalid --once-timeout: must not be negative, got %v", config.OnceTimeout)
+		}
+		logger.Info("Once mode enabled: exiting after the first delivered VAA",
+			zap.Duration("timeout", config.OnceTimeout))
+		relayer.SetOnce(config.OnceTimeout)
+	}
+
+	// Record an audit trail of every handled VAA if requested; buffered events are
+	// flushed once the relayer has stopped and in-flight VAAs have finished
+	if config.AuditLog != "" {
+		sink, err := internal.NewAuditLogSink(config.AuditLog)
+		if err != nil {
+			return err
+		}
+		relayer.SetEventSink(sink)
+		defer func() {
+			if err := sink.Close(); err != nil {
+				logger.Error("Failed to flush audit log", zap.Error(err))
+			}
+		}()
+	}
+
+	// Skip VAAs delivered by an earlier run if requested; deliveries are written as they
+	// happen, so the cache is closed once in-flight VAAs have finished
+	if config.DeliveryCache != "" {
+		cache, err := internal.OpenDeliveryCache(config.DeliveryCache, config.DeliveryCacheSize, config.DeliveryCacheTTL)
+		if err != nil {
+			return err
+		}
+		logger.Info("Loaded delivery cache",
+			zap.String("path", config.DeliveryCache),
+			zap.Int("deliveries", cache.Len()),
+			zap.Duration("ttl", config.DeliveryCacheTTL))
+		relayer.SetDeliveryCache(cache)
+		defer cache.Close()
+	}
+
+	// Recover the VAAs missed while the spy stream was down, if requested
+	if config.GapBackfill {
+		if config.GapBackfillMax <= 0 {
+			return fmt.Errorf("invalid --gap-backfill-max-sequences: must be positive, got %d", config.GapBackfillMax)
+		}
+		logger.Info("Gap backfill enabled",
+			zap.String("wormholescan", config.WormholescanURL),
+			zap.Int("maxSequencesPerEmitter", config.GapBackfillMax))
+		relayer.SetGapBackfill(clients.NewWormholescanClient(logger, clients.WormholescanConfig{URL: config.WormholescanURL}), config.GapBackfillMax)
+	}
+
+	// Resume each emitter from the sequence the previous run stopped at, if requested
+	if config.Resume || config.ResumeBackfill {
+		path, err := sequenceStorePath(config)
+		if err != nil {
+			return err
+		}
+		store, err := internal.OpenSequenceStore(path)
+		if err != nil {
+			return err
+		}
+		logger.Info("Loaded sequence store",
+			zap.String("path", path),
+			zap.Int("emitters", len(store.Sequences())),
+			zap.Bool("backfill", config.ResumeBackfill))
+		relayer.SetSequenceStore(store, config.ResumeBackfill)
+	}
+
+	// Serve Prometheus metrics, recent VAAs and the dedup admin endpoints if requested
+	if config.AdminToken != "" && config.MetricsAddr == "" {
+		logger.Warn("--admin-token has no effect without --metrics-addr")
+	}
+	if config.MetricsAddr != "" {
+		handlers := map[string]http.Handler{"/healthz": buildinfo.HealthHandler(clients.ActiveRPCEndpoints, internal.CircuitBreakerStates)}
+		if healthMonitor != nil {
+			handlers["/readyz"] = healthMonitor
+		}
+		if config.RecentVAAsSize > 0 {
+			recentVAAs := internal.NewRecentVAAs(config.RecentVAAsSize)
+			relayer.SetRecentVAAs(recentVAAs)
+			handlers["/recent"] = recentVAAs
+		}
+		if config.AdminToken != "" {
+			dedupe := relayer.DedupeHandler(config.AdminToken)
+			handlers["/dedupe"] = dedupe
+			handlers["/dedupe/"] = dedupe
+			logger.Info("Serving dedup admin endpoints", zap.String("path", "/dedupe"))
+		}
+
+		metricsServer := metrics.NewServer(config.MetricsAddr, handlers)
+		go func() {
+			logger.Info("Serving metrics", zap.String("addr", config.MetricsAddr))
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("Metrics server failed", zap.Error(err))
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
+	// Setup context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle graceful shutdown
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(c)
+
+	// Reload the emitter lists on SIGHUP without restarting
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	go func() {
+		for {
+			select {
+			case <-c:
+				logger.Info("Received shutdown signal")
+				cancel()
+				return
+			case <-hup:
+				if err := processor.ReloadEmitterLists(); err != nil {
+					logger.Error("Failed to reload emitter lists, keeping the previous lists", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	if healthMonitor != nil {
+		go healthMonitor.Run(ctx)
+	}
+
+	// Start the relayer
+	if err := relayer.Start(ctx); err != nil {
+		return fmt.Errorf("relayer stopped with error: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// unreachableURL points at a port nothing listens on, so connection attempts fail fast
+const unreachableURL = "http://127.0.0.1:1"
+
+func testHexKey(t *testing.T) string {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return hex.EncodeToString(crypto.FromECDSA(key))
+}
+
+func TestBuildEVMSubmitter(t *testing.T) {
+	vaaSubmitter, err := buildEVMSubmitter(zap.NewNop(), EVMConfig{
+		ChainName:         "arbitrum",
+		EVMRPCURL:         unreachableURL,
+		PrivateKey:        testHexKey(t),
+		EVMTargetContract: "0x1111111111111111111111111111111111111111",
+		RPCRetry:          clients.DefaultRetryConfig(),
+	})
+	if err != nil {
+		t.Fatalf("buildEVMSubmitter failed: %v", err)
+	}
+	if _, ok := vaaSubmitter.(*submitter.EVMSubmitter); !ok {
+		t.Fatalf("expected *submitter.EVMSubmitter, got %T", vaaSubmitter)
+	}
+
+	if _, err := buildEVMSubmitter(zap.NewNop(), EVMConfig{EVMRPCURL: unreachableURL, PrivateKey: "not-a-key"}); err == nil {
+		t.Fatal("expected an error for an invalid private key")
+	}
+}
+
+func TestBuildSolanaSubmitter(t *testing.T) {
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	// The Solana client checks node health at construction
+	solanaRPC := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "ok"})
+	}))
+	defer solanaRPC.Close()
+
+	vaaSubmitter, err := buildSolanaSubmitter(zap.NewNop(), SolanaConfig{
+		SolanaRPCURL:     solanaRPC.URL,
+		SolanaPrivateKey: payer.String(),
+		SolanaProgramID:  solana.SystemProgramID.String(),
+	})
+	if err != nil {
+		t.Fatalf("buildSolanaSubmitter failed: %v", err)
+	}
+	if _, ok := vaaSubmitter.(*submitter.SolanaSubmitter); !ok {
+		t.Fatalf("expected *submitter.SolanaSubmitter, got %T", vaaSubmitter)
+	}
+
+	if _, err := buildSolanaSubmitter(zap.NewNop(), SolanaConfig{
+		SolanaRPCURL:     unreachableURL,
+		SolanaPrivateKey: payer.String(),
+		SolanaProgramID:  solana.SystemProgramID.String(),
+	}); err == nil {
+		t.Fatal("expected an error for an unreachable Solana RPC")
+	}
+
+	if _, err := buildSolanaSubmitter(zap.NewNop(), SolanaConfig{
+		SolanaRPCURL:     solanaRPC.URL,
+		SolanaPrivateKey: payer.String(),
+		SolanaProgramID:  solana.SystemProgramID.String(),
+		SolanaPostPolicy: "lenient",
+	}); err == nil {
+		t.Fatal("expected an error for an unknown post policy")
+	}
+}
+
+func TestBuildCosmosSubmitter(t *testing.T) {
+	target, err := clients.Bech32Encode(DefaultCosmosBech32Prefix, make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to encode target: %v", err)
+	}
+
+	vaaSubmitter, err := buildCosmosSubmitter(zap.NewNop(), CosmosConfig{
+		CosmosLCDURL:         unreachableURL,
+		CosmosChainID:        "wormchain",
+		CosmosPrivateKey:     testHexKey(t),
+		CosmosTargetContract: target,
+		CosmosBech32Prefix:   DefaultCosmosBech32Prefix,
+		CosmosGasLimit:       DefaultCosmosGasLimit,
+		CosmosFeeAmount:      "0",
+		CosmosFeeDenom:       DefaultCosmosFeeDenom,
+		CosmosExecuteMsgKey:  submitter.DefaultCosmosExecuteMsgKey,
+	})
+	if err != nil {
+		t.Fatalf("buildCosmosSubmitter failed: %v", err)
+	}
+	if _, ok := vaaSubmitter.(*submitter.CosmosSubmitter); !ok {
+		t.Fatalf("expected *submitter.CosmosSubmitter, got %T", vaaSubmitter)
+	}
+}
+
+func TestBuildAztecSubmitter(t *testing.T) {
+	verificationService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer verificationService.Close()
+
+	config := AztecConfig{
+		AztecPXEURL:            "invalid://pxe",
+		AztecWalletAddress:     DefaultAztecWalletAddress,
+		AztecTargetContract:    DefaultAztecTargetContract,
+		VerificationServiceURL: verificationService.URL,
+	}
+
+	// A healthy verification service is enough without a PXE
+	vaaSubmitter, err := buildAztecSubmitter(zap.NewNop(), config)
+	if err != nil {
+		t.Fatalf("buildAztecSubmitter failed: %v", err)
+	}
+	if _, ok := vaaSubmitter.(*submitter.AztecSubmitter); !ok {
+		t.Fatalf("expected *submitter.AztecSubmitter, got %T", vaaSubmitter)
+	}
+
+	// Neither the verification service nor the PXE is usable
+	config.VerificationServiceURL = unreachableURL
+	if _, err := buildAztecSubmitter(zap.NewNop(), config); err == nil {
+		t.Fatal("expected an error when neither PXE nor verification service is available")
+	}
+}
+
+func TestReadRelayConfig(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	registerRelayFlags(cmd, nil, time.Minute, "chain ids", "emitter")
+
+	config := readRelayConfig(cmd, []int{56, 1})
+	if len(config.ChainIDs) != 2 || config.ChainIDs[0] != 56 || config.ChainIDs[1] != 1 {
+		t.Fatalf("expected default chain IDs [56 1], got %v", config.ChainIDs)
+	}
+
+	if err := cmd.Flags().Set("chain-ids", "10003,10004"); err != nil {
+		t.Fatalf("failed to set chain-ids: %v", err)
+	}
+	if err := cmd.Flags().Set("emitter-address", "0xabc"); err != nil {
+		t.Fatalf("failed to set emitter-address: %v", err)
+	}
+	if err := cmd.Flags().Set("emitter-denylist-file", "deny.txt"); err != nil {
+		t.Fatalf("failed to set emitter-denylist-file: %v", err)
+	}
+
+	config = readRelayConfig(cmd, []int{56, 1})
+	if len(config.ChainIDs) != 2 || config.ChainIDs[0] != 10003 || config.ChainIDs[1] != 10004 {
+		t.Fatalf("expected chain IDs [10003 10004], got %v", config.ChainIDs)
+	}
+	if config.EmitterAddress != "0xabc" {
+		t.Fatalf("expected emitter address 0xabc, got %q", config.EmitterAddress)
+	}
+	if config.EmitterAllowlist != "" || config.EmitterDenylist != "deny.txt" {
+		t.Fatalf("expected only the emitter denylist to be set, got %q and %q", config.EmitterAllowlist, config.EmitterDenylist)
+	}
+	if config.SubmissionTimeout != time.Minute {
+		t.Fatalf("expected default submission timeout 1m, got %v", config.SubmissionTimeout)
+	}
+	if config.OrderedDelivery {
+		t.Fatal("expected ordered delivery to be off by default")
+	}
+	if config.OrderingGapTimeout != internal.DefaultOrderingGapTimeout {
+		t.Fatalf("expected default ordering gap timeout %v, got %v", internal.DefaultOrderingGapTimeout, config.OrderingGapTimeout)
+	}
+	if config.ShardIndex != 0 || config.ShardCount != 1 {
+		t.Fatalf("expected no sharding by default, got shard %d of %d", config.ShardIndex, config.ShardCount)
+	}
+	if config.GapBackfill || config.GapBackfillMax != internal.DefaultGapBackfillMaxSequences {
+		t.Fatalf("expected gap backfill off with a cap of %d, got %v with %d", internal.DefaultGapBackfillMaxSequences, config.GapBackfill, config.GapBackfillMax)
+	}
+	if config.LogVAAOnFailure {
+		t.Fatal("expected failed VAAs not to be dumped by default")
+	}
+	if config.Once || config.OnceTimeout != 0 {
+		t.Fatalf("expected once mode off without a timeout, got %v with %v", config.Once, config.OnceTimeout)
+	}
+	if config.HealthCheckInterval != DefaultHealthCheckInterval || config.PauseUnhealthyAfter != 0 {
+		t.Fatalf("expected health checks every %v without pausing, got %v and %v", DefaultHealthCheckInterval, config.HealthCheckInterval, config.PauseUnhealthyAfter)
+	}
+	if config.BreakerThreshold != 0 || config.BreakerCooldown != internal.DefaultBreakerCooldown {
+		t.Fatalf("expected no circuit breaker with the default cooldown, got %d and %v", config.BreakerThreshold, config.BreakerCooldown)
+	}
+	if config.AllowSameChain {
+		t.Fatal("expected VAAs from the destination chain to be skipped by default")
+	}
+	if config.MaxVAAAge != 0 {
+		t.Fatalf("expected no maximum VAA age by default, got %v", config.MaxVAAAge)
+	}
+	if config.Resume || config.ResumeBackfill || config.SequenceStore != "" {
+		t.Fatalf("expected resuming off by default, got %v, %v and %q", config.Resume, config.ResumeBackfill, config.SequenceStore)
+	}
+	if config.DedupeTTL != internal.DefaultDedupeTTL {
+		t.Fatalf("expected the default dedup TTL %v, got %v", internal.DefaultDedupeTTL, config.DedupeTTL)
+	}
+}
+
+func TestSequenceStorePath(t *testing.T) {
+	for name, tt := range map[string]struct {
+		config RelayConfig
+		want   string
+	}{
+		"explicit path":                 {config: RelayConfig{Resume: true, SequenceStore: "/var/lib/relayer/seq.json", DeliveryCache: "/tmp/cache"}, want: "/var/lib/relayer/seq.json"},
+		"beside the cache":              {config: RelayConfig{Resume: true, DeliveryCache: "/var/lib/relayer/deliveries"}, want: "/var/lib/relayer/deliveries.sequences"},
+		"backfill":                      {config: RelayConfig{Resume: true, ResumeBackfill: true, GapBackfill: true, DeliveryCache: "/tmp/cache"}, want: "/tmp/cache.sequences"},
+		"no path":                       {config: RelayConfig{Resume: true}},
+		"backfill without resume":       {config: RelayConfig{ResumeBackfill: true, GapBackfill: true, DeliveryCache: "/tmp/cache"}},
+		"backfill without gap backfill": {config: RelayConfig{Resume: true, ResumeBackfill: true, DeliveryCache: "/tmp/cache"}},
+	} {
+		path, err := sequenceStorePath(tt.config)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %q", name, path)
+			}
+			continue
+		}
+		if err != nil || path != tt.want {
+			t.Errorf("%s: expected %q, got %q (%v)", name, tt.want, path, err)
+		}
+	}
+}
+
+func TestServiceAuth(t *testing.T) {
+	auth, err := serviceAuth("--verification-service-header", "tok", map[string]string{"X-Api-Key": "key"})
+	if err != nil || auth.BearerToken != "tok" || auth.Headers["X-Api-Key"] != "key" {
+		t.Fatalf("expected the token and header to be kept, got %+v (%v)", auth, err)
+	}
+	for name, headers := range map[string]map[string]string{
+		"empty name":          {"": "x"},
+		"name with a colon":   {"X-Key:": "x"},
+		"authorization twice": {"authorization": "Basic abc"},
+	} {
+		if _, err := serviceAuth("--verification-service-header", "tok", headers); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := serviceAuth("--verification-service-header", "", map[string]string{"Authorization": "Basic abc"}); err != nil {
+		t.Errorf("expected an Authorization header without a token to be accepted, got %v", err)
+	}
+}
+
+func TestReconcileDedupeTTL(t *testing.T) {
+	// The default TTL covers every destination's default submission timeout
+	for _, timeout := range []time.Duration{DefaultAztecSubmissionTimeout, DefaultEVMSubmissionTimeout, DefaultSolanaSubmissionTimeout, DefaultCosmosSubmissionTimeout} {
+		if ttl, err := reconcileDedupeTTL(zap.NewNop(), internal.DefaultDedupeTTL, timeout); err != nil || ttl != internal.DefaultDedupeTTL {
+			t.Errorf("expected the default TTL to be kept with a %v timeout, got %v (err %v)", timeout, ttl, err)
+		}
+	}
+
+	core, logs := observer.New(zap.WarnLevel)
+	ttl, err := reconcileDedupeTTL(zap.New(core), time.Minute, 20*time.Minute)
+	if err != nil || ttl != 20*time.Minute {
+		t.Fatalf("expected a short TTL to be raised to 20m, got %v (err %v)", ttl, err)
+	}
+	if logs.FilterMessageSnippet("--dedupe-ttl is shorter").Len() != 1 {
+		t.Error("expected a warning when the TTL is raised")
+	}
+
+	if _, err := reconcileDedupeTTL(zap.NewNop(), 0, time.Minute); err == nil || !strings.Contains(err.Error(), "--dedupe-ttl") {
+		t.Errorf("expected a zero TTL to be rejected, got %v", err)
+	}
+}
+
+func TestSubmissionTimeoutDefaults(t *testing.T) {
+	tests := []struct {
+		cmd  *cobra.Command
+		want time.Duration
+	}{
+		{cmd: aztecCmd, want: DefaultAztecSubmissionTimeout},
+		{cmd: evmCmd, want: DefaultEVMSubmissionTimeout},
+		{cmd: solanaCmd, want: DefaultSolanaSubmissionTimeout},
+		{cmd: cosmosCmd, want: DefaultCosmosSubmissionTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd.Name(), func(t *testing.T) {
+			got, err := tt.cmd.Flags().GetDuration("submission-timeout")
+			if err != nil {
+				t.Fatalf("failed to read submission-timeout: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected default submission timeout %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// EVM submissions must fail fast rather than inherit Aztec's long proving deadline
+	if DefaultEVMSubmissionTimeout >= DefaultAztecSubmissionTimeout {
+		t.Errorf("expected the EVM deadline (%v) to be shorter than Aztec's (%v)", DefaultEVMSubmissionTimeout, DefaultAztecSubmissionTimeout)
+	}
+}
+
+func TestBuildPayloadRules(t *testing.T) {
+	rules, err := buildPayloadRules(RelayConfig{})
+	if err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules by default, got %d rules (err %v)", len(rules), err)
+	}
+
+	rules, err = buildPayloadRules(RelayConfig{PayloadLengths: []int{18, 50}, PayloadDestinations: []int{10003}, PayloadRequireValue: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, rule := range rules {
+		names = append(names, rule.Name)
+	}
+	if got := strings.Join(names, ","); got != "payload_length,known_destination,non_zero_value" {
+		t.Fatalf("unexpected rules %s", got)
+	}
+
+	for _, config := range []RelayConfig{{PayloadLengths: []int{0}}, {PayloadDestinations: []int{70000}}} {
+		if _, err := buildPayloadRules(config); err == nil {
+			t.Errorf("expected an error for %+v", config)
+		}
+	}
+}
+
+func TestShortPayloadPolicy(t *testing.T) {
+	for chainID, want := range map[uint16]internal.ShortPayloadPolicy{
+		AztecDestinationChainID:                        internal.ShortPayloadFail,
+		SolanaDestinationChainID:                       internal.ShortPayloadFail,
+		EVMChainConfigs["arbitrum"].DestinationChainID: internal.ShortPayloadFail,
+		CosmosDestinationChainID:                       internal.ShortPayloadSkip,
+	} {
+		if got := shortPayloadPolicy(chainID); got != want {
+			t.Errorf("destination %d: expected %q, got %q", chainID, want, got)
+		}
+	}
+}
+
+func TestSpyEmitterFilter(t *testing.T) {
+	emitter := "0x248EC2E5595480fF371031698ae3a4099b8dC229"
+	normalized := internal.NormalizeEmitter(emitter)
+
+	if emitters, err := spyEmitterFilter(clients.SpySubscriptionAll, internal.VAAProcessorConfig{}); err != nil || emitters != nil {
+		t.Fatalf("expected no filter in all mode, got %v (err %v)", emitters, err)
+	}
+	if _, err := spyEmitterFilter("by-type"); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+
+	// Every source chain is paired with every emitter, once across routes
+	emitters, err := spyEmitterFilter(clients.SpySubscriptionEmitters,
+		internal.VAAProcessorConfig{ChainIDs: []uint16{10003, 56}, EmitterAddress: emitter},
+		internal.VAAProcessorConfig{ChainIDs: []uint16{56}, EmitterAddress: emitter})
+	if err != nil {
+		t.Fatalf("spyEmitterFilter failed: %v", err)
+	}
+	want := []clients.SpyEmitter{{ChainID: 10003, Address: normalized}, {ChainID: 56, Address: normalized}}
+	if len(emitters) != len(want) || emitters[0] != want[0] || emitters[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, emitters)
+	}
+
+	if _, err := spyEmitterFilter(clients.SpySubscriptionEmitters, internal.VAAProcessorConfig{EmitterAddress: emitter}); err == nil {
+		t.Error("expected emitters mode without source chains to be rejected")
+	}
+	if _, err := spyEmitterFilter(clients.SpySubscriptionEmitters, internal.VAAProcessorConfig{ChainIDs: []uint16{56}}); err == nil {
+		t.Error("expected emitters mode without an emitter to be rejected")
+	}
+}
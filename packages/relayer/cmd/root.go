@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -38,6 +41,16 @@ func init() {
 		"localhost:7073",
 		"Wormhole spy service endpoint")
 
+	rootCmd.PersistentFlags().Duration(
+		"spy-max-backoff",
+		clients.DefaultSpyMaxBackoff,
+		"Maximum delay between spy reconnect attempts (delays grow exponentially from 1s, with jitter)")
+
+	rootCmd.PersistentFlags().String(
+		"spy-subscription-mode",
+		clients.SpySubscriptionAll,
+		"VAAs to subscribe to: all (every VAA the spy sees) or emitters (only the allowlisted emitters on the source chains, filtered by the spy)")
+
 	rootCmd.PersistentFlags().String(
 		"wormhole-contract",
 		"0x0848d2af89dfd7c0e171238f9216399e61e908cd31b0222a920f1bf621a16ed6",
@@ -48,15 +61,43 @@ func init() {
 		"",
 		"Emitter address to monitor")
 
-	// Optional Verification Service
+	rootCmd.PersistentFlags().String(
+		"metrics-addr",
+		"",
+		"Address to serve Prometheus metrics on (e.g. :9090); disabled when empty")
+
+	rootCmd.PersistentFlags().String(
+		"admin-token",
+		"",
+		"Bearer token enabling the /dedupe admin endpoints on the metrics server (prefer WORMHOLE_RELAYER_ADMIN_TOKEN); disabled when empty")
+
+	rootCmd.PersistentFlags().String(
+		"secrets-file",
+		"",
+		"JSON or YAML file of secrets (keys, tokens, RPC URLs with embedded keys), applied below flags and environment variables")
+
+	// Horizontal sharding, usually set per instance through the environment
+	rootCmd.PersistentFlags().Int(
+		"shard-index",
+		0,
+		"Shard handled by this instance (0-based); only VAAs whose sequence % --shard-count equals it are relayed")
+
+	rootCmd.PersistentFlags().Int(
+		"shard-count",
+		1,
+		"Number of relayer instances splitting VAAs by sequence (1 = no sharding)")
 
 	// Bind flags to viper for env variable support
 	viper.BindPFlag("spy_rpc_host", rootCmd.PersistentFlags().Lookup("spy-rpc-host"))
-	viper.BindPFlag("source_chain_id", rootCmd.PersistentFlags().Lookup("source-chain-id"))
-	viper.BindPFlag("dest_chain_id", rootCmd.PersistentFlags().Lookup("dest-chain-id"))
+	viper.BindPFlag("spy_max_backoff", rootCmd.PersistentFlags().Lookup("spy-max-backoff"))
+	viper.BindPFlag("spy_subscription_mode", rootCmd.PersistentFlags().Lookup("spy-subscription-mode"))
 	viper.BindPFlag("wormhole_contract", rootCmd.PersistentFlags().Lookup("wormhole-contract"))
 	viper.BindPFlag("emitter_address", rootCmd.PersistentFlags().Lookup("emitter-address"))
-	viper.BindPFlag("verification_service_url", rootCmd.PersistentFlags().Lookup("verification-service-url"))
+	viper.BindPFlag("metrics_addr", rootCmd.PersistentFlags().Lookup("metrics-addr"))
+	viper.BindPFlag("admin_token", rootCmd.PersistentFlags().Lookup("admin-token"))
+	viper.BindPFlag("shard_index", rootCmd.PersistentFlags().Lookup("shard-index"))
+	viper.BindPFlag("shard_count", rootCmd.PersistentFlags().Lookup("shard-count"))
+	viper.BindPFlag("secrets_file", rootCmd.PersistentFlags().Lookup("secrets-file"))
 
 	cobra.OnInitialize(initConfig)
 }
@@ -72,9 +113,23 @@ func initConfig() {
 	viper.SetEnvPrefix("wormhole-relayer")
 	viper.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
 	viper.AutomaticEnv() // read in environment variables that match
+
+	if path := viper.GetString("secrets_file"); path != "" {
+		cobra.CheckErr(loadSecretsFile(viper.GetViper(), path))
+	}
+}
+
+// printBanner prints the coloured banner to stdout. Under --json it prints nothing, so stdout
+// carries no ANSI escapes or other unstructured text for log pipelines to trip over.
+func printBanner(cmd *cobra.Command) {
+	if json, _ := cmd.Flags().GetBool("json"); json {
+		return
+	}
+	writeBanner(os.Stdout)
 }
 
-func printBanner() {
+// writeBanner writes the banner to w, one colour per line
+func writeBanner(w io.Writer) {
 	colours := []string{
 		"\033[38;5;81m", // Cyan
 		"\033[38;5;75m", // Light Blue
@@ -103,10 +158,10 @@ func printBanner() {
 	}
 
 	for i, line := range lines {
-		fmt.Printf("%s%s\n", colours[i], line)
+		fmt.Fprintf(w, "%s%s\n", colours[i], line)
 	}
 
-	fmt.Println("\033[0m") // Reset
+	fmt.Fprintln(w, "\033[0m") // Reset
 }
 
 func configureLogging(cmd *cobra.Command, _ []string) *zap.Logger {
@@ -124,9 +179,11 @@ func configureLogging(cmd *cobra.Command, _ []string) *zap.Logger {
 		config.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
 	}
 
-	// Configure JSON output if requested
+	// Configure JSON output if requested. JSON always uses the production field names and
+	// plain lowercase levels, so --debug changes neither the keys nor adds colour escapes.
 	if json {
 		config.Encoding = "json"
+		config.EncoderConfig = zap.NewProductionEncoderConfig()
 	} else {
 		config.Encoding = "console"
 		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
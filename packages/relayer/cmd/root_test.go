@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// captureOutput runs fn with stdout and stderr redirected and returns what it wrote to them
+func captureOutput(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	done := make(chan []byte)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- out
+	}()
+	fn()
+	w.Close()
+	return string(<-done)
+}
+
+func TestJSONOutputHasNoANSIEscapes(t *testing.T) {
+	for _, debug := range []bool{false, true} {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().Bool("debug", false, "")
+		cmd.Flags().Bool("json", false, "")
+		cmd.Flags().Set("json", "true")
+		if debug {
+			cmd.Flags().Set("debug", "true")
+		}
+
+		out := captureOutput(t, func() {
+			printBanner(cmd)
+			logger := configureLogging(cmd, nil)
+			logger.Info("Starting relayer", zap.String("chain", "arbitrum"))
+			logger.Sync()
+		})
+		zap.ReplaceGlobals(zap.NewNop())
+
+		if strings.Contains(out, "\x1b") {
+			t.Errorf("debug=%v: expected no ANSI escapes under --json, got %q", debug, out)
+		}
+		if !strings.Contains(out, `"level":"info"`) || !strings.Contains(out, `"msg":"Starting relayer"`) {
+			t.Errorf("debug=%v: expected a JSON log line with production field names, got %q", debug, out)
+		}
+	}
+}
+
+func TestWriteBanner(t *testing.T) {
+	var buf bytes.Buffer
+	writeBanner(&buf)
+	if !strings.HasSuffix(buf.String(), "\033[0m\n") {
+		t.Errorf("expected the banner to reset the colour at the end, got %q", buf.String())
+	}
+}
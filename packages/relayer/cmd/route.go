@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/buildinfo"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// routeCmd represents the command to relay VAAs to several destinations from one process
+var routeCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Relay Wormhole VAAs to several destinations using a routing table",
+	Long: `Listens for Wormhole VAAs and dispatches each one to the destination named by its
+payload's destination chain, using the routing table in --config.
+
+Each route names a destination (arbitrum, base, solana, aztec or cosmos), the source
+chains and emitters it accepts, and the destination's settings. VAAs whose destination
+has no route are logged and dropped. See routes.example.yaml.`,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		printBanner(cmd)
+		configureLogging(cmd, args)
+	},
+	RunE: runRoute,
+}
+
+func init() {
+	rootCmd.AddCommand(routeCmd)
+
+	routeCmd.Flags().String(
+		"config",
+		"",
+		"Routing table file (YAML) mapping sources to destinations (required)")
+	routeCmd.MarkFlagRequired("config")
+
+	registerRelayerFlags(routeCmd)
+}
+
+// RouteSpec is one entry of the routing table: the VAAs a destination accepts and its settings.
+// The source fields mirror the shared relay flags; only the section for the destination is used.
+type RouteSpec struct {
+	Destination         string        `mapstructure:"destination"`               // arbitrum, base, solana, aztec or cosmos
+	SourceChains        []int         `mapstructure:"source_chains"`             // Source chain IDs (empty = the destination's defaults)
+	EmitterAddress      string        `mapstructure:"emitter_address"`           // Source emitter address to filter (empty = any)
+	EmitterAllowlist    string        `mapstructure:"emitter_allowlist_file"`    // File of additional emitter addresses to accept
+	EmitterDenylist     string        `mapstructure:"emitter_denylist_file"`     // File of emitter addresses to reject
+	SubmissionTimeout   time.Duration `mapstructure:"submission_timeout"`        // 0 = the destination's default
+	MinConsistencyLevel uint8         `mapstructure:"min_consistency_level"`     // Minimum VAA consistency level (0 = no filter)
+	AllowSameChain      bool          `mapstructure:"allow_same_chain"`          // Relay VAAs emitted on the destination chain itself
+	MaxVAAAge           time.Duration `mapstructure:"max_vaa_age"`               // Skip VAAs older than this (0 = no limit)
+	RateLimit           float64       `mapstructure:"rate_limit"`                // Maximum submissions per second (0 = unlimited)
+	RateBurst           int           `mapstructure:"rate_burst"`                // Submissions allowed in a burst above RateLimit
+	BreakerThreshold    int           `mapstructure:"circuit_breaker_threshold"` // Consecutive failures that open the circuit breaker (0 = no breaker)
+	BreakerCooldown     time.Duration `mapstructure:"circuit_breaker_cooldown"`  // 0 = internal.DefaultBreakerCooldown
+	MinValue            string        `mapstructure:"min_value"`                 // Minimum payload value (empty = no bound)
+	MaxValue            string        `mapstructure:"max_value"`                 // Maximum payload value (empty = no bound)
+	PayloadLengths      []int         `mapstructure:"payload_lengths"`           // Accepted payload lengths (empty = no rule)
+	PayloadRequireValue bool          `mapstructure:"payload_require_value"`     // Reject payloads without a non-zero value
+
+	EVM    EVMConfig    `mapstructure:"evm"` // For arbitrum and base
+	Solana SolanaConfig `mapstructure:"solana"`
+	Aztec  AztecConfig  `mapstructure:"aztec"`
+	Cosmos CosmosConfig `mapstructure:"cosmos"`
+}
+
+// defaultRouteSpec returns a route with every destination section set to the defaults of its flags
+func defaultRouteSpec() RouteSpec {
+	return RouteSpec{
+		EVM: EVMConfig{RPCRetry: clients.DefaultRetryConfig()},
+		Solana: SolanaConfig{
+			SolanaNetwork:         DefaultSolanaNetwork,
+			SolanaVAAServiceURL:   viper.GetString("solana_vaa_service_url"),
+			SolanaVAAServiceToken: viper.GetString("solana_vaa_service_token"),
+			SolanaCommitment:      DefaultSolanaBlockhashCommitment,
+			SolanaConfirmation:    DefaultSolanaConfirmation,
+		},
+		Aztec: AztecConfig{
+			AztecPXEURL:            DefaultAztecPXEURL,
+			AztecWalletAddress:     DefaultAztecWalletAddress,
+			AztecTargetContract:    DefaultAztecTargetContract,
+			VerificationServiceURL: DefaultVerificationServiceURL,
+			VerificationRetry:      clients.DefaultRetryConfig(),
+			VerificationToken:      viper.GetString("verification_service_token"),
+		},
+		Cosmos: CosmosConfig{
+			CosmosLCDURL:        DefaultCosmosLCDURL,
+			CosmosBech32Prefix:  DefaultCosmosBech32Prefix,
+			CosmosGasLimit:      DefaultCosmosGasLimit,
+			CosmosFeeAmount:     "0",
+			CosmosFeeDenom:      DefaultCosmosFeeDenom,
+			CosmosExecuteMsgKey: submitter.DefaultCosmosExecuteMsgKey,
+		},
+	}
+}
+
+// loadRouteSpecs reads the routing table from the YAML file at path. Unknown keys are
+// rejected so a misspelt setting fails at startup instead of being ignored.
+func loadRouteSpecs(path string) ([]RouteSpec, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read route config: %v", err)
+	}
+
+	entries, _ := file.Get("routes").([]any)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("route config %s has no routes", path)
+	}
+
+	specs := make([]RouteSpec, 0, len(entries))
+	for i, entry := range entries {
+		fields, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("route %d: expected a mapping, got %T", i+1, entry)
+		}
+
+		// Decode each route over the defaults, so omitted settings keep them
+		route := viper.New()
+		if err := route.MergeConfigMap(fields); err != nil {
+			return nil, fmt.Errorf("route %d: %v", i+1, err)
+		}
+		spec := defaultRouteSpec()
+		if err := route.UnmarshalExact(&spec); err != nil {
+			return nil, fmt.Errorf("route %d: %v", i+1, err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// routeTarget is a validated route destination
+type routeTarget struct {
+	chainID        uint16
+	defaultSources []int
+	defaultTimeout time.Duration
+	build          submitterBuilder
+}
+
+// resolveRouteTarget validates the destination settings of spec
+func resolveRouteTarget(spec RouteSpec) (routeTarget, error) {
+	switch spec.Destination {
+	case "solana":
+		config, err := applySolanaNetwork(spec.Solana)
+		if err != nil {
+			return routeTarget{}, err
+		}
+		return routeTarget{
+			chainID:        SolanaDestinationChainID,
+			defaultSources: DefaultSolanaSourceChains,
+			defaultTimeout: DefaultSolanaSubmissionTimeout,
+			build: func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+				return buildSolanaSubmitter(logger, config)
+			},
+		}, validateSolanaConfig(config)
+
+	case "aztec":
+		config := spec.Aztec
+		if config.Confirmation != nil {
+			// A confirmation section enables it; omitted settings keep the flag defaults
+			confirmation := clients.DefaultAztecConfirmationConfig()
+			if config.Confirmation.PollInterval != 0 {
+				confirmation.PollInterval = config.Confirmation.PollInterval
+			}
+			if config.Confirmation.Timeout != 0 {
+				confirmation.Timeout = config.Confirmation.Timeout
+			}
+			config.Confirmation = &confirmation
+		}
+		return routeTarget{
+			chainID:        AztecDestinationChainID,
+			defaultSources: DefaultAztecSourceChains,
+			defaultTimeout: DefaultAztecSubmissionTimeout,
+			build: func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+				return buildAztecSubmitter(logger, config)
+			},
+		}, validateAztecConfig(config)
+
+	case "cosmos":
+		config := spec.Cosmos
+		return routeTarget{
+			chainID:        CosmosDestinationChainID,
+			defaultSources: DefaultCosmosSourceChains,
+			defaultTimeout: DefaultCosmosSubmissionTimeout,
+			build: func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+				return buildCosmosSubmitter(logger, config)
+			},
+		}, validateCosmosConfig(config)
+	}
+
+	chainConfig, ok := EVMChainConfigs[spec.Destination]
+	if !ok {
+		return routeTarget{}, fmt.Errorf("unsupported destination %q (valid: arbitrum, base, solana, aztec, cosmos)", spec.Destination)
+	}
+	config := spec.EVM
+	config.ChainName = spec.Destination
+	if config.EVMRPCURL == "" {
+		config.EVMRPCURL = chainConfig.DefaultRPCURL
+	}
+	return routeTarget{
+		chainID:        chainConfig.DestinationChainID,
+		defaultSources: chainConfig.DefaultSourceChains,
+		defaultTimeout: DefaultEVMSubmissionTimeout,
+		build: func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+			return buildEVMSubmitter(logger, config)
+		},
+	}, validateEVMConfig(config)
+}
+
+// routeProcessorConfig converts the source settings of spec into a processor configuration
+func routeProcessorConfig(spec RouteSpec, target routeTarget) (internal.VAAProcessorConfig, error) {
+	minValue, err := parseValueBound("min_value", spec.MinValue)
+	if err != nil {
+		return internal.VAAProcessorConfig{}, err
+	}
+	maxValue, err := parseValueBound("max_value", spec.MaxValue)
+	if err != nil {
+		return internal.VAAProcessorConfig{}, err
+	}
+	payloadRules, err := buildPayloadRules(RelayConfig{PayloadLengths: spec.PayloadLengths, PayloadRequireValue: spec.PayloadRequireValue})
+	if err != nil {
+		return internal.VAAProcessorConfig{}, err
+	}
+
+	sourceChains := spec.SourceChains
+	if len(sourceChains) == 0 {
+		sourceChains = target.defaultSources
+	}
+	submissionTimeout := spec.SubmissionTimeout
+	if submissionTimeout == 0 {
+		submissionTimeout = target.defaultTimeout
+	}
+
+	return internal.VAAProcessorConfig{
+		ChainIDs:             toChainIDs(sourceChains),
+		EmitterAddress:       spec.EmitterAddress,
+		EmitterAllowlistFile: spec.EmitterAllowlist,
+		EmitterDenylistFile:  spec.EmitterDenylist,
+		SubmissionTimeout:    submissionTimeout,
+		MinConsistencyLevel:  spec.MinConsistencyLevel,
+		AllowSameChain:       spec.AllowSameChain,
+		MaxVAAAge:            spec.MaxVAAAge,
+		RateLimit:            spec.RateLimit,
+		RateBurst:            spec.RateBurst,
+		BreakerThreshold:     spec.BreakerThreshold,
+		BreakerCooldown:      spec.BreakerCooldown,
+		MinValue:             minValue,
+		MaxValue:             maxValue,
+		PayloadRules:         payloadRules,
+	}, nil
+}
+
+func runRoute(cmd *cobra.Command, args []string) error {
+	logger := configureLogging(cmd, args)
+	logger.Info("Starting relayer", buildinfo.Get().Fields()...)
+
+	path, _ := cmd.Flags().GetString("config")
+	specs, err := loadRouteSpecs(path)
+	if err != nil {
+		return err
+	}
+
+	relayConfig := readRelayConfig(cmd, nil)
+
+	// Validate every route before connecting to any destination
+	targets := make([]routeTarget, len(specs))
+	configs := make([]internal.VAAProcessorConfig, len(specs))
+	seen := make(map[uint16]bool, len(specs))
+	for i, spec := range specs {
+		if targets[i], err = resolveRouteTarget(spec); err != nil {
+			return fmt.Errorf("route %d (%s): %v", i+1, spec.Destination, err)
+		}
+		if seen[targets[i].chainID] {
+			return fmt.Errorf("route %d (%s): duplicate route for destination chain %d", i+1, spec.Destination, targets[i].chainID)
+		}
+		seen[targets[i].chainID] = true
+		if configs[i], err = routeProcessorConfig(spec, targets[i]); err != nil {
+			return fmt.Errorf("route %d (%s): %v", i+1, spec.Destination, err)
+		}
+		configs[i].ShardIndex, configs[i].ShardCount = relayConfig.ShardIndex, relayConfig.ShardCount
+	}
+
+	spyEmitters, err := spyEmitterFilter(relayConfig.SpySubscription, configs...)
+	if err != nil {
+		return err
+	}
+
+	// One dedup cache serves every route, so it must outlast the slowest destination's submissions
+	var longestSubmission time.Duration
+	for _, config := range configs {
+		longestSubmission = max(longestSubmission, config.SubmissionTimeout)
+	}
+	if relayConfig.DedupeTTL, err = reconcileDedupeTTL(logger, relayConfig.DedupeTTL, longestSubmission); err != nil {
+		return err
+	}
+
+	routes := make([]internal.Route, len(specs))
+	var healthChecks []internal.HealthCheck
+	for i, spec := range specs {
+		logger.Info("Route",
+			zap.String("destination", spec.Destination),
+			zap.Uint16("destinationChainID", targets[i].chainID),
+			zap.Any("sourceChainIds", configs[i].ChainIDs),
+			zap.String("emitterFilter", configs[i].EmitterAddress),
+			zap.Duration("submissionTimeout", configs[i].SubmissionTimeout))
+
+		vaaSubmitter, err := targets[i].build(logger.With(zap.String("route", spec.Destination)))
+		if err != nil {
+			return fmt.Errorf("route %d (%s): %v", i+1, spec.Destination, err)
+		}
+		routes[i] = internal.Route{DestinationChainID: targets[i].chainID, Submitter: vaaSubmitter, Config: configs[i]}
+		healthChecks = append(healthChecks, submitterHealthChecks(spec.Destination, vaaSubmitter)...)
+	}
+
+	router, err := internal.NewRouter(logger, routes)
+	if err != nil {
+		return fmt.Errorf("invalid routing table: %v", err)
+	}
+
+	return serveRelayer(logger, relayConfig, router, spyEmitters, healthChecks)
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func writeRouteConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "routes.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write route config: %v", err)
+	}
+	return path
+}
+
+func TestLoadRouteSpecs(t *testing.T) {
+	path := writeRouteConfig(t, `
+routes:
+  - destination: base
+    source_chains: [56]
+    emitter_address: "0x0000000000000000000000001111111111111111111111111111111111111111"
+    submission_timeout: 90s
+    evm:
+      private_key_file: /run/secrets/evm-key
+      target_contract: "0x2222222222222222222222222222222222222222"
+      target_routes:
+        "10003": "0x3333333333333333333333333333333333333333"
+      rpc_retry:
+        max_attempts: 5
+  - destination: solana
+    solana:
+      keypair_file: /run/secrets/solana.json
+      program_id: 11111111111111111111111111111111
+`)
+
+	specs, err := loadRouteSpecs(path)
+	if err != nil {
+		t.Fatalf("loadRouteSpecs failed: %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(specs))
+	}
+
+	base, solana := specs[0], specs[1]
+	if base.Destination != "base" || len(base.SourceChains) != 1 || base.SourceChains[0] != 56 || base.SubmissionTimeout != 90*time.Second {
+		t.Errorf("unexpected source settings %+v", base)
+	}
+	if base.EVM.PrivateKeyFile != "/run/secrets/evm-key" || base.EVM.EVMTargetRoutes[10003] != "0x3333333333333333333333333333333333333333" {
+		t.Errorf("unexpected EVM settings %+v", base.EVM)
+	}
+	// Omitted settings keep their flag defaults
+	if base.EVM.RPCRetry.MaxAttempts != 5 || base.EVM.RPCRetry.InitialBackoff != clients.DefaultRetryConfig().InitialBackoff {
+		t.Errorf("expected 5 attempts with the default backoff, got %+v", base.EVM.RPCRetry)
+	}
+	if solana.Solana.SolanaNetwork != DefaultSolanaNetwork || solana.Solana.SolanaCommitment != DefaultSolanaBlockhashCommitment ||
+		solana.Solana.SolanaConfirmation != DefaultSolanaConfirmation {
+		t.Errorf("expected the Solana defaults, got %+v", solana.Solana)
+	}
+	if solana.Solana.SolanaKeypairFile != "/run/secrets/solana.json" {
+		t.Errorf("expected the keypair file to be set, got %q", solana.Solana.SolanaKeypairFile)
+	}
+}
+
+func TestLoadRouteSpecsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{name: "no routes", contents: "routes: []\n"},
+		{name: "unknown key", contents: "routes:\n  - destination: base\n    source_chain: [56]\n"},
+		{name: "route is not a mapping", contents: "routes:\n  - base\n"},
+		{name: "invalid duration", contents: "routes:\n  - destination: base\n    submission_timeout: soon\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadRouteSpecs(writeRouteConfig(t, tt.contents)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := loadRouteSpecs(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestResolveRouteTarget(t *testing.T) {
+	spec := defaultRouteSpec()
+	spec.Destination = "arbitrum"
+	spec.EVM.PrivateKeyFile = "key.txt"
+	spec.EVM.EVMTargetContract = "0x1111111111111111111111111111111111111111"
+
+	target, err := resolveRouteTarget(spec)
+	if err != nil {
+		t.Fatalf("resolveRouteTarget failed: %v", err)
+	}
+	if target.chainID != EVMChainConfigs["arbitrum"].DestinationChainID || target.defaultTimeout != DefaultEVMSubmissionTimeout {
+		t.Errorf("unexpected target %+v", target)
+	}
+
+	config, err := routeProcessorConfig(spec, target)
+	if err != nil {
+		t.Fatalf("routeProcessorConfig failed: %v", err)
+	}
+	if len(config.ChainIDs) != len(EVMChainConfigs["arbitrum"].DefaultSourceChains) || config.SubmissionTimeout != DefaultEVMSubmissionTimeout {
+		t.Errorf("expected the destination's default sources and timeout, got %+v", config)
+	}
+
+	spec.MinValue = "not-a-number"
+	if _, err := routeProcessorConfig(spec, target); err == nil {
+		t.Error("expected an error for an invalid min_value")
+	}
+
+	for _, invalid := range []RouteSpec{
+		{Destination: "ethereum"},
+		{Destination: "base"}, // No EVM key
+		{Destination: "solana", Solana: defaultRouteSpec().Solana}, // No payer key or program ID
+	} {
+		if _, err := resolveRouteTarget(invalid); err == nil {
+			t.Errorf("expected an error for destination %q", invalid.Destination)
+		}
+	}
+}
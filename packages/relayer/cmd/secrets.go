@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// secretKeys are the settings a secrets file may hold: keys, tokens and endpoints whose URLs
+// commonly embed an API key. They are the viper keys of the matching flags.
+var secretKeys = map[string]bool{
+	"spy_rpc_host":               true,
+	"admin_token":                true,
+	"private_key":                true,
+	"keystore_password":          true,
+	"evm_rpc_url":                true,
+	"evm_remote_signer_url":      true,
+	"remote_signer_token":        true,
+	"solana_private_key":         true,
+	"solana_rpc_url":             true,
+	"solana_remote_signer_url":   true,
+	"solana_vaa_service_url":     true,
+	"solana_vaa_service_token":   true,
+	"aztec_pxe_url":              true,
+	"verification_service_url":   true,
+	"verification_service_token": true,
+	"cosmos_private_key":         true,
+	"cosmos_lcd_url":             true,
+}
+
+// loadSecretsFile merges the settings of the JSON or YAML secrets file at path into v, below
+// flags and environment variables. Only secretKeys are accepted, so a typo fails loudly instead
+// of leaving a secret unset. The file contents are zeroed once parsed.
+func loadSecretsFile(v *viper.Viper, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read secrets file: %v", err)
+	}
+	defer clear(data)
+
+	configType := "yaml" // JSON documents are valid YAML too
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		configType = "json"
+	}
+	secrets := viper.New()
+	secrets.SetConfigType(configType)
+	if err := secrets.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("failed to parse secrets file %s: %v", path, err)
+	}
+
+	var unknown []string
+	for _, key := range secrets.AllKeys() {
+		if !secretKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("secrets file %s has unknown keys %s (expected some of %s)",
+			path, strings.Join(unknown, ", "), strings.Join(sortedSecretKeys(), ", "))
+	}
+	return v.MergeConfigMap(secrets.AllSettings())
+}
+
+// sortedSecretKeys returns the keys a secrets file accepts, sorted
+func sortedSecretKeys() []string {
+	keys := make([]string, 0, len(secretKeys))
+	for key := range secretKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadSecretsFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"secrets.json": `{"private_key": "0x01", "evm_rpc_url": "https://rpc.example/key"}`,
+		"secrets.yaml": "private_key: \"0x01\"\nevm_rpc_url: https://rpc.example/key\n",
+	}
+
+	for name, contents := range files {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			v := viper.New()
+			v.SetDefault("evm_rpc_url", "https://default.example")
+			v.Set("private_key", "0xflag") // Set explicitly, like a flag
+			if err := loadSecretsFile(v, path); err != nil {
+				t.Fatalf("loadSecretsFile failed: %v", err)
+			}
+			if got := v.GetString("evm_rpc_url"); got != "https://rpc.example/key" {
+				t.Errorf("expected the secret to override the default, got %q", got)
+			}
+			if got := v.GetString("private_key"); got != "0xflag" {
+				t.Errorf("expected an explicit value to win over the secret, got %q", got)
+			}
+		})
+	}
+
+	unknown := filepath.Join(dir, "unknown.yaml")
+	if err := os.WriteFile(unknown, []byte("privte_key: \"0x01\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadSecretsFile(viper.New(), unknown); err == nil || !strings.Contains(err.Error(), "privte_key") {
+		t.Errorf("expected the misspelled key to be rejected, got %v", err)
+	}
+	if err := loadSecretsFile(viper.New(), filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected a missing file to be rejected")
+	}
+}
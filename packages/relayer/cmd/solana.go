@@ -1,11 +1,9 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
-	"os"
-	"os/signal"
-	"syscall"
+	"math"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -18,7 +16,12 @@ import (
 
 const (
 	// Default configuration values for Solana
-	DefaultSolanaRPCURL = "https://api.devnet.solana.com"
+	DefaultSolanaNetwork           = "devnet" // Selects the default RPC URL and Wormhole program ID
+	DefaultSolanaSubmissionTimeout = 180 * time.Second
+	// Finalized blockhashes cannot be rolled back, at the cost of some of their validity window
+	DefaultSolanaBlockhashCommitment = "finalized"
+	// Confirmed transactions are voted on by a supermajority and are almost never rolled back
+	DefaultSolanaConfirmation = "confirmed"
 
 	// Wormhole chain ID for Solana
 	SolanaDestinationChainID uint16 = 1
@@ -36,7 +39,7 @@ var solanaCmd = &cobra.Command{
 This command monitors the Wormhole network for messages from EVM chains, Aztec,
 or other configured chains and submits them to the Solana MessageBridge program.`,
 	PreRun: func(cmd *cobra.Command, args []string) {
-		printBanner()
+		printBanner(cmd)
 		configureLogging(cmd, args)
 	},
 	RunE: runSolanaRelay,
@@ -45,161 +48,436 @@ or other configured chains and submits them to the Solana MessageBridge program.
 func init() {
 	rootCmd.AddCommand(solanaCmd)
 
-	// Solana-specific flags
-	solanaCmd.Flags().String(
+	registerSolanaFlags(solanaCmd)
+
+	registerRelayFlags(solanaCmd, DefaultSolanaSourceChains, DefaultSolanaSubmissionTimeout,
+		"Source chain IDs to listen for (Arbitrum=10003, Aztec=56, Base=10004)",
+		"Source emitter address to filter (hex)")
+
+	// Mark required flags (the payer key comes from --solana-private-key, --solana-keypair-file or --solana-remote-signer-url)
+	solanaCmd.MarkFlagRequired("solana-program-id")
+	solanaCmd.MarkFlagsMutuallyExclusive("solana-private-key", "solana-keypair-file", "solana-remote-signer-url")
+
+	// Bind flags to viper
+	bindSolanaFlags(solanaCmd)
+}
+
+// registerSolanaFlags registers the Solana destination flags on cmd
+func registerSolanaFlags(cmd *cobra.Command) {
+	cmd.Flags().String(
+		"solana-network",
+		DefaultSolanaNetwork,
+		"Solana cluster (devnet, testnet, mainnet-beta), selecting the default RPC URL and Wormhole program ID")
+
+	cmd.Flags().String(
 		"solana-rpc-url",
-		DefaultSolanaRPCURL,
-		"RPC URL for Solana (devnet)")
+		"",
+		"RPC URL for Solana (default: the public RPC of --solana-network); comma-separate several URLs to fail over between them")
 
-	solanaCmd.Flags().String(
+	cmd.Flags().String(
 		"solana-private-key",
 		"",
-		"Private key for Solana transactions (base58 encoded, required)")
+		"Private key for Solana transactions (base58 encoded; prefer --solana-keypair-file)")
+
+	cmd.Flags().String(
+		"solana-keypair-file",
+		"",
+		"Path to a Solana CLI JSON keypair file (e.g. ~/.config/solana/id.json)")
 
-	solanaCmd.Flags().String(
+	cmd.Flags().String(
+		"solana-remote-signer-url",
+		"",
+		"Remote signer (e.g. a KMS or HSM gateway) signing as --solana-signer-pubkey instead of a local key")
+
+	cmd.Flags().String(
+		"solana-signer-pubkey",
+		"",
+		"Payer public key the --solana-remote-signer-url signs for (base58)")
+
+	cmd.Flags().String(
 		"solana-program-id",
 		"",
 		"MessageBridge program ID on Solana (required)")
 
-	solanaCmd.Flags().String(
+	cmd.Flags().String(
 		"solana-wormhole-program-id",
 		"",
-		"Wormhole Core Bridge program ID on Solana (default: devnet)")
+		"Wormhole Core Bridge program ID on Solana (default: the Core Bridge of --solana-network)")
+
+	cmd.Flags().Bool(
+		"solana-preflight",
+		false,
+		"Simulate each transaction before sending it (costs an extra RPC call, surfaces program logs on failure)")
+
+	cmd.Flags().String(
+		"solana-blockhash-commitment",
+		DefaultSolanaBlockhashCommitment,
+		"Commitment the transaction blockhash is fetched at (finalized, or confirmed for a faster, longer-lived blockhash)")
+
+	cmd.Flags().String(
+		"solana-confirmation",
+		DefaultSolanaConfirmation,
+		"Commitment a sent transaction must reach before its VAA counts as delivered (processed, confirmed, finalized, or none)")
+
+	cmd.Flags().Float64(
+		"min-sol-balance",
+		0,
+		"Refuse to send transactions while the payer holds less than this many SOL (0 = only the estimated fee is required)")
+
+	cmd.Flags().StringToString(
+		"solana-program-errors",
+		nil,
+		"Names for custom program error codes, added to the built-in MessageBridge and Anchor names (e.g. 6009=NewError,0x177a=OtherError)")
+
+	cmd.Flags().Bool(
+		"solana-skip-emitter-check",
+		false,
+		"Skip checking the VAA's emitter is registered in the program's foreign_emitter account before posting it")
+
+	cmd.Flags().String(
+		"solana-idl-file",
+		"",
+		"Anchor IDL JSON of the MessageBridge program to build receive_value from (default: the built-in discriminator and arguments)")
 
-	solanaCmd.Flags().IntSlice(
-		"chain-ids",
-		DefaultSolanaSourceChains,
-		"Source chain IDs to listen for (Arbitrum=10003, Aztec=56, Base=10004)")
+	cmd.Flags().String(
+		"solana-post-policy",
+		string(submitter.SolanaPostPolicyStrict),
+		"What to do with a VAA not confirmed posted to Wormhole after every post attempt: strict (fail it) or optimistic (send receive_value anyway)")
 
-	solanaCmd.Flags().String(
-		"emitter-address",
+	cmd.Flags().String(
+		"solana-nonce-account",
 		"",
-		"Source emitter address to filter (hex)")
+		"Durable nonce account whose nonce replaces the recent blockhash, so transactions never expire (default: recent blockhash)")
 
-	// Mark required flags
-	solanaCmd.MarkFlagRequired("solana-private-key")
-	solanaCmd.MarkFlagRequired("solana-program-id")
+	cmd.Flags().String(
+		"solana-nonce-authority-keypair-file",
+		"",
+		"Solana CLI JSON keypair file of the --solana-nonce-account authority (default: the payer)")
+
+	cmd.Flags().Bool(
+		"solana-vaa-service-gzip",
+		false,
+		"Gzip requests to the VAA posting service once it advertises support (Accept-Encoding: gzip)")
+
+	cmd.Flags().Int64(
+		"solana-vaa-service-max-response-bytes",
+		clients.DefaultMaxResponseBytes,
+		"Largest VAA posting service response body read, in bytes; larger responses fail the request")
+
+	cmd.Flags().StringToString(
+		"solana-vaa-service-header",
+		nil,
+		"Static headers sent with every VAA posting service request, e.g. X-Api-Key=... (bearer token: WORMHOLE_RELAYER_SOLANA_VAA_SERVICE_TOKEN)")
+
+	cmd.Flags().Int(
+		"solana-rpc-read-concurrency",
+		0,
+		"Maximum account reads (getAccountInfo) in flight across all VAAs, for rate-limited RPCs (0 = unlimited)")
+}
 
-	// Bind flags to viper (but chain_ids read from flags directly to avoid conflicts)
-	viper.BindPFlag("solana_rpc_url", solanaCmd.Flags().Lookup("solana-rpc-url"))
-	viper.BindPFlag("solana_private_key", solanaCmd.Flags().Lookup("solana-private-key"))
-	viper.BindPFlag("solana_program_id", solanaCmd.Flags().Lookup("solana-program-id"))
-	viper.BindPFlag("solana_wormhole_program_id", solanaCmd.Flags().Lookup("solana-wormhole-program-id"))
-	viper.BindPFlag("emitter_address", solanaCmd.Flags().Lookup("emitter-address"))
-	// Note: solana_vaa_service_url is read from env WORMHOLE_RELAYER_SOLANA_VAA_SERVICE_URL
+// bindSolanaFlags binds the Solana destination flags of cmd to viper
+func bindSolanaFlags(cmd *cobra.Command) {
+	viper.BindPFlag("solana_network", cmd.Flags().Lookup("solana-network"))
+	viper.BindPFlag("solana_rpc_url", cmd.Flags().Lookup("solana-rpc-url"))
+	viper.BindPFlag("solana_private_key", cmd.Flags().Lookup("solana-private-key"))
+	viper.BindPFlag("solana_keypair_file", cmd.Flags().Lookup("solana-keypair-file"))
+	viper.BindPFlag("solana_remote_signer_url", cmd.Flags().Lookup("solana-remote-signer-url"))
+	viper.BindPFlag("solana_signer_pubkey", cmd.Flags().Lookup("solana-signer-pubkey"))
+	viper.BindPFlag("solana_program_id", cmd.Flags().Lookup("solana-program-id"))
+	viper.BindPFlag("solana_wormhole_program_id", cmd.Flags().Lookup("solana-wormhole-program-id"))
+	viper.BindPFlag("solana_preflight", cmd.Flags().Lookup("solana-preflight"))
+	viper.BindPFlag("solana_blockhash_commitment", cmd.Flags().Lookup("solana-blockhash-commitment"))
+	viper.BindPFlag("solana_confirmation", cmd.Flags().Lookup("solana-confirmation"))
+	viper.BindPFlag("min_sol_balance", cmd.Flags().Lookup("min-sol-balance"))
+	viper.BindPFlag("solana_program_errors", cmd.Flags().Lookup("solana-program-errors"))
+	viper.BindPFlag("solana_skip_emitter_check", cmd.Flags().Lookup("solana-skip-emitter-check"))
+	viper.BindPFlag("solana_idl_file", cmd.Flags().Lookup("solana-idl-file"))
+	viper.BindPFlag("solana_post_policy", cmd.Flags().Lookup("solana-post-policy"))
+	viper.BindPFlag("solana_nonce_account", cmd.Flags().Lookup("solana-nonce-account"))
+	viper.BindPFlag("solana_nonce_authority_keypair_file", cmd.Flags().Lookup("solana-nonce-authority-keypair-file"))
+	viper.BindPFlag("solana_vaa_service_gzip", cmd.Flags().Lookup("solana-vaa-service-gzip"))
+	viper.BindPFlag("solana_vaa_service_max_response_bytes", cmd.Flags().Lookup("solana-vaa-service-max-response-bytes"))
+	viper.BindPFlag("solana_vaa_service_headers", cmd.Flags().Lookup("solana-vaa-service-header"))
+	viper.BindPFlag("solana_rpc_read_concurrency", cmd.Flags().Lookup("solana-rpc-read-concurrency"))
+	// Note: solana_vaa_service_url and solana_vaa_service_token are read from env
+	// WORMHOLE_RELAYER_SOLANA_VAA_SERVICE_URL and WORMHOLE_RELAYER_SOLANA_VAA_SERVICE_TOKEN
 }
 
 type SolanaConfig struct {
-	SpyRPCHost              string   // Wormhole spy service endpoint
-	ChainIDs                []uint16 // Source chain IDs to listen for
-	SolanaRPCURL            string   // RPC URL for Solana
-	SolanaPrivateKey        string   // Private key for Solana transactions (base58)
-	SolanaProgramID         string   // MessageBridge program ID
-	SolanaWormholeProgramID string   // Wormhole Core Bridge program ID (optional, defaults to devnet)
-	SolanaVAAServiceURL     string   // URL for the Solana VAA posting service
-	EmitterAddress          string   // Source emitter address to filter
+	SolanaNetwork           string  `mapstructure:"network"`              // Solana cluster selecting the defaults of the RPC URL and Wormhole program ID
+	SolanaRPCURL            string  `mapstructure:"rpc_url"`              // RPC URL for Solana
+	SolanaPrivateKey        string  `mapstructure:"private_key"`          // Private key for Solana transactions (base58)
+	SolanaKeypairFile       string  `mapstructure:"keypair_file"`         // Path to a Solana CLI JSON keypair file
+	SolanaProgramID         string  `mapstructure:"program_id"`           // MessageBridge program ID
+	SolanaWormholeProgramID string  `mapstructure:"wormhole_program_id"`  // Wormhole Core Bridge program ID (optional, defaults to devnet)
+	SolanaVAAServiceURL     string  `mapstructure:"vaa_service_url"`      // URL for the Solana VAA posting service
+	SolanaVAAServiceGzip    bool    `mapstructure:"vaa_service_gzip"`     // Gzip VAA service requests once it advertises support
+	SolanaPreflight         bool    `mapstructure:"preflight"`            // Simulate transactions before sending them
+	SolanaCommitment        string  `mapstructure:"blockhash_commitment"` // Commitment the transaction blockhash is fetched at
+	SolanaMinBalance        float64 `mapstructure:"min_sol_balance"`      // Minimum payer balance in SOL for a transaction to be sent
+	SolanaSkipEmitterCheck  bool    `mapstructure:"skip_emitter_check"`   // Skip checking the emitter is registered before submitting
+	SolanaNonceAccount      string  `mapstructure:"nonce_account"`        // Durable nonce account replacing the recent blockhash (optional)
+	// Maximum account reads in flight across all VAAs (0 = unlimited)
+	SolanaRPCReadConcurrency int `mapstructure:"rpc_read_concurrency"`
+	// Largest VAA posting service response body read, in bytes (0 = clients.DefaultMaxResponseBytes)
+	SolanaVAAServiceMaxResponse int64 `mapstructure:"vaa_service_max_response_bytes"`
+	// Bearer token and static headers sent with every VAA posting service request
+	SolanaVAAServiceToken   string            `mapstructure:"vaa_service_token"`
+	SolanaVAAServiceHeaders map[string]string `mapstructure:"vaa_service_headers"`
+	// Names for custom program error codes (decimal or 0x hex), added to clients.DefaultProgramErrors
+	SolanaProgramErrors map[string]string `mapstructure:"program_errors"`
+	// Path to a Solana CLI JSON keypair file of the nonce account's authority (empty = the payer)
+	SolanaNonceAuthority string `mapstructure:"nonce_authority_keypair_file"`
+	// Commitment a sent transaction must reach before its VAA counts as delivered (none = once sent)
+	SolanaConfirmation string `mapstructure:"confirmation"`
+	// Remote signer signing as SolanaSignerPubkey in place of a local payer key (optional)
+	SolanaRemoteSignerURL string `mapstructure:"remote_signer_url"`
+	SolanaSignerPubkey    string `mapstructure:"signer_pubkey"`
+	// What to do with a VAA not confirmed posted after every post attempt (empty = strict)
+	SolanaPostPolicy string `mapstructure:"post_policy"`
+	// Anchor IDL JSON file receive_value is built from (empty = the built-in layout)
+	SolanaIDLFile string `mapstructure:"idl_file"`
 }
 
 func runSolanaRelay(cmd *cobra.Command, args []string) error {
 	logger := configureLogging(cmd, args)
 	logger.Info("Starting Solana relayer")
 
-	// Get flags directly from command (viper bindings conflict across commands)
-	emitterAddress, _ := cmd.Flags().GetString("emitter-address")
-	chainIDsInt, _ := cmd.Flags().GetIntSlice("chain-ids")
-
-	// Convert chain IDs from []int to []uint16
-	chainIDs := make([]uint16, len(chainIDsInt))
-	for i, id := range chainIDsInt {
-		chainIDs[i] = uint16(id)
+	config, err := readSolanaConfig()
+	if err != nil {
+		return err
 	}
 
+	logger.Info("Configuration",
+		zap.String("solanaNetwork", config.SolanaNetwork),
+		zap.String("solanaRPC", config.SolanaRPCURL),
+		zap.String("wormholeProgramID", config.SolanaWormholeProgramID),
+		zap.String("solanaProgramID", config.SolanaProgramID),
+		zap.String("vaaServiceURL", config.SolanaVAAServiceURL),
+		zap.Bool("vaaServiceGzip", config.SolanaVAAServiceGzip),
+		zap.Stringer("vaaServiceAuth", vaaServiceAuth(config)),
+		zap.Bool("preflight", config.SolanaPreflight),
+		zap.String("blockhashCommitment", config.SolanaCommitment),
+		zap.String("confirmation", config.SolanaConfirmation),
+		zap.Float64("minSOLBalance", config.SolanaMinBalance),
+		zap.Bool("skipEmitterCheck", config.SolanaSkipEmitterCheck),
+		zap.String("postPolicy", config.SolanaPostPolicy),
+		zap.String("idlFile", config.SolanaIDLFile),
+		zap.String("nonceAccount", config.SolanaNonceAccount),
+		zap.Int("rpcReadConcurrency", config.SolanaRPCReadConcurrency))
+
+	return runRelay(logger, readRelayConfig(cmd, DefaultSolanaSourceChains), SolanaDestinationChainID,
+		func(logger *zap.Logger) (submitter.VAASubmitter, error) {
+			return buildSolanaSubmitter(logger, config)
+		})
+}
+
+// readSolanaConfig reads and validates the Solana-specific configuration
+func readSolanaConfig() (SolanaConfig, error) {
 	config := SolanaConfig{
-		SpyRPCHost:              viper.GetString("spy_rpc_host"),
-		ChainIDs:                chainIDs,
-		SolanaRPCURL:            viper.GetString("solana_rpc_url"),
-		SolanaPrivateKey:        viper.GetString("solana_private_key"),
-		SolanaProgramID:         viper.GetString("solana_program_id"),
-		SolanaWormholeProgramID: viper.GetString("solana_wormhole_program_id"),
-		SolanaVAAServiceURL:     viper.GetString("solana_vaa_service_url"),
-		EmitterAddress:          emitterAddress,
+		SolanaNetwork:               viper.GetString("solana_network"),
+		SolanaRPCURL:                viper.GetString("solana_rpc_url"),
+		SolanaPrivateKey:            viper.GetString("solana_private_key"),
+		SolanaKeypairFile:           viper.GetString("solana_keypair_file"),
+		SolanaProgramID:             viper.GetString("solana_program_id"),
+		SolanaWormholeProgramID:     viper.GetString("solana_wormhole_program_id"),
+		SolanaVAAServiceURL:         viper.GetString("solana_vaa_service_url"),
+		SolanaVAAServiceGzip:        viper.GetBool("solana_vaa_service_gzip"),
+		SolanaVAAServiceMaxResponse: viper.GetInt64("solana_vaa_service_max_response_bytes"),
+		SolanaVAAServiceToken:       viper.GetString("solana_vaa_service_token"),
+		SolanaVAAServiceHeaders:     viper.GetStringMapString("solana_vaa_service_headers"),
+		SolanaPreflight:             viper.GetBool("solana_preflight"),
+		SolanaCommitment:            viper.GetString("solana_blockhash_commitment"),
+		SolanaMinBalance:            viper.GetFloat64("min_sol_balance"),
+		SolanaProgramErrors:         viper.GetStringMapString("solana_program_errors"),
+		SolanaSkipEmitterCheck:      viper.GetBool("solana_skip_emitter_check"),
+		SolanaNonceAccount:          viper.GetString("solana_nonce_account"),
+		SolanaNonceAuthority:        viper.GetString("solana_nonce_authority_keypair_file"),
+		SolanaConfirmation:          viper.GetString("solana_confirmation"),
+		SolanaRemoteSignerURL:       viper.GetString("solana_remote_signer_url"),
+		SolanaSignerPubkey:          viper.GetString("solana_signer_pubkey"),
+		SolanaRPCReadConcurrency:    viper.GetInt("solana_rpc_read_concurrency"),
+		SolanaPostPolicy:            viper.GetString("solana_post_policy"),
+		SolanaIDLFile:               viper.GetString("solana_idl_file"),
+	}
+
+	config, err := applySolanaNetwork(config)
+	if err != nil {
+		return config, err
+	}
+	return config, validateSolanaConfig(config)
+}
+
+// applySolanaNetwork fills in the RPC URL and Wormhole program ID left empty with the
+// well-known ones of config's network
+func applySolanaNetwork(config SolanaConfig) (SolanaConfig, error) {
+	if config.SolanaNetwork == "" {
+		config.SolanaNetwork = DefaultSolanaNetwork
+	}
+	network, err := clients.LookupSolanaNetwork(config.SolanaNetwork)
+	if err != nil {
+		return config, fmt.Errorf("invalid --solana-network: %v", err)
 	}
 
-	// Validate required config
-	if config.SolanaPrivateKey == "" {
-		return fmt.Errorf("Solana private key is required")
+	if config.SolanaRPCURL == "" {
+		config.SolanaRPCURL = network.RPCURL
+	}
+	if config.SolanaWormholeProgramID == "" {
+		if network.WormholeProgramID == "" {
+			return config, fmt.Errorf("Solana %s has no well-known Wormhole Core Bridge; set --solana-wormhole-program-id", config.SolanaNetwork)
+		}
+		config.SolanaWormholeProgramID = network.WormholeProgramID
+	}
+	return config, nil
+}
+
+// validateSolanaConfig checks the payer key source and program IDs of a Solana destination
+func validateSolanaConfig(config SolanaConfig) error {
+	keySources := 0
+	for _, source := range []string{config.SolanaPrivateKey, config.SolanaKeypairFile, config.SolanaRemoteSignerURL} {
+		if source != "" {
+			keySources++
+		}
+	}
+	if keySources == 0 {
+		return fmt.Errorf("--solana-private-key, --solana-keypair-file or --solana-remote-signer-url is required")
+	}
+	if keySources > 1 {
+		return fmt.Errorf("--solana-private-key, --solana-keypair-file and --solana-remote-signer-url are mutually exclusive")
+	}
+	if config.SolanaRemoteSignerURL != "" {
+		if err := internal.ValidateSolanaAddress(config.SolanaSignerPubkey); err != nil {
+			return fmt.Errorf("invalid --solana-signer-pubkey (required with --solana-remote-signer-url): %v", err)
+		}
+	} else if config.SolanaSignerPubkey != "" {
+		return fmt.Errorf("--solana-signer-pubkey requires --solana-remote-signer-url")
 	}
 	if config.SolanaProgramID == "" {
 		return fmt.Errorf("Solana program ID is required")
 	}
+	if err := internal.ValidateSolanaAddress(config.SolanaProgramID); err != nil {
+		return fmt.Errorf("invalid --solana-program-id: %v", err)
+	}
+	if config.SolanaWormholeProgramID != "" {
+		if err := internal.ValidateSolanaAddress(config.SolanaWormholeProgramID); err != nil {
+			return fmt.Errorf("invalid --solana-wormhole-program-id: %v", err)
+		}
+	}
+	if _, err := clients.ParseBlockhashCommitment(config.SolanaCommitment); err != nil {
+		return fmt.Errorf("invalid --solana-blockhash-commitment: %v", err)
+	}
+	if _, err := clients.ParseSolanaConfirmation(config.SolanaConfirmation); err != nil {
+		return fmt.Errorf("invalid --solana-confirmation: %v", err)
+	}
+	if config.SolanaMinBalance < 0 || math.IsNaN(config.SolanaMinBalance) {
+		return fmt.Errorf("invalid --min-sol-balance: %v is not a non-negative amount of SOL", config.SolanaMinBalance)
+	}
+	if _, err := clients.ParseProgramErrors(config.SolanaProgramErrors); err != nil {
+		return fmt.Errorf("invalid --solana-program-errors: %v", err)
+	}
+	if _, err := submitter.ParseSolanaPostPolicy(config.SolanaPostPolicy); err != nil {
+		return fmt.Errorf("invalid --solana-post-policy: %v", err)
+	}
+	if config.SolanaRPCReadConcurrency < 0 {
+		return fmt.Errorf("--solana-rpc-read-concurrency must not be negative")
+	}
+	if config.SolanaVAAServiceMaxResponse < 0 {
+		return fmt.Errorf("--solana-vaa-service-max-response-bytes must not be negative")
+	}
+	if _, err := serviceAuth("--solana-vaa-service-header", config.SolanaVAAServiceToken, config.SolanaVAAServiceHeaders); err != nil {
+		return err
+	}
+	if config.SolanaNonceAccount != "" {
+		if err := internal.ValidateSolanaAddress(config.SolanaNonceAccount); err != nil {
+			return fmt.Errorf("invalid --solana-nonce-account: %v", err)
+		}
+	} else if config.SolanaNonceAuthority != "" {
+		return fmt.Errorf("--solana-nonce-authority-keypair-file requires --solana-nonce-account")
+	}
 
-	logger.Info("Configuration",
-		zap.String("spyRPC", config.SpyRPCHost),
-		zap.Any("chainIds", config.ChainIDs),
-		zap.String("solanaRPC", config.SolanaRPCURL),
-		zap.String("solanaProgramID", config.SolanaProgramID),
-		zap.String("vaaServiceURL", config.SolanaVAAServiceURL),
-		zap.String("emitterFilter", config.EmitterAddress))
+	return nil
+}
 
-	// Create spy client
-	spyClient, err := clients.NewSpyClient(logger, config.SpyRPCHost)
+// solanaSigner returns the remote signer of config, or nil if the payer key is local
+func solanaSigner(config SolanaConfig) (clients.SolanaSigner, error) {
+	if config.SolanaRemoteSignerURL == "" {
+		return nil, nil
+	}
+	return clients.NewRemoteSolanaSigner(remoteSignerConfig(config.SolanaRemoteSignerURL), config.SolanaSignerPubkey)
+}
+
+// vaaServiceAuth returns the credentials sent to the VAA posting service of config, which
+// validateSolanaConfig has checked
+func vaaServiceAuth(config SolanaConfig) clients.ServiceAuth {
+	auth, _ := serviceAuth("--solana-vaa-service-header", config.SolanaVAAServiceToken, config.SolanaVAAServiceHeaders)
+	return auth
+}
+
+// buildSolanaSubmitter creates the Solana client and submitter
+func buildSolanaSubmitter(logger *zap.Logger, config SolanaConfig) (submitter.VAASubmitter, error) {
+	postPolicy, err := submitter.ParseSolanaPostPolicy(config.SolanaPostPolicy)
 	if err != nil {
-		return fmt.Errorf("failed to create spy client: %v", err)
-	}
-
-	// Create Solana client
-	solanaClient, err := clients.NewSolanaClient(
-		logger,
-		config.SolanaRPCURL,
-		config.SolanaPrivateKey,
-		config.SolanaProgramID,
-		config.SolanaWormholeProgramID,
-		config.SolanaVAAServiceURL,
-	)
+		return nil, fmt.Errorf("invalid --solana-post-policy: %v", err)
+	}
+	solanaClient, err := newSolanaClient(logger, config)
 	if err != nil {
-		return fmt.Errorf("failed to create Solana client: %v", err)
+		return nil, err
 	}
 
-	logger.Info("Connected to Solana",
-		zap.String("payer", solanaClient.GetPayerAddress().String()),
-		zap.String("programID", solanaClient.GetProgramID().String()))
-
-	// Create Solana submitter
 	solanaSubmitter := submitter.NewSolanaSubmitter(logger, solanaClient)
+	solanaSubmitter.SetEmitterCheck(!config.SolanaSkipEmitterCheck)
+	solanaSubmitter.SetPostPolicy(postPolicy)
+	return solanaSubmitter, nil
+}
 
-	// Create VAA processor
-	vaaProcessor := internal.NewDefaultVAAProcessor(logger,
-		internal.VAAProcessorConfig{
-			ChainIDs:           config.ChainIDs,
-			EmitterAddress:     config.EmitterAddress,
-			DestinationChainID: SolanaDestinationChainID,
-		},
-		solanaSubmitter)
-
-	// Create and start relayer
-	relayer, err := internal.NewRelayer(logger, spyClient, vaaProcessor)
+// newSolanaClient connects to the Solana RPC of config with its payer and program settings
+func newSolanaClient(logger *zap.Logger, config SolanaConfig) (*clients.SolanaClient, error) {
+	programErrors, err := clients.ParseProgramErrors(config.SolanaProgramErrors)
 	if err != nil {
-		return fmt.Errorf("failed to initialize relayer: %v", err)
+		return nil, fmt.Errorf("invalid --solana-program-errors: %v", err)
+	}
+	signer, err := solanaSigner(config)
+	if err != nil {
+		return nil, err
 	}
-	defer relayer.Close()
-
-	// Setup context with cancellation
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Handle graceful shutdown
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		logger.Info("Received shutdown signal")
-		cancel()
-	}()
 
-	// Start the relayer
-	if err := relayer.Start(ctx); err != nil {
-		return fmt.Errorf("relayer stopped with error: %v", err)
+	solanaClient, err := clients.NewSolanaClient(logger, clients.SolanaClientConfig{
+		RPCURL:                     config.SolanaRPCURL,
+		PrivateKey:                 config.SolanaPrivateKey,
+		KeypairFile:                config.SolanaKeypairFile,
+		ProgramID:                  config.SolanaProgramID,
+		WormholeProgramID:          config.SolanaWormholeProgramID,
+		VAAServiceURL:              config.SolanaVAAServiceURL,
+		VAAServiceGzip:             config.SolanaVAAServiceGzip,
+		VAAServiceMaxResponseBytes: config.SolanaVAAServiceMaxResponse,
+		VAAServiceAuth:             vaaServiceAuth(config),
+		Preflight:                  config.SolanaPreflight,
+		BlockhashCommitment:        config.SolanaCommitment,
+		MinBalanceLamports:         solToLamports(config.SolanaMinBalance),
+		ProgramErrors:              programErrors,
+		// Remote signer replacing the payer key, if configured
+		Signer: signer,
+		// Durable nonce, if configured
+		NonceAccount:              config.SolanaNonceAccount,
+		NonceAuthorityKeypairFile: config.SolanaNonceAuthority,
+		// Commitment a sent transaction must reach before it counts as delivered
+		ConfirmationCommitment: config.SolanaConfirmation,
+		// Cap on the account reads in flight, if configured
+		ReadConcurrency: config.SolanaRPCReadConcurrency,
+		// Program IDL receive_value is built from, if configured
+		IDLFile: config.SolanaIDLFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Solana client: %v", err)
 	}
 
-	return nil
+	logger.Info("Connected to Solana",
+		zap.String("payer", solanaClient.GetPayerAddress().String()),
+		zap.String("programID", solanaClient.GetProgramID().String()))
+	return solanaClient, nil
+}
+
+// solToLamports converts an amount of SOL, as given in flags, to lamports
+func solToLamports(sol float64) uint64 {
+	return uint64(math.Round(sol * clients.LamportsPerSOL))
 }
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// Payload layouts a foreign emitter can be registered with (--payload-layout)
+const (
+	payloadLayoutDefault = "default" // 18 bytes: [chainId(2) | value(16)], sent by Solana and EVM
+	payloadLayoutAztec   = "aztec"   // 50 bytes: [txId(32) | chainId(2) | value(16)]
+)
+
+// solanaRegisterEmitterCmd registers the emitter of a source chain on the MessageBridge, which
+// receive_value needs before it accepts that chain's VAAs
+var solanaRegisterEmitterCmd = &cobra.Command{
+	Use:   "register-emitter",
+	Short: "Register a source chain's emitter on the Solana MessageBridge",
+	Long: `Creates the foreign_emitter account of --source-chain on the MessageBridge program, so
+receive_value accepts VAAs from --emitter. The payer must be the program owner.
+
+The payload layout defaults to aztec for the Aztec chains (54, 56) and to default otherwise.
+The program cannot change a registration: if the chain already has the same emitter
+registered the command succeeds without sending anything, and if it has another one it fails.
+The account is read back after the transaction to verify the registration.`,
+	Example:      `  wormhole-relayer solana register-emitter --source-chain 10003 --emitter 0x... --solana-program-id ... --solana-keypair-file owner.json`,
+	SilenceUsage: true,
+	RunE:         runSolanaRegisterEmitter,
+}
+
+func init() {
+	solanaCmd.AddCommand(solanaRegisterEmitterCmd)
+
+	solanaRegisterEmitterCmd.Flags().Int(
+		"source-chain",
+		0,
+		"Wormhole chain ID of the emitter")
+
+	solanaRegisterEmitterCmd.Flags().String(
+		"emitter",
+		"",
+		"Emitter address to register (32-byte hex, or a 20-byte EVM address)")
+
+	solanaRegisterEmitterCmd.Flags().String(
+		"payload-layout",
+		"",
+		"Payload layout the emitter sends: default (18 bytes) or aztec (50 bytes) (default: aztec for chains 54 and 56)")
+
+	solanaRegisterEmitterCmd.Flags().Duration(
+		"submission-timeout",
+		DefaultSolanaSubmissionTimeout,
+		"Deadline for sending and confirming the registration")
+
+	registerSolanaFlags(solanaRegisterEmitterCmd)
+
+	solanaRegisterEmitterCmd.MarkFlagRequired("source-chain")
+	solanaRegisterEmitterCmd.MarkFlagRequired("emitter")
+	solanaRegisterEmitterCmd.MarkFlagRequired("solana-program-id")
+	solanaRegisterEmitterCmd.MarkFlagsMutuallyExclusive("solana-private-key", "solana-keypair-file", "solana-remote-signer-url")
+}
+
+func runSolanaRegisterEmitter(cmd *cobra.Command, args []string) error {
+	logger := configureLogging(cmd, args)
+
+	sourceChain, _ := cmd.Flags().GetInt("source-chain")
+	emitter, _ := cmd.Flags().GetString("emitter")
+	layout, _ := cmd.Flags().GetString("payload-layout")
+	submissionTimeout, _ := cmd.Flags().GetDuration("submission-timeout")
+
+	// Validate the registration before connecting to anything
+	registration, err := parseEmitterRegistration(sourceChain, emitter, layout)
+	if err != nil {
+		return err
+	}
+
+	// The Solana flags are bound here, not in init, so the relay command keeps its own binding
+	bindSolanaFlags(cmd)
+	config, err := readSolanaConfig()
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Registering emitter",
+		zap.String("emitter", internal.FormatEmitter(registration.ChainID, hex.EncodeToString(registration.Address[:]))),
+		zap.String("payloadLayout", payloadLayoutName(registration.DefaultPayload)),
+		zap.String("solanaRPC", config.SolanaRPCURL),
+		zap.String("solanaProgramID", config.SolanaProgramID))
+
+	solanaClient, err := newSolanaClient(logger, config)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, submissionTimeout)
+	defer cancel()
+
+	return registerSolanaEmitter(ctx, cmd.OutOrStdout(), solanaClient, registration)
+}
+
+// registerSolanaEmitter registers registration unless it is already in place, and verifies the
+// foreign_emitter account afterwards
+func registerSolanaEmitter(ctx context.Context, w io.Writer, solanaClient *clients.SolanaClient, registration clients.SolanaForeignEmitter) error {
+	// A payer other than the owner fails with OwnerOnly; say so before paying for it
+	owner, err := solanaClient.ProgramOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if payer := solanaClient.GetPayerAddress(); !payer.Equals(owner) {
+		return fmt.Errorf("payer %s is not the MessageBridge owner %s; only the owner can register emitters", payer, owner)
+	}
+
+	existing, pda, err := solanaClient.ForeignEmitter(ctx, registration.ChainID)
+	switch {
+	case err == nil && existing == registration:
+		fmt.Fprintf(w, "chain %d already has emitter %x registered (%s payload) at %s\n",
+			registration.ChainID, registration.Address, payloadLayoutName(registration.DefaultPayload), pda)
+		return nil
+	case err == nil:
+		return fmt.Errorf("chain %d already has emitter %x registered (%s payload) at %s; the MessageBridge cannot change a registration",
+			registration.ChainID, existing.Address, payloadLayoutName(existing.DefaultPayload), pda)
+	case !errors.Is(err, clients.ErrEmitterNotRegistered):
+		return err
+	}
+
+	signature, err := solanaClient.RegisterEmitter(ctx, registration)
+	if err != nil {
+		return fmt.Errorf("register emitter for chain %d: %w", registration.ChainID, err)
+	}
+
+	registered, pda, err := solanaClient.ForeignEmitter(ctx, registration.ChainID)
+	if err != nil {
+		return fmt.Errorf("transaction %s landed but the registration could not be verified: %w", signature, err)
+	}
+	if registered != registration {
+		return fmt.Errorf("transaction %s landed but %s holds emitter %x of chain %d (%s payload)",
+			signature, pda, registered.Address, registered.ChainID, payloadLayoutName(registered.DefaultPayload))
+	}
+	fmt.Fprintf(w, "chain %d registered emitter %x (%s payload) at %s: %s\n",
+		registration.ChainID, registration.Address, payloadLayoutName(registration.DefaultPayload), pda, signature)
+	return nil
+}
+
+// parseEmitterRegistration validates the register-emitter flags. An empty layout is aztec for
+// the Aztec chains and default otherwise.
+func parseEmitterRegistration(sourceChain int, emitter, layout string) (clients.SolanaForeignEmitter, error) {
+	if sourceChain <= 0 || sourceChain > math.MaxUint16 {
+		return clients.SolanaForeignEmitter{}, fmt.Errorf("invalid --source-chain: %d is not a Wormhole chain ID", sourceChain)
+	}
+	if uint16(sourceChain) == SolanaDestinationChainID {
+		return clients.SolanaForeignEmitter{}, fmt.Errorf("invalid --source-chain: Solana (%d) cannot be registered as a foreign emitter", sourceChain)
+	}
+	registration := clients.SolanaForeignEmitter{ChainID: uint16(sourceChain)}
+
+	normalized, err := internal.ValidateEmitterAddress(emitter)
+	if err != nil {
+		return clients.SolanaForeignEmitter{}, fmt.Errorf("invalid --emitter: %v", err)
+	}
+	raw, _ := hex.DecodeString(normalized)
+	copy(registration.Address[:], raw)
+	if registration.Address == ([32]byte{}) {
+		return clients.SolanaForeignEmitter{}, fmt.Errorf("invalid --emitter: the zero address cannot be registered")
+	}
+
+	switch layout {
+	case "":
+		registration.DefaultPayload = registration.ChainID != 54 && registration.ChainID != 56
+	case payloadLayoutDefault:
+		registration.DefaultPayload = true
+	case payloadLayoutAztec:
+		registration.DefaultPayload = false
+	default:
+		return clients.SolanaForeignEmitter{}, fmt.Errorf("invalid --payload-layout %q (want %s or %s)", layout, payloadLayoutDefault, payloadLayoutAztec)
+	}
+	return registration, nil
+}
+
+// payloadLayoutName names the payload layout of a foreign emitter
+func payloadLayoutName(defaultPayload bool) string {
+	if defaultPayload {
+		return payloadLayoutDefault
+	}
+	return payloadLayoutAztec
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestApplySolanaNetwork(t *testing.T) {
+	mainnet := clients.SolanaNetworks["mainnet-beta"]
+	tests := []struct {
+		name        string
+		config      SolanaConfig
+		wantRPC     string
+		wantProgram string
+		wantErr     bool
+	}{
+		{
+			name:        "defaults to devnet",
+			config:      SolanaConfig{},
+			wantRPC:     clients.SolanaNetworks["devnet"].RPCURL,
+			wantProgram: clients.DefaultWormholeProgramID.String(),
+		},
+		{
+			name:        "network defaults",
+			config:      SolanaConfig{SolanaNetwork: "mainnet-beta"},
+			wantRPC:     mainnet.RPCURL,
+			wantProgram: mainnet.WormholeProgramID,
+		},
+		{
+			name:        "explicit settings win",
+			config:      SolanaConfig{SolanaNetwork: "mainnet-beta", SolanaRPCURL: "http://localhost:8899", SolanaWormholeProgramID: "11111111111111111111111111111111"},
+			wantRPC:     "http://localhost:8899",
+			wantProgram: "11111111111111111111111111111111",
+		},
+		{
+			name:    "testnet needs a Wormhole program ID",
+			config:  SolanaConfig{SolanaNetwork: "testnet"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown network",
+			config:  SolanaConfig{SolanaNetwork: "localnet"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := applySolanaNetwork(tt.config)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", config)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applySolanaNetwork failed: %v", err)
+			}
+			if config.SolanaRPCURL != tt.wantRPC || config.SolanaWormholeProgramID != tt.wantProgram {
+				t.Errorf("expected RPC %s and program %s, got %s and %s", tt.wantRPC, tt.wantProgram, config.SolanaRPCURL, config.SolanaWormholeProgramID)
+			}
+		})
+	}
+}
+
+func TestParseEmitterRegistration(t *testing.T) {
+	evmEmitter := "0x1111111111111111111111111111111111111111"
+	tests := []struct {
+		name        string
+		sourceChain int
+		emitter     string
+		layout      string
+		wantDefault bool
+		wantErr     bool
+	}{
+		{name: "EVM chain defaults to the default layout", sourceChain: 10003, emitter: evmEmitter, wantDefault: true},
+		{name: "Aztec chain defaults to the aztec layout", sourceChain: 56, emitter: evmEmitter, wantDefault: false},
+		{name: "explicit layout wins", sourceChain: 56, emitter: evmEmitter, layout: "default", wantDefault: true},
+		{name: "unknown layout", sourceChain: 10003, emitter: evmEmitter, layout: "compact", wantErr: true},
+		{name: "Solana", sourceChain: 1, emitter: evmEmitter, wantErr: true},
+		{name: "chain out of range", sourceChain: 70000, emitter: evmEmitter, wantErr: true},
+		{name: "malformed emitter", sourceChain: 10003, emitter: "0x1234", wantErr: true},
+		{name: "zero emitter", sourceChain: 10003, emitter: strings.Repeat("0", 64), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registration, err := parseEmitterRegistration(tt.sourceChain, tt.emitter, tt.layout)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", registration)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEmitterRegistration failed: %v", err)
+			}
+			if registration.ChainID != uint16(tt.sourceChain) || registration.DefaultPayload != tt.wantDefault || registration.Address[31] != 0x11 || registration.Address[0] != 0 {
+				t.Errorf("unexpected registration %+v", registration)
+			}
+		})
+	}
+}
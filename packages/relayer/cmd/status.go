@@ -0,0 +1,348 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// DefaultStatusCheckTimeout bounds each individual status check
+const DefaultStatusCheckTimeout = 15 * time.Second
+
+// statusCmd checks the dependencies of a relay command without relaying anything
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check that a relay command's dependencies are reachable",
+	Long: `Checks every dependency of a relay command without relaying anything:
+the spy service, the destination RPC (and its chain ID), the signer's balance
+and, for Aztec, the verification service and PXE.
+
+Pass --chain and the same destination flags as the relay command. A table of
+OK/FAIL results is printed and the command exits non-zero if any check fails.`,
+	Example:      `  wormhole-relayer status --chain base --private-key-file key.hex --evm-target-contract 0x...`,
+	SilenceUsage: true,
+	RunE:         runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().String(
+		"chain",
+		"",
+		"Destination to check (aztec, solana, cosmos, arbitrum, base)")
+
+	statusCmd.Flags().Duration(
+		"check-timeout",
+		DefaultStatusCheckTimeout,
+		"Deadline for each individual check")
+
+	// Every destination's flags are accepted; only those of --chain are used
+	registerAztecFlags(statusCmd)
+	registerSolanaFlags(statusCmd)
+	registerCosmosFlags(statusCmd)
+	registerEVMFlags(statusCmd)
+
+	statusCmd.MarkFlagRequired("chain")
+}
+
+// statusCheck is a single named dependency check. On success it returns a short detail for the table.
+type statusCheck struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// statusResult is the outcome of a statusCheck
+type statusResult struct {
+	name   string
+	ok     bool
+	detail string
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	// Client constructors log at info level, which would drown the table; only show logs with --debug
+	logger := zap.NewNop()
+	if debug, _ := cmd.Flags().GetBool("debug"); debug {
+		logger = configureLogging(cmd, args)
+	}
+
+	chain, _ := cmd.Flags().GetString("chain")
+	timeout, _ := cmd.Flags().GetDuration("check-timeout")
+
+	checks, err := buildStatusChecks(cmd, logger, chain)
+	if err != nil {
+		return err
+	}
+
+	results := runStatusChecks(context.Background(), checks, timeout)
+	if failed := printStatusTable(cmd.OutOrStdout(), results); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// buildStatusChecks reads the configuration of the given destination and returns its checks.
+// Only one command runs per process, so the destination's flags are bound to viper here.
+func buildStatusChecks(cmd *cobra.Command, logger *zap.Logger, chain string) ([]statusCheck, error) {
+	checks := []statusCheck{spyStatusCheck(logger, viper.GetString("spy_rpc_host"))}
+
+	switch chain {
+	case "aztec":
+		bindAztecFlags(cmd)
+		config, err := readAztecConfig(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return append(checks, aztecStatusChecks(logger, config)...), nil
+	case "solana":
+		bindSolanaFlags(cmd)
+		config, err := readSolanaConfig()
+		if err != nil {
+			return nil, err
+		}
+		return append(checks, solanaStatusChecks(logger, config)...), nil
+	case "cosmos":
+		bindCosmosFlags(cmd)
+		config, err := readCosmosConfig(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return append(checks, cosmosStatusChecks(logger, config)...), nil
+	}
+
+	chainConfig, ok := EVMChainConfigs[chain]
+	if !ok {
+		return nil, fmt.Errorf("unsupported chain: %s (valid: aztec, solana, cosmos, arbitrum, base)", chain)
+	}
+	bindEVMFlags(cmd)
+	config, err := readEVMConfig(cmd, chain, chainConfig)
+	if err != nil {
+		return nil, err
+	}
+	return append(checks, evmStatusChecks(logger, config, chainConfig)...), nil
+}
+
+// runStatusChecks runs checks in order, each with its own deadline
+func runStatusChecks(ctx context.Context, checks []statusCheck, timeout time.Duration) []statusResult {
+	results := make([]statusResult, 0, len(checks))
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		detail, err := check.run(checkCtx)
+		cancel()
+
+		result := statusResult{name: check.name, ok: err == nil, detail: detail}
+		if err != nil {
+			result.detail = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// printStatusTable writes results as an aligned table and returns the number of failed checks
+func printStatusTable(w io.Writer, results []statusResult) int {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tDETAIL")
+
+	failed := 0
+	for _, result := range results {
+		status := "OK"
+		if !result.ok {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.name, status, result.detail)
+	}
+	tw.Flush()
+	return failed
+}
+
+// spyStatusCheck opens and immediately closes a VAA subscription
+func spyStatusCheck(logger *zap.Logger, host string) statusCheck {
+	return statusCheck{name: "spy", run: func(ctx context.Context) (string, error) {
+		spyClient, err := clients.NewSpyClient(logger, host)
+		if err != nil {
+			return "", err
+		}
+		defer spyClient.Close()
+
+		if err := spyClient.CheckSubscription(ctx); err != nil {
+			return "", fmt.Errorf("%s: %v", host, err)
+		}
+		return "subscribed at " + host, nil
+	}}
+}
+
+// evmStatusChecks checks the EVM RPC reports the expected chain ID and the signer has funds
+func evmStatusChecks(logger *zap.Logger, config EVMConfig, chainConfig EVMChainConfig) []statusCheck {
+	var evmClient *clients.EVMClient
+	return []statusCheck{
+		{name: "evm rpc", run: func(ctx context.Context) (string, error) {
+			signer, err := evmSigner(config)
+			if err != nil {
+				return "", err
+			}
+			evmClient, err = clients.NewEVMClient(logger, clients.EVMClientConfig{
+				RPCURL:               config.EVMRPCURL,
+				PrivateKey:           config.PrivateKey,
+				PrivateKeyFile:       config.PrivateKeyFile,
+				KeystoreFile:         config.KeystoreFile,
+				KeystorePassword:     config.KeystorePassword,
+				KeystorePasswordFile: config.KeystorePasswordFile,
+				Signer:               signer,
+				Retry:                config.RPCRetry,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			chainID, err := evmClient.ChainID(ctx)
+			if err != nil {
+				return "", fmt.Errorf("%s: failed to get chain ID: %v", config.EVMRPCURL, err)
+			}
+			if chainID.Uint64() != chainConfig.EVMChainID {
+				return "", fmt.Errorf("%s reports chain ID %s, expected %d (%s)",
+					config.EVMRPCURL, chainID, chainConfig.EVMChainID, chainConfig.DisplayName)
+			}
+			return fmt.Sprintf("%s, chain ID %s", config.EVMRPCURL, chainID), nil
+		}},
+		{name: "evm signer balance", run: func(ctx context.Context) (string, error) {
+			if evmClient == nil {
+				return "", fmt.Errorf("skipped: EVM client unavailable")
+			}
+			balance, err := evmClient.Balance(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to get balance: %v", err)
+			}
+			return checkFunded(evmClient.GetAddress().Hex(), balance, "wei")
+		}},
+	}
+}
+
+// solanaStatusChecks checks the Solana RPC is healthy and the payer has funds
+func solanaStatusChecks(logger *zap.Logger, config SolanaConfig) []statusCheck {
+	var solanaClient *clients.SolanaClient
+	return []statusCheck{
+		{name: "solana rpc", run: func(ctx context.Context) (string, error) {
+			// The constructor verifies the RPC endpoint reports healthy
+			signer, err := solanaSigner(config)
+			if err != nil {
+				return "", err
+			}
+			solanaClient, err = clients.NewSolanaClient(logger, clients.SolanaClientConfig{
+				RPCURL:              config.SolanaRPCURL,
+				PrivateKey:          config.SolanaPrivateKey,
+				KeypairFile:         config.SolanaKeypairFile,
+				Signer:              signer,
+				ProgramID:           config.SolanaProgramID,
+				WormholeProgramID:   config.SolanaWormholeProgramID,
+				VAAServiceURL:       config.SolanaVAAServiceURL,
+				VAAServiceAuth:      vaaServiceAuth(config),
+				BlockhashCommitment: config.SolanaCommitment,
+			})
+			if err != nil {
+				return "", err
+			}
+			return config.SolanaRPCURL + " healthy", nil
+		}},
+		{name: "solana payer balance", run: func(ctx context.Context) (string, error) {
+			if solanaClient == nil {
+				return "", fmt.Errorf("skipped: Solana client unavailable")
+			}
+			balance, err := solanaClient.Balance(ctx)
+			if err != nil {
+				return "", err
+			}
+			if minBalance := solToLamports(config.SolanaMinBalance); balance < minBalance {
+				return "", fmt.Errorf("%s has %s SOL, below --min-sol-balance %s SOL",
+					solanaClient.GetPayerAddress(), clients.FormatSOL(balance), clients.FormatSOL(minBalance))
+			}
+			return checkFunded(solanaClient.GetPayerAddress().String(), new(big.Int).SetUint64(balance), "lamports")
+		}},
+	}
+}
+
+// cosmosStatusChecks checks the LCD endpoint reports the configured chain ID and the signer has funds
+func cosmosStatusChecks(logger *zap.Logger, config CosmosConfig) []statusCheck {
+	var cosmosClient *clients.CosmosClient
+	return []statusCheck{
+		{name: "cosmos lcd", run: func(ctx context.Context) (string, error) {
+			var err error
+			cosmosClient, err = clients.NewCosmosClient(logger, clients.CosmosClientConfig{
+				LCDURL:       config.CosmosLCDURL,
+				ChainID:      config.CosmosChainID,
+				PrivateKey:   config.CosmosPrivateKey,
+				Bech32Prefix: config.CosmosBech32Prefix,
+				GasLimit:     config.CosmosGasLimit,
+				FeeAmount:    config.CosmosFeeAmount,
+				FeeDenom:     config.CosmosFeeDenom,
+			})
+			if err != nil {
+				return "", err
+			}
+
+			chainID, err := cosmosClient.NodeChainID(ctx)
+			if err != nil {
+				return "", fmt.Errorf("%s: failed to get chain ID: %v", config.CosmosLCDURL, err)
+			}
+			if config.CosmosChainID != "" && chainID != config.CosmosChainID {
+				return "", fmt.Errorf("%s reports chain ID %q, expected %q", config.CosmosLCDURL, chainID, config.CosmosChainID)
+			}
+			return fmt.Sprintf("%s, chain ID %s", config.CosmosLCDURL, chainID), nil
+		}},
+		{name: "cosmos signer balance", run: func(ctx context.Context) (string, error) {
+			if cosmosClient == nil {
+				return "", fmt.Errorf("skipped: Cosmos client unavailable")
+			}
+			balance, err := cosmosClient.Balance(ctx)
+			if err != nil {
+				return "", fmt.Errorf("failed to get balance: %v", err)
+			}
+			return checkFunded(cosmosClient.GetAddress(), balance, config.CosmosFeeDenom)
+		}},
+	}
+}
+
+// aztecStatusChecks checks the verification service and the PXE
+func aztecStatusChecks(logger *zap.Logger, config AztecConfig) []statusCheck {
+	return []statusCheck{
+		{name: "verification service", run: func(ctx context.Context) (string, error) {
+			verificationService := clients.NewVerificationServiceClientWithConfig(logger, clients.VerificationServiceConfig{
+				URL:  config.VerificationServiceURL,
+				Auth: verificationAuth(config),
+			})
+			if err := verificationService.CheckHealth(ctx); err != nil {
+				return "", fmt.Errorf("%s: %v", config.VerificationServiceURL, err)
+			}
+			return config.VerificationServiceURL + " healthy", nil
+		}},
+		{name: "aztec pxe", run: func(ctx context.Context) (string, error) {
+			pxeClient, err := clients.NewAztecPXEClient(logger, config.AztecPXEURL, config.AztecWalletAddress)
+			if err != nil {
+				return "", err
+			}
+			blockNumber, err := pxeClient.GetBlockNumber(ctx)
+			if err != nil {
+				return "", fmt.Errorf("%s: %v", config.AztecPXEURL, err)
+			}
+			return fmt.Sprintf("%s at block %d", config.AztecPXEURL, blockNumber), nil
+		}},
+	}
+}
+
+// checkFunded fails when an account has no balance to pay for transactions
+func checkFunded(account string, balance *big.Int, unit string) (string, error) {
+	if balance.Sign() <= 0 {
+		return "", fmt.Errorf("%s has no funds", account)
+	}
+	return fmt.Sprintf("%s has %s %s", account, balance, unit), nil
+}
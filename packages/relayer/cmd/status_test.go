@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestRunStatusChecks(t *testing.T) {
+	checks := []statusCheck{
+		{name: "ok", run: func(ctx context.Context) (string, error) { return "fine", nil }},
+		{name: "broken", run: func(ctx context.Context) (string, error) { return "", errors.New("connection refused") }},
+		{name: "slow", run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+
+	results := runStatusChecks(context.Background(), checks, 50*time.Millisecond)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if !results[0].ok || results[0].detail != "fine" {
+		t.Errorf("unexpected result for ok check: %+v", results[0])
+	}
+	if results[1].ok || results[1].detail != "connection refused" {
+		t.Errorf("unexpected result for broken check: %+v", results[1])
+	}
+	if results[2].ok {
+		t.Error("expected the slow check to fail once its deadline expires")
+	}
+
+	var out bytes.Buffer
+	if failed := printStatusTable(&out, results); failed != 2 {
+		t.Errorf("expected 2 failed checks, got %d", failed)
+	}
+	table := out.String()
+	for _, want := range []string{"CHECK", "ok      OK      fine", "broken  FAIL    connection refused"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("expected table to contain %q, got:\n%s", want, table)
+		}
+	}
+}
+
+// newEVMRPCServer serves eth_chainId and eth_getBalance with the given values
+func newEVMRPCServer(t *testing.T, chainID, balance string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		switch req.Method {
+		case "eth_chainId":
+			response["result"] = chainID
+		case "eth_getBalance":
+			response["result"] = balance
+		default:
+			response["error"] = map[string]interface{}{"code": -32601, "message": "method not found"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEVMStatusChecks(t *testing.T) {
+	chainConfig := EVMChainConfigs["base"]
+	tests := []struct {
+		name       string
+		chainID    string
+		balance    string
+		rpcOK      bool
+		balanceOK  bool
+		wantDetail string
+	}{
+		{name: "healthy", chainID: "0x14a34", balance: "0xde0b6b3a7640000", rpcOK: true, balanceOK: true},
+		{name: "wrong chain", chainID: "0x66eee", balance: "0x1", rpcOK: false, balanceOK: true, wantDetail: "expected 84532"},
+		{name: "unfunded", chainID: "0x14a34", balance: "0x0", rpcOK: true, balanceOK: false, wantDetail: "has no funds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newEVMRPCServer(t, tt.chainID, tt.balance)
+			config := EVMConfig{
+				ChainName:  "base",
+				EVMRPCURL:  server.URL,
+				PrivateKey: testHexKey(t),
+				RPCRetry:   clients.RetryConfig{MaxAttempts: 1},
+			}
+
+			results := runStatusChecks(context.Background(), evmStatusChecks(zap.NewNop(), config, chainConfig), time.Second)
+			if len(results) != 2 {
+				t.Fatalf("expected 2 results, got %d", len(results))
+			}
+			if results[0].ok != tt.rpcOK || results[1].ok != tt.balanceOK {
+				t.Fatalf("unexpected results: %+v", results)
+			}
+			if tt.wantDetail != "" && !strings.Contains(results[0].detail+results[1].detail, tt.wantDetail) {
+				t.Errorf("expected a detail containing %q, got %+v", tt.wantDetail, results)
+			}
+		})
+	}
+}
+
+func TestAztecStatusChecks(t *testing.T) {
+	verificationService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer verificationService.Close()
+
+	results := runStatusChecks(context.Background(), aztecStatusChecks(zap.NewNop(), AztecConfig{
+		AztecPXEURL:            unreachableURL,
+		AztecWalletAddress:     DefaultAztecWalletAddress,
+		VerificationServiceURL: verificationService.URL,
+	}), time.Second)
+
+	if !results[0].ok {
+		t.Errorf("expected the verification service check to pass, got %+v", results[0])
+	}
+	if results[1].ok {
+		t.Errorf("expected the PXE check to fail for an unreachable PXE, got %+v", results[1])
+	}
+}
+
+func TestBuildStatusChecksUnsupportedChain(t *testing.T) {
+	if _, err := buildStatusChecks(statusCmd, zap.NewNop(), "ethereum"); err == nil {
+		t.Fatal("expected an error for an unsupported chain")
+	}
+}
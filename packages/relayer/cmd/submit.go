@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal"
+	"github.com/wormhole-demo/relayer/internal/buildinfo"
+)
+
+// submitCmd replays a single VAA, e.g. one copied out of a failure log
+var submitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Submit one VAA given as hex, e.g. the rawHex of a logged failure",
+	Long: `Replays a single signed VAA to --chain. The VAA is given as hex with --vaa, or read
+from --vaa-file ("-" for stdin), in the format the relayer logs it: the rawHex field of
+"Full VAA Details" (see --log-vaa-on-failure) can be pasted as is. A 0x prefix, surrounding
+quotes and whitespace, including line breaks, are accepted.
+
+The VAA goes through the same checks as one from the spy, so a VAA the destination already
+processed is reported as such, not as a failure. The command exits non-zero if the VAA fails
+or is filtered. Pass the same destination flags as the relay command.`,
+	Example:      `  wormhole-relayer submit --chain base --vaa 0x01000000... --private-key-file key.hex --evm-target-contract 0x...`,
+	SilenceUsage: true,
+	RunE:         runSubmit,
+}
+
+func init() {
+	rootCmd.AddCommand(submitCmd)
+
+	submitCmd.Flags().String(
+		"chain",
+		"",
+		"Destination to submit to (aztec, solana, cosmos, arbitrum, base)")
+
+	submitCmd.Flags().String(
+		"vaa",
+		"",
+		"VAA to submit, as hex (0x prefix and whitespace allowed)")
+
+	submitCmd.Flags().String(
+		"vaa-file",
+		"",
+		"File holding the VAA hex, or - for stdin (instead of --vaa)")
+
+	submitCmd.Flags().Duration(
+		"submission-timeout",
+		0,
+		"Deadline for submitting the VAA (0 = the destination's relay command default)")
+
+	// Every destination's flags are accepted; only those of --chain are used
+	registerAztecFlags(submitCmd)
+	registerSolanaFlags(submitCmd)
+	registerCosmosFlags(submitCmd)
+	registerEVMFlags(submitCmd)
+
+	submitCmd.MarkFlagRequired("chain")
+	submitCmd.MarkFlagsMutuallyExclusive("vaa", "vaa-file")
+}
+
+func runSubmit(cmd *cobra.Command, args []string) error {
+	logger := configureLogging(cmd, args)
+	logger.Info("Starting submit", buildinfo.Get().Fields()...)
+
+	chain, _ := cmd.Flags().GetString("chain")
+	vaaHex, _ := cmd.Flags().GetString("vaa")
+	vaaFile, _ := cmd.Flags().GetString("vaa-file")
+	submissionTimeout, _ := cmd.Flags().GetDuration("submission-timeout")
+
+	// Validate the VAA before connecting to anything
+	input, err := readSubmitInput(cmd.InOrStdin(), vaaHex, vaaFile)
+	if err != nil {
+		return err
+	}
+	vaaData, err := internal.ParseVAAHex(input)
+	if err != nil {
+		return err
+	}
+
+	target, err := backfillTarget(cmd, chain)
+	if err != nil {
+		return err
+	}
+	if submissionTimeout == 0 {
+		submissionTimeout = target.defaultTimeout
+	}
+
+	logger.Info("Submitting VAA",
+		zap.String("destination", chain),
+		zap.Uint16("destinationChainID", target.chainID),
+		zap.Stringer("vaa", vaaData.ID),
+		zap.String("emitter", internal.FormatEmitter(vaaData.ChainID, vaaData.EmitterHex)),
+		zap.Duration("submissionTimeout", submissionTimeout))
+
+	vaaSubmitter, err := target.build(logger)
+	if err != nil {
+		return err
+	}
+	processor, err := internal.NewDefaultVAAProcessor(logger,
+		internal.VAAProcessorConfig{
+			ChainIDs:           []uint16{vaaData.ChainID},
+			DestinationChainID: target.chainID,
+			SubmissionTimeout:  submissionTimeout,
+			ShortPayloads:      shortPayloadPolicy(target.chainID),
+		},
+		vaaSubmitter)
+	if err != nil {
+		return fmt.Errorf("invalid VAA processor configuration: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result := internal.ReplayVAA(ctx, processor, *vaaData)
+	return printSubmitResult(cmd.OutOrStdout(), result)
+}
+
+// readSubmitInput returns the VAA hex given with --vaa, or read from --vaa-file ("-" = stdin)
+func readSubmitInput(stdin io.Reader, vaaHex, vaaFile string) (string, error) {
+	switch {
+	case vaaHex != "":
+		return vaaHex, nil
+	case vaaFile == "-":
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read VAA from stdin: %v", err)
+		}
+		return string(data), nil
+	case vaaFile != "":
+		data, err := os.ReadFile(vaaFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read --vaa-file: %v", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("--vaa or --vaa-file is required")
+	}
+}
+
+// printSubmitResult writes the outcome of a replayed VAA, returning an error unless it was
+// delivered or already processed
+func printSubmitResult(w io.Writer, result internal.ReplayResult) error {
+	switch result.Decision {
+	case internal.DecisionSubmitted:
+		fmt.Fprintf(w, "%s submitted: %s\n", result.ID, result.TxHash)
+	case internal.DecisionAlreadyProcessed:
+		fmt.Fprintf(w, "%s already processed\n", result.ID)
+	case internal.DecisionFiltered:
+		fmt.Fprintf(w, "%s filtered\n", result.ID)
+		return fmt.Errorf("VAA %s was filtered and not submitted; see the log for the reason", result.ID)
+	default:
+		fmt.Fprintf(w, "%s failed: %v\n", result.ID, result.Err)
+		return fmt.Errorf("VAA %s failed: %w", result.ID, result.Err)
+	}
+	return nil
+}
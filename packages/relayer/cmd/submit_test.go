@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal"
+)
+
+func TestReadSubmitInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vaa.hex")
+	if err := os.WriteFile(path, []byte("0x01ab\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tt := range map[string]struct {
+		vaa, file string
+		want      string
+	}{
+		"flag":  {vaa: "0x01ab", want: "0x01ab"},
+		"file":  {file: path, want: "0x01ab\n"},
+		"stdin": {file: "-", want: "01ab cd\n"},
+	} {
+		got, err := readSubmitInput(strings.NewReader("01ab cd\n"), tt.vaa, tt.file)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: expected %q, got %q (%v)", name, tt.want, got, err)
+		}
+	}
+	if _, err := readSubmitInput(strings.NewReader(""), "", ""); err == nil {
+		t.Error("expected an error without --vaa or --vaa-file")
+	}
+}
+
+func TestPrintSubmitResult(t *testing.T) {
+	id := internal.NewVAAIdentity(2, strings.Repeat("0", 64), 7)
+	for name, tt := range map[string]struct {
+		result  internal.ReplayResult
+		want    string
+		wantErr bool
+	}{
+		"submitted":         {result: internal.ReplayResult{ID: id, Decision: internal.DecisionSubmitted, TxHash: "0xabc"}, want: "submitted: 0xabc"},
+		"already processed": {result: internal.ReplayResult{ID: id, Decision: internal.DecisionAlreadyProcessed}, want: "already processed"},
+		"filtered":          {result: internal.ReplayResult{ID: id, Decision: internal.DecisionFiltered}, want: "filtered", wantErr: true},
+		"failed":            {result: internal.ReplayResult{ID: id, Decision: internal.DecisionFailed, Err: errors.New("reverted")}, want: "failed: reverted", wantErr: true},
+	} {
+		var out bytes.Buffer
+		err := printSubmitResult(&out, tt.result)
+		if (err != nil) != tt.wantErr || !strings.Contains(out.String(), tt.want) || !strings.HasPrefix(out.String(), id.String()) {
+			t.Errorf("%s: expected %q (error %v), got %q (%v)", name, tt.want, tt.wantErr, out.String(), err)
+		}
+	}
+}
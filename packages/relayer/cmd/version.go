@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/wormhole-demo/relayer/internal/buildinfo"
+)
+
+// versionCmd prints the build info of the relayer binary
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the relayer's version, commit, build date and Go version",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Fprint(cmd.OutOrStdout(), buildinfo.Get())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RelayEvent is the audit record of a single handled VAA
+type RelayEvent struct {
+	HandledAt          time.Time `json:"handledAt"`
+	VAA                string    `json:"vaa"` // Identity of the VAA as chain/emitter/sequence (see VAAIdentity)
+	ChainID            uint16    `json:"chainId"`
+	Emitter            string    `json:"emitter"`
+	Sequence           uint64    `json:"sequence"`
+	SourceTxID         string    `json:"sourceTxId,omitempty"`
+	DestinationChainID *uint16   `json:"destinationChainId,omitempty"` // nil if the payload has no destination
+	Decision           string    `json:"decision"`
+	TxHash             string    `json:"txHash,omitempty"`
+	Error              string    `json:"error,omitempty"`
+	DurationMs         int64     `json:"durationMs"` // Time spent in the processor, including the submission
+}
+
+// EventSink records an audit trail of handled VAAs
+type EventSink interface {
+	// Record writes a single event. Implementations may buffer it until Close.
+	Record(event RelayEvent) error
+	// Close flushes buffered events and releases the sink
+	Close() error
+}
+
+// JSONLSink writes one JSON event per line through a buffer. It is safe for concurrent use.
+type JSONLSink struct {
+	mu     sync.Mutex
+	buf    *bufio.Writer
+	closer io.Closer // nil when the underlying writer is not owned by the sink
+}
+
+// NewJSONLSink creates a sink writing to w. Closing the sink flushes it but does not close w.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{buf: bufio.NewWriter(w)}
+}
+
+// NewAuditLogSink opens the audit log at path for appending, creating it if needed.
+// A path of "-" writes to stdout.
+func NewAuditLogSink(path string) (*JSONLSink, error) {
+	if path == "-" {
+		return NewJSONLSink(os.Stdout), nil
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %v", err)
+	}
+	sink := NewJSONLSink(file)
+	sink.closer = file
+	return sink, nil
+}
+
+// Record buffers event as a single JSON line
+func (s *JSONLSink) Record(event RelayEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.buf.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit event: %v", err)
+	}
+	return nil
+}
+
+// Close flushes buffered events and closes the audit log file, if the sink opened one
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.buf.Flush()
+	if s.closer != nil {
+		if closeErr := s.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("close audit log: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestJSONLSinkBuffersUntilClose(t *testing.T) {
+	var out bytes.Buffer
+	sink := NewJSONLSink(&out)
+
+	var wg sync.WaitGroup
+	for seq := uint64(1); seq <= 20; seq++ {
+		wg.Add(1)
+		go func(seq uint64) {
+			defer wg.Done()
+			if err := sink.Record(RelayEvent{Sequence: seq, Decision: DecisionSubmitted}); err != nil {
+				t.Errorf("Record failed: %v", err)
+			}
+		}(seq)
+	}
+	wg.Wait()
+
+	if out.Len() != 0 {
+		t.Fatalf("expected events to be buffered until Close, got %q", out.String())
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		var event RelayEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Decision != DecisionSubmitted {
+			t.Fatalf("expected a submitted event, got %q (err %v)", line, err)
+		}
+	}
+}
+
+func TestAuditLogSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	for seq := uint64(1); seq <= 2; seq++ {
+		sink, err := NewAuditLogSink(path)
+		if err != nil {
+			t.Fatalf("NewAuditLogSink failed: %v", err)
+		}
+		if err := sink.Record(RelayEvent{Sequence: seq}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("expected both runs to append a line, got %q", data)
+	}
+
+	if _, err := NewAuditLogSink(filepath.Join(t.TempDir(), "missing", "audit.jsonl")); err == nil {
+		t.Error("expected an error for an audit log in a missing directory")
+	}
+}
+
+// recordingSink keeps every recorded event in memory
+type recordingSink struct {
+	events []RelayEvent
+}
+
+func (s *recordingSink) Record(event RelayEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestRelayerRecordEvent(t *testing.T) {
+	relayer, _ := NewRelayer(zap.NewNop(), nil, nil)
+	sink := &recordingSink{}
+	relayer.SetEventSink(sink)
+
+	payload := make([]byte, 18)
+	payload[0], payload[1] = 0x27, 0x13 // Destination 10003
+	submitted := testVAADataWithPayload(payload)
+	relayer.recordEvent(&submitted, time.Now().Add(-time.Second), "0xabc", nil)
+
+	short := testVAADataWithPayload(make([]byte, 10))
+	relayer.recordEvent(&short, time.Now(), "", errors.New("boom"))
+
+	if len(sink.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(sink.events))
+	}
+	first, second := sink.events[0], sink.events[1]
+	if first.Decision != DecisionSubmitted || first.TxHash != "0xabc" || first.ChainID != 2 || first.Sequence != 1 {
+		t.Errorf("unexpected submitted event %+v", first)
+	}
+	if first.DestinationChainID == nil || *first.DestinationChainID != 10003 {
+		t.Errorf("expected destination 10003, got %v", first.DestinationChainID)
+	}
+	if first.DurationMs < 1000 {
+		t.Errorf("expected a duration of at least 1s, got %dms", first.DurationMs)
+	}
+	if second.Decision != DecisionFailed || second.Error != "boom" || second.DestinationChainID != nil {
+		t.Errorf("unexpected failed event %+v", second)
+	}
+}
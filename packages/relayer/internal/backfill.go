@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// VAAFetcher fetches a signed VAA by its identity, e.g. from Wormholescan
+type VAAFetcher interface {
+	GetVAA(ctx context.Context, chainID uint16, emitter string, sequence uint64) ([]byte, error)
+}
+
+// BackfillConfig selects the VAAs to backfill: sequences FromSequence to ToSequence, inclusive,
+// of one emitter
+type BackfillConfig struct {
+	ChainID         uint16 // Source chain ID
+	Emitter         string // Normalized emitter address (see ValidateEmitterAddress)
+	FromSequence    uint64
+	ToSequence      uint64
+	ContinueOnError bool // Keep going after a sequence fails instead of stopping
+	// Sequences submitted together when the processor is a VAABatchProcessor (0 or 1 = one at a time).
+	// A batch counts as one submission against the rate limit and shares one submission deadline.
+	BatchSize int
+}
+
+// VAABatchProcessor is a VAAProcessor that can process several VAAs together
+type VAABatchProcessor interface {
+	VAAProcessor
+	// ProcessVAABatch processes vaas and returns the transaction hash and error of each, in order
+	ProcessVAABatch(ctx context.Context, vaas []VAAData) ([]string, []error)
+}
+
+// BackfillResult is the outcome of backfilling a single sequence
+type BackfillResult struct {
+	ID       VAAIdentity // Identity of the requested VAA
+	Sequence uint64
+	Decision string // One of the Decision* values
+	TxHash   string
+	Err      error
+}
+
+// Backfill fetches each VAA of config's range in sequence order and runs it through processor,
+// which applies its filters, rate limit and submission timeout. A VAA the destination already
+// processed is not a failure. Unless ContinueOnError is set, it stops after the first failed
+// sequence; it always stops when ctx is done. It returns the result of every attempted sequence.
+//
+// With a BatchSize above 1 and a processor that is a VAABatchProcessor, the range is fetched
+// and submitted BatchSize sequences at a time; a failure then stops after the batch it is in.
+func Backfill(ctx context.Context, logger *zap.Logger, fetcher VAAFetcher, processor VAAProcessor, config BackfillConfig) []BackfillResult {
+	if batcher, ok := processor.(VAABatchProcessor); ok && config.BatchSize > 1 {
+		return backfillBatches(ctx, logger, fetcher, batcher, config)
+	}
+
+	var results []BackfillResult
+	for seq := config.FromSequence; seq <= config.ToSequence; seq++ {
+		if ctx.Err() != nil {
+			logger.Warn("Backfill interrupted", zap.Uint64("nextSequence", seq))
+			break
+		}
+
+		result := backfillSequence(ctx, fetcher, processor, config, seq)
+		results = append(results, result)
+		logBackfillResult(logger, result)
+		if result.Err != nil && !config.ContinueOnError {
+			break
+		}
+
+		// Guard against wrapping when the range ends at the largest sequence
+		if seq == config.ToSequence {
+			break
+		}
+	}
+	return results
+}
+
+// backfillBatches backfills config's range BatchSize sequences at a time: each batch is fetched
+// in sequence order, then the VAAs fetched are processed together
+func backfillBatches(ctx context.Context, logger *zap.Logger, fetcher VAAFetcher, processor VAABatchProcessor, config BackfillConfig) []BackfillResult {
+	var results []BackfillResult
+	for start := config.FromSequence; ; {
+		if ctx.Err() != nil {
+			logger.Warn("Backfill interrupted", zap.Uint64("nextSequence", start))
+			break
+		}
+		end := config.ToSequence
+		if end-start >= uint64(config.BatchSize) {
+			end = start + uint64(config.BatchSize) - 1
+		}
+
+		batch := make([]BackfillResult, 0, end-start+1)
+		var vaas []VAAData
+		var fetched []int // Index in batch of each VAA in vaas
+		for seq := start; ; seq++ {
+			result := BackfillResult{ID: NewVAAIdentity(config.ChainID, config.Emitter, seq), Sequence: seq, Decision: DecisionFailed}
+			vaaData, err := fetchSequence(ctx, fetcher, config, seq)
+			if err != nil {
+				result.Err = err
+			} else {
+				vaas = append(vaas, *vaaData)
+				fetched = append(fetched, len(batch))
+			}
+			batch = append(batch, result)
+			if seq == end {
+				break
+			}
+		}
+
+		if len(vaas) > 0 {
+			txHashes, errs := processor.ProcessVAABatch(ctx, vaas)
+			for j, i := range fetched {
+				batch[i].Decision = vaaDecision(txHashes[j], errs[j])
+				batch[i].TxHash = txHashes[j]
+				if batch[i].Decision == DecisionFailed {
+					batch[i].Err = errs[j]
+				}
+			}
+		}
+
+		failed := false
+		for _, result := range batch {
+			logBackfillResult(logger, result)
+			failed = failed || result.Err != nil
+		}
+		results = append(results, batch...)
+		if (failed && !config.ContinueOnError) || end == config.ToSequence {
+			break
+		}
+		start = end + 1
+	}
+	return results
+}
+
+// logBackfillResult logs the outcome of backfilling one sequence
+func logBackfillResult(logger *zap.Logger, result BackfillResult) {
+	fields := []zap.Field{
+		zap.Stringer("vaa", result.ID),
+		zap.String("decision", result.Decision),
+		zap.String("txHash", result.TxHash),
+	}
+	if result.Err != nil {
+		logger.Error("Backfill failed for sequence", append(fields, zap.Error(result.Err))...)
+	} else {
+		logger.Info("Backfilled sequence", fields...)
+	}
+}
+
+// backfillSequence fetches and processes a single sequence
+func backfillSequence(ctx context.Context, fetcher VAAFetcher, processor VAAProcessor, config BackfillConfig, seq uint64) BackfillResult {
+	result := BackfillResult{ID: NewVAAIdentity(config.ChainID, config.Emitter, seq), Sequence: seq, Decision: DecisionFailed}
+
+	vaaData, err := fetchSequence(ctx, fetcher, config, seq)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	txHash, err := processor.ProcessVAA(ctx, *vaaData)
+	result.Decision = vaaDecision(txHash, err)
+	result.TxHash = txHash
+	if result.Decision == DecisionFailed {
+		result.Err = err
+	}
+	return result
+}
+
+// fetchSequence fetches and parses the VAA of one sequence of config's emitter
+func fetchSequence(ctx context.Context, fetcher VAAFetcher, config BackfillConfig, seq uint64) (*VAAData, error) {
+	vaaBytes, err := fetcher.GetVAA(ctx, config.ChainID, config.Emitter, seq)
+	if err != nil {
+		return nil, fmt.Errorf("fetch VAA: %w", err)
+	}
+	return parseFetchedVAA(vaaBytes, config.ChainID, config.Emitter, seq)
+}
+
+// parseFetchedVAA parses a VAA fetched by identity, rejecting one other than the VAA asked for
+func parseFetchedVAA(vaaBytes []byte, chainID uint16, emitter string, seq uint64) (*VAAData, error) {
+	wormholeVAA, err := ParseVAAPermissive(vaaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse VAA: %w", err)
+	}
+	vaaData := NewVAAData(wormholeVAA, vaaBytes)
+	if vaaData.ChainID != chainID || vaaData.EmitterHex != emitter || vaaData.Sequence != seq {
+		return nil, fmt.Errorf("fetched VAA %s does not match the requested %s",
+			vaaData.ID, NewVAAIdentity(chainID, emitter, seq))
+	}
+	return vaaData, nil
+}
@@ -0,0 +1,215 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+	"go.uber.org/zap"
+)
+
+// fakeFetcher serves the VAAs in vaas by sequence, failing the others
+type fakeFetcher struct {
+	vaas map[uint64][]byte
+}
+
+func (f *fakeFetcher) GetVAA(ctx context.Context, chainID uint16, emitter string, sequence uint64) ([]byte, error) {
+	if vaaBytes, ok := f.vaas[sequence]; ok {
+		return vaaBytes, nil
+	}
+	return nil, fmt.Errorf("sequence %d: %w", sequence, clients.ErrVAANotFound)
+}
+
+// sequenceSubmitter returns the error in failures for a VAA's sequence, and submits the others
+type sequenceSubmitter struct {
+	submitted []uint64
+	failures  map[uint64]error
+}
+
+func (s *sequenceSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	vaa, err := ParseVAAPermissive(vaaBytes)
+	if err != nil {
+		return "", err
+	}
+	if err := s.failures[vaa.Sequence]; err != nil {
+		return "", err
+	}
+	s.submitted = append(s.submitted, vaa.Sequence)
+	return fmt.Sprintf("0x%d", vaa.Sequence), nil
+}
+
+func TestBackfill(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xab
+	fetcher := &fakeFetcher{vaas: make(map[uint64][]byte)}
+	for seq := uint64(1); seq <= 5; seq++ {
+		if seq != 4 { // Sequence 4 cannot be fetched
+			fetcher.vaas[seq] = buildV1VAA(1, 2, emitter, seq, destinationPayload(10003))
+		}
+	}
+	fetcher.vaas[6] = buildV1VAA(1, 2, emitter, 7, destinationPayload(10003)) // Wrong sequence served
+
+	config := BackfillConfig{ChainID: 2, Emitter: NormalizeEmitter(emitter[:]), FromSequence: 1, ToSequence: 6}
+	newProcessor := func() (*DefaultVAAProcessor, *sequenceSubmitter) {
+		s := &sequenceSubmitter{failures: map[uint64]error{2: fmt.Errorf("nonce too low: %w", submitter.ErrAlreadyProcessed)}}
+		p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+		if err != nil {
+			t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+		}
+		return p, s
+	}
+
+	t.Run("stops on the first failure", func(t *testing.T) {
+		p, s := newProcessor()
+		results := Backfill(context.Background(), zap.NewNop(), fetcher, p, config)
+		if len(results) != 4 {
+			t.Fatalf("expected to stop after sequence 4, got %d results", len(results))
+		}
+		want := []string{DecisionSubmitted, DecisionAlreadyProcessed, DecisionSubmitted, DecisionFailed}
+		for i, result := range results {
+			if result.Sequence != uint64(i+1) || result.Decision != want[i] {
+				t.Errorf("result %d: expected sequence %d %s, got %+v", i, i+1, want[i], result)
+			}
+		}
+		if results[0].TxHash != "0x1" || results[1].Err != nil || results[3].Err == nil {
+			t.Errorf("unexpected results %+v", results)
+		}
+		if len(s.submitted) != 2 {
+			t.Errorf("expected sequences 1 and 3 to be submitted, got %v", s.submitted)
+		}
+	})
+
+	t.Run("continues on error", func(t *testing.T) {
+		p, _ := newProcessor()
+		continued := config
+		continued.ContinueOnError = true
+		results := Backfill(context.Background(), zap.NewNop(), fetcher, p, continued)
+		if len(results) != 6 {
+			t.Fatalf("expected every sequence to be attempted, got %d results", len(results))
+		}
+		if results[4].Decision != DecisionSubmitted || results[5].Decision != DecisionFailed {
+			t.Errorf("expected sequence 5 submitted and the mismatched sequence 6 failed, got %+v", results[4:])
+		}
+	})
+
+	t.Run("stops when cancelled", func(t *testing.T) {
+		p, _ := newProcessor()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if results := Backfill(ctx, zap.NewNop(), fetcher, p, config); len(results) != 0 {
+			t.Errorf("expected no sequences to be attempted, got %d", len(results))
+		}
+	})
+}
+
+func TestBackfillLastSequence(t *testing.T) {
+	var emitter [32]byte
+	const last = ^uint64(0)
+	fetcher := &fakeFetcher{vaas: map[uint64][]byte{last: buildV1VAA(1, 2, emitter, last, destinationPayload(10003))}}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{}, &countingSubmitter{})
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	config := BackfillConfig{ChainID: 2, Emitter: NormalizeEmitter(emitter[:]), FromSequence: last, ToSequence: last}
+	results := Backfill(context.Background(), zap.NewNop(), fetcher, p, config)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Errorf("expected the last sequence to be backfilled once, got %+v", results)
+	}
+}
+
+// batchSequenceSubmitter submits each batch like sequenceSubmitter, recording the batch sizes
+type batchSequenceSubmitter struct {
+	sequenceSubmitter
+	batches []int
+}
+
+func (s *batchSequenceSubmitter) SubmitVAABatch(ctx context.Context, vaas [][]byte) []submitter.BatchResult {
+	s.batches = append(s.batches, len(vaas))
+	results := make([]submitter.BatchResult, len(vaas))
+	for i, vaaBytes := range vaas {
+		results[i].TxHash, results[i].Err = s.SubmitVAA(ctx, vaaBytes)
+	}
+	return results
+}
+
+func TestBackfillBatches(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xab
+	fetcher := &fakeFetcher{vaas: make(map[uint64][]byte)}
+	for seq := uint64(1); seq <= 7; seq++ {
+		if seq != 4 { // Sequence 4 cannot be fetched
+			fetcher.vaas[seq] = buildV1VAA(1, 2, emitter, seq, destinationPayload(10003))
+		}
+	}
+	fetcher.vaas[5] = buildV1VAA(1, 2, emitter, 5, destinationPayload(10004)) // For another destination
+
+	config := BackfillConfig{ChainID: 2, Emitter: NormalizeEmitter(emitter[:]), FromSequence: 1, ToSequence: 7, BatchSize: 3}
+	newProcessor := func() (*DefaultVAAProcessor, *batchSequenceSubmitter) {
+		s := &batchSequenceSubmitter{sequenceSubmitter: sequenceSubmitter{failures: map[uint64]error{2: fmt.Errorf("already in use: %w", submitter.ErrAlreadyProcessed)}}}
+		p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+		if err != nil {
+			t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+		}
+		return p, s
+	}
+
+	t.Run("stops after the failed batch", func(t *testing.T) {
+		p, s := newProcessor()
+		results := Backfill(context.Background(), zap.NewNop(), fetcher, p, config)
+		want := []string{DecisionSubmitted, DecisionAlreadyProcessed, DecisionSubmitted, DecisionFailed, DecisionFiltered, DecisionSubmitted}
+		if len(results) != len(want) {
+			t.Fatalf("expected to stop after the batch of sequences 4-6, got %d results", len(results))
+		}
+		for i, result := range results {
+			if result.Sequence != uint64(i+1) || result.Decision != want[i] {
+				t.Errorf("result %d: expected sequence %d %s, got %+v", i, i+1, want[i], result)
+			}
+		}
+		// Only fetched VAAs for this destination are submitted, one batch at a time
+		if len(s.batches) != 2 || s.batches[0] != 3 || s.batches[1] != 1 {
+			t.Errorf("expected batches of 3 and 1 VAAs, got %v", s.batches)
+		}
+	})
+
+	t.Run("continues on error", func(t *testing.T) {
+		p, s := newProcessor()
+		continued := config
+		continued.ContinueOnError = true
+		results := Backfill(context.Background(), zap.NewNop(), fetcher, p, continued)
+		if len(results) != 7 || results[6].Decision != DecisionSubmitted {
+			t.Fatalf("expected every sequence to be attempted, got %+v", results)
+		}
+		if len(s.batches) != 3 {
+			t.Errorf("expected three batches, got %v", s.batches)
+		}
+	})
+
+	t.Run("submits one at a time without a batch submitter", func(t *testing.T) {
+		s := &sequenceSubmitter{}
+		p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+		if err != nil {
+			t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+		}
+		results := Backfill(context.Background(), zap.NewNop(), fetcher, p, config)
+		if len(results) != 6 || len(s.submitted) != 4 {
+			t.Errorf("expected sequences 1, 2, 3 and 6 submitted one at a time, got %v", s.submitted)
+		}
+	})
+
+	t.Run("ends at the last sequence", func(t *testing.T) {
+		const last = ^uint64(0)
+		fetcher := &fakeFetcher{vaas: map[uint64][]byte{
+			last - 1: buildV1VAA(1, 2, emitter, last-1, destinationPayload(10003)),
+			last:     buildV1VAA(1, 2, emitter, last, destinationPayload(10003)),
+		}}
+		p, s := newProcessor()
+		ranged := config
+		ranged.FromSequence, ranged.ToSequence = last-1, last
+		if results := Backfill(context.Background(), zap.NewNop(), fetcher, p, ranged); len(results) != 2 || len(s.batches) != 1 {
+			t.Errorf("expected the last two sequences backfilled in one batch, got %+v", results)
+		}
+	})
+}
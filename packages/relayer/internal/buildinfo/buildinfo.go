@@ -0,0 +1,101 @@
+// Package buildinfo describes the running build of the relayer.
+//
+// Version, Commit and BuildDate are set at build time with -ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/wormhole-demo/relayer/internal/buildinfo.Version=v1.2.0 \
+//	  -X github.com/wormhole-demo/relayer/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/wormhole-demo/relayer/internal/buildinfo.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// Set with -ldflags at build time
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// Info identifies a build of the relayer
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build info of the running binary. When the commit was not set with
+// -ldflags, the VCS revision Go embeds in module-aware builds is used instead.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit == "unknown" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+// String formats the build info for humans, e.g. for the version command
+func (i Info) String() string {
+	return fmt.Sprintf("version:    %s\ncommit:     %s\nbuild date: %s\ngo version: %s\n",
+		i.Version, i.Commit, i.BuildDate, i.GoVersion)
+}
+
+// Fields returns the build info as log fields
+func (i Info) Fields() []zap.Field {
+	return []zap.Field{
+		zap.String("version", i.Version),
+		zap.String("commit", i.Commit),
+		zap.String("buildDate", i.BuildDate),
+		zap.String("goVersion", i.GoVersion),
+	}
+}
+
+// HealthHandler answers liveness probes with the build info of the running relayer as JSON.
+// If rpcEndpoints is set, the RPC endpoints it returns (keyed by the preferred endpoint, with the
+// one in use as value) are included as rpcEndpoints. Likewise, if circuitBreakers is set, the
+// circuit breaker states it returns (keyed by destination) are included as circuitBreakers;
+// an open breaker leaves the status ok, as the relayer itself is alive.
+func HealthHandler(rpcEndpoints, circuitBreakers func() map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var endpoints, breakers map[string]string
+		if rpcEndpoints != nil {
+			endpoints = rpcEndpoints()
+		}
+		if circuitBreakers != nil {
+			breakers = circuitBreakers()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Status string `json:"status"`
+			Info
+			RPCEndpoints    map[string]string `json:"rpcEndpoints,omitempty"`
+			CircuitBreakers map[string]string `json:"circuitBreakers,omitempty"`
+		}{Status: "ok", Info: Get(), RPCEndpoints: endpoints, CircuitBreakers: breakers})
+	})
+}
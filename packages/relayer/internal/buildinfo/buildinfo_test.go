@@ -0,0 +1,97 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	Version, Commit, BuildDate = "v1.2.0", "abc123", "2024-01-02T03:04:05Z"
+	defer func() { Version, Commit, BuildDate = "dev", "unknown", "unknown" }()
+
+	recorder := httptest.NewRecorder()
+	HealthHandler(nil, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := map[string]string{
+		"status":    "ok",
+		"version":   "v1.2.0",
+		"commit":    "abc123",
+		"buildDate": "2024-01-02T03:04:05Z",
+		"goVersion": runtime.Version(),
+	}
+	for key, value := range want {
+		if body[key] != value {
+			t.Errorf("expected %s = %q, got %q", key, value, body[key])
+		}
+	}
+	if _, ok := body["rpcEndpoints"]; ok {
+		t.Error("expected no rpcEndpoints without failover")
+	}
+	if _, ok := body["circuitBreakers"]; ok {
+		t.Error("expected no circuitBreakers without a circuit breaker")
+	}
+
+	recorder = httptest.NewRecorder()
+	HealthHandler(nil, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405 for POST, got %d", recorder.Code)
+	}
+}
+
+func TestHealthHandlerRPCEndpoints(t *testing.T) {
+	endpoints := func() map[string]string {
+		return map[string]string{"https://primary.example": "https://backup.example"}
+	}
+
+	recorder := httptest.NewRecorder()
+	HealthHandler(endpoints, nil).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var body struct {
+		Status       string            `json:"status"`
+		RPCEndpoints map[string]string `json:"rpcEndpoints"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Status != "ok" || body.RPCEndpoints["https://primary.example"] != "https://backup.example" {
+		t.Errorf("expected the backup endpoint to be reported in use, got %+v", body)
+	}
+}
+
+func TestHealthHandlerCircuitBreakers(t *testing.T) {
+	breakers := func() map[string]string { return map[string]string{"2": "open"} }
+
+	recorder := httptest.NewRecorder()
+	HealthHandler(nil, breakers).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	var body struct {
+		Status          string            `json:"status"`
+		CircuitBreakers map[string]string `json:"circuitBreakers"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if recorder.Code != http.StatusOK || body.Status != "ok" || body.CircuitBreakers["2"] != "open" {
+		t.Errorf("expected the open breaker to be reported with status ok, got %d with %+v", recorder.Code, body)
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	out := Info{Version: "v1.2.0", Commit: "abc123", BuildDate: "today", GoVersion: "go1.23.3"}.String()
+	for _, want := range []string{"v1.2.0", "abc123", "today", "go1.23.3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// DefaultBreakerCooldown is how long an open circuit breaker holds submissions when no
+// cooldown is configured
+const DefaultBreakerCooldown = time.Minute
+
+// Circuit breaker states, as reported on /healthz
+const (
+	BreakerClosed   = "closed"
+	BreakerHalfOpen = "half-open"
+	BreakerOpen     = "open"
+)
+
+// breakerStateValues are the values of the circuit_breaker_state metric by state
+var breakerStateValues = map[string]float64{BreakerClosed: 0, BreakerHalfOpen: 1, BreakerOpen: 2}
+
+// CircuitBreaker stops submissions to a destination that keeps failing. After threshold
+// consecutive failures it opens, holding every submission for the cooldown instead of
+// sending it to a destination that is likely down. It then half-opens, letting one probe
+// submission through: if the probe succeeds the breaker closes and held submissions go
+// ahead, otherwise it opens for another cooldown. Held VAAs wait in Acquire, so none are
+// dropped while the breaker is open. It is safe for concurrent use.
+type CircuitBreaker struct {
+	name      string // Destination chain ID, labelling the metric and /healthz entry
+	threshold int
+	cooldown  time.Duration
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	state    string
+	failures int       // Consecutive failed submissions
+	openedAt time.Time // When the breaker last opened
+	probing  bool      // Whether a half-open probe is in flight
+	changed  chan struct{}
+	now      func() time.Time
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   []*CircuitBreaker
+)
+
+// NewCircuitBreaker creates a closed breaker for the destination name that opens after
+// threshold consecutive failures (threshold must be positive) for cooldown (0 =
+// DefaultBreakerCooldown). Its state is reported by CircuitBreakerStates.
+func NewCircuitBreaker(logger *zap.Logger, name string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	b := &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger.With(zap.String("component", "CircuitBreaker"), zap.String("destination", name)),
+		state:     BreakerClosed,
+		changed:   make(chan struct{}),
+		now:       time.Now,
+	}
+	metrics.CircuitBreakerState.WithLabelValues(name).Set(breakerStateValues[BreakerClosed])
+
+	breakersMu.Lock()
+	breakers = append(breakers, b)
+	breakersMu.Unlock()
+	return b
+}
+
+// CircuitBreakerStates returns the state of every circuit breaker keyed by destination,
+// or nil if none was created
+func CircuitBreakerStates() map[string]string {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	if len(breakers) == 0 {
+		return nil
+	}
+	states := make(map[string]string, len(breakers))
+	for _, b := range breakers {
+		states[b.name] = b.State()
+	}
+	return states
+}
+
+// State returns the breaker's current state. An open breaker whose cooldown has passed
+// reports half-open, as the next submission will probe the destination.
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && !b.now().Before(b.openedAt.Add(b.cooldown)) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Acquire blocks until a submission may go ahead: at once while the breaker is closed, once
+// the cooldown has passed while it is open, and while it is half-open, once the probe has
+// settled. It returns ctx's error if ctx is done first. Every successful Acquire must be
+// followed by Record or Release.
+func (b *CircuitBreaker) Acquire(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		var wait <-chan time.Time
+		switch b.state {
+		case BreakerClosed:
+			b.mu.Unlock()
+			return nil
+		case BreakerOpen:
+			remaining := b.openedAt.Add(b.cooldown).Sub(b.now())
+			if remaining <= 0 {
+				b.setState(BreakerHalfOpen)
+				b.probing = true
+				b.mu.Unlock()
+				b.logger.Info("Circuit breaker half-open, probing the destination")
+				return nil
+			}
+			wait = time.After(remaining)
+		case BreakerHalfOpen:
+			if !b.probing {
+				b.probing = true
+				b.mu.Unlock()
+				return nil
+			}
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Record settles a submission let through by Acquire with its outcome. Transient and
+// unclassified errors count as failures, opening the breaker at the threshold or re-opening it
+// after a failed probe. Anything else means the destination answered, so it closes the breaker
+// like a success: VAAs it had already processed, and permanent or config rejections of one VAA
+// or emitter, which say nothing about the others.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isBreakerFailure(err) {
+		b.failures = 0
+		if b.state != BreakerClosed {
+			b.setState(BreakerClosed)
+			b.logger.Info("Circuit breaker closed, destination recovered")
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.state == BreakerHalfOpen:
+		b.open()
+		b.logger.Warn("Circuit breaker probe failed, re-opening",
+			zap.Duration("cooldown", b.cooldown),
+			zap.Error(err))
+	case b.state == BreakerClosed && b.failures >= b.threshold:
+		b.open()
+		b.logger.Warn("Circuit breaker opened, holding submissions",
+			zap.Int("consecutiveFailures", b.failures),
+			zap.Duration("cooldown", b.cooldown),
+			zap.Error(err))
+	}
+	// Failures of submissions started before the breaker opened leave the cooldown as is
+}
+
+// isBreakerFailure reports whether err counts against the destination: a transient failure, or
+// one no submitter classified
+func isBreakerFailure(err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, submitter.ErrTransient):
+		return true
+	case errors.Is(err, submitter.ErrAlreadyProcessed), errors.Is(err, submitter.ErrPermanent), errors.Is(err, submitter.ErrConfig):
+		return false
+	default:
+		return true
+	}
+}
+
+// Release settles a submission let through by Acquire that says nothing about the
+// destination, e.g. one cut short by shutdown. An unsettled probe is given to the next waiter.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen && b.probing {
+		b.probing = false
+		b.signal()
+	}
+}
+
+// open opens the breaker for a cooldown; b.mu must be held
+func (b *CircuitBreaker) open() {
+	b.openedAt = b.now()
+	b.setState(BreakerOpen)
+}
+
+// setState moves the breaker to state and wakes every waiter; b.mu must be held
+func (b *CircuitBreaker) setState(state string) {
+	b.state = state
+	b.probing = false
+	metrics.CircuitBreakerState.WithLabelValues(b.name).Set(breakerStateValues[state])
+	b.signal()
+}
+
+// signal wakes every goroutine waiting in Acquire; b.mu must be held
+func (b *CircuitBreaker) signal() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
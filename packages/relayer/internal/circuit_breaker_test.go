@@ -0,0 +1,188 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	b := NewCircuitBreaker(zap.NewNop(), "breaker-test", 2, time.Minute)
+	now := time.Unix(1700000000, 0)
+	b.now = func() time.Time { return now }
+	down := fmt.Errorf("%w: connection refused", submitter.ErrTransient)
+
+	acquire := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		return b.Acquire(ctx)
+	}
+
+	// Failures below the threshold, or broken up by a success or a rejection of one VAA, leave
+	// the breaker closed
+	rejected := fmt.Errorf("%w: execution reverted: invalid payload", submitter.ErrPermanent)
+	unregistered := fmt.Errorf("%w: emitter not registered", submitter.ErrConfig)
+	for _, err := range []error{down, nil, down, submitter.ErrAlreadyProcessed, down, rejected, down, unregistered, down} {
+		if err := acquire(); err != nil {
+			t.Fatalf("expected a closed breaker to let submissions through, got %v", err)
+		}
+		b.Record(err)
+	}
+	if state := b.State(); state != BreakerClosed {
+		t.Fatalf("expected the breaker to stay closed, got %s", state)
+	}
+
+	// The threshold-th consecutive failure opens it, holding submissions for the cooldown
+	if err := acquire(); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	b.Record(down)
+	if state := b.State(); state != BreakerOpen {
+		t.Fatalf("expected the breaker to open, got %s", state)
+	}
+	if CircuitBreakerStates()["breaker-test"] != BreakerOpen {
+		t.Fatalf("expected the open breaker to be reported, got %v", CircuitBreakerStates())
+	}
+	if err := acquire(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected an open breaker to hold submissions, got %v", err)
+	}
+
+	// After the cooldown one probe goes through while the others wait for it
+	now = now.Add(time.Minute)
+	if err := acquire(); err != nil {
+		t.Fatalf("expected a probe after the cooldown, got %v", err)
+	}
+	if state := b.State(); state != BreakerHalfOpen {
+		t.Fatalf("expected the breaker to be half-open, got %s", state)
+	}
+	if err := acquire(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a second submission to wait for the probe, got %v", err)
+	}
+
+	// A failed probe re-opens the breaker for another cooldown
+	b.Record(down)
+	if state := b.State(); state != BreakerOpen {
+		t.Fatalf("expected a failed probe to re-open the breaker, got %s", state)
+	}
+
+	// A successful probe closes it and wakes the held submissions
+	now = now.Add(time.Minute)
+	if err := acquire(); err != nil {
+		t.Fatalf("expected a probe after the cooldown, got %v", err)
+	}
+	held := make(chan error, 1)
+	go func() { held <- b.Acquire(context.Background()) }()
+	b.Record(nil)
+	select {
+	case err := <-held:
+		if err != nil {
+			t.Fatalf("expected the held submission to go ahead, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the held submission to go ahead once the breaker closed")
+	}
+	if state := b.State(); state != BreakerClosed {
+		t.Fatalf("expected a successful probe to close the breaker, got %s", state)
+	}
+}
+
+func TestCircuitBreakerRelease(t *testing.T) {
+	b := NewCircuitBreaker(zap.NewNop(), "breaker-release-test", 1, time.Millisecond)
+	b.Record(errors.New("down"))
+	time.Sleep(2 * time.Millisecond)
+
+	// A probe cut short says nothing about the destination, so the next submission probes
+	if err := b.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected a probe after the cooldown, got %v", err)
+	}
+	b.Release()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.Acquire(ctx); err != nil {
+		t.Fatalf("expected the next submission to probe after a release, got %v", err)
+	}
+	if state := b.State(); state != BreakerHalfOpen {
+		t.Fatalf("expected the breaker to stay half-open, got %s", state)
+	}
+}
+
+// flakySubmitter fails every submission with err, if set, and counts the calls
+type flakySubmitter struct {
+	err   error
+	calls int
+}
+
+func (s *flakySubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	s.calls++
+	return "0xabc", s.err
+}
+
+func TestProcessVAACircuitBreaker(t *testing.T) {
+	s := &flakySubmitter{err: fmt.Errorf("%w: 503 Service Unavailable", submitter.ErrTransient)}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{
+		DestinationChainID: 10003,
+		BreakerThreshold:   2,
+		BreakerCooldown:    time.Hour,
+	}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	toArbitrum := make([]byte, 18)
+	toArbitrum[0], toArbitrum[1] = 0x27, 0x13 // Destination 10003
+	for i := 0; i < 2; i++ {
+		if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(toArbitrum)); err == nil {
+			t.Fatalf("expected submission %d to fail", i)
+		}
+	}
+
+	// The breaker is open, so the next VAA waits instead of being submitted
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.ProcessVAA(ctx, testVAADataWithPayload(toArbitrum)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the VAA to be held by the open breaker, got %v", err)
+	}
+	if s.calls != 2 {
+		t.Fatalf("expected no submission while the breaker is open, got %d", s.calls)
+	}
+	if state := CircuitBreakerStates()["10003"]; state != BreakerOpen {
+		t.Fatalf("expected the breaker of destination 10003 to be open, got %q", state)
+	}
+
+	if _, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{BreakerThreshold: -1}, s); err == nil {
+		t.Fatal("expected an error for a negative circuit breaker threshold")
+	}
+}
+
+func TestProcessVAACircuitBreakerIgnoresRejections(t *testing.T) {
+	// A destination rejecting VAA after VAA as invalid is answering, so the breaker stays closed
+	s := &flakySubmitter{err: fmt.Errorf("%w: execution reverted: invalid payload", submitter.ErrPermanent)}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{
+		DestinationChainID: 10004,
+		BreakerThreshold:   2,
+		BreakerCooldown:    time.Hour,
+	}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	toSepolia := make([]byte, 18)
+	toSepolia[0], toSepolia[1] = 0x27, 0x14 // Destination 10004
+	for i := 0; i < 3; i++ {
+		if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(toSepolia)); !errors.Is(err, submitter.ErrPermanent) {
+			t.Fatalf("expected submission %d to be rejected, got %v", i, err)
+		}
+	}
+	if s.calls != 3 {
+		t.Fatalf("expected every VAA to be submitted, got %d submissions", s.calls)
+	}
+	if state := CircuitBreakerStates()["10004"]; state != BreakerClosed {
+		t.Fatalf("expected the breaker of destination 10004 to stay closed, got %q", state)
+	}
+}
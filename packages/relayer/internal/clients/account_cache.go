@@ -0,0 +1,65 @@
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+// DefaultAccountCacheTTL is how long a Solana account seen to exist is remembered
+const DefaultAccountCacheTTL = 30 * time.Second
+
+// accountCache remembers Solana accounts (PDAs) that were seen to exist, so the repeated
+// existence checks made while posting and delivering a VAA cost a single RPC call.
+//
+// Only positive results are cached. A missing account (a VAA not yet posted) is looked up
+// again every time, since it is expected to appear while the caller polls for it. The
+// accounts checked here are never closed, so a positive result only expires to bound memory.
+type accountCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	expires map[solana.PublicKey]time.Time
+	now     func() time.Time
+}
+
+func newAccountCache(ttl time.Duration) *accountCache {
+	if ttl <= 0 {
+		ttl = DefaultAccountCacheTTL
+	}
+	return &accountCache{
+		ttl:     ttl,
+		expires: make(map[solana.PublicKey]time.Time),
+		now:     time.Now,
+	}
+}
+
+// exists reports whether account was recently seen to exist
+func (c *accountCache) exists(account solana.PublicKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiry, ok := c.expires[account]
+	if !ok {
+		return false
+	}
+	if !c.now().Before(expiry) {
+		delete(c.expires, account)
+		return false
+	}
+	return true
+}
+
+// markExists records that account exists, dropping expired entries
+func (c *accountCache) markExists(account solana.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, expiry := range c.expires {
+		if !now.Before(expiry) {
+			delete(c.expires, key)
+		}
+	}
+	c.expires[account] = now.Add(c.ttl)
+}
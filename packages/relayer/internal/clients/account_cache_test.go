@@ -0,0 +1,90 @@
+package clients
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+func TestAccountCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newAccountCache(10 * time.Second)
+	cache.now = func() time.Time { return now }
+
+	account := solana.SystemProgramID
+	if cache.exists(account) {
+		t.Fatal("expected an unknown account not to be cached")
+	}
+
+	cache.markExists(account)
+	now = now.Add(9 * time.Second)
+	if !cache.exists(account) {
+		t.Fatal("expected the account to be cached within the TTL")
+	}
+
+	now = now.Add(time.Second)
+	if cache.exists(account) {
+		t.Fatal("expected the account to expire after the TTL")
+	}
+	if len(cache.expires) != 0 {
+		t.Errorf("expected the expired entry to be dropped, got %d entries", len(cache.expires))
+	}
+}
+
+// newAccountInfoServer serves getAccountInfo, reporting accounts as existing once exists is set,
+// and counts the lookups it receives
+func newAccountInfoServer(t *testing.T, exists *atomic.Bool, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return newJSONRPCServer(t, func(method string) interface{} {
+		if method != "getAccountInfo" {
+			t.Errorf("unexpected method %s", method)
+		}
+		calls.Add(1)
+		if exists.Load() {
+			return solanaAccountInfo([]byte{})
+		}
+		return solanaAccountInfo(nil)
+	})
+}
+
+func TestSolanaAccountExistsCachesPositiveResults(t *testing.T) {
+	var exists atomic.Bool
+	var calls atomic.Int32
+	server := newAccountInfoServer(t, &exists, &calls)
+
+	client := &SolanaClient{
+		client:   rpc.New(server.URL),
+		accounts: newAccountCache(time.Minute),
+		logger:   zap.NewNop(),
+	}
+	account := solana.SystemProgramID
+
+	// A VAA that is not posted yet is looked up on every poll
+	for i := 0; i < 3; i++ {
+		posted, err := client.accountExists(context.Background(), account)
+		if err != nil || posted {
+			t.Fatalf("expected a missing account, got %v (err %v)", posted, err)
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 lookups while the account is missing, got %d", got)
+	}
+
+	// Once posted, the post check and the receive_value check share a single lookup
+	exists.Store(true)
+	for i := 0; i < 5; i++ {
+		posted, err := client.accountExists(context.Background(), account)
+		if err != nil || !posted {
+			t.Fatalf("expected an existing account, got %v (err %v)", posted, err)
+		}
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("expected 1 lookup for the existing account (4 total), got %d total", got)
+	}
+}
@@ -2,7 +2,9 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
@@ -10,6 +12,41 @@ import (
 	"go.uber.org/zap"
 )
 
+// SyntheticTxHashPrefix marks the placeholder hash returned when the PXE response
+// carries no transaction hash; such a transaction cannot be confirmed
+const SyntheticTxHashPrefix = "tx_submitted_"
+
+// ErrAztecTxDropped is returned by WaitForTransaction when the node dropped the transaction
+// before inclusion; unlike a revert, resubmitting the same VAA may succeed
+var ErrAztecTxDropped = errors.New("aztec transaction was dropped")
+
+// Aztec transaction receipt statuses (see the node's TxStatus)
+const (
+	aztecTxStatusSuccess = "success"
+	aztecTxStatusDropped = "dropped"
+)
+
+// AztecConfirmationConfig controls how WaitForTransaction polls for inclusion
+type AztecConfirmationConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"` // Delay between receipt polls
+	Timeout      time.Duration `mapstructure:"timeout"`       // How long to wait for inclusion before giving up
+}
+
+// DefaultAztecConfirmationConfig returns the default inclusion polling settings
+func DefaultAztecConfirmationConfig() AztecConfirmationConfig {
+	return AztecConfirmationConfig{
+		PollInterval: 5 * time.Second,
+		Timeout:      10 * time.Minute,
+	}
+}
+
+// aztecTxReceipt is the subset of the node's tx receipt the relayer needs
+type aztecTxReceipt struct {
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
 // AztecPXEClient handles interactions with Aztec blockchain via PXE
 type AztecPXEClient struct {
 	rpcClient     *rpc.Client
@@ -133,7 +170,80 @@ func (c *AztecPXEClient) SendVerifyTransaction(ctx context.Context, targetContra
 	}
 
 	c.logger.Debug("PXE transaction result", zap.Any("result", txResult))
-	return fmt.Sprintf("tx_submitted_%d", time.Now().Unix()), nil
+	return fmt.Sprintf("%s%d", SyntheticTxHashPrefix, time.Now().Unix()), nil
+}
+
+// WaitForTransaction polls the node until txHash is included in a block, returning the
+// block number. It fails if the transaction is dropped or reverted, if the hash is a
+// synthetic placeholder, or if it is not included within config.Timeout.
+func (c *AztecPXEClient) WaitForTransaction(ctx context.Context, txHash string, config AztecConfirmationConfig) (uint64, error) {
+	if txHash == "" || strings.HasPrefix(txHash, SyntheticTxHashPrefix) {
+		return 0, fmt.Errorf("cannot confirm transaction: no transaction hash was returned (got %q)", txHash)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	c.logger.Debug("Waiting for Aztec transaction inclusion",
+		zap.String("txHash", txHash),
+		zap.Duration("pollInterval", config.PollInterval),
+		zap.Duration("timeout", config.Timeout))
+
+	for {
+		var receipt aztecTxReceipt
+		err := c.rpcClient.CallContext(ctx, &receipt, "node_getTxReceipt", txHash)
+		switch {
+		case err != nil:
+			// The node may not know the tx yet; keep polling until the deadline
+			c.logger.Debug("Failed to get Aztec tx receipt, retrying", zap.String("txHash", txHash), zap.Error(err))
+		case receipt.Status == aztecTxStatusSuccess && receipt.BlockNumber > 0:
+			// Make sure the block is actually served by the node
+			var block interface{}
+			if err := c.rpcClient.CallContext(ctx, &block, "node_getBlock", receipt.BlockNumber); err != nil || block == nil {
+				c.logger.Debug("Block for Aztec tx not available yet, retrying",
+					zap.String("txHash", txHash),
+					zap.Uint64("blockNumber", receipt.BlockNumber),
+					zap.Error(err))
+				break
+			}
+			c.logger.Info("Aztec transaction included",
+				zap.String("txHash", txHash),
+				zap.Uint64("blockNumber", receipt.BlockNumber))
+			return receipt.BlockNumber, nil
+		case receipt.Status == aztecTxStatusDropped:
+			return 0, fmt.Errorf("%w: %s: %s", ErrAztecTxDropped, txHash, receipt.Error)
+		case strings.HasSuffix(receipt.Status, "reverted"):
+			return 0, fmt.Errorf("aztec transaction %s reverted (%s): %s", txHash, receipt.Status, receipt.Error)
+		default:
+			c.logger.Debug("Aztec transaction not yet included",
+				zap.String("txHash", txHash),
+				zap.String("status", receipt.Status))
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("aztec transaction %s not included within %v: %w", txHash, config.Timeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetBlockNumber returns the latest block number known to the node
+func (c *AztecPXEClient) GetBlockNumber(ctx context.Context) (uint64, error) {
+	var blockNumber uint64
+	if err := c.rpcClient.CallContext(ctx, &blockNumber, "node_getBlockNumber"); err != nil {
+		return 0, fmt.Errorf("failed to get block number: %v", err)
+	}
+	return blockNumber, nil
+}
+
+// CheckHealth verifies the node behind the PXE is reachable and reports a block number
+func (c *AztecPXEClient) CheckHealth(ctx context.Context) error {
+	_, err := c.GetBlockNumber(ctx)
+	return err
 }
 
 // GetWalletAddress returns the wallet address being used
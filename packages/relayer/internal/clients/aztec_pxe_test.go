@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newAztecNodeServer starts a JSON-RPC server answering node_getBlock with an empty block
+// and node_getTxReceipt with the receipts in order (repeating the last one)
+func newAztecNodeServer(t *testing.T, receipts ...map[string]interface{}) *httptest.Server {
+	t.Helper()
+	var calls int32
+	return newJSONRPCServer(t, func(method string) interface{} {
+		switch method {
+		case "node_getBlock":
+			return map[string]interface{}{}
+		case "node_getTxReceipt":
+			i := int(atomic.AddInt32(&calls, 1)) - 1
+			if i >= len(receipts) {
+				i = len(receipts) - 1
+			}
+			return receipts[i]
+		}
+		return nil
+	})
+}
+
+func TestAztecWaitForTransaction(t *testing.T) {
+	config := AztecConfirmationConfig{PollInterval: 10 * time.Millisecond, Timeout: 200 * time.Millisecond}
+	pending := map[string]interface{}{"status": "pending"}
+
+	tests := []struct {
+		name      string
+		txHash    string
+		receipts  []map[string]interface{}
+		wantBlock uint64
+		wantErr   string
+	}{
+		{name: "included after pending", txHash: "0x01", receipts: []map[string]interface{}{pending, pending, {"status": "success", "blockNumber": 42}}, wantBlock: 42},
+		{name: "dropped", txHash: "0x01", receipts: []map[string]interface{}{{"status": "dropped", "error": "nullifier conflict"}}, wantErr: "dropped"},
+		{name: "reverted", txHash: "0x01", receipts: []map[string]interface{}{{"status": "app_logic_reverted", "error": "assertion failed"}}, wantErr: "reverted"},
+		{name: "never included", txHash: "0x01", receipts: []map[string]interface{}{pending}, wantErr: "not included"},
+		{name: "synthetic hash", txHash: SyntheticTxHashPrefix + "123", receipts: []map[string]interface{}{pending}, wantErr: "no transaction hash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAztecNodeServer(t, tt.receipts...)
+			client, err := NewAztecPXEClient(zap.NewNop(), server.URL, "0xwallet")
+			if err != nil {
+				t.Fatalf("NewAztecPXEClient failed: %v", err)
+			}
+
+			block, err := client.WaitForTransaction(context.Background(), tt.txHash, config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitForTransaction failed: %v", err)
+			}
+			if block != tt.wantBlock {
+				t.Fatalf("expected block %d, got %d", tt.wantBlock, block)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package clients
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Spy reconnect backoff defaults
+const (
+	DefaultSpyInitialBackoff = 1 * time.Second
+	DefaultSpyMaxBackoff     = 30 * time.Second
+)
+
+// backoffJitter is the fraction by which each delay is randomly shortened or lengthened,
+// so relayers reconnecting after the same outage do not retry in lockstep
+const backoffJitter = 0.2
+
+// Backoff produces exponentially growing delays with jitter for reconnect loops: initial,
+// 2*initial, 4*initial... up to max, each varied by ±20%. It is not safe for concurrent use.
+type Backoff struct {
+	initial time.Duration
+	max     time.Duration
+	next    time.Duration // Delay before jitter returned by the next call to Next
+	jitter  func(d time.Duration) time.Duration
+}
+
+// NewBackoff creates a backoff starting at initial and capped at max
+func NewBackoff(initial, max time.Duration) *Backoff {
+	if initial <= 0 {
+		initial = DefaultSpyInitialBackoff
+	}
+	if max < initial {
+		max = initial
+	}
+	return &Backoff{initial: initial, max: max, next: initial, jitter: jitterDelay}
+}
+
+// Next returns the delay before the next attempt and doubles the delay after it
+func (b *Backoff) Next() time.Duration {
+	delay := b.jitter(b.next)
+	if delay > b.max {
+		delay = b.max
+	}
+
+	b.next *= 2
+	if b.next > b.max {
+		b.next = b.max
+	}
+	return delay
+}
+
+// Reset starts the sequence again from the initial delay, e.g. after a successful attempt
+func (b *Backoff) Reset() {
+	b.next = b.initial
+}
+
+// jitterDelay returns d varied uniformly by ±backoffJitter
+func jitterDelay(d time.Duration) time.Duration {
+	spread := int64(float64(d) * backoffJitter)
+	if spread <= 0 {
+		return d
+	}
+	return d - time.Duration(spread) + time.Duration(rand.Int64N(2*spread+1))
+}
@@ -0,0 +1,48 @@
+package clients
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffGrowsAndResets(t *testing.T) {
+	b := NewBackoff(time.Second, 30*time.Second)
+	b.jitter = func(d time.Duration) time.Duration { return d }
+
+	want := []time.Duration{1, 2, 4, 8, 16, 30, 30}
+	for i, w := range want {
+		if got := b.Next(); got != w*time.Second {
+			t.Fatalf("delay %d: expected %v, got %v", i, w*time.Second, got)
+		}
+	}
+
+	b.Reset()
+	if got := b.Next(); got != time.Second {
+		t.Errorf("expected %v after reset, got %v", time.Second, got)
+	}
+	if got := b.Next(); got != 2*time.Second {
+		t.Errorf("expected the sequence to grow again after reset, got %v", got)
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	b := NewBackoff(time.Second, 4*time.Second)
+	for i := 0; i < 50; i++ {
+		base := time.Duration(1<<min(i, 2)) * time.Second
+		got := b.Next()
+		if got < base*8/10 || got > min(base*12/10, 4*time.Second) {
+			t.Fatalf("delay %d: %v is outside ±20%% of %v (capped at 4s)", i, got, base)
+		}
+	}
+}
+
+func TestNewBackoffDefaults(t *testing.T) {
+	b := NewBackoff(0, 0)
+	b.jitter = func(d time.Duration) time.Duration { return d }
+	if got := b.Next(); got != DefaultSpyInitialBackoff {
+		t.Errorf("expected the default initial delay %v, got %v", DefaultSpyInitialBackoff, got)
+	}
+	if got := b.Next(); got != DefaultSpyInitialBackoff {
+		t.Errorf("expected a max below initial to be raised to initial, got %v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package clients
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Minimal BIP-173 bech32 implementation for Cosmos account and contract addresses
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+// convertBits regroups a byte slice from fromBits-wide to toBits-wide groups
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<toBits - 1
+	out := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data range")
+		}
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits)&maxv))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return out, nil
+}
+
+// Bech32Encode encodes data with the given human-readable prefix
+func Bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	polymod := bech32Polymod(append(append(bech32HRPExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	return sb.String(), nil
+}
+
+// Bech32Decode decodes a bech32 string into its prefix and data
+func Bech32Decode(addr string) (string, []byte, error) {
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return "", nil, fmt.Errorf("mixed-case bech32 string")
+	}
+	addr = strings.ToLower(addr)
+
+	sep := strings.LastIndexByte(addr, '1')
+	if sep < 1 || sep+7 > len(addr) {
+		return "", nil, fmt.Errorf("invalid bech32 separator position")
+	}
+
+	hrp := addr[:sep]
+	values := make([]byte, 0, len(addr)-sep-1)
+	for i := sep + 1; i < len(addr); i++ {
+		idx := strings.IndexByte(bech32Charset, addr[i])
+		if idx < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character %q", addr[i])
+		}
+		values = append(values, byte(idx))
+	}
+
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid bech32 checksum")
+	}
+
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
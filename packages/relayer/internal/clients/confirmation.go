@@ -0,0 +1,132 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// ErrUnconfirmed is returned when a transaction was sent but did not reach the required
+// confirmation depth before the deadline, or was dropped from the chain by a reorg. Whether it
+// eventually lands is unknown, so the VAA is worth submitting again: should the first
+// transaction land after all, the destination's replay guard rejects the second.
+var ErrUnconfirmed = errors.New("transaction not confirmed")
+
+// SolanaConfirmationNone selects not waiting for Solana transactions to be confirmed
+const SolanaConfirmationNone = "none"
+
+// solanaSignaturePollInterval is how often the status of a sent Solana transaction is polled
+const solanaSignaturePollInterval = 500 * time.Millisecond
+
+// ParseSolanaConfirmation parses the commitment a sent transaction must reach before it counts
+// as delivered: processed, confirmed or finalized, or none to count it delivered once sent.
+// Empty selects confirmed.
+func ParseSolanaConfirmation(commitment string) (rpc.CommitmentType, error) {
+	switch commitment {
+	case "", string(rpc.CommitmentConfirmed):
+		return rpc.CommitmentConfirmed, nil
+	case string(rpc.CommitmentProcessed), string(rpc.CommitmentFinalized):
+		return rpc.CommitmentType(commitment), nil
+	case SolanaConfirmationNone:
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid confirmation commitment %q (valid: none, processed, confirmed, finalized)", commitment)
+	}
+}
+
+// solanaCommitmentRank orders confirmation statuses, so a finalized transaction also counts as confirmed
+var solanaCommitmentRank = map[rpc.ConfirmationStatusType]int{
+	rpc.ConfirmationStatusProcessed: 1,
+	rpc.ConfirmationStatusConfirmed: 2,
+	rpc.ConfirmationStatusFinalized: 3,
+}
+
+// waitForSignature polls the status of a sent transaction until it reaches the client's
+// confirmation commitment. A transaction that failed on chain returns its decoded error; one that
+// is still unconfirmed when ctx is done returns an error wrapping ErrUnconfirmed.
+func (c *SolanaClient) waitForSignature(ctx context.Context, sig solana.Signature) error {
+	if c.confirmation == "" {
+		return nil
+	}
+	want := solanaCommitmentRank[rpc.ConfirmationStatusType(c.confirmation)]
+
+	ticker := time.NewTicker(solanaSignaturePollInterval)
+	defer ticker.Stop()
+	for {
+		result, err := c.client.GetSignatureStatuses(ctx, false, sig)
+		if err == nil && result != nil && len(result.Value) == 1 && result.Value[0] != nil {
+			status := result.Value[0]
+			if status.Err != nil {
+				reason := describeSimulationError(status.Err, nil, c.programErrors)
+				c.logger.Error("Transaction failed on chain", zap.String("signature", sig.String()), zap.String("reason", reason))
+				return fmt.Errorf("transaction %s failed: %s", sig, reason)
+			}
+			if solanaCommitmentRank[status.ConfirmationStatus] >= want {
+				return nil
+			}
+		} else if err != nil {
+			c.logger.Debug("Could not get transaction status, retrying", zap.String("signature", sig.String()), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: transaction %s did not reach %s commitment: %w", ErrUnconfirmed, sig, c.confirmation, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitForConfirmations waits until the block including receipt's transaction is the client's
+// number of confirmations deep (1 = the including block itself), then checks the transaction is
+// still in that block. A transaction moved to another block by a reorg is waited on there.
+func (c *EVMClient) waitForConfirmations(ctx context.Context, receipt *types.Receipt) error {
+	if c.confirmations <= 1 {
+		return nil
+	}
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+	for {
+		target := receipt.BlockNumber.Uint64() + c.confirmations - 1
+		head, err := retryCall(ctx, c.retry, c.logger, "BlockNumber", func() (uint64, error) {
+			return c.client.BlockNumber(ctx)
+		})
+		if err == nil && head >= target {
+			current, err := c.fetchReceipt(ctx, receipt.TxHash)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrUnconfirmed, err)
+			}
+			if current == nil {
+				return fmt.Errorf("%w: transaction %s was reorged out of block %s", ErrUnconfirmed, receipt.TxHash.Hex(), receipt.BlockNumber)
+			}
+			if current.BlockHash == receipt.BlockHash {
+				return nil
+			}
+			c.logger.Warn("Transaction moved to another block by a reorg",
+				zap.String("txHash", receipt.TxHash.Hex()),
+				zap.String("fromBlock", receipt.BlockNumber.String()),
+				zap.String("toBlock", current.BlockNumber.String()))
+			if current.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("transaction %s reverted in block %s", receipt.TxHash.Hex(), current.BlockNumber)
+			}
+			receipt = current
+			continue
+		}
+		if err != nil {
+			c.logger.Debug("Could not get the latest block number, retrying", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: transaction %s included in block %s but not %d blocks deep: %w",
+				ErrUnconfirmed, receipt.TxHash.Hex(), receipt.BlockNumber, c.confirmations, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// newJSONRPCServer starts a JSON-RPC server answering each method with the result of handle,
+// or failing it with the error if handle returns a *jsonRPCError
+func newJSONRPCServer(t *testing.T, handle func(method string) interface{}) *httptest.Server {
+	t.Helper()
+	return newJSONRPCParamsServer(t, func(method string, _ []json.RawMessage) interface{} { return handle(method) })
+}
+
+// jsonRPCError is a JSON-RPC error object; a server handler returns one to fail the call with it
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// newJSONRPCParamsServer is newJSONRPCServer for handlers that also read the call's params
+func newJSONRPCParamsServer(t *testing.T, handle func(method string, params []json.RawMessage) interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": handle(req.Method, req.Params)}
+		if rpcErr, ok := response["result"].(*jsonRPCError); ok {
+			delete(response, "result")
+			response["error"] = rpcErr
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseSolanaConfirmation(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    rpc.CommitmentType
+		wantErr bool
+	}{
+		{input: "", want: rpc.CommitmentConfirmed},
+		{input: "confirmed", want: rpc.CommitmentConfirmed},
+		{input: "processed", want: rpc.CommitmentProcessed},
+		{input: "finalized", want: rpc.CommitmentFinalized},
+		{input: "none", want: ""},
+		{input: "Finalized", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSolanaConfirmation(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expected an error for %q, got %s", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSolanaConfirmation(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestSolanaClientWaitForSignature(t *testing.T) {
+	tests := []struct {
+		name         string
+		confirmation rpc.CommitmentType
+		status       interface{} // Status of the signature, nil if the node has not seen it
+		wantErr      string
+		unconfirmed  bool
+	}{
+		{name: "confirmed", confirmation: rpc.CommitmentConfirmed, status: map[string]interface{}{"slot": 5, "err": nil, "confirmationStatus": "confirmed"}},
+		{name: "finalized counts as confirmed", confirmation: rpc.CommitmentConfirmed, status: map[string]interface{}{"slot": 5, "err": nil, "confirmationStatus": "finalized"}},
+		{name: "sent but only processed", confirmation: rpc.CommitmentFinalized, status: map[string]interface{}{"slot": 5, "err": nil, "confirmationStatus": "processed"}, wantErr: "did not reach finalized", unconfirmed: true},
+		{name: "sent but never seen", confirmation: rpc.CommitmentConfirmed, wantErr: "did not reach confirmed", unconfirmed: true},
+		{name: "failed on chain", confirmation: rpc.CommitmentConfirmed, status: map[string]interface{}{"slot": 5, "err": map[string]interface{}{"InstructionError": []interface{}{0, map[string]interface{}{"Custom": 6000}}}, "confirmationStatus": "confirmed"}, wantErr: "failed"},
+		{name: "not waiting", confirmation: "", wantErr: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newJSONRPCServer(t, func(method string) interface{} {
+				if method != "getSignatureStatuses" {
+					t.Errorf("unexpected method %s", method)
+				}
+				return map[string]interface{}{"context": map[string]interface{}{"slot": 5}, "value": []interface{}{tt.status}}
+			})
+			client := &SolanaClient{client: rpc.New(server.URL), confirmation: tt.confirmation, logger: zap.NewNop()}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := client.waitForSignature(ctx, solana.Signature{1})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected the transaction to count as confirmed, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, ErrUnconfirmed) != tt.unconfirmed {
+				t.Errorf("errors.Is(err, ErrUnconfirmed) = %v, want %v (err: %v)", !tt.unconfirmed, tt.unconfirmed, err)
+			}
+		})
+	}
+}
+
+func TestEVMClientWaitForConfirmations(t *testing.T) {
+	txHash := common.HexToHash("0xaa")
+	blockHash := common.HexToHash("0xbb")
+	receiptJSON := func(hash common.Hash) map[string]interface{} {
+		return map[string]interface{}{
+			"status":            "0x1",
+			"cumulativeGasUsed": "0x5208",
+			"logsBloom":         "0x" + strings.Repeat("00", 256),
+			"logs":              []interface{}{},
+			"transactionHash":   txHash.Hex(),
+			"gasUsed":           "0x5208",
+			"blockHash":         hash.Hex(),
+			"blockNumber":       "0x64",
+			"transactionIndex":  "0x0",
+		}
+	}
+
+	tests := []struct {
+		name          string
+		confirmations uint64
+		head          uint64
+		receipt       interface{} // Receipt the node returns once the block is deep enough, nil if none
+		wantErr       string
+	}{
+		{name: "deep enough", confirmations: 3, head: 102, receipt: receiptJSON(blockHash)},
+		{name: "included only", confirmations: 1, head: 100},
+		{name: "sent but not deep enough", confirmations: 3, head: 101, wantErr: "not 3 blocks deep"},
+		{name: "reorged out", confirmations: 3, head: 110, wantErr: "reorged out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newJSONRPCServer(t, func(method string) interface{} {
+				switch method {
+				case "eth_blockNumber":
+					return fmt.Sprintf("0x%x", tt.head)
+				case "eth_getTransactionReceipt":
+					return tt.receipt
+				default:
+					t.Errorf("unexpected method %s", method)
+					return nil
+				}
+			})
+			ethClient, err := ethclient.Dial(server.URL)
+			if err != nil {
+				t.Fatalf("failed to dial: %v", err)
+			}
+			client := &EVMClient{client: ethClient, confirmations: tt.confirmations, logger: zap.NewNop()}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err = client.waitForConfirmations(ctx, &types.Receipt{TxHash: txHash, BlockHash: blockHash, BlockNumber: big.NewInt(100)})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected the transaction to count as confirmed, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if !errors.Is(err, ErrUnconfirmed) {
+				t.Errorf("expected the error to wrap ErrUnconfirmed, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // Cosmos addresses are defined in terms of RIPEMD-160
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Protobuf type URLs used when building Cosmos transactions
+const (
+	typeURLMsgExecuteContract = "/cosmwasm.wasm.v1.MsgExecuteContract"
+	typeURLSecp256k1PubKey    = "/cosmos.crypto.secp256k1.PubKey"
+	signModeDirect            = 1
+)
+
+// cosmosTxPollInterval is how often a broadcast tx is looked up until it is included
+const cosmosTxPollInterval = 2 * time.Second
+
+// CosmosClientConfig holds the settings for a CosmosClient
+type CosmosClientConfig struct {
+	LCDURL       string // REST (LCD) endpoint of the Cosmos chain, e.g. a Wormhole gateway node
+	ChainID      string // Cosmos chain ID (fetched from the node when empty)
+	PrivateKey   string // Hex-encoded secp256k1 private key
+	Bech32Prefix string // Account address prefix (e.g. "wormhole")
+	GasLimit     uint64 // Gas limit for each transaction
+	FeeAmount    string // Fee amount in FeeDenom (e.g. "0")
+	FeeDenom     string // Fee denomination (e.g. "uworm")
+	// Transport for requests to the LCD endpoint (nil = default transport, honouring
+	// the HTTP_PROXY and HTTPS_PROXY environment variables)
+	HTTPTransport *http.Transport
+}
+
+// CosmosClient handles interactions with Cosmos SDK chains running CosmWasm
+type CosmosClient struct {
+	config     CosmosClientConfig
+	privateKey *ecdsa.PrivateKey
+	pubKey     []byte // Compressed secp256k1 public key
+	address    string // Bech32 account address
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// NewCosmosClient creates a new client for a CosmWasm-enabled Cosmos chain
+func NewCosmosClient(logger *zap.Logger, config CosmosClientConfig) (*CosmosClient, error) {
+	client := &CosmosClient{
+		config:     config,
+		httpClient: newHTTPClient(30*time.Second, config.HTTPTransport),
+		logger:     logger.With(zap.String("component", "CosmosClient")),
+	}
+
+	if config.Bech32Prefix == "" {
+		return nil, fmt.Errorf("bech32 prefix is required")
+	}
+	if config.FeeDenom == "" {
+		return nil, fmt.Errorf("fee denom is required")
+	}
+	if _, err := strconv.ParseUint(config.FeeAmount, 10, 64); err != nil {
+		return nil, fmt.Errorf("invalid fee amount %q: %v", config.FeeAmount, err)
+	}
+
+	// Parse private key
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	client.privateKey = privateKey
+	client.pubKey = crypto.CompressPubkey(&privateKey.PublicKey)
+
+	// Derive account address: bech32(prefix, ripemd160(sha256(compressed pubkey)))
+	shaSum := sha256.Sum256(client.pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(shaSum[:])
+	address, err := Bech32Encode(config.Bech32Prefix, hasher.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive account address: %v", err)
+	}
+	client.address = address
+
+	client.config.LCDURL = strings.TrimSuffix(config.LCDURL, "/")
+	client.logger.Info("Cosmos client initialized",
+		zap.String("lcdURL", client.config.LCDURL),
+		zap.String("address", client.address))
+
+	return client, nil
+}
+
+// GetAddress returns the bech32 account address of the signer
+func (c *CosmosClient) GetAddress() string {
+	return c.address
+}
+
+// NodeChainID returns the chain ID reported by the node, regardless of the configured one
+func (c *CosmosClient) NodeChainID(ctx context.Context) (string, error) {
+	return c.fetchChainID(ctx)
+}
+
+// CheckHealth verifies the LCD endpoint is reachable and answers queries
+func (c *CosmosClient) CheckHealth(ctx context.Context) error {
+	if _, err := c.fetchChainID(ctx); err != nil {
+		return fmt.Errorf("failed to get node info: %v", err)
+	}
+	return nil
+}
+
+// Balance returns the signer's balance in the fee denomination
+func (c *CosmosClient) Balance(ctx context.Context) (*big.Int, error) {
+	var result struct {
+		Balance struct {
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	}
+	path := "/cosmos/bank/v1beta1/balances/" + c.address + "/by_denom?denom=" + url.QueryEscape(c.config.FeeDenom)
+	if err := c.getJSON(ctx, path, &result); err != nil {
+		return nil, err
+	}
+
+	amount, ok := new(big.Int).SetString(result.Balance.Amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance amount %q", result.Balance.Amount)
+	}
+	return amount, nil
+}
+
+// ExecuteContract signs and broadcasts a MsgExecuteContract carrying msg to the target contract,
+// waiting for the transaction to be included. Returns the transaction hash.
+func (c *CosmosClient) ExecuteContract(ctx context.Context, contract string, msg []byte) (string, error) {
+	chainID := c.config.ChainID
+	if chainID == "" {
+		var err error
+		if chainID, err = c.fetchChainID(ctx); err != nil {
+			return "", fmt.Errorf("failed to get chain ID: %v", err)
+		}
+	}
+
+	accountNumber, sequence, err := c.fetchAccount(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account: %v", err)
+	}
+
+	c.logger.Debug("Building MsgExecuteContract",
+		zap.String("contract", contract),
+		zap.String("chainID", chainID),
+		zap.Uint64("accountNumber", accountNumber),
+		zap.Uint64("sequence", sequence))
+
+	bodyBytes := c.encodeTxBody(contract, msg)
+	authInfoBytes := c.encodeAuthInfo(sequence)
+
+	// Sign the SignDoc (SIGN_MODE_DIRECT)
+	var signDoc []byte
+	signDoc = protowire.AppendTag(signDoc, 1, protowire.BytesType)
+	signDoc = protowire.AppendBytes(signDoc, bodyBytes)
+	signDoc = protowire.AppendTag(signDoc, 2, protowire.BytesType)
+	signDoc = protowire.AppendBytes(signDoc, authInfoBytes)
+	signDoc = protowire.AppendTag(signDoc, 3, protowire.BytesType)
+	signDoc = protowire.AppendString(signDoc, chainID)
+	signDoc = appendUint64Field(signDoc, 4, accountNumber)
+
+	digest := sha256.Sum256(signDoc)
+	sig, err := crypto.Sign(digest[:], c.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %v", err)
+	}
+
+	// TxRaw with the 64-byte r||s signature (recovery id dropped)
+	var txRaw []byte
+	txRaw = protowire.AppendTag(txRaw, 1, protowire.BytesType)
+	txRaw = protowire.AppendBytes(txRaw, bodyBytes)
+	txRaw = protowire.AppendTag(txRaw, 2, protowire.BytesType)
+	txRaw = protowire.AppendBytes(txRaw, authInfoBytes)
+	txRaw = protowire.AppendTag(txRaw, 3, protowire.BytesType)
+	txRaw = protowire.AppendBytes(txRaw, sig[:64])
+
+	txHash, err := c.broadcast(ctx, txRaw)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.Debug("Transaction broadcast, waiting for inclusion", zap.String("txHash", txHash))
+	if err := c.waitForTx(ctx, txHash); err != nil {
+		return "", fmt.Errorf("failed to confirm transaction %s: %w", txHash, err)
+	}
+
+	return txHash, nil
+}
+
+// encodeTxBody encodes a TxBody holding a single MsgExecuteContract
+func (c *CosmosClient) encodeTxBody(contract string, msg []byte) []byte {
+	var execMsg []byte
+	execMsg = protowire.AppendTag(execMsg, 1, protowire.BytesType)
+	execMsg = protowire.AppendString(execMsg, c.address)
+	execMsg = protowire.AppendTag(execMsg, 2, protowire.BytesType)
+	execMsg = protowire.AppendString(execMsg, contract)
+	execMsg = protowire.AppendTag(execMsg, 3, protowire.BytesType)
+	execMsg = protowire.AppendBytes(execMsg, msg)
+
+	var body []byte
+	body = protowire.AppendTag(body, 1, protowire.BytesType)
+	body = protowire.AppendBytes(body, encodeAny(typeURLMsgExecuteContract, execMsg))
+	return body
+}
+
+// encodeAuthInfo encodes an AuthInfo with a single direct-mode secp256k1 signer and the configured fee
+func (c *CosmosClient) encodeAuthInfo(sequence uint64) []byte {
+	var pubKey []byte
+	pubKey = protowire.AppendTag(pubKey, 1, protowire.BytesType)
+	pubKey = protowire.AppendBytes(pubKey, c.pubKey)
+
+	var single []byte
+	single = appendUint64Field(single, 1, signModeDirect)
+	var modeInfo []byte
+	modeInfo = protowire.AppendTag(modeInfo, 1, protowire.BytesType)
+	modeInfo = protowire.AppendBytes(modeInfo, single)
+
+	var signerInfo []byte
+	signerInfo = protowire.AppendTag(signerInfo, 1, protowire.BytesType)
+	signerInfo = protowire.AppendBytes(signerInfo, encodeAny(typeURLSecp256k1PubKey, pubKey))
+	signerInfo = protowire.AppendTag(signerInfo, 2, protowire.BytesType)
+	signerInfo = protowire.AppendBytes(signerInfo, modeInfo)
+	signerInfo = appendUint64Field(signerInfo, 3, sequence)
+
+	var coin []byte
+	coin = protowire.AppendTag(coin, 1, protowire.BytesType)
+	coin = protowire.AppendString(coin, c.config.FeeDenom)
+	coin = protowire.AppendTag(coin, 2, protowire.BytesType)
+	coin = protowire.AppendString(coin, c.config.FeeAmount)
+
+	var fee []byte
+	fee = protowire.AppendTag(fee, 1, protowire.BytesType)
+	fee = protowire.AppendBytes(fee, coin)
+	fee = appendUint64Field(fee, 2, c.config.GasLimit)
+
+	var authInfo []byte
+	authInfo = protowire.AppendTag(authInfo, 1, protowire.BytesType)
+	authInfo = protowire.AppendBytes(authInfo, signerInfo)
+	authInfo = protowire.AppendTag(authInfo, 2, protowire.BytesType)
+	authInfo = protowire.AppendBytes(authInfo, fee)
+	return authInfo
+}
+
+// encodeAny wraps a message in a google.protobuf.Any
+func encodeAny(typeURL string, value []byte) []byte {
+	var out []byte
+	out = protowire.AppendTag(out, 1, protowire.BytesType)
+	out = protowire.AppendString(out, typeURL)
+	out = protowire.AppendTag(out, 2, protowire.BytesType)
+	out = protowire.AppendBytes(out, value)
+	return out
+}
+
+// appendUint64Field appends a varint field, omitting zero values as proto3 does
+func appendUint64Field(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// fetchChainID reads the chain ID from the node info endpoint
+func (c *CosmosClient) fetchChainID(ctx context.Context) (string, error) {
+	var result struct {
+		DefaultNodeInfo struct {
+			Network string `json:"network"`
+		} `json:"default_node_info"`
+	}
+	if err := c.getJSON(ctx, "/cosmos/base/tendermint/v1beta1/node_info", &result); err != nil {
+		return "", err
+	}
+	if result.DefaultNodeInfo.Network == "" {
+		return "", fmt.Errorf("node info did not include a network")
+	}
+	return result.DefaultNodeInfo.Network, nil
+}
+
+// fetchAccount reads the signer's account number and sequence
+func (c *CosmosClient) fetchAccount(ctx context.Context) (uint64, uint64, error) {
+	var result struct {
+		Account struct {
+			AccountNumber string `json:"account_number"`
+			Sequence      string `json:"sequence"`
+		} `json:"account"`
+	}
+	if err := c.getJSON(ctx, "/cosmos/auth/v1beta1/accounts/"+c.address, &result); err != nil {
+		return 0, 0, err
+	}
+
+	accountNumber, err := strconv.ParseUint(result.Account.AccountNumber, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid account number %q: %v", result.Account.AccountNumber, err)
+	}
+	sequence, err := strconv.ParseUint(result.Account.Sequence, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid sequence %q: %v", result.Account.Sequence, err)
+	}
+	return accountNumber, sequence, nil
+}
+
+// cosmosTxResponse is the subset of a TxResponse the relayer inspects
+type cosmosTxResponse struct {
+	TxHash string `json:"txhash"`
+	Code   uint32 `json:"code"`
+	RawLog string `json:"raw_log"`
+}
+
+// broadcast submits signed tx bytes in sync mode and returns the tx hash
+func (c *CosmosClient) broadcast(ctx context.Context, txRaw []byte) (string, error) {
+	reqJSON, err := json.Marshal(map[string]string{
+		"tx_bytes": base64.StdEncoding.EncodeToString(txRaw),
+		"mode":     "BROADCAST_MODE_SYNC",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal broadcast request: %v", err)
+	}
+
+	var result struct {
+		TxResponse cosmosTxResponse `json:"tx_response"`
+	}
+	if err := c.doJSON(ctx, "POST", "/cosmos/tx/v1beta1/txs", reqJSON, &result); err != nil {
+		return "", fmt.Errorf("failed to broadcast transaction: %v", err)
+	}
+	if result.TxResponse.Code != 0 {
+		return "", fmt.Errorf("transaction rejected (code %d): %s", result.TxResponse.Code, result.TxResponse.RawLog)
+	}
+	return result.TxResponse.TxHash, nil
+}
+
+// waitForTx polls until the transaction is found, failing if it executed with a non-zero code.
+// Tendermint blocks are final, so inclusion is all the confirmation a transaction needs.
+func (c *CosmosClient) waitForTx(ctx context.Context, txHash string) error {
+	ticker := time.NewTicker(cosmosTxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var result struct {
+			TxResponse cosmosTxResponse `json:"tx_response"`
+		}
+		err := c.getJSON(ctx, "/cosmos/tx/v1beta1/txs/"+txHash, &result)
+		if err == nil && result.TxResponse.TxHash != "" {
+			if result.TxResponse.Code != 0 {
+				return fmt.Errorf("transaction failed (code %d): %s", result.TxResponse.Code, result.TxResponse.RawLog)
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrUnconfirmed, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *CosmosClient) getJSON(ctx context.Context, path string, out interface{}) error {
+	return c.doJSON(ctx, "GET", path, nil, out)
+}
+
+// doJSON performs an LCD request and decodes the JSON response
+func (c *CosmosClient) doJSON(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.config.LCDURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response, refusing to buffer an oversized body
+	respBody, err := readResponseBody(resp.Body, DefaultMaxResponseBytes)
+	if err != nil {
+		return fmt.Errorf("failed to read response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, truncateBody(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestBech32RoundTrip(t *testing.T) {
+	// BIP-173 test vector
+	hrp, data, err := Bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw")
+	if err != nil {
+		t.Fatalf("failed to decode test vector: %v", err)
+	}
+	if hrp != "abcdef" {
+		t.Errorf("expected hrp abcdef, got %s", hrp)
+	}
+
+	encoded, err := Bech32Encode(hrp, data)
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	if encoded != "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw" {
+		t.Errorf("round trip mismatch: %s", encoded)
+	}
+
+	if _, _, err := Bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx"); err == nil {
+		t.Error("expected checksum error")
+	}
+}
+
+// decodeFields splits a protobuf message into its length-delimited fields
+func decodeFields(t *testing.T, b []byte) map[protowire.Number][][]byte {
+	t.Helper()
+	fields := make(map[protowire.Number][][]byte)
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag")
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			t.Fatalf("invalid bytes field")
+		}
+		fields[num] = append(fields[num], v)
+		b = b[n:]
+	}
+	return fields
+}
+
+func TestCosmosClientExecuteContract(t *testing.T) {
+	contract, err := Bech32Encode("wormhole", make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to encode contract address: %v", err)
+	}
+	var broadcastTx []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/cosmos/auth/v1beta1/accounts/"):
+			w.Write([]byte(`{"account":{"account_number":"7","sequence":"3"}}`))
+		case r.URL.Path == "/cosmos/tx/v1beta1/txs" && r.Method == "POST":
+			var req struct {
+				TxBytes string `json:"tx_bytes"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			broadcastTx, _ = base64.StdEncoding.DecodeString(req.TxBytes)
+			w.Write([]byte(`{"tx_response":{"txhash":"ABCD","code":0}}`))
+		case r.URL.Path == "/cosmos/tx/v1beta1/txs/ABCD":
+			w.Write([]byte(`{"tx_response":{"txhash":"ABCD","code":0}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewCosmosClient(zap.NewNop(), CosmosClientConfig{
+		LCDURL:       server.URL,
+		ChainID:      "wormchain-testnet-0",
+		PrivateKey:   "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318",
+		Bech32Prefix: "wormhole",
+		GasLimit:     200000,
+		FeeAmount:    "0",
+		FeeDenom:     "uworm",
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if !strings.HasPrefix(client.GetAddress(), "wormhole1") {
+		t.Errorf("unexpected address %s", client.GetAddress())
+	}
+
+	txHash, err := client.ExecuteContract(context.Background(), contract, []byte(`{"submit_vaa":{"vaa":"AQ=="}}`))
+	if err != nil {
+		t.Fatalf("ExecuteContract failed: %v", err)
+	}
+	if txHash != "ABCD" {
+		t.Errorf("expected tx hash ABCD, got %s", txHash)
+	}
+
+	// TxRaw: body_bytes=1, auth_info_bytes=2, signatures=3
+	txRaw := decodeFields(t, broadcastTx)
+	body, authInfo, sig := txRaw[1][0], txRaw[2][0], txRaw[3][0]
+	if len(sig) != 64 {
+		t.Fatalf("expected 64-byte signature, got %d", len(sig))
+	}
+
+	// The message must carry the contract and the execute msg
+	anyMsg := decodeFields(t, decodeFields(t, body)[1][0])
+	if string(anyMsg[1][0]) != typeURLMsgExecuteContract {
+		t.Errorf("unexpected type URL %s", anyMsg[1][0])
+	}
+	execMsg := decodeFields(t, anyMsg[2][0])
+	if string(execMsg[1][0]) != client.GetAddress() || string(execMsg[2][0]) != contract {
+		t.Errorf("unexpected sender/contract %s/%s", execMsg[1][0], execMsg[2][0])
+	}
+
+	// Rebuild the sign doc and check the signature
+	var signDoc []byte
+	signDoc = protowire.AppendTag(signDoc, 1, protowire.BytesType)
+	signDoc = protowire.AppendBytes(signDoc, body)
+	signDoc = protowire.AppendTag(signDoc, 2, protowire.BytesType)
+	signDoc = protowire.AppendBytes(signDoc, authInfo)
+	signDoc = protowire.AppendTag(signDoc, 3, protowire.BytesType)
+	signDoc = protowire.AppendString(signDoc, "wormchain-testnet-0")
+	signDoc = appendUint64Field(signDoc, 4, 7)
+	digest := sha256.Sum256(signDoc)
+	if !crypto.VerifySignature(client.pubKey, digest[:], sig) {
+		t.Error("signature does not verify against the sign doc")
+	}
+}
+
+func TestCosmosClientRefusesOversizedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"account":{"account_number":"7","sequence":"3","padding":"`))
+		w.Write(bytes.Repeat([]byte("x"), int(DefaultMaxResponseBytes)))
+		w.Write([]byte(`"}}`))
+	}))
+	defer server.Close()
+
+	client := &CosmosClient{config: CosmosClientConfig{LCDURL: server.URL}, httpClient: server.Client()}
+	var out map[string]interface{}
+	err := client.doJSON(context.Background(), http.MethodGet, "/cosmos/auth/v1beta1/accounts/wormhole1", nil, &out)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
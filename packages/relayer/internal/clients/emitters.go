@@ -0,0 +1,7 @@
+package clients
+
+import "errors"
+
+// ErrEmitterNotRegistered is returned when a destination contract has no foreign emitter registered
+// for a VAA's source chain, or has a different one: it would reject the VAA's delivery
+var ErrEmitterNotRegistered = errors.New("emitter not registered on destination")
@@ -2,145 +2,531 @@ package clients
 
 import (
 	"context"
-	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"go.uber.org/zap"
 )
 
+// receiveValueABI is the contract ABI for the receiveValue function
+const receiveValueABI = `[{
+        "inputs": [
+            {"internalType": "bytes", "name": "encodedVaa", "type": "bytes"}
+        ],
+        "name": "receiveValue",
+        "outputs": [],
+        "stateMutability": "nonpayable",
+        "type": "function"
+    }]`
+
+// registeredEmittersABI is the contract ABI for the registeredEmitters getter (the public mapping
+// of source chain ID to the emitter address the contract accepts VAAs from)
+const registeredEmittersABI = `[{
+        "inputs": [
+            {"internalType": "uint16", "name": "", "type": "uint16"}
+        ],
+        "name": "registeredEmitters",
+        "outputs": [
+            {"internalType": "bytes32", "name": "", "type": "bytes32"}
+        ],
+        "stateMutability": "view",
+        "type": "function"
+    }]`
+
+// Receipt confirmation modes
+const (
+	ConfirmationModeSubscription = "subscription" // Wait on new-head notifications (ws:// and wss:// endpoints)
+	ConfirmationModePolling      = "polling"      // Poll for the receipt on an interval (http:// and https:// endpoints)
+)
+
+// receiptPollInterval is how often the receipt is polled for when subscriptions are unavailable
+const receiptPollInterval = 2 * time.Second
+
 // EVMClient handles interactions with EVM-compatible blockchains (Arbitrum)
 type EVMClient struct {
-	client     *ethclient.Client
-	privateKey *ecdsa.PrivateKey
-	address    common.Address
-	logger     *zap.Logger
+	client           *ethclient.Client
+	signer           EVMSigner
+	address          common.Address
+	contractABI      abi.ABI           // Parsed once at construction and reused per send
+	relayMethod      abi.Method        // Method called with the encoded VAA
+	emittersMethod   abi.Method        // Getter of the emitter registered for a source chain
+	l1GasMethod      abi.Method        // NodeInterface.gasEstimateL1Component (FeeModelArbitrum)
+	l1FeeMethod      abi.Method        // GasPriceOracle.getL1Fee (FeeModelOPStack)
+	processedCheck   *processedCheck   // View function reporting consumed VAAs, if configured
+	confirmationMode string            // How tx inclusion is detected (subscription or polling)
+	confirmations    uint64            // Blocks deep the including block must be before a tx counts as delivered
+	retry            RetryConfig       // Retry policy for transient RPC errors
+	reads            *ReadLimiter      // Bounds the header, nonce and contract call reads in flight (nil = unlimited)
+	fees             FeeStrategy       // Computes the tip and fee cap of each transaction
+	feeModel         FeeModel          // Configured fee model ("" = chosen from the chain ID)
+	gasLimit         uint64            // Gas limit of each transaction
+	feeOracle        *ethclient.Client // Queried for the priority fee instead of client, if configured
+	logger           *zap.Logger
+}
+
+// EVMClientConfig holds the settings for an EVMClient.
+// Exactly one of PrivateKey, PrivateKeyFile, KeystoreFile and Signer must be set.
+type EVMClientConfig struct {
+	RPCURL               string      // RPC URL (http(s) for polling, ws(s) for subscriptions), or comma-separated http(s) URLs to fail over between
+	PrivateKey           string      // Hex-encoded private key
+	PrivateKeyFile       string      // Path to a file holding the hex-encoded private key
+	KeystoreFile         string      // Path to a go-ethereum V3 keystore file
+	KeystorePassword     string      // Password for KeystoreFile
+	KeystorePasswordFile string      // Path to a file holding the password for KeystoreFile
+	Retry                RetryConfig // Retry policy for transient RPC errors
+	// Signs transactions in place of a local key, e.g. a remote signer (see NewRemoteEVMSigner)
+	Signer EVMSigner
+	// Blocks deep the block including a transaction must be before it counts as delivered
+	// (0 or 1 = once included)
+	Confirmations uint64
+	// How the priority fee (tip) of each transaction is chosen
+	PriorityFee PriorityFeeConfig
+	// Gas limit of each transaction (0 = DefaultEVMGasLimit)
+	GasLimit uint64
+	// Fee cap (max fee per gas) of each transaction in wei, replacing BaseFeeMultiplier times the
+	// base fee plus the tip (nil = computed from the base fee)
+	MaxFee *big.Int
+	// How many times the latest base fee the fee cap covers (0 = DefaultBaseFeeMultiplier)
+	BaseFeeMultiplier uint64
+	// View function asked before each send whether the target already consumed the VAA
+	// (nil = always send)
+	ProcessedCheck *ProcessedCheckConfig
+	// Maximum header, nonce and contract call reads in flight, across all VAAs (0 = unlimited)
+	ReadConcurrency int
+	// How the chain charges for transactions beyond EIP-1559 gas ("" = FeeModelForChain of the
+	// chain ID the RPC reports)
+	FeeModel FeeModel
 }
 
 // NewEVMClient creates a new client for EVM-compatible blockchains
-func NewEVMClient(logger *zap.Logger, rpcURL, privateKeyHex string) (*EVMClient, error) {
+func NewEVMClient(logger *zap.Logger, config EVMClientConfig) (*EVMClient, error) {
+	rpcURLs, err := ParseRPCURLs(config.RPCURL)
+	if err != nil {
+		return nil, err
+	}
+	rpcURL := rpcURLs[0]
+	if _, err := ParseFeeModel(string(config.FeeModel)); err != nil {
+		return nil, err
+	}
 	client := &EVMClient{
-		logger: logger.With(zap.String("component", "EVMClient")),
+		retry:         config.Retry,
+		feeModel:      config.FeeModel,
+		fees:          FeeStrategy{Tip: config.PriorityFee, BaseFeeMultiplier: config.BaseFeeMultiplier, MaxFee: config.MaxFee},
+		gasLimit:      config.GasLimit,
+		confirmations: config.Confirmations,
+		logger:        logger.With(zap.String("component", "EVMClient")),
+	}
+	if client.gasLimit == 0 {
+		client.gasLimit = DefaultEVMGasLimit
+	}
+	if client.reads, err = NewReadLimiter("evm", config.ReadConcurrency); err != nil {
+		return nil, err
 	}
 
-	client.logger.Info("Connecting to EVM chain", zap.String("rpcURL", rpcURL))
-	ethClient, err := ethclient.Dial(rpcURL)
+	parsedABI, err := abi.JSON(strings.NewReader(receiveValueABI))
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to EVM node: %v", err)
+		return nil, fmt.Errorf("ABI parse error: %v", err)
 	}
+	method, ok := parsedABI.Methods["receiveValue"]
+	if !ok {
+		return nil, fmt.Errorf("ABI does not define receiveValue")
+	}
+	client.contractABI = parsedABI
+	client.relayMethod = method
 
-	// Parse private key
-	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	emittersABI, err := abi.JSON(strings.NewReader(registeredEmittersABI))
 	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+		return nil, fmt.Errorf("ABI parse error: %v", err)
 	}
+	client.emittersMethod = emittersABI.Methods["registeredEmitters"]
 
-	// Derive public address
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("error casting public key to ECDSA")
+	if client.l1GasMethod, client.l1FeeMethod, err = parseL1FeeMethods(); err != nil {
+		return nil, err
+	}
+
+	if config.ProcessedCheck != nil {
+		check, err := newProcessedCheck(*config.ProcessedCheck)
+		if err != nil {
+			return nil, err
+		}
+		client.processedCheck = check
+	}
+
+	client.logger.Info("Connecting to EVM chain", zap.Strings("rpcURLs", rpcURLs))
+	ethClient, err := dialEVM(client.logger, rpcURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to EVM node: %v", err)
+	}
+
+	if config.PriorityFee.OracleURL != "" {
+		feeOracle, err := ethclient.Dial(config.PriorityFee.OracleURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to fee oracle: %v", err)
+		}
+		client.feeOracle = feeOracle
+	}
+
+	// Use the configured signer, or load the private key from the flag value, the key file or the keystore
+	signer := config.Signer
+	if signer == nil {
+		privateKey, err := loadEVMPrivateKey(config)
+		if err != nil {
+			return nil, err
+		}
+		signer = NewLocalEVMSigner(privateKey)
+	} else if config.PrivateKey != "" || config.PrivateKeyFile != "" || config.KeystoreFile != "" {
+		return nil, fmt.Errorf("both a signer and a private key source were provided; use only one")
 	}
-	address := crypto.PubkeyToAddress(*publicKeyECDSA)
 
 	client.client = ethClient
-	client.privateKey = privateKey
-	client.address = address
+	client.signer = signer
+	client.address = signer.Address()
+	client.confirmationMode = ConfirmationModePolling
+	if strings.HasPrefix(rpcURL, "ws://") || strings.HasPrefix(rpcURL, "wss://") {
+		client.confirmationMode = ConfirmationModeSubscription
+	}
 
 	return client, nil
 }
 
+// dialEVM connects to the EVM node at the only URL of rpcURLs, or to the first URL with failover
+// to the others
+func dialEVM(logger *zap.Logger, rpcURLs []string) (*ethclient.Client, error) {
+	if len(rpcURLs) == 1 {
+		return ethclient.Dial(rpcURLs[0])
+	}
+	failover, err := newRPCFailover(logger, rpcURLs, http.DefaultTransport.(*http.Transport).Clone())
+	if err != nil {
+		return nil, err
+	}
+	rpcClient, err := rpc.DialOptions(context.Background(), rpcURLs[0], rpc.WithHTTPClient(&http.Client{Transport: failover}))
+	if err != nil {
+		return nil, err
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
+
 // GetAddress returns the public address for this client
 func (c *EVMClient) GetAddress() common.Address {
 	return c.address
 }
 
-// SendVerifyTransaction sends a transaction to the verify function to process and store a VAA
-func (c *EVMClient) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
-	c.logger.Debug("Sending verify transaction to EVM", zap.Int("vaaLength", len(vaaBytes)))
+// ChainID returns the chain ID reported by the node (eth_chainId)
+func (c *EVMClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return retryCall(ctx, c.retry, c.logger, "ChainID", func() (*big.Int, error) {
+		return c.client.ChainID(ctx)
+	})
+}
 
-	// Contract ABI for the receiveValue function
-	const abiJSON = `[{
-        "inputs": [
-            {"internalType": "bytes", "name": "encodedVaa", "type": "bytes"}
-        ],
-        "name": "receiveValue",
-        "outputs": [],
-        "stateMutability": "nonpayable",
-        "type": "function"
-    }]`
+// CheckHealth verifies the RPC endpoint is reachable, without retrying
+func (c *EVMClient) CheckHealth(ctx context.Context) error {
+	if _, err := c.client.BlockNumber(ctx); err != nil {
+		return fmt.Errorf("failed to get block number: %v", err)
+	}
+	return nil
+}
+
+// Balance returns the signer's balance in wei at the latest block
+func (c *EVMClient) Balance(ctx context.Context) (*big.Int, error) {
+	return retryCall(ctx, c.retry, c.logger, "BalanceAt", func() (*big.Int, error) {
+		return c.client.BalanceAt(ctx, c.address, nil)
+	})
+}
 
-	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+// GetConfirmationMode returns how transaction inclusion is detected
+func (c *EVMClient) GetConfirmationMode() string {
+	return c.confirmationMode
+}
+
+// VerifyEmitterRegistered calls registeredEmitters(chainID) on targetContract and checks it returns
+// emitter. It returns an error wrapping ErrEmitterNotRegistered if no emitter or another one is
+// registered, since the contract would then revert; other errors mean the call itself failed.
+func (c *EVMClient) VerifyEmitterRegistered(ctx context.Context, targetContract string, chainID uint16, emitter [32]byte) error {
+	args, err := c.emittersMethod.Inputs.Pack(chainID)
 	if err != nil {
-		return "", fmt.Errorf("ABI parse error: %v", err)
+		return fmt.Errorf("ABI pack error: %v", err)
 	}
+	target := common.HexToAddress(targetContract)
+	call := ethereum.CallMsg{To: &target, Data: append(append([]byte{}, c.emittersMethod.ID...), args...)}
+
+	output, err := retryCall(ctx, c.retry, c.logger, "CallContract", func() ([]byte, error) {
+		return limitRead(ctx, c.reads, func() ([]byte, error) {
+			return c.client.CallContract(ctx, call, nil)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to call registeredEmitters on %s: %v", targetContract, err)
+	}
+	registered, err := c.unpackRegisteredEmitter(output)
+	if err != nil {
+		return fmt.Errorf("failed to decode registeredEmitters result from %s: %v", targetContract, err)
+	}
+
+	if registered == ([32]byte{}) {
+		return fmt.Errorf("%w: %s has no emitter registered for chain %d", ErrEmitterNotRegistered, targetContract, chainID)
+	}
+	if registered != emitter {
+		return fmt.Errorf("%w: %s has emitter %x registered for chain %d, VAA is from %x",
+			ErrEmitterNotRegistered, targetContract, registered, chainID, emitter)
+	}
+	return nil
+}
+
+// unpackRegisteredEmitter decodes the bytes32 returned by registeredEmitters
+func (c *EVMClient) unpackRegisteredEmitter(output []byte) ([32]byte, error) {
+	values, err := c.emittersMethod.Outputs.Unpack(output)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(values) != 1 {
+		return [32]byte{}, fmt.Errorf("expected 1 value, got %d", len(values))
+	}
+	registered, ok := values[0].([32]byte)
+	if !ok {
+		return [32]byte{}, fmt.Errorf("unexpected value type %T", values[0])
+	}
+	return registered, nil
+}
+
+// RelayVAA sends a transaction to the verify function of targetContract to process and store a VAA,
+// waits for it to be included and returns the transaction hash
+func (c *EVMClient) RelayVAA(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	c.logger.Debug("Sending verify transaction to EVM", zap.Int("vaaLength", len(vaaBytes)))
 
 	// Pack the function call data
-	data, err := parsedABI.Pack("receiveValue", vaaBytes)
+	data, err := c.packRelayCall(vaaBytes)
 	if err != nil {
 		return "", fmt.Errorf("ABI pack error: %v", err)
 	}
 
 	// Get the latest nonce for our account
-	nonce, err := c.client.PendingNonceAt(ctx, c.address)
+	nonce, err := retryCall(ctx, c.retry, c.logger, "PendingNonceAt", func() (uint64, error) {
+		return limitRead(ctx, c.reads, func() (uint64, error) {
+			return c.client.PendingNonceAt(ctx, c.address)
+		})
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get nonce: %v", err)
 	}
 
 	// Get the chain ID
-	chainID, err := c.client.NetworkID(ctx)
+	chainID, err := retryCall(ctx, c.retry, c.logger, "NetworkID", func() (*big.Int, error) {
+		return c.client.NetworkID(ctx)
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get chain ID: %v", err)
 	}
 
 	// Get the current base fee from the latest block header
-	header, err := c.client.HeaderByNumber(ctx, nil)
+	header, err := retryCall(ctx, c.retry, c.logger, "HeaderByNumber", func() (*types.Header, error) {
+		return limitRead(ctx, c.reads, func() (*types.Header, error) {
+			return c.client.HeaderByNumber(ctx, nil)
+		})
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get latest block header: %v", err)
 	}
 
-	// Calculate gas fees with buffer for EIP-1559
-	// Use 2x base fee as max fee to handle fluctuations
-	baseFee := header.BaseFee
-	maxPriorityFeePerGas := big.NewInt(100000000) // 0.1 gwei tip
-	maxFeePerGas := new(big.Int).Mul(baseFee, big.NewInt(2))
-	maxFeePerGas.Add(maxFeePerGas, maxPriorityFeePerGas)
+	// Calculate gas fees for EIP-1559, capped at the configured fee cap if any. Arbitrum never
+	// charges a tip, so none is asked for there.
+	model := c.feeModelOf(chainID)
+	strategy := c.fees
+	strategy.Model = model
+	var suggestedTip *big.Int
+	var tipSource string
+	if model != FeeModelArbitrum {
+		suggestedTip, tipSource = c.suggestedTip(ctx)
+	}
+	fees, err := strategy.Fees(header, suggestedTip, tipSource)
+	if err != nil {
+		return "", err
+	}
 
-	c.logger.Debug("Gas fees calculated",
-		zap.String("baseFee", baseFee.String()),
-		zap.String("maxFeePerGas", maxFeePerGas.String()),
-		zap.String("maxPriorityFeePerGas", maxPriorityFeePerGas.String()))
+	// On Arbitrum the L1 cost of the transaction is charged as L2 gas on top of execution
+	targetAddr := common.HexToAddress(targetContract)
+	gasLimit := c.gasLimit
+	var l1Fields []zap.Field
+	if model == FeeModelArbitrum {
+		l1Gas, err := c.arbitrumL1Gas(ctx, targetAddr, data)
+		if err != nil {
+			c.logger.Warn("Could not estimate the L1 gas of the transaction, using the configured gas limit alone", zap.Error(err))
+		} else {
+			gasLimit += l1Gas
+			l1Fields = append(l1Fields, zap.Uint64("l1Gas", l1Gas))
+		}
+	}
 
 	// Create EIP-1559 dynamic fee transaction
-	targetAddr := common.HexToAddress(targetContract)
 	tx := types.NewTx(&types.DynamicFeeTx{
 		ChainID:   chainID,
 		Nonce:     nonce,
-		GasTipCap: maxPriorityFeePerGas,
-		GasFeeCap: maxFeePerGas,
-		Gas:       3000000, // Gas limit
+		GasTipCap: fees.MaxPriorityFeePerGas,
+		GasFeeCap: fees.MaxFeePerGas,
+		Gas:       gasLimit,
 		To:        &targetAddr,
 		Value:     big.NewInt(0),
 		Data:      data,
 	})
 
+	// On the OP Stack the L1 data fee is paid on top of gas; it is only estimated, to log the cost
+	if model == FeeModelOPStack {
+		l1Fee, err := c.opStackL1Fee(ctx, tx)
+		if err != nil {
+			c.logger.Debug("Could not estimate the L1 data fee of the transaction", zap.Error(err))
+		} else {
+			l1Fields = append(l1Fields, zap.String("l1DataFee", l1Fee.String()))
+		}
+	}
+
+	c.logger.Info("Gas fees calculated", append([]zap.Field{
+		zap.String("feeModel", string(model)),
+		zap.String("baseFee", fees.BaseFee.String()),
+		zap.String("maxFeePerGas", fees.MaxFeePerGas.String()),
+		zap.String("maxFeeSource", fees.MaxFeeSource),
+		zap.String("maxPriorityFeePerGas", fees.MaxPriorityFeePerGas.String()),
+		zap.String("priorityFeeSource", fees.PriorityFeeSource),
+		zap.Uint64("gasLimit", gasLimit)}, l1Fields...)...)
+	if fees.MaxFeePerGas.Cmp(fees.BaseFee) < 0 {
+		c.logger.Warn("Fee cap is below the base fee; the transaction waits until the base fee drops",
+			zap.String("baseFee", fees.BaseFee.String()),
+			zap.String("maxFeePerGas", fees.MaxFeePerGas.String()))
+	}
+
 	// Sign the transaction with London signer for EIP-1559 transactions
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), c.privateKey)
+	txSigner := types.NewLondonSigner(chainID)
+	signature, err := c.signer.SignHash(ctx, txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	signedTx, err := tx.WithSignature(txSigner, signature)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign transaction: %v", err)
 	}
 
-	// Send the transaction
-	err = c.client.SendTransaction(ctx, signedTx)
+	// Send the transaction. A retried send of a tx the node already accepted is not an error.
+	_, err = retryCall(ctx, c.retry, c.logger, "SendTransaction", func() (struct{}, error) {
+		err := c.client.SendTransaction(ctx, signedTx)
+		if err != nil && strings.Contains(strings.ToLower(err.Error()), "already known") {
+			return struct{}{}, nil
+		}
+		return struct{}{}, err
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %v", err)
 	}
 
+	c.logger.Debug("Transaction sent, waiting for inclusion",
+		zap.String("txHash", signedTx.Hash().Hex()),
+		zap.String("confirmationMode", c.confirmationMode))
+
+	receipt, err := c.waitForReceipt(ctx, signedTx.Hash())
+	if err != nil {
+		return "", fmt.Errorf("%w: transaction %s: %w", ErrUnconfirmed, signedTx.Hash().Hex(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", fmt.Errorf("transaction %s reverted in block %s", signedTx.Hash().Hex(), receipt.BlockNumber)
+	}
+	if err := c.waitForConfirmations(ctx, receipt); err != nil {
+		return "", err
+	}
+
+	c.logger.Debug("Transaction included",
+		zap.String("txHash", signedTx.Hash().Hex()),
+		zap.String("blockNumber", receipt.BlockNumber.String()),
+		zap.Uint64("gasUsed", receipt.GasUsed))
+
 	return signedTx.Hash().Hex(), nil
 }
+
+// waitForReceipt blocks until the transaction is included or the context is done.
+// WebSocket endpoints check for the receipt on every new head; HTTP endpoints
+// (or a failed subscription) fall back to polling.
+func (c *EVMClient) waitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if c.confirmationMode == ConfirmationModeSubscription {
+		receipt, err := c.waitForReceiptSubscription(ctx, txHash)
+		if err == nil || ctx.Err() != nil {
+			return receipt, err
+		}
+		c.logger.Warn("Head subscription failed, falling back to polling", zap.Error(err))
+	}
+	return c.waitForReceiptPolling(ctx, txHash)
+}
+
+// waitForReceiptSubscription checks for the receipt each time a new head arrives
+func (c *EVMClient) waitForReceiptSubscription(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	heads := make(chan *types.Header, 16)
+	sub, err := c.client.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to new heads: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	// The tx may already be included before the subscription was established
+	if receipt, err := c.fetchReceipt(ctx, txHash); receipt != nil || err != nil {
+		return receipt, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case err := <-sub.Err():
+			return nil, fmt.Errorf("head subscription dropped: %v", err)
+		case <-heads:
+			if receipt, err := c.fetchReceipt(ctx, txHash); receipt != nil || err != nil {
+				return receipt, err
+			}
+		}
+	}
+}
+
+// waitForReceiptPolling polls for the receipt until it is available
+func (c *EVMClient) waitForReceiptPolling(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if receipt, err := c.fetchReceipt(ctx, txHash); receipt != nil || err != nil {
+			return receipt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchReceipt returns the receipt, or nil without error if the tx is not yet included
+func (c *EVMClient) fetchReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := retryCall(ctx, c.retry, c.logger, "TransactionReceipt", func() (*types.Receipt, error) {
+		return c.client.TransactionReceipt(ctx, txHash)
+	})
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+// packRelayCall encodes the call data for the relay method using the cached ABI
+func (c *EVMClient) packRelayCall(vaaBytes []byte) ([]byte, error) {
+	args, err := c.relayMethod.Inputs.Pack(vaaBytes)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, c.relayMethod.ID...), args...), nil
+}
@@ -0,0 +1,185 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// FeeModel is how a chain charges for a transaction on top of EIP-1559 gas, which decides how its
+// tip and gas limit are chosen
+type FeeModel string
+
+const (
+	// FeeModelGeneric is plain EIP-1559: the tip and fee cap of FeeStrategy and the configured gas
+	// limit. Chains with no known model use it.
+	FeeModelGeneric FeeModel = "generic"
+	// FeeModelArbitrum is Arbitrum Nitro: the sequencer includes transactions first come, first
+	// served and never charges the tip, and the cost of posting the transaction to L1 is charged
+	// as extra L2 gas, which the gas limit must cover on top of execution
+	FeeModelArbitrum FeeModel = "arbitrum"
+	// FeeModelOPStack is the OP Stack (Base, OP Mainnet): EIP-1559 on L2 with its own base fee
+	// parameters, plus an L1 data fee taken from the sender's balance outside of gas, so neither
+	// the gas limit nor the fee cap bound it
+	FeeModelOPStack FeeModel = "op-stack"
+)
+
+// feeModelsByChainID are the fee models of known chains, by EVM chain ID
+var feeModelsByChainID = map[uint64]FeeModel{
+	42161:    FeeModelArbitrum, // Arbitrum One
+	42170:    FeeModelArbitrum, // Arbitrum Nova
+	421614:   FeeModelArbitrum, // Arbitrum Sepolia
+	10:       FeeModelOPStack,  // OP Mainnet
+	11155420: FeeModelOPStack,  // OP Sepolia
+	8453:     FeeModelOPStack,  // Base
+	84532:    FeeModelOPStack,  // Base Sepolia
+}
+
+// FeeModelForChain returns the fee model of the EVM chain chainID, FeeModelGeneric if unknown
+func FeeModelForChain(chainID uint64) FeeModel {
+	if model, ok := feeModelsByChainID[chainID]; ok {
+		return model
+	}
+	return FeeModelGeneric
+}
+
+// ParseFeeModel parses a fee model name. An empty name is returned as is, leaving the model to be
+// chosen from the chain ID.
+func ParseFeeModel(s string) (FeeModel, error) {
+	switch model := FeeModel(s); model {
+	case "", FeeModelGeneric, FeeModelArbitrum, FeeModelOPStack:
+		return model, nil
+	}
+	return "", fmt.Errorf("unknown fee model %q (want %s, %s or %s)", s, FeeModelGeneric, FeeModelArbitrum, FeeModelOPStack)
+}
+
+// arbitrumNodeInterface is Arbitrum's NodeInterface, a virtual contract answering eth_call only
+var arbitrumNodeInterface = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+// opStackGasPriceOracle is the OP Stack GasPriceOracle predeploy
+var opStackGasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+// nodeInterfaceABI is the part of the NodeInterface ABI estimating the L1 component of a call
+const nodeInterfaceABI = `[{
+        "inputs": [
+            {"internalType": "address", "name": "to", "type": "address"},
+            {"internalType": "bool", "name": "contractCreation", "type": "bool"},
+            {"internalType": "bytes", "name": "data", "type": "bytes"}
+        ],
+        "name": "gasEstimateL1Component",
+        "outputs": [
+            {"internalType": "uint64", "name": "gasEstimateForL1", "type": "uint64"},
+            {"internalType": "uint256", "name": "baseFee", "type": "uint256"},
+            {"internalType": "uint256", "name": "l1BaseFeeEstimate", "type": "uint256"}
+        ],
+        "stateMutability": "payable",
+        "type": "function"
+    }]`
+
+// gasPriceOracleABI is the part of the GasPriceOracle ABI estimating the L1 data fee of a transaction
+const gasPriceOracleABI = `[{
+        "inputs": [
+            {"internalType": "bytes", "name": "_data", "type": "bytes"}
+        ],
+        "name": "getL1Fee",
+        "outputs": [
+            {"internalType": "uint256", "name": "", "type": "uint256"}
+        ],
+        "stateMutability": "view",
+        "type": "function"
+    }]`
+
+// arbitrumL1GasMargin is the percentage added to Arbitrum's L1 gas estimate, as the L1 price can
+// rise between the estimate and inclusion. Unused gas is refunded, so the margin costs nothing.
+const arbitrumL1GasMargin = 25
+
+// parseL1FeeMethods parses NodeInterface.gasEstimateL1Component and GasPriceOracle.getL1Fee
+func parseL1FeeMethods() (abi.Method, abi.Method, error) {
+	nodeInterface, err := abi.JSON(strings.NewReader(nodeInterfaceABI))
+	if err != nil {
+		return abi.Method{}, abi.Method{}, fmt.Errorf("ABI parse error: %v", err)
+	}
+	gasPriceOracle, err := abi.JSON(strings.NewReader(gasPriceOracleABI))
+	if err != nil {
+		return abi.Method{}, abi.Method{}, fmt.Errorf("ABI parse error: %v", err)
+	}
+	return nodeInterface.Methods["gasEstimateL1Component"], gasPriceOracle.Methods["getL1Fee"], nil
+}
+
+// feeModelOf returns the configured fee model, or the one of chainID without one
+func (c *EVMClient) feeModelOf(chainID *big.Int) FeeModel {
+	if c.feeModel != "" {
+		return c.feeModel
+	}
+	if !chainID.IsUint64() {
+		return FeeModelGeneric
+	}
+	return FeeModelForChain(chainID.Uint64())
+}
+
+// arbitrumL1Gas asks Arbitrum's NodeInterface how much L2 gas posting a call of data to to costs on
+// L1, padded by arbitrumL1GasMargin
+func (c *EVMClient) arbitrumL1Gas(ctx context.Context, to common.Address, data []byte) (uint64, error) {
+	args, err := c.l1GasMethod.Inputs.Pack(to, false, data)
+	if err != nil {
+		return 0, fmt.Errorf("ABI pack error: %v", err)
+	}
+	nodeInterface := arbitrumNodeInterface
+	call := ethereum.CallMsg{To: &nodeInterface, Data: append(append([]byte{}, c.l1GasMethod.ID...), args...)}
+
+	output, err := retryCall(ctx, c.retry, c.logger, "CallContract", func() ([]byte, error) {
+		return limitRead(ctx, c.reads, func() ([]byte, error) {
+			return c.client.CallContract(ctx, call, nil)
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to call gasEstimateL1Component: %v", err)
+	}
+	values, err := c.l1GasMethod.Outputs.Unpack(output)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode gasEstimateL1Component result: %v", err)
+	}
+	l1Gas, ok := values[0].(uint64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected gasEstimateL1Component value type %T", values[0])
+	}
+	return l1Gas + l1Gas*arbitrumL1GasMargin/100, nil
+}
+
+// opStackL1Fee asks the OP Stack GasPriceOracle for the L1 data fee, in wei, of the unsigned tx
+func (c *EVMClient) opStackL1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	encoded, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %v", err)
+	}
+	args, err := c.l1FeeMethod.Inputs.Pack(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("ABI pack error: %v", err)
+	}
+	oracle := opStackGasPriceOracle
+	call := ethereum.CallMsg{To: &oracle, Data: append(append([]byte{}, c.l1FeeMethod.ID...), args...)}
+
+	output, err := retryCall(ctx, c.retry, c.logger, "CallContract", func() ([]byte, error) {
+		return limitRead(ctx, c.reads, func() ([]byte, error) {
+			return c.client.CallContract(ctx, call, nil)
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getL1Fee: %v", err)
+	}
+	values, err := c.l1FeeMethod.Outputs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode getL1Fee result: %v", err)
+	}
+	fee, ok := values[0].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected getL1Fee value type %T", values[0])
+	}
+	return fee, nil
+}
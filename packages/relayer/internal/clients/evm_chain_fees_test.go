@@ -0,0 +1,141 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// newCallServer starts a JSON-RPC server answering eth_call to the address to with result,
+// recording the call data of each call in calls
+func newCallServer(t *testing.T, to common.Address, result []byte, calls *[]hexutil.Bytes) *ethclient.Client {
+	t.Helper()
+	server := newJSONRPCParamsServer(t, func(method string, params []json.RawMessage) interface{} {
+		var call struct {
+			To    common.Address `json:"to"`
+			Input hexutil.Bytes  `json:"input"`
+			Data  hexutil.Bytes  `json:"data"`
+		}
+		if method != "eth_call" || len(params) == 0 || json.Unmarshal(params[0], &call) != nil || call.To != to {
+			t.Errorf("unexpected call %s %s", method, params)
+			return &jsonRPCError{Code: -32602, Message: "unexpected call"}
+		}
+		if call.Input == nil {
+			call.Input = call.Data
+		}
+		*calls = append(*calls, call.Input)
+		return hexutil.Bytes(result)
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial call server: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func newFeeModelTestClient(t *testing.T, ethClient *ethclient.Client) *EVMClient {
+	t.Helper()
+	l1GasMethod, l1FeeMethod, err := parseL1FeeMethods()
+	if err != nil {
+		t.Fatalf("parseL1FeeMethods failed: %v", err)
+	}
+	return &EVMClient{client: ethClient, l1GasMethod: l1GasMethod, l1FeeMethod: l1FeeMethod, logger: zap.NewNop()}
+}
+
+func TestFeeModelForChain(t *testing.T) {
+	for chainID, want := range map[uint64]FeeModel{
+		421614: FeeModelArbitrum,
+		42161:  FeeModelArbitrum,
+		84532:  FeeModelOPStack,
+		8453:   FeeModelOPStack,
+		1:      FeeModelGeneric,
+		31337:  FeeModelGeneric,
+	} {
+		if got := FeeModelForChain(chainID); got != want {
+			t.Errorf("chain %d: expected %s, got %s", chainID, want, got)
+		}
+	}
+
+	// A configured model wins over the chain ID
+	client := &EVMClient{feeModel: FeeModelGeneric}
+	if got := client.feeModelOf(big.NewInt(421614)); got != FeeModelGeneric {
+		t.Errorf("expected the configured model, got %s", got)
+	}
+	client.feeModel = ""
+	if got := client.feeModelOf(big.NewInt(421614)); got != FeeModelArbitrum {
+		t.Errorf("expected the model of the chain, got %s", got)
+	}
+
+	for _, name := range []string{"", "generic", "arbitrum", "op-stack"} {
+		if model, err := ParseFeeModel(name); err != nil || string(model) != name {
+			t.Errorf("expected %q to parse, got %q (%v)", name, model, err)
+		}
+	}
+	if _, err := ParseFeeModel("optimism"); err == nil {
+		t.Error("expected an unknown fee model to be rejected")
+	}
+}
+
+func TestArbitrumL1Gas(t *testing.T) {
+	// gasEstimateL1Component returns (gasEstimateForL1, baseFee, l1BaseFeeEstimate)
+	result := make([]byte, 96)
+	big.NewInt(40_000).FillBytes(result[0:32])
+	big.NewInt(100_000_000).FillBytes(result[32:64])
+	big.NewInt(30_000_000_000).FillBytes(result[64:96])
+
+	var calls []hexutil.Bytes
+	client := newFeeModelTestClient(t, newCallServer(t, arbitrumNodeInterface, result, &calls))
+	target := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	l1Gas, err := client.arbitrumL1Gas(context.Background(), target, []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatalf("arbitrumL1Gas failed: %v", err)
+	}
+	if l1Gas != 50_000 {
+		t.Errorf("expected 40000 gas plus a 25%% margin, got %d", l1Gas)
+	}
+
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	args, err := client.l1GasMethod.Inputs.Unpack(calls[0][4:])
+	if err != nil {
+		t.Fatalf("failed to decode the call: %v", err)
+	}
+	if args[0].(common.Address) != target || args[1].(bool) || string(args[2].([]byte)) != "\x01\x02" {
+		t.Errorf("expected a call to %s with the data, got %v", target.Hex(), args)
+	}
+}
+
+func TestOPStackL1Fee(t *testing.T) {
+	result := make([]byte, 32)
+	big.NewInt(123_456_789).FillBytes(result)
+
+	var calls []hexutil.Bytes
+	client := newFeeModelTestClient(t, newCallServer(t, opStackGasPriceOracle, result, &calls))
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(84532), Gas: 100_000, Data: []byte{0x01}})
+
+	fee, err := client.opStackL1Fee(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("opStackL1Fee failed: %v", err)
+	}
+	if fee.Int64() != 123_456_789 {
+		t.Errorf("expected a fee of 123456789 wei, got %s", fee)
+	}
+
+	// The oracle is passed the encoded transaction
+	encoded, _ := tx.MarshalBinary()
+	if len(calls) != 1 || !strings.Contains(calls[0].String(), hexutil.Encode(encoded)[2:]) {
+		t.Errorf("expected the encoded transaction to be passed to getL1Fee, got %v", calls)
+	}
+}
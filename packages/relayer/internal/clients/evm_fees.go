@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+)
+
+// DefaultPriorityFee is the tip, in wei, used when no node can suggest one: 0.1 gwei
+var DefaultPriorityFee = big.NewInt(100_000_000)
+
+// DefaultEVMGasLimit is the gas limit of each transaction when none is configured
+const DefaultEVMGasLimit uint64 = 3_000_000
+
+// Sources of the fee cap (max fee per gas) of a transaction, as logged
+const (
+	MaxFeeSourceBaseFee    = "base_fee"   // A multiple of the latest base fee plus the tip
+	MaxFeeSourceConfigured = "configured" // The configured fee cap
+)
+
+// Sources of the priority fee of a transaction, as logged
+const (
+	PriorityFeeSourceRPC    = "rpc"    // eth_maxPriorityFeePerGas of the client's RPC
+	PriorityFeeSourceOracle = "oracle" // eth_maxPriorityFeePerGas of the fee oracle
+	PriorityFeeSourceStatic = "static" // The static fallback tip
+	PriorityFeeSourceNone   = "none"   // No tip, as the chain never charges one (FeeModelArbitrum)
+)
+
+// PriorityFeeConfig selects the EIP-1559 tip of each transaction. The tip suggested by
+// eth_maxPriorityFeePerGas, of OracleURL if set or of the client's RPC otherwise, is clamped to
+// [Min, Max]; Static is used instead when the node does not support the method or the call fails.
+type PriorityFeeConfig struct {
+	Static    *big.Int // Fallback tip in wei (nil = DefaultPriorityFee)
+	Min       *big.Int // Lowest tip in wei (nil = no lower bound)
+	Max       *big.Int // Highest tip in wei (nil = no upper bound)
+	OracleURL string   // RPC endpoint queried for the tip instead of the client's RPC (optional)
+}
+
+// DefaultBaseFeeMultiplier is how many times the latest base fee the fee cap covers when no
+// multiplier is configured, leaving room for the base fee to rise before inclusion
+const DefaultBaseFeeMultiplier uint64 = 2
+
+// FeeStrategy computes the EIP-1559 fees of a transaction from the latest block header: the tip,
+// chosen as Tip describes, and the fee cap, BaseFeeMultiplier times the base fee plus the tip
+// unless MaxFee replaces it. It only does the arithmetic; EVMClient fetches the header and the
+// suggested tip.
+type FeeStrategy struct {
+	Tip               PriorityFeeConfig
+	BaseFeeMultiplier uint64   // 0 = DefaultBaseFeeMultiplier
+	MaxFee            *big.Int // Fee cap in wei (nil = computed from the base fee)
+	// Fee model of the chain; FeeModelArbitrum sends no tip, whatever is suggested or configured
+	Model FeeModel
+}
+
+// Fees are the EIP-1559 fees of one transaction and where they came from, as logged
+type Fees struct {
+	BaseFee              *big.Int // Base fee of the header the fees were computed from
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeeSource         string // MaxFeeSourceBaseFee or MaxFeeSourceConfigured
+	PriorityFeeSource    string // PriorityFeeSourceRPC, PriorityFeeSourceOracle, PriorityFeeSourceStatic or PriorityFeeSourceNone
+}
+
+// Fees returns the fees of a transaction built on header. suggestedTip is the tip suggested by
+// tipSource, or nil when none could be fetched, in which case the static tip is used.
+func (s FeeStrategy) Fees(header *types.Header, suggestedTip *big.Int, tipSource string) (Fees, error) {
+	if header == nil || header.BaseFee == nil {
+		return Fees{}, fmt.Errorf("latest block has no base fee; the chain does not support EIP-1559 transactions")
+	}
+
+	tip := suggestedTip
+	if tip == nil {
+		tip, tipSource = s.Tip.Static, PriorityFeeSourceStatic
+		if tip == nil {
+			tip = DefaultPriorityFee
+		}
+	}
+	tip = clampPriorityFee(tip, s.Tip.Min, s.Tip.Max)
+	if s.Model == FeeModelArbitrum {
+		// The sequencer charges the base fee only; a tip would just raise the fee cap
+		tip, tipSource = new(big.Int), PriorityFeeSourceNone
+	}
+
+	fees := Fees{BaseFee: header.BaseFee, PriorityFeeSource: tipSource}
+	fees.MaxFeePerGas, fees.MaxPriorityFeePerGas, fees.MaxFeeSource = s.feeCap(header.BaseFee, tip)
+	return fees, nil
+}
+
+// feeCap returns the fee cap (max fee per gas) of a transaction with tip at baseFee, the tip
+// adjusted to it, and where the cap came from. A configured cap replaces the one computed from
+// the base fee, lowering the tip to the cap if needed as nodes reject a tip above the fee cap.
+func (s FeeStrategy) feeCap(baseFee, tip *big.Int) (*big.Int, *big.Int, string) {
+	if s.MaxFee != nil {
+		if tip.Cmp(s.MaxFee) > 0 {
+			tip = new(big.Int).Set(s.MaxFee)
+		}
+		return new(big.Int).Set(s.MaxFee), tip, MaxFeeSourceConfigured
+	}
+	multiplier := s.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultBaseFeeMultiplier
+	}
+	maxFee := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(multiplier))
+	return maxFee.Add(maxFee, tip), tip, MaxFeeSourceBaseFee
+}
+
+// suggestedTip asks the fee oracle, or the client's RPC without one, for the tip of the next
+// transaction. It returns nil when the node does not support eth_maxPriorityFeePerGas or the
+// call fails, leaving the fee strategy to use its static tip.
+func (c *EVMClient) suggestedTip(ctx context.Context) (*big.Int, string) {
+	source, suggester := PriorityFeeSourceRPC, c.client
+	if c.feeOracle != nil {
+		source, suggester = PriorityFeeSourceOracle, c.feeOracle
+	}
+	suggested, err := retryCall(ctx, c.retry, c.logger, "SuggestGasTipCap", func() (*big.Int, error) {
+		return suggester.SuggestGasTipCap(ctx)
+	})
+	if err != nil {
+		c.logger.Debug("Could not get a suggested priority fee, using the static tip",
+			zap.String("source", source),
+			zap.Error(err))
+		return nil, PriorityFeeSourceStatic
+	}
+	return suggested, source
+}
+
+// clampPriorityFee bounds tip to [lower, upper]; a nil bound is not applied
+func clampPriorityFee(tip, lower, upper *big.Int) *big.Int {
+	if lower != nil && tip.Cmp(lower) < 0 {
+		return new(big.Int).Set(lower)
+	}
+	if upper != nil && tip.Cmp(upper) > 0 {
+		return new(big.Int).Set(upper)
+	}
+	return tip
+}
@@ -0,0 +1,170 @@
+package clients
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+)
+
+// newTipServer starts a JSON-RPC server answering eth_maxPriorityFeePerGas with tip,
+// or with a method-not-found error if tip is empty
+func newTipServer(t *testing.T, tip string) *ethclient.Client {
+	t.Helper()
+	server := newJSONRPCServer(t, func(method string) interface{} {
+		if method != "eth_maxPriorityFeePerGas" {
+			t.Errorf("unexpected method %s", method)
+		}
+		if tip == "" {
+			return &jsonRPCError{Code: -32601, Message: "the method eth_maxPriorityFeePerGas does not exist/is not available"}
+		}
+		return tip
+	})
+
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("failed to dial tip server: %v", err)
+	}
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClampPriorityFee(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000)) }
+
+	tests := []struct {
+		name         string
+		tip          *big.Int
+		lower, upper *big.Int
+		want         *big.Int
+	}{
+		{name: "unbounded", tip: gwei(5), want: gwei(5)},
+		{name: "within bounds", tip: gwei(5), lower: gwei(1), upper: gwei(10), want: gwei(5)},
+		{name: "below minimum", tip: big.NewInt(1), lower: gwei(1), upper: gwei(10), want: gwei(1)},
+		{name: "above maximum", tip: gwei(50), lower: gwei(1), upper: gwei(10), want: gwei(10)},
+		{name: "only a maximum", tip: gwei(50), upper: gwei(10), want: gwei(10)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clampPriorityFee(tt.tip, tt.lower, tt.upper); got.Cmp(tt.want) != 0 {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEVMClientPriorityFee(t *testing.T) {
+	supported := newTipServer(t, "0x3b9aca00") // 1 gwei
+	unsupported := newTipServer(t, "")
+	header := &types.Header{BaseFee: big.NewInt(1_000_000_000)}
+
+	tests := []struct {
+		name       string
+		client     *EVMClient
+		wantTip    int64
+		wantSource string
+	}{
+		{
+			name:       "suggested by the RPC",
+			client:     &EVMClient{client: supported},
+			wantTip:    1_000_000_000,
+			wantSource: PriorityFeeSourceRPC,
+		},
+		{
+			name:       "suggested by the oracle",
+			client:     &EVMClient{client: unsupported, feeOracle: supported},
+			wantTip:    1_000_000_000,
+			wantSource: PriorityFeeSourceOracle,
+		},
+		{
+			name:       "suggestion clamped",
+			client:     &EVMClient{client: supported, fees: FeeStrategy{Tip: PriorityFeeConfig{Max: big.NewInt(500_000_000)}}},
+			wantTip:    500_000_000,
+			wantSource: PriorityFeeSourceRPC,
+		},
+		{
+			name:       "default static fallback",
+			client:     &EVMClient{client: unsupported},
+			wantTip:    DefaultPriorityFee.Int64(),
+			wantSource: PriorityFeeSourceStatic,
+		},
+		{
+			name:       "configured static fallback",
+			client:     &EVMClient{client: unsupported, fees: FeeStrategy{Tip: PriorityFeeConfig{Static: big.NewInt(2_000_000_000)}}},
+			wantTip:    2_000_000_000,
+			wantSource: PriorityFeeSourceStatic,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.client.logger = zap.NewNop()
+			suggested, source := tt.client.suggestedTip(context.Background())
+			fees, err := tt.client.fees.Fees(header, suggested, source)
+			if err != nil {
+				t.Fatalf("Fees failed: %v", err)
+			}
+			if fees.MaxPriorityFeePerGas.Int64() != tt.wantTip || fees.PriorityFeeSource != tt.wantSource {
+				t.Errorf("expected %d from %s, got %s from %s", tt.wantTip, tt.wantSource, fees.MaxPriorityFeePerGas, fees.PriorityFeeSource)
+			}
+		})
+	}
+}
+
+func TestFeeStrategy(t *testing.T) {
+	gwei := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), big.NewInt(1_000_000_000)) }
+
+	tests := []struct {
+		name                string
+		strategy            FeeStrategy
+		baseFee, tip        *big.Int
+		wantMaxFee, wantTip *big.Int
+		wantSource          string
+	}{
+		{name: "zero base fee", baseFee: big.NewInt(0), tip: gwei(1), wantMaxFee: gwei(1), wantTip: gwei(1), wantSource: MaxFeeSourceBaseFee},
+		{name: "1 wei base fee", baseFee: big.NewInt(1), tip: gwei(1), wantMaxFee: new(big.Int).Add(gwei(1), big.NewInt(2)), wantTip: gwei(1), wantSource: MaxFeeSourceBaseFee},
+		{name: "10 gwei base fee", baseFee: gwei(10), tip: gwei(1), wantMaxFee: gwei(21), wantTip: gwei(1), wantSource: MaxFeeSourceBaseFee},
+		{name: "10k gwei base fee", baseFee: gwei(10_000), tip: gwei(2), wantMaxFee: gwei(20_002), wantTip: gwei(2), wantSource: MaxFeeSourceBaseFee},
+		{name: "multiplier", strategy: FeeStrategy{BaseFeeMultiplier: 3}, baseFee: gwei(10), tip: gwei(1), wantMaxFee: gwei(31), wantTip: gwei(1), wantSource: MaxFeeSourceBaseFee},
+		{name: "configured cap", strategy: FeeStrategy{MaxFee: gwei(15)}, baseFee: gwei(10), tip: gwei(1), wantMaxFee: gwei(15), wantTip: gwei(1), wantSource: MaxFeeSourceConfigured},
+		{name: "cap below the base fee", strategy: FeeStrategy{MaxFee: gwei(15)}, baseFee: gwei(100), tip: gwei(1), wantMaxFee: gwei(15), wantTip: gwei(1), wantSource: MaxFeeSourceConfigured},
+		{name: "tip above the cap", strategy: FeeStrategy{MaxFee: gwei(3)}, baseFee: gwei(1), tip: gwei(5), wantMaxFee: gwei(3), wantTip: gwei(3), wantSource: MaxFeeSourceConfigured},
+		{name: "tip clamped before the cap", strategy: FeeStrategy{Tip: PriorityFeeConfig{Min: gwei(2)}}, baseFee: gwei(10), tip: gwei(1), wantMaxFee: gwei(22), wantTip: gwei(2), wantSource: MaxFeeSourceBaseFee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fees, err := tt.strategy.Fees(&types.Header{BaseFee: tt.baseFee}, tt.tip, PriorityFeeSourceRPC)
+			if err != nil {
+				t.Fatalf("Fees failed: %v", err)
+			}
+			if fees.MaxFeePerGas.Cmp(tt.wantMaxFee) != 0 || fees.MaxPriorityFeePerGas.Cmp(tt.wantTip) != 0 || fees.MaxFeeSource != tt.wantSource {
+				t.Errorf("expected %s (tip %s) from %s, got %s (tip %s) from %s",
+					tt.wantMaxFee, tt.wantTip, tt.wantSource, fees.MaxFeePerGas, fees.MaxPriorityFeePerGas, fees.MaxFeeSource)
+			}
+			if fees.BaseFee.Cmp(tt.baseFee) != 0 || fees.PriorityFeeSource != PriorityFeeSourceRPC {
+				t.Errorf("expected base fee %s from rpc, got %s from %s", tt.baseFee, fees.BaseFee, fees.PriorityFeeSource)
+			}
+		})
+	}
+
+	// Arbitrum never charges a tip, so none is sent whatever is suggested or configured
+	arbitrum := FeeStrategy{Model: FeeModelArbitrum, Tip: PriorityFeeConfig{Min: gwei(1)}}
+	fees, err := arbitrum.Fees(&types.Header{BaseFee: gwei(10)}, gwei(2), PriorityFeeSourceRPC)
+	if err != nil {
+		t.Fatalf("Fees failed: %v", err)
+	}
+	if fees.MaxPriorityFeePerGas.Sign() != 0 || fees.PriorityFeeSource != PriorityFeeSourceNone || fees.MaxFeePerGas.Cmp(gwei(20)) != 0 {
+		t.Errorf("expected no tip and a 20 gwei cap on Arbitrum, got tip %s from %s and cap %s",
+			fees.MaxPriorityFeePerGas, fees.PriorityFeeSource, fees.MaxFeePerGas)
+	}
+
+	// Pre-London chains have no base fee to build EIP-1559 fees from
+	if _, err := (FeeStrategy{}).Fees(&types.Header{}, big.NewInt(1), PriorityFeeSourceRPC); err == nil {
+		t.Error("expected a header without a base fee to be rejected")
+	}
+}
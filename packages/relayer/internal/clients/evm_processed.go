@@ -0,0 +1,173 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Arguments a processed check passes to the view function, in the order of ProcessedCheckConfig.Args
+const (
+	ProcessedArgChain    = "chain"    // Emitter chain ID (uint16)
+	ProcessedArgEmitter  = "emitter"  // Emitter address (bytes32)
+	ProcessedArgSequence = "sequence" // Sequence (uint64)
+	ProcessedArgHash     = "hash"     // VAA digest, the double keccak256 of the body (bytes32)
+)
+
+// DefaultProcessedCheckArgs are the arguments passed when none are configured
+var DefaultProcessedCheckArgs = []string{ProcessedArgChain, ProcessedArgEmitter, ProcessedArgSequence}
+
+// processedArgTypes are the Solidity types each argument must be declared with
+var processedArgTypes = map[string]string{
+	ProcessedArgChain:    "uint16",
+	ProcessedArgEmitter:  "bytes32",
+	ProcessedArgSequence: "uint64",
+	ProcessedArgHash:     "bytes32",
+}
+
+// ProcessedCheckConfig describes a view function on the target contract reporting whether a VAA
+// was already consumed, e.g. isProcessed(uint16,bytes32,uint64) or a public mapping getter
+// such as processed(bytes32). It must return a single bool.
+type ProcessedCheckConfig struct {
+	Signature string   // Function signature, e.g. isProcessed(uint16,bytes32,uint64)
+	Args      []string // What is passed for each parameter, in order (default chain, emitter, sequence)
+}
+
+// VAAReference identifies the VAA a processed check asks about
+type VAAReference struct {
+	EmitterChain uint16
+	Emitter      [32]byte
+	Sequence     uint64
+	Digest       [32]byte // Double keccak256 of the VAA body
+}
+
+// String returns the reference as chain/emitter/sequence, the form of the VAA identity in logs
+func (r VAAReference) String() string {
+	return fmt.Sprintf("%d/%x/%d", r.EmitterChain, r.Emitter, r.Sequence)
+}
+
+// processedCheck is a parsed ProcessedCheckConfig
+type processedCheck struct {
+	method abi.Method
+	args   []string
+}
+
+// newProcessedCheck parses and validates config: the signature's parameters must match the
+// types of the configured arguments
+func newProcessedCheck(config ProcessedCheckConfig) (*processedCheck, error) {
+	signature := strings.ReplaceAll(config.Signature, " ", "")
+	open := strings.Index(signature, "(")
+	if open <= 0 || !strings.HasSuffix(signature, ")") {
+		return nil, fmt.Errorf("invalid processed check signature %q: expected name(type,...)", config.Signature)
+	}
+	name := signature[:open]
+	var types []string
+	if params := signature[open+1 : len(signature)-1]; params != "" {
+		types = strings.Split(params, ",")
+	}
+
+	args := config.Args
+	if len(args) == 0 {
+		args = DefaultProcessedCheckArgs
+	}
+	if len(args) != len(types) {
+		return nil, fmt.Errorf("processed check %s takes %d parameters but %d arguments are configured (%s)",
+			signature, len(types), len(args), strings.Join(args, ","))
+	}
+
+	inputs := make(abi.Arguments, len(types))
+	for i, arg := range args {
+		want, ok := processedArgTypes[arg]
+		if !ok {
+			return nil, fmt.Errorf("unknown processed check argument %q (expected chain, emitter, sequence or hash)", arg)
+		}
+		if types[i] != want {
+			return nil, fmt.Errorf("processed check %s: parameter %d is %s, but %s is passed as %s", signature, i+1, types[i], arg, want)
+		}
+		t, err := abi.NewType(want, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		inputs[i] = abi.Argument{Type: t}
+	}
+	boolType, err := abi.NewType("bool", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	outputs := abi.Arguments{{Type: boolType}}
+
+	method := abi.NewMethod(name, name, abi.Function, "view", false, false, inputs, outputs)
+	return &processedCheck{method: method, args: args}, nil
+}
+
+// pack returns the call data asking whether ref was processed
+func (p *processedCheck) pack(ref VAAReference) ([]byte, error) {
+	values := make([]interface{}, len(p.args))
+	for i, arg := range p.args {
+		switch arg {
+		case ProcessedArgChain:
+			values[i] = ref.EmitterChain
+		case ProcessedArgEmitter:
+			values[i] = ref.Emitter
+		case ProcessedArgSequence:
+			values[i] = ref.Sequence
+		case ProcessedArgHash:
+			values[i] = ref.Digest
+		}
+	}
+	args, err := p.method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("ABI pack error: %v", err)
+	}
+	return append(append([]byte{}, p.method.ID...), args...), nil
+}
+
+// unpack decodes the bool returned by the view function
+func (p *processedCheck) unpack(output []byte) (bool, error) {
+	values, err := p.method.Outputs.Unpack(output)
+	if err != nil {
+		return false, err
+	}
+	if len(values) != 1 {
+		return false, fmt.Errorf("expected 1 value, got %d", len(values))
+	}
+	processed, ok := values[0].(bool)
+	if !ok {
+		return false, fmt.Errorf("unexpected value type %T", values[0])
+	}
+	return processed, nil
+}
+
+// IsVAAProcessed calls the configured processed check on targetContract and reports whether it
+// already consumed the VAA ref names. Without a configured check it returns false, leaving it to
+// the transaction to find out.
+func (c *EVMClient) IsVAAProcessed(ctx context.Context, targetContract string, ref VAAReference) (bool, error) {
+	if c.processedCheck == nil {
+		return false, nil
+	}
+	data, err := c.processedCheck.pack(ref)
+	if err != nil {
+		return false, err
+	}
+	target := common.HexToAddress(targetContract)
+	call := ethereum.CallMsg{To: &target, Data: data}
+	name := c.processedCheck.method.Name
+
+	output, err := retryCall(ctx, c.retry, c.logger, "CallContract", func() ([]byte, error) {
+		return limitRead(ctx, c.reads, func() ([]byte, error) {
+			return c.client.CallContract(ctx, call, nil)
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to call %s on %s: %v", name, targetContract, err)
+	}
+	processed, err := c.processedCheck.unpack(output)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode %s result from %s: %v", name, targetContract, err)
+	}
+	return processed, nil
+}
@@ -0,0 +1,142 @@
+package clients
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+func newTestEVMClient(tb testing.TB) *EVMClient {
+	tb.Helper()
+	parsedABI, err := abi.JSON(strings.NewReader(receiveValueABI))
+	if err != nil {
+		tb.Fatalf("failed to parse ABI: %v", err)
+	}
+	emittersABI, err := abi.JSON(strings.NewReader(registeredEmittersABI))
+	if err != nil {
+		tb.Fatalf("failed to parse ABI: %v", err)
+	}
+	return &EVMClient{contractABI: parsedABI, relayMethod: parsedABI.Methods["receiveValue"], emittersMethod: emittersABI.Methods["registeredEmitters"]}
+}
+
+func TestPackRelayCallMatchesABIPack(t *testing.T) {
+	client := newTestEVMClient(t)
+	vaaBytes := bytes.Repeat([]byte{0xab}, 300)
+
+	got, err := client.packRelayCall(vaaBytes)
+	if err != nil {
+		t.Fatalf("packRelayCall failed: %v", err)
+	}
+	want, err := client.contractABI.Pack("receiveValue", vaaBytes)
+	if err != nil {
+		t.Fatalf("abi.Pack failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("packed call data mismatch")
+	}
+}
+
+// BenchmarkPackRelayCall_Cached measures call-data packing with the ABI cached on the client
+func BenchmarkPackRelayCall_Cached(b *testing.B) {
+	client := newTestEVMClient(b)
+	vaaBytes := bytes.Repeat([]byte{0xab}, 300)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.packRelayCall(vaaBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPackRelayCall_Reparse measures the previous behaviour of parsing the ABI on every call
+func BenchmarkPackRelayCall_Reparse(b *testing.B) {
+	vaaBytes := bytes.Repeat([]byte{0xab}, 300)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parsedABI, err := abi.JSON(strings.NewReader(receiveValueABI))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := parsedABI.Pack("receiveValue", vaaBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUnpackRegisteredEmitter(t *testing.T) {
+	client := newTestEVMClient(t)
+	var emitter [32]byte
+	emitter[31] = 0xaa
+
+	output, err := client.emittersMethod.Outputs.Pack(emitter)
+	if err != nil {
+		t.Fatalf("failed to pack output: %v", err)
+	}
+	got, err := client.unpackRegisteredEmitter(output)
+	if err != nil || got != emitter {
+		t.Errorf("expected %x, got %x (%v)", emitter, got, err)
+	}
+	if _, err := client.unpackRegisteredEmitter(output[:16]); err == nil {
+		t.Error("expected a truncated result to be rejected")
+	}
+}
+
+func TestProcessedCheck(t *testing.T) {
+	check, err := newProcessedCheck(ProcessedCheckConfig{Signature: "isProcessed(uint16, bytes32, uint64)"})
+	if err != nil {
+		t.Fatalf("newProcessedCheck failed: %v", err)
+	}
+	ref := VAAReference{EmitterChain: 2, Sequence: 42}
+	ref.Emitter[31] = 0xaa
+
+	data, err := check.pack(ref)
+	if err != nil {
+		t.Fatalf("pack failed: %v", err)
+	}
+	want, err := abi.Arguments{check.method.Inputs[0], check.method.Inputs[1], check.method.Inputs[2]}.Pack(ref.EmitterChain, ref.Emitter, ref.Sequence)
+	if err != nil {
+		t.Fatalf("abi pack failed: %v", err)
+	}
+	if !bytes.Equal(data[:4], check.method.ID) || !bytes.Equal(data[4:], want) {
+		t.Errorf("unexpected call data %x", data)
+	}
+	if sig := check.method.Sig; sig != "isProcessed(uint16,bytes32,uint64)" {
+		t.Errorf("expected the canonical signature, got %s", sig)
+	}
+
+	output, err := check.method.Outputs.Pack(true)
+	if err != nil {
+		t.Fatalf("failed to pack output: %v", err)
+	}
+	if processed, err := check.unpack(output); err != nil || !processed {
+		t.Errorf("expected true, got %v (%v)", processed, err)
+	}
+
+	// A mapping getter keyed by the VAA digest
+	if _, err := newProcessedCheck(ProcessedCheckConfig{Signature: "processed(bytes32)", Args: []string{"hash"}}); err != nil {
+		t.Errorf("expected a digest getter to be accepted, got %v", err)
+	}
+
+	for _, config := range []ProcessedCheckConfig{
+		{Signature: "isProcessed"},
+		{Signature: "isProcessed(uint16,bytes32)"},
+		{Signature: "isProcessed(uint256,bytes32,uint64)"},
+		{Signature: "processed(bytes32)", Args: []string{"digest"}},
+	} {
+		if _, err := newProcessedCheck(config); err == nil {
+			t.Errorf("expected %+v to be rejected", config)
+		}
+	}
+}
+
+func TestVAAReferenceString(t *testing.T) {
+	ref := VAAReference{EmitterChain: 2, Sequence: 7}
+	ref.Emitter[31] = 0xaa
+	if got, want := ref.String(), "2/"+strings.Repeat("00", 31)+"aa/7"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
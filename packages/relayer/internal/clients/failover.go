@@ -0,0 +1,222 @@
+package clients
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultFailbackInterval is how often the preferred RPC endpoint is tried again while a
+// fallback endpoint is serving requests
+const DefaultFailbackInterval = time.Minute
+
+// ParseRPCURLs splits a comma-separated list of RPC URLs, the first being the preferred endpoint.
+// Failover resends requests over HTTP, so a list of several URLs may only hold http(s) URLs.
+func ParseRPCURLs(list string) ([]string, error) {
+	var urls []string
+	for _, rawURL := range strings.Split(list, ",") {
+		if rawURL = strings.TrimSpace(rawURL); rawURL != "" {
+			urls = append(urls, rawURL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no RPC URL provided")
+	}
+	if len(urls) > 1 {
+		for _, rawURL := range urls {
+			parsed, err := url.Parse(rawURL)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RPC URL %q: %v", rawURL, err)
+			}
+			if parsed.Scheme != "http" && parsed.Scheme != "https" {
+				return nil, fmt.Errorf("invalid RPC URL %q: failover between several endpoints needs http(s) URLs", rawURL)
+			}
+		}
+	}
+	return urls, nil
+}
+
+// rpcFailover is an http.RoundTripper sending JSON-RPC requests to one of several endpoints of the
+// same chain. Requests go to the active endpoint, the preferred (first) one to begin with; on a
+// connection error or a 5xx response the request is resent to the next endpoint, which becomes
+// active if it answers. While a fallback is active, the preferred endpoint is tried again every
+// failbackInterval and becomes active again as soon as it answers.
+type rpcFailover struct {
+	endpoints        []*url.URL
+	next             http.RoundTripper
+	failbackInterval time.Duration
+	logger           *zap.Logger
+
+	mu           sync.Mutex
+	active       int       // Index of the endpoint serving requests
+	lastFailback time.Time // When the preferred endpoint was last given up on or tried again
+}
+
+// Every failover in use, reported on /healthz by ActiveRPCEndpoints
+var (
+	failoversMu sync.Mutex
+	failovers   []*rpcFailover
+)
+
+// newRPCFailover creates a failover transport between urls, sending requests through next
+func newRPCFailover(logger *zap.Logger, urls []string, next http.RoundTripper) (*rpcFailover, error) {
+	f := &rpcFailover{
+		next:             next,
+		failbackInterval: DefaultFailbackInterval,
+		logger:           logger,
+	}
+	for _, rawURL := range urls {
+		endpoint, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RPC URL %q: %v", rawURL, err)
+		}
+		f.endpoints = append(f.endpoints, endpoint)
+	}
+
+	failoversMu.Lock()
+	failovers = append(failovers, f)
+	failoversMu.Unlock()
+	return f, nil
+}
+
+// RoundTrip sends req to the active endpoint, failing over to the others in turn
+func (f *rpcFailover) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	first := f.pick()
+	for attempt := 0; ; attempt++ {
+		index := (first + attempt) % len(f.endpoints)
+		resp, err := f.next.RoundTrip(f.rewrite(req, index, body))
+		if req.Context().Err() != nil || !endpointFailed(resp, err) {
+			if err == nil {
+				f.settle(index)
+			}
+			return resp, err
+		}
+
+		last := attempt == len(f.endpoints)-1
+		f.failed(index, resp, err, last)
+		if last {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+	}
+}
+
+// endpointFailed reports whether the endpoint, rather than the request, is at fault
+func endpointFailed(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// pick returns the endpoint a new request is sent to first: the active one, or the preferred
+// one if it is due to be tried again
+func (f *rpcFailover) pick() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active != 0 && time.Since(f.lastFailback) >= f.failbackInterval {
+		f.lastFailback = time.Now()
+		return 0
+	}
+	return f.active
+}
+
+// settle makes the endpoint that answered a request the active one
+func (f *rpcFailover) settle(index int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if index == f.active {
+		return
+	}
+	if index == 0 {
+		f.logger.Info("Preferred RPC endpoint recovered, switching back",
+			zap.String("from", f.endpoints[f.active].String()),
+			zap.String("to", f.endpoints[0].String()))
+	} else {
+		f.logger.Warn("Failed over to another RPC endpoint",
+			zap.String("from", f.endpoints[f.active].String()),
+			zap.String("to", f.endpoints[index].String()))
+	}
+	f.active = index
+}
+
+// failed logs a failed attempt on an endpoint, remembering when the preferred one was given up on
+func (f *rpcFailover) failed(index int, resp *http.Response, err error, last bool) {
+	f.mu.Lock()
+	if index == 0 && f.active == 0 {
+		f.lastFailback = time.Now()
+	}
+	f.mu.Unlock()
+
+	fields := []zap.Field{zap.String("endpoint", f.endpoints[index].String()), zap.Bool("lastEndpoint", last)}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	} else {
+		fields = append(fields, zap.Int("status", resp.StatusCode))
+	}
+	f.logger.Warn("RPC endpoint failed", fields...)
+}
+
+// rewrite returns a copy of req addressed to the endpoint at index, with its own copy of body
+func (f *rpcFailover) rewrite(req *http.Request, index int, body []byte) *http.Request {
+	out := req.Clone(req.Context())
+	endpoint := *f.endpoints[index]
+	out.URL = &endpoint
+	out.Host = endpoint.Host
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		out.ContentLength = int64(len(body))
+	}
+	return out
+}
+
+// Active returns the URL of the endpoint serving requests
+func (f *rpcFailover) Active() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.endpoints[f.active].String()
+}
+
+// ActiveRPCEndpoints maps the preferred URL of each RPC failover in use to the URL of the endpoint
+// currently serving it. Paths and queries are redacted, since providers put API keys in them.
+func ActiveRPCEndpoints() map[string]string {
+	failoversMu.Lock()
+	defer failoversMu.Unlock()
+	if len(failovers) == 0 {
+		return nil
+	}
+	active := make(map[string]string, len(failovers))
+	for _, f := range failovers {
+		f.mu.Lock()
+		active[redactURL(f.endpoints[0])] = redactURL(f.endpoints[f.active])
+		f.mu.Unlock()
+	}
+	return active
+}
+
+// redactURL returns the scheme and host of u, marking any path or query it had
+func redactURL(u *url.URL) string {
+	redacted := u.Scheme + "://" + u.Host
+	if strings.Trim(u.Path, "/") != "" || u.RawQuery != "" {
+		redacted += "/..."
+	}
+	return redacted
+}
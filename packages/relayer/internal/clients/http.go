@@ -0,0 +1,201 @@
+package clients
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// newHTTPClient returns an HTTP client with the given timeout that sends requests through
+// transport. A nil transport uses a clone of http.DefaultTransport, which honours the
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables. A custom transport is used
+// as is, so it must set its own Proxy to go through a proxy.
+func newHTTPClient(timeout time.Duration, transport *http.Transport) *http.Client {
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// ServiceAuth is attached to every request to an HTTP service that sits behind an auth gateway.
+// Its values are credentials: they are never logged, and String lists only the header names.
+type ServiceAuth struct {
+	BearerToken string            // Sent as Authorization: Bearer <token> (empty = none)
+	Headers     map[string]string // Static headers, e.g. an API key (nil = none)
+}
+
+// apply sets the headers, then the bearer token, on req
+func (a ServiceAuth) apply(req *http.Request) {
+	for name, value := range a.Headers {
+		req.Header.Set(name, value)
+	}
+	if a.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+a.BearerToken)
+	}
+}
+
+// String describes a without its values, so printing a config cannot leak them
+func (a ServiceAuth) String() string {
+	names := make([]string, 0, len(a.Headers)+1)
+	for name := range a.Headers {
+		names = append(names, http.CanonicalHeaderKey(name))
+	}
+	if a.BearerToken != "" {
+		names = append(names, "Authorization")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",") + " (redacted)"
+}
+
+// maxErrorBodyLength bounds how much of a raw response body is kept in an error
+const maxErrorBodyLength = 512
+
+// HTTPStatusError is a non-2xx response from an HTTP service. Body holds the service's own
+// error message when it sent one, or the start of the raw body (e.g. a proxy's HTML page).
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// DefaultMaxResponseBytes bounds the response bodies read from the verification and VAA posting
+// services when no limit is configured, and from the Cosmos LCD. Their responses are at most a
+// few kilobytes of JSON.
+const DefaultMaxResponseBytes int64 = 4 << 20
+
+// ErrResponseTooLarge is returned when a service's response body exceeds the configured limit
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// readResponseBody reads r up to limit bytes (DefaultMaxResponseBytes when limit is not
+// positive), returning an error wrapping ErrResponseTooLarge rather than buffering a larger body
+func readResponseBody(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return body, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], fmt.Errorf("%w: over %d bytes", ErrResponseTooLarge, limit)
+	}
+	return body, nil
+}
+
+// truncateBody returns body as a string for error messages, cut to maxErrorBodyLength bytes
+func truncateBody(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if len(text) > maxErrorBodyLength {
+		return text[:maxErrorBodyLength] + "... (truncated)"
+	}
+	return text
+}
+
+// minGzipBodyLength is the smallest request body worth compressing
+const minGzipBodyLength = 1024
+
+// gzipRequests negotiates gzip compression of request bodies with one HTTP service. Bodies are
+// sent uncompressed until a response advertises support with an Accept-Encoding header listing
+// gzip (RFC 7694), and again after the service rejects a compressed body with 415 Unsupported
+// Media Type or stops advertising gzip. A nil *gzipRequests never compresses.
+type gzipRequests struct {
+	mu        sync.Mutex
+	supported bool
+}
+
+// newGzipRequests returns a negotiator when enabled, or nil to send every body uncompressed
+func newGzipRequests(enabled bool) *gzipRequests {
+	if !enabled {
+		return nil
+	}
+	return &gzipRequests{}
+}
+
+// newRequest creates a request with body, gzipped and marked with Content-Encoding: gzip when
+// the service has advertised support and the body is large enough to be worth it. It reports
+// whether the body was compressed.
+func (g *gzipRequests) newRequest(ctx context.Context, method, url string, body []byte) (*http.Request, bool, error) {
+	compress := g != nil && len(body) >= minGzipBodyLength
+	if compress {
+		g.mu.Lock()
+		compress = g.supported
+		g.mu.Unlock()
+	}
+	if compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return nil, false, fmt.Errorf("failed to compress request body: %v", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, false, fmt.Errorf("failed to compress request body: %v", err)
+		}
+		body = buf.Bytes()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	if compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	return req, compress, nil
+}
+
+// observe updates the negotiated support from resp, the response to a request whose body was
+// compressed or not. It reports whether a compressed body was rejected and must be resent
+// uncompressed.
+func (g *gzipRequests) observe(resp *http.Response, compressed bool) (rejected bool) {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if compressed && resp.StatusCode == http.StatusUnsupportedMediaType {
+		g.supported = false
+		return true
+	}
+	if values := resp.Header.Values("Accept-Encoding"); len(values) > 0 {
+		g.supported = acceptsGzip(values)
+	}
+	return false
+}
+
+// acceptsGzip reports whether the Accept-Encoding header values list gzip with a non-zero weight
+func acceptsGzip(values []string) bool {
+	for _, value := range values {
+		for _, coding := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(coding, ";")
+			if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+				continue
+			}
+			weight := strings.TrimSpace(params)
+			if q, ok := strings.CutPrefix(weight, "q="); ok {
+				if w, err := strconv.ParseFloat(q, 64); err == nil && w == 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
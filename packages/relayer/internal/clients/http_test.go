@@ -0,0 +1,149 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// stubProxy is a forward HTTP proxy that answers every request itself and records the targets
+type stubProxy struct {
+	mu      sync.Mutex
+	targets []string
+}
+
+func (p *stubProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.targets = append(p.targets, r.URL.String())
+	p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"success":true,"txHash":"0xproxied"}`))
+}
+
+func TestVerificationServiceClientUsesTransport(t *testing.T) {
+	proxy := &stubProxy{}
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	proxyURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("parse proxy URL: %v", err)
+	}
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+
+	// The service host does not resolve, so the request can only succeed through the proxy
+	client := NewVerificationServiceClientWithTransport(zap.NewNop(), "http://verification.invalid/", transport)
+	txHash, err := client.VerifyVAA(context.Background(), []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("VerifyVAA failed: %v", err)
+	}
+	if txHash != "0xproxied" {
+		t.Errorf("expected the proxy's response, got %q", txHash)
+	}
+	if err := client.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+
+	want := []string{"http://verification.invalid/verify", "http://verification.invalid/health"}
+	if len(proxy.targets) != len(want) {
+		t.Fatalf("expected the proxy to see %v, got %v", want, proxy.targets)
+	}
+	for i := range want {
+		if proxy.targets[i] != want[i] {
+			t.Errorf("expected request %d to target %s, got %s", i, want[i], proxy.targets[i])
+		}
+	}
+}
+
+func TestServiceAuthIsSentWithEveryRequest(t *testing.T) {
+	auth := ServiceAuth{BearerToken: "s3cret", Headers: map[string]string{"x-api-key": "k3y"}}
+
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" || r.Header.Get("X-Api-Key") != "k3y" {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"success":true,"txHash":"0xabc","signature":"sig"}`))
+	}))
+	defer server.Close()
+
+	verification := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Auth: auth})
+	if _, err := verification.VerifyVAA(context.Background(), []byte{1, 2, 3}); err != nil {
+		t.Fatalf("VerifyVAA failed: %v", err)
+	}
+	if err := verification.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	solana := &SolanaClient{vaaServiceURL: server.URL, vaaServiceAuth: auth, httpClient: server.Client(), logger: zap.NewNop()}
+	if err := solana.callVAAService(context.Background(), []byte{1, 2, 3}); err != nil {
+		t.Fatalf("callVAAService failed: %v", err)
+	}
+
+	want := []string{"/verify", "/health", "/post-vaa"}
+	if len(paths) != len(want) {
+		t.Fatalf("expected authenticated requests to %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Errorf("expected request %d to %s, got %s", i, want[i], paths[i])
+		}
+	}
+
+	if got := fmt.Sprint(auth); strings.Contains(got, "s3cret") || strings.Contains(got, "k3y") || got != "Authorization,X-Api-Key (redacted)" {
+		t.Errorf("expected the credentials to be redacted, got %q", got)
+	}
+}
+
+func TestNewHTTPClientDefaultsToEnvironmentProxy(t *testing.T) {
+	client := newHTTPClient(time.Minute, nil)
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok || transport == http.DefaultTransport {
+		t.Fatalf("expected a dedicated *http.Transport, got %T", client.Transport)
+	}
+
+	// Cloned from http.DefaultTransport, which resolves the proxy from HTTP_PROXY / HTTPS_PROXY
+	if transport.Proxy == nil {
+		t.Fatal("expected the default transport to resolve proxies from the environment")
+	}
+	if client.Timeout != time.Minute {
+		t.Errorf("expected timeout 1m, got %v", client.Timeout)
+	}
+
+	custom := &http.Transport{}
+	if newHTTPClient(time.Minute, custom).Transport != custom {
+		t.Error("expected a custom transport to be used as is")
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   bool
+	}{
+		{values: []string{"gzip"}, want: true},
+		{values: []string{"br, GZIP;q=0.5"}, want: true},
+		{values: []string{"identity", "gzip"}, want: true},
+		{values: []string{"gzip;q=0"}, want: false},
+		{values: []string{"identity"}, want: false},
+		{values: []string{"x-gzip-ish"}, want: false},
+	}
+	for _, tt := range tests {
+		if got := acceptsGzip(tt.values); got != tt.want {
+			t.Errorf("acceptsGzip(%q) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
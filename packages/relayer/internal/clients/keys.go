@@ -0,0 +1,139 @@
+package clients
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+)
+
+// ed25519SecretKeyLength is the length of a Solana secret key (seed followed by public key)
+const ed25519SecretKeyLength = 64
+
+// Key material is never included in the errors returned below, only its source.
+
+// loadSolanaPrivateKey returns the payer key from exactly one of a base58 string or a
+// Solana CLI keypair file (the JSON byte array written by `solana-keygen`, e.g. id.json)
+func loadSolanaPrivateKey(privateKeyBase58, keypairFile string) (solana.PrivateKey, error) {
+	switch {
+	case privateKeyBase58 != "" && keypairFile != "":
+		return nil, fmt.Errorf("both a private key and a keypair file were provided; use only one")
+	case keypairFile != "":
+		return readSolanaKeypairFile(keypairFile)
+	default:
+		return parseSolanaPrivateKey(privateKeyBase58)
+	}
+}
+
+// parseSolanaPrivateKey parses a base58-encoded 64-byte Solana secret key
+func parseSolanaPrivateKey(privateKeyBase58 string) (solana.PrivateKey, error) {
+	if privateKeyBase58 == "" {
+		return nil, fmt.Errorf("invalid private key: empty (expected a base58-encoded 64-byte secret key or a keypair file)")
+	}
+	privKey, err := solana.PrivateKeyFromBase58(privateKeyBase58)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key (expected a base58-encoded 64-byte secret key): %v", err)
+	}
+	if len(privKey) != ed25519SecretKeyLength {
+		return nil, fmt.Errorf("invalid private key: decoded to %d bytes, expected %d", len(privKey), ed25519SecretKeyLength)
+	}
+	return privKey, nil
+}
+
+// readSolanaKeypairFile reads a Solana CLI JSON keypair file
+func readSolanaKeypairFile(path string) (solana.PrivateKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keypair file %s: %v", path, err)
+	}
+
+	var keyBytes []byte
+	var values []int
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("invalid keypair file %s (expected a JSON array of %d bytes): %v", path, ed25519SecretKeyLength, err)
+	}
+	for _, v := range values {
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("invalid keypair file %s: value %d is not a byte", path, v)
+		}
+		keyBytes = append(keyBytes, byte(v))
+	}
+	if len(keyBytes) != ed25519SecretKeyLength {
+		return nil, fmt.Errorf("invalid keypair file %s: contains %d bytes, expected %d", path, len(keyBytes), ed25519SecretKeyLength)
+	}
+
+	return solana.PrivateKey(keyBytes), nil
+}
+
+// loadEVMPrivateKey returns the signing key from exactly one of the sources in config:
+// a hex string, a file containing the hex-encoded key (surrounding whitespace and a 0x
+// prefix are ignored), or a go-ethereum V3 keystore file with its password
+func loadEVMPrivateKey(config EVMClientConfig) (*ecdsa.PrivateKey, error) {
+	sources := 0
+	for _, source := range []string{config.PrivateKey, config.PrivateKeyFile, config.KeystoreFile} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fmt.Errorf("more than one of private key, private key file and keystore file were provided; use only one")
+	}
+
+	switch {
+	case config.KeystoreFile != "":
+		return readEVMKeystore(config.KeystoreFile, config.KeystorePassword, config.KeystorePasswordFile)
+	case config.PrivateKeyFile != "":
+		content, err := os.ReadFile(config.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key file %s: %v", config.PrivateKeyFile, err)
+		}
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key in file %s: %v", config.PrivateKeyFile, err)
+		}
+		return privateKey, nil
+	case config.PrivateKey == "":
+		return nil, fmt.Errorf("no private key provided (expected a hex-encoded key, a private key file or a keystore file)")
+	default:
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.PrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %v", err)
+		}
+		return privateKey, nil
+	}
+}
+
+// readEVMKeystore decrypts a go-ethereum V3 keystore file. The password is taken from
+// password or, if that is empty, from the first line of passwordFile.
+func readEVMKeystore(path, password, passwordFile string) (*ecdsa.PrivateKey, error) {
+	if password != "" && passwordFile != "" {
+		return nil, fmt.Errorf("both a keystore password and a keystore password file were provided; use only one")
+	}
+	if passwordFile != "" {
+		content, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore password file %s: %v", passwordFile, err)
+		}
+		password = strings.TrimRight(strings.SplitN(string(content), "\n", 2)[0], "\r")
+	}
+
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore file %s: %v", path, err)
+	}
+
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return nil, fmt.Errorf("failed to decrypt keystore file %s: wrong password", path)
+		}
+		return nil, fmt.Errorf("malformed keystore file %s (expected a V3 keystore): %v", path, err)
+	}
+	return key.PrivateKey, nil
+}
@@ -0,0 +1,158 @@
+package clients
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadSolanaPrivateKey(t *testing.T) {
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	keyBytes := make([]int, len(payer))
+	for i, b := range payer {
+		keyBytes[i] = int(b)
+	}
+	keypairJSON, err := json.Marshal(keyBytes)
+	if err != nil {
+		t.Fatalf("failed to encode keypair: %v", err)
+	}
+	keypairFile := writeFile(t, "id.json", string(keypairJSON))
+
+	fromFile, err := loadSolanaPrivateKey("", keypairFile)
+	if err != nil {
+		t.Fatalf("failed to load keypair file: %v", err)
+	}
+	if !fromFile.PublicKey().Equals(payer.PublicKey()) {
+		t.Errorf("expected payer %s, got %s", payer.PublicKey(), fromFile.PublicKey())
+	}
+
+	fromBase58, err := loadSolanaPrivateKey(payer.String(), "")
+	if err != nil || !fromBase58.PublicKey().Equals(payer.PublicKey()) {
+		t.Errorf("failed to load base58 key: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		privateKey  string
+		keypairFile string
+		wantErr     string
+	}{
+		{name: "both provided", privateKey: payer.String(), keypairFile: keypairFile, wantErr: "use only one"},
+		{name: "neither provided", wantErr: "invalid private key"},
+		{name: "missing file", keypairFile: filepath.Join(t.TempDir(), "missing.json"), wantErr: "failed to read keypair file"},
+		{name: "not JSON", keypairFile: writeFile(t, "bad.json", payer.String()), wantErr: "invalid keypair file"},
+		{name: "wrong length", keypairFile: writeFile(t, "short.json", "[1,2,3]"), wantErr: "contains 3 bytes"},
+		{name: "not bytes", keypairFile: writeFile(t, "range.json", "[256]"), wantErr: "is not a byte"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSolanaPrivateKey(tt.privateKey, tt.keypairFile)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoadEVMPrivateKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	keyHex := hex.EncodeToString(crypto.FromECDSA(key))
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	keyFile := writeFile(t, "key.txt", "0x"+keyHex+"\n")
+	fromFile, err := loadEVMPrivateKey(EVMClientConfig{PrivateKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("failed to load key file: %v", err)
+	}
+	if crypto.PubkeyToAddress(fromFile.PublicKey) != address {
+		t.Errorf("key file produced the wrong address")
+	}
+
+	fromHex, err := loadEVMPrivateKey(EVMClientConfig{PrivateKey: "0x" + keyHex})
+	if err != nil || crypto.PubkeyToAddress(fromHex.PublicKey) != address {
+		t.Errorf("failed to load hex key: %v", err)
+	}
+
+	if _, err := loadEVMPrivateKey(EVMClientConfig{PrivateKey: keyHex, PrivateKeyFile: keyFile}); err == nil || !strings.Contains(err.Error(), "use only one") {
+		t.Errorf("expected an error when both sources are provided, got %v", err)
+	}
+	if _, err := loadEVMPrivateKey(EVMClientConfig{}); err == nil {
+		t.Error("expected an error when no key is provided")
+	}
+
+	badFile := writeFile(t, "bad.txt", "not-hex")
+	if _, err := loadEVMPrivateKey(EVMClientConfig{PrivateKeyFile: badFile}); err == nil || strings.Contains(err.Error(), "not-hex") {
+		t.Errorf("expected an error that does not echo the file contents, got %v", err)
+	}
+}
+
+func TestLoadEVMPrivateKeyFromKeystore(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	// Light scrypt parameters keep the test fast
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{Address: address, PrivateKey: key}, "s3cret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to encrypt key: %v", err)
+	}
+	keystoreFile := writeFile(t, "keystore.json", string(keyJSON))
+
+	loaded, err := loadEVMPrivateKey(EVMClientConfig{KeystoreFile: keystoreFile, KeystorePassword: "s3cret"})
+	if err != nil {
+		t.Fatalf("failed to load keystore: %v", err)
+	}
+	if crypto.PubkeyToAddress(loaded.PublicKey) != address {
+		t.Errorf("keystore produced the wrong address")
+	}
+
+	passwordFile := writeFile(t, "password.txt", "s3cret\n")
+	if _, err := loadEVMPrivateKey(EVMClientConfig{KeystoreFile: keystoreFile, KeystorePasswordFile: passwordFile}); err != nil {
+		t.Errorf("failed to load keystore with a password file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		config  EVMClientConfig
+		wantErr string
+	}{
+		{name: "wrong password", config: EVMClientConfig{KeystoreFile: keystoreFile, KeystorePassword: "wrong"}, wantErr: "wrong password"},
+		{name: "malformed keystore", config: EVMClientConfig{KeystoreFile: writeFile(t, "bad.json", "{}"), KeystorePassword: "s3cret"}, wantErr: "malformed keystore"},
+		{name: "missing keystore", config: EVMClientConfig{KeystoreFile: filepath.Join(t.TempDir(), "missing.json")}, wantErr: "failed to read keystore file"},
+		{name: "password and password file", config: EVMClientConfig{KeystoreFile: keystoreFile, KeystorePassword: "s3cret", KeystorePasswordFile: passwordFile}, wantErr: "use only one"},
+		{name: "keystore and private key", config: EVMClientConfig{KeystoreFile: keystoreFile, PrivateKey: "00"}, wantErr: "use only one"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadEVMPrivateKey(tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Payload layouts the relayer decodes. A payload shorter than the layout of its source chain
+// carries neither a destination nor a value. The processor filters and the submitter routing
+// both decode payloads here, so they always agree on a VAA's destination.
+const (
+	DefaultPayloadLength = 18 // [chainId(2) | value(16)]
+	AztecPayloadLength   = 50 // [txId(32) | chainId(2) | value(16)]
+)
+
+// PayloadFormat is the layout a payload is decoded with
+type PayloadFormat string
+
+const (
+	PayloadFormatNone    PayloadFormat = "none"    // Too short for its layout: no destination or value
+	PayloadFormatDefault PayloadFormat = "default" // [chainId(2) | value(16)], sent by Solana and EVM
+	PayloadFormatAztec   PayloadFormat = "aztec"   // [txId(32) | chainId(2) | value(16)], sent by Aztec
+)
+
+// aztecChainIDs are the Wormhole chain IDs of Aztec, whose emitters send the Aztec layout
+var aztecChainIDs = map[uint16]bool{54: true, 56: true}
+
+// DetectPayloadFormat returns the layout of payload, emitted on emitterChain.
+//
+// The length alone is ambiguous from 50 bytes on: a default payload followed by other data is
+// as long as an Aztec one, and reading it as Aztec would take the destination from its value
+// bytes. So the source chain decides, as it does for the MessageBridge contracts, which register
+// one layout per source chain: Aztec (54, 56) sends the Aztec layout and every other chain the
+// default layout. A payload shorter than its chain's layout fits none; bytes past it are ignored.
+func DetectPayloadFormat(emitterChain uint16, payload []byte) PayloadFormat {
+	if aztecChainIDs[emitterChain] {
+		if len(payload) >= AztecPayloadLength {
+			return PayloadFormatAztec
+		}
+		return PayloadFormatNone
+	}
+	if len(payload) >= DefaultPayloadLength {
+		return PayloadFormatDefault
+	}
+	return PayloadFormatNone
+}
+
+// MinPayloadLength is the shortest payload from emitterChain that carries a destination and value
+func MinPayloadLength(emitterChain uint16) int {
+	if aztecChainIDs[emitterChain] {
+		return AztecPayloadLength
+	}
+	return DefaultPayloadLength
+}
+
+// ExtractDestinationChainID extracts the destination chain ID from a payload in format:
+//   - Default: [chainId(2) | value(16)] - destination at bytes 0-1
+//   - Aztec:   [txId(32) | chainId(2) | value(16)] - destination at bytes 32-33
+//
+// Returns false for PayloadFormatNone, so a missing destination is never mistaken for chain 0.
+func ExtractDestinationChainID(format PayloadFormat, payload []byte) (uint16, bool) {
+	switch format {
+	case PayloadFormatAztec:
+		return (uint16(payload[32]) << 8) | uint16(payload[33]), true
+	case PayloadFormatDefault:
+		return (uint16(payload[0]) << 8) | uint16(payload[1]), true
+	}
+	return 0, false
+}
+
+// ExtractPayloadValue extracts the uint128 value from a payload in format:
+//   - Default: [chainId(2) | value(16)] - value at bytes 2-17
+//   - Aztec:   [txId(32) | chainId(2) | value(16)] - value at bytes 34-49
+func ExtractPayloadValue(format PayloadFormat, payload []byte) (*big.Int, bool) {
+	switch format {
+	case PayloadFormatAztec:
+		return new(big.Int).SetBytes(payload[34:50]), true
+	case PayloadFormatDefault:
+		return new(big.Int).SetBytes(payload[2:18]), true
+	}
+	return nil, false
+}
+
+// ExtractSourceTxID extracts the source transaction ID, formatted as 0x hex, from a payload in
+// format. Only the Aztec format carries one:
+//   - Default: [chainId(2) | value(16)] - no tx ID
+//   - Aztec:   [txId(32) | chainId(2) | value(16)] - tx ID at bytes 0-31
+func ExtractSourceTxID(format PayloadFormat, payload []byte) (string, bool) {
+	if format == PayloadFormatAztec {
+		return fmt.Sprintf("0x%x", payload[:32]), true
+	}
+	return "", false
+}
+
+// PayloadDestinationChainID extracts the destination chain ID from a payload emitted on
+// emitterChain, in the layout DetectPayloadFormat picks for it
+func PayloadDestinationChainID(emitterChain uint16, payload []byte) (uint16, bool) {
+	return ExtractDestinationChainID(DetectPayloadFormat(emitterChain, payload), payload)
+}
@@ -0,0 +1,122 @@
+package clients
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectPayloadFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		chain  uint16
+		length int
+		want   PayloadFormat
+	}{
+		{name: "aztec, 50 bytes", chain: 56, length: 50, want: PayloadFormatAztec},
+		{name: "aztec devnet, 50 bytes", chain: 54, length: 50, want: PayloadFormatAztec},
+		{name: "aztec, longer", chain: 56, length: 64, want: PayloadFormatAztec},
+		{name: "aztec, default length", chain: 56, length: 18, want: PayloadFormatNone},
+		{name: "aztec, 49 bytes", chain: 56, length: 49, want: PayloadFormatNone},
+		{name: "solana, 18 bytes", chain: 1, length: 18, want: PayloadFormatDefault},
+		{name: "evm, 18 bytes", chain: 10003, length: 18, want: PayloadFormatDefault},
+		{name: "evm, aztec length", chain: 10003, length: 50, want: PayloadFormatDefault},
+		{name: "evm, 17 bytes", chain: 10003, length: 17, want: PayloadFormatNone},
+		{name: "other chain, aztec length", chain: 3104, length: 50, want: PayloadFormatDefault},
+		{name: "empty", chain: 2, want: PayloadFormatNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectPayloadFormat(tt.chain, make([]byte, tt.length)); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExtractDestinationChainID(t *testing.T) {
+	aztecPayload := make([]byte, 50)
+	aztecPayload[0], aztecPayload[1] = 0xff, 0xff   // Part of the tx ID, not the destination
+	aztecPayload[32], aztecPayload[33] = 0x27, 0x14 // Destination 10004
+
+	defaultPayload := make([]byte, 18)
+	defaultPayload[0], defaultPayload[1] = 0x27, 0x13 // Destination 10003
+
+	// A default payload followed by 32 bytes of other data is as long as an Aztec payload;
+	// bytes 32-33 fall in the extra data, not the destination
+	extendedPayload := append(append([]byte{}, defaultPayload...), make([]byte, 32)...)
+	extendedPayload[32], extendedPayload[33] = 0x00, 0x01
+
+	tests := []struct {
+		name    string
+		chain   uint16
+		payload []byte
+		want    uint16
+		ok      bool
+	}{
+		{name: "empty", chain: 2, payload: nil},
+		{name: "17 bytes", chain: 2, payload: make([]byte, 17)},
+		{name: "18 bytes", chain: 2, payload: defaultPayload, want: 10003, ok: true},
+		{name: "18 bytes to chain 0", chain: 2, payload: make([]byte, 18), want: 0, ok: true},
+		{name: "50 bytes from aztec", chain: 56, payload: aztecPayload, want: 10004, ok: true},
+		{name: "50 bytes from evm", chain: 10004, payload: extendedPayload, want: 10003, ok: true},
+		{name: "50 bytes from solana", chain: 1, payload: extendedPayload, want: 10003, ok: true},
+		{name: "18 bytes from aztec", chain: 56, payload: defaultPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractDestinationChainID(DetectPayloadFormat(tt.chain, tt.payload), tt.payload)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestExtractPayloadValue(t *testing.T) {
+	// The same 50 bytes hold value 0x0102 in the default layout and 0x0304 in the Aztec layout
+	payload := make([]byte, 50)
+	payload[16], payload[17] = 0x01, 0x02
+	payload[48], payload[49] = 0x03, 0x04
+
+	for chain, want := range map[uint16]int64{10003: 0x0102, 1: 0x0102, 56: 0x0304} {
+		value, ok := ExtractPayloadValue(DetectPayloadFormat(chain, payload), payload)
+		if !ok || value.Int64() != want {
+			t.Errorf("chain %d: expected value %d, got %v (ok = %v)", chain, want, value, ok)
+		}
+	}
+	if value, ok := ExtractPayloadValue(DetectPayloadFormat(56, payload[:18]), payload[:18]); ok {
+		t.Errorf("expected no value in an Aztec payload under 50 bytes, got %s", value)
+	}
+}
+
+func TestExtractSourceTxID(t *testing.T) {
+	aztecPayload := make([]byte, 50)
+	aztecPayload[0], aztecPayload[31] = 0xab, 0xcd
+
+	defaultPayload := make([]byte, 40) // Default layout followed by bytes of another format
+	defaultPayload[0], defaultPayload[1] = 0x27, 0x13
+
+	tests := []struct {
+		name    string
+		chain   uint16
+		payload []byte
+		want    string
+	}{
+		{name: "empty", chain: 56, payload: nil},
+		{name: "18 bytes", chain: 2, payload: make([]byte, 18)},
+		{name: "40 bytes", chain: 2, payload: defaultPayload},
+		{name: "50 bytes from evm", chain: 2, payload: aztecPayload},
+		{name: "50 bytes from aztec", chain: 56, payload: aztecPayload, want: "0xab" + strings.Repeat("00", 30) + "cd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractSourceTxID(DetectPayloadFormat(tt.chain, tt.payload), tt.payload)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("expected %q, got %q (ok = %v)", tt.want, got, ok)
+			}
+		})
+	}
+}
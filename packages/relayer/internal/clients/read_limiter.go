@@ -0,0 +1,69 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
+)
+
+// ReadLimiter bounds the RPC reads (account lookups, headers, nonces, contract calls) a client
+// has in flight. Each VAA needs several reads, so even a modest number of VAAs in flight can
+// overwhelm a rate-limited public RPC; the limiter caps the reads themselves, independently of
+// how many VAAs are submitted at once. Transactions are never held back by it.
+//
+// A nil ReadLimiter allows every read.
+type ReadLimiter struct {
+	slots  chan struct{}
+	client string // Label of the client in the metrics
+}
+
+// NewReadLimiter returns a limiter allowing limit reads in flight, reported in the metrics under
+// client (e.g. "evm", "solana"), or nil if limit is 0 (unlimited)
+func NewReadLimiter(client string, limit int) (*ReadLimiter, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("RPC read concurrency must not be negative, got %d", limit)
+	}
+	if limit == 0 {
+		return nil, nil
+	}
+	metrics.RPCReadConcurrencyLimit.WithLabelValues(client).Set(float64(limit))
+	return &ReadLimiter{slots: make(chan struct{}, limit), client: client}, nil
+}
+
+// acquire takes a slot, waiting for one to free up while all are taken, until ctx is done
+func (l *ReadLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		// Saturated: count the wait so an alert can tell the limit is what slows reads down
+		metrics.RPCReadWaits.WithLabelValues(l.client).Inc()
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for an RPC read slot: %w", ctx.Err())
+		}
+	}
+	metrics.RPCReadsInFlight.WithLabelValues(l.client).Inc()
+	return nil
+}
+
+// release frees the slot taken by acquire
+func (l *ReadLimiter) release() {
+	<-l.slots
+	metrics.RPCReadsInFlight.WithLabelValues(l.client).Dec()
+}
+
+// limitRead runs the read fn while holding a slot of l. It wraps a single attempt, inside
+// retryCall, so no slot is held while a retry backs off.
+func limitRead[T any](ctx context.Context, l *ReadLimiter, fn func() (T, error)) (T, error) {
+	if l == nil {
+		return fn()
+	}
+	if err := l.acquire(ctx); err != nil {
+		var zero T
+		return zero, err
+	}
+	defer l.release()
+	return fn()
+}
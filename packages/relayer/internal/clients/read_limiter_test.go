@@ -0,0 +1,76 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadLimiter(t *testing.T) {
+	if l, err := NewReadLimiter("test", 0); l != nil || err != nil {
+		t.Fatalf("expected no limiter for a limit of 0, got %v (%v)", l, err)
+	}
+	if _, err := NewReadLimiter("test", -1); err == nil {
+		t.Fatal("expected a negative limit to be rejected")
+	}
+
+	limiter, err := NewReadLimiter("test", 2)
+	if err != nil {
+		t.Fatalf("NewReadLimiter failed: %v", err)
+	}
+
+	// Six reads at once never have more than two in flight
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := limitRead(context.Background(), limiter, func() (struct{}, error) {
+				n := inFlight.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				inFlight.Add(-1)
+				return struct{}{}, nil
+			})
+			if err != nil {
+				t.Errorf("limitRead failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := peak.Load(); got != 2 {
+		t.Errorf("expected at most 2 reads in flight, peaked at %d", got)
+	}
+
+	// A read waiting for a slot gives up with its context
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		go limitRead(context.Background(), limiter, func() (struct{}, error) {
+			<-release
+			return struct{}{}, nil
+		})
+	}
+	for len(limiter.slots) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	called := false
+	_, err = limitRead(ctx, limiter, func() (struct{}, error) {
+		called = true
+		return struct{}{}, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || called {
+		t.Errorf("expected the read to give up without running, got %v (ran: %v)", err, called)
+	}
+	close(release)
+}
@@ -0,0 +1,127 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// RetryConfig controls retries of RPC calls that fail with transient errors
+type RetryConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`    // Total attempts including the first (1 disables retries)
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"` // Delay before the first retry, doubled on each subsequent retry
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`     // Upper bound on the delay between retries
+}
+
+// DefaultRetryConfig returns the retry policy used when none is configured
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// transientErrorMarkers are phrases of RPC errors caused by the node or network rather than the
+// request, for errors that reach us as text only. Each is anchored to its reason phrase or a
+// "status code" prefix so digits in tx hashes, block numbers or amounts never match.
+var transientErrorMarkers = []string{
+	"status code 429",
+	"too many requests",
+	"rate limit exceeded",
+	"status code 502",
+	"status code 503",
+	"status code 504",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+	"i/o timeout",
+	"connection reset",
+	"connection refused",
+	"broken pipe",
+	"unexpected eof",
+}
+
+// IsTransientRPCError reports whether err is worth retrying (rate limits, 5xx, timeouts, dropped connections).
+// Logic errors such as reverts, nonce problems or insufficient funds are never transient.
+func IsTransientRPCError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == 429 || statusErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	// A connection the node closed mid-request surfaces as a bare EOF, e.g. `Post "http://node": EOF`
+	msg := strings.ToLower(err.Error())
+	if msg == "eof" || strings.HasSuffix(msg, ": eof") {
+		return true
+	}
+	for _, marker := range transientErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryCall runs fn until it succeeds, returns a non-transient error, the attempts
+// are exhausted, or ctx is done. Backoff doubles from InitialBackoff up to MaxBackoff.
+func retryCall[T any](ctx context.Context, cfg RetryConfig, logger *zap.Logger, op string, fn func() (T, error)) (T, error) {
+	attempts := cfg.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := cfg.InitialBackoff
+
+	var result T
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err = fn()
+		if err == nil || !IsTransientRPCError(err) || attempt == attempts {
+			return result, err
+		}
+
+		logger.Warn("Transient RPC error, retrying",
+			zap.String("operation", op),
+			zap.Int("attempt", attempt),
+			zap.Int("maxAttempts", attempts),
+			zap.Duration("retryIn", backoff),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if cfg.MaxBackoff > 0 && backoff > cfg.MaxBackoff {
+			backoff = cfg.MaxBackoff
+		}
+	}
+	return result, err
+}
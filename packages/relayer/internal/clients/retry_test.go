@@ -0,0 +1,119 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.uber.org/zap"
+)
+
+// flakyNode fails a fixed number of times with the given error before succeeding
+type flakyNode struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (n *flakyNode) PendingNonceAt() (uint64, error) {
+	n.calls++
+	if n.calls <= n.failures {
+		return 0, n.err
+	}
+	return 42, nil
+}
+
+func testRetryConfig() RetryConfig {
+	return RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestRetryCall_FailsTwiceThenSucceeds(t *testing.T) {
+	node := &flakyNode{failures: 2, err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}}
+
+	nonce, err := retryCall(context.Background(), testRetryConfig(), zap.NewNop(), "PendingNonceAt", node.PendingNonceAt)
+	if err != nil {
+		t.Fatalf("expected success after retries, got %v", err)
+	}
+	if nonce != 42 {
+		t.Errorf("expected nonce 42, got %d", nonce)
+	}
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", node.calls)
+	}
+}
+
+func TestRetryCall_ExhaustsAttempts(t *testing.T) {
+	node := &flakyNode{failures: 5, err: errors.New("503 Service Unavailable")}
+
+	_, err := retryCall(context.Background(), testRetryConfig(), zap.NewNop(), "PendingNonceAt", node.PendingNonceAt)
+	if err == nil {
+		t.Fatal("expected error after exhausting attempts")
+	}
+	if node.calls != 3 {
+		t.Errorf("expected 3 calls, got %d", node.calls)
+	}
+}
+
+func TestRetryCall_LogicErrorBubblesImmediately(t *testing.T) {
+	for _, msg := range []string{"execution reverted: already processed", "nonce too low", "insufficient funds for gas * price + value"} {
+		node := &flakyNode{failures: 2, err: errors.New(msg)}
+
+		_, err := retryCall(context.Background(), testRetryConfig(), zap.NewNop(), "SendTransaction", node.PendingNonceAt)
+		if err == nil {
+			t.Fatalf("expected %q to bubble up", msg)
+		}
+		if node.calls != 1 {
+			t.Errorf("%q: expected 1 call, got %d", msg, node.calls)
+		}
+	}
+}
+
+func TestRetryCall_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	node := &flakyNode{failures: 5, err: errors.New("connection reset by peer")}
+
+	cfg := RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour}
+	if _, err := retryCall(ctx, cfg, zap.NewNop(), "HeaderByNumber", node.PendingNonceAt); err == nil {
+		t.Fatal("expected error when context is cancelled")
+	}
+	if node.calls != 1 {
+		t.Errorf("expected 1 call, got %d", node.calls)
+	}
+}
+
+func TestIsTransientRPCError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "HTTP 429", err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"}, want: true},
+		{name: "HTTP 400", err: rpc.HTTPError{StatusCode: 400, Status: "400 Bad Request"}, want: false},
+		{name: "5xx status text", err: errors.New("failed to send transaction: 503 Service Unavailable"), want: true},
+		{name: "dial refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "closed mid-request", err: errors.New(`Post "http://node:8545": EOF`), want: true},
+		{name: "wrapped EOF", err: fmt.Errorf("read response: %w", io.ErrUnexpectedEOF), want: true},
+		{
+			// Hashes and block numbers carry status-code digits without being HTTP failures
+			name: "revert with status digits",
+			err:  errors.New("transaction 0x4290a502b503c504 reverted in block 15029"),
+			want: false,
+		},
+		{name: "revert mentioning a timeout", err: errors.New("execution reverted: timeout not reached"), want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientRPCError(tt.err); got != tt.want {
+				t.Errorf("IsTransientRPCError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
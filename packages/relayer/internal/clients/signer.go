@@ -0,0 +1,252 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+)
+
+// EVMSigner signs the transactions of one EVM account. A signer backed by a key held elsewhere
+// (a KMS or HSM) keeps the key out of the relayer's process.
+type EVMSigner interface {
+	// Address returns the account whose key signs
+	Address() common.Address
+	// SignHash returns the 65-byte [R || S || V] secp256k1 signature of a 32-byte hash, V being 0 or 1
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// SolanaSigner signs the transactions of one Solana account, as payer or nonce authority
+type SolanaSigner interface {
+	// PublicKey returns the account whose key signs
+	PublicKey() solana.PublicKey
+	// Sign returns the ed25519 signature of a serialized transaction message
+	Sign(ctx context.Context, message []byte) (solana.Signature, error)
+}
+
+// localEVMSigner signs with a private key held in memory
+type localEVMSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocalEVMSigner returns a signer for a private key held in memory
+func NewLocalEVMSigner(key *ecdsa.PrivateKey) EVMSigner {
+	return &localEVMSigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *localEVMSigner) Address() common.Address { return s.address }
+
+func (s *localEVMSigner) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+// localSolanaSigner signs with a secret key held in memory
+type localSolanaSigner struct {
+	key solana.PrivateKey
+}
+
+// NewLocalSolanaSigner returns a signer for a secret key held in memory
+func NewLocalSolanaSigner(key solana.PrivateKey) SolanaSigner {
+	return &localSolanaSigner{key: key}
+}
+
+func (s *localSolanaSigner) PublicKey() solana.PublicKey { return s.key.PublicKey() }
+
+func (s *localSolanaSigner) Sign(ctx context.Context, message []byte) (solana.Signature, error) {
+	return s.key.Sign(message)
+}
+
+// DefaultRemoteSignerTimeout bounds a single request to a remote signer
+const DefaultRemoteSignerTimeout = 30 * time.Second
+
+// RemoteSignerConfig holds the settings for a remote signer.
+//
+// The signer is sent POST requests with a JSON body {"key": "<account>", "payload": "<hex>"},
+// the account being a 0x-prefixed EVM address or a base58 Solana public key, and must answer
+// {"signature": "<hex>"}: the 65-byte [R || S || V] signature of the 32-byte hash for EVM, or
+// the 64-byte ed25519 signature of the message for Solana. Every signature is checked against
+// the account before it is used.
+type RemoteSignerConfig struct {
+	URL   string // Endpoint the signing requests are posted to
+	Token string // Bearer token sent with each request (empty = none)
+	// Transport for requests to the signer (nil = default transport, honouring the
+	// HTTP_PROXY and HTTPS_PROXY environment variables)
+	HTTPTransport *http.Transport
+}
+
+// remoteSigner posts signing requests to an HTTP endpoint
+type remoteSigner struct {
+	url        string
+	token      string
+	httpClient *http.Client
+}
+
+func newRemoteSigner(config RemoteSignerConfig) (*remoteSigner, error) {
+	if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+		return nil, fmt.Errorf("invalid remote signer URL %q (expected an http(s) URL)", config.URL)
+	}
+	return &remoteSigner{
+		url:        config.URL,
+		token:      config.Token,
+		httpClient: newHTTPClient(DefaultRemoteSignerTimeout, config.HTTPTransport),
+	}, nil
+}
+
+// sign asks the remote signer for key's signature of payload
+func (s *remoteSigner) sign(ctx context.Context, key string, payload []byte) ([]byte, error) {
+	reqJSON, err := json.Marshal(map[string]string{"key": key, "payload": hex.EncodeToString(payload)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal signing request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signing request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read remote signer response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote signer request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: truncateBody(body)})
+	}
+
+	var result struct {
+		Signature string `json:"signature"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid remote signer response: %v", err)
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(result.Signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer signature %q: %v", result.Signature, err)
+	}
+	return signature, nil
+}
+
+// remoteEVMSigner signs EVM transactions through a remote signer
+type remoteEVMSigner struct {
+	remote  *remoteSigner
+	address common.Address
+}
+
+// NewRemoteEVMSigner returns a signer asking the remote signer of config for the signatures of
+// address (0x-prefixed hex)
+func NewRemoteEVMSigner(config RemoteSignerConfig, address string) (EVMSigner, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid signer address %q (expected a 0x-prefixed EVM address)", address)
+	}
+	remote, err := newRemoteSigner(config)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteEVMSigner{remote: remote, address: common.HexToAddress(address)}, nil
+}
+
+func (s *remoteEVMSigner) Address() common.Address { return s.address }
+
+func (s *remoteEVMSigner) SignHash(ctx context.Context, hash []byte) ([]byte, error) {
+	signature, err := s.remote.sign(ctx, s.address.Hex(), hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("remote signer returned a %d-byte signature, expected %d", len(signature), crypto.SignatureLength)
+	}
+	// Some signers return the Ethereum-style V of 27 or 28
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned an invalid signature: %v", err)
+	}
+	if signer := crypto.PubkeyToAddress(*publicKey); signer != s.address {
+		return nil, fmt.Errorf("remote signer signed with %s, expected %s", signer.Hex(), s.address.Hex())
+	}
+	return signature, nil
+}
+
+// remoteSolanaSigner signs Solana transactions through a remote signer
+type remoteSolanaSigner struct {
+	remote    *remoteSigner
+	publicKey solana.PublicKey
+}
+
+// NewRemoteSolanaSigner returns a signer asking the remote signer of config for the signatures of
+// publicKey (base58)
+func NewRemoteSolanaSigner(config RemoteSignerConfig, publicKey string) (SolanaSigner, error) {
+	key, err := solana.PublicKeyFromBase58(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signer public key %q (expected a base58 public key): %v", publicKey, err)
+	}
+	remote, err := newRemoteSigner(config)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSolanaSigner{remote: remote, publicKey: key}, nil
+}
+
+func (s *remoteSolanaSigner) PublicKey() solana.PublicKey { return s.publicKey }
+
+func (s *remoteSolanaSigner) Sign(ctx context.Context, message []byte) (solana.Signature, error) {
+	signature, err := s.remote.sign(ctx, s.publicKey.String(), message)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	if len(signature) != len(solana.Signature{}) {
+		return solana.Signature{}, fmt.Errorf("remote signer returned a %d-byte signature, expected %d", len(signature), len(solana.Signature{}))
+	}
+	sig := solana.SignatureFromBytes(signature)
+	if !sig.Verify(s.publicKey, message) {
+		return solana.Signature{}, fmt.Errorf("remote signer returned a signature that does not verify against %s", s.publicKey)
+	}
+	return sig, nil
+}
+
+// signSolanaTransaction signs tx with the signer of each account that must sign it
+func signSolanaTransaction(ctx context.Context, tx *solana.Transaction, signers ...SolanaSigner) error {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode message for signing: %v", err)
+	}
+
+	required := tx.Message.AccountKeys[:tx.Message.Header.NumRequiredSignatures]
+	tx.Signatures = make([]solana.Signature, len(required))
+	for i, key := range required {
+		var signer SolanaSigner
+		for _, candidate := range signers {
+			if candidate != nil && candidate.PublicKey().Equals(key) {
+				signer = candidate
+				break
+			}
+		}
+		if signer == nil {
+			return fmt.Errorf("no signer for required signer account %s", key)
+		}
+		if tx.Signatures[i], err = signer.Sign(ctx, message); err != nil {
+			return fmt.Errorf("failed to sign with %s: %w", key, err)
+		}
+	}
+	return nil
+}
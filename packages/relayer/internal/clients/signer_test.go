@@ -0,0 +1,197 @@
+package clients
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+// mockRemoteSigner is a remote signer holding keys by account, like a KMS would
+type mockRemoteSigner struct {
+	evmKeys    map[string]*ecdsa.PrivateKey
+	solanaKeys map[string]solana.PrivateKey
+	token      string
+	ethereumV  bool // Return V as 27 or 28 instead of 0 or 1
+	requests   int
+}
+
+func (m *mockRemoteSigner) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.requests++
+		if r.Method != http.MethodPost || (m.token != "" && r.Header.Get("Authorization") != "Bearer "+m.token) {
+			http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Key     string `json:"key"`
+			Payload string `json:"payload"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		payload, err := hex.DecodeString(req.Payload)
+		if err != nil {
+			http.Error(w, "bad payload", http.StatusBadRequest)
+			return
+		}
+
+		var signature []byte
+		if key, ok := m.evmKeys[req.Key]; ok {
+			signature, err = crypto.Sign(payload, key)
+			if m.ethereumV {
+				signature[crypto.RecoveryIDOffset] += 27
+			}
+		} else if key, ok := m.solanaKeys[req.Key]; ok {
+			var sig solana.Signature
+			sig, err = key.Sign(payload)
+			signature = sig[:]
+		} else {
+			http.Error(w, "unknown key", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"signature": "0x" + hex.EncodeToString(signature)})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRemoteEVMSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	mock := &mockRemoteSigner{evmKeys: map[string]*ecdsa.PrivateKey{address.Hex(): key}, token: "secret", ethereumV: true}
+	server := mock.start(t)
+
+	signer, err := NewRemoteEVMSigner(RemoteSignerConfig{URL: server.URL, Token: "secret"}, address.Hex())
+	if err != nil {
+		t.Fatalf("NewRemoteEVMSigner failed: %v", err)
+	}
+
+	// A transaction signed remotely recovers to the signer's address
+	chainID := big.NewInt(421614)
+	to := common.HexToAddress("0x1")
+	tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), Gas: 21000, To: &to, Value: big.NewInt(0)})
+	txSigner := types.NewLondonSigner(chainID)
+	signature, err := signer.SignHash(context.Background(), txSigner.Hash(tx).Bytes())
+	if err != nil {
+		t.Fatalf("SignHash failed: %v", err)
+	}
+	signedTx, err := tx.WithSignature(txSigner, signature)
+	if err != nil {
+		t.Fatalf("WithSignature failed: %v", err)
+	}
+	if sender, err := types.Sender(txSigner, signedTx); err != nil || sender != address {
+		t.Errorf("expected the transaction to be signed by %s, got %s (err %v)", address.Hex(), sender.Hex(), err)
+	}
+
+	// A signature from another key is refused
+	other, _ := crypto.GenerateKey()
+	mock.evmKeys[address.Hex()] = other
+	if _, err := signer.SignHash(context.Background(), make([]byte, 32)); err == nil || !strings.Contains(err.Error(), "signed with") {
+		t.Errorf("expected a signature from the wrong key to be refused, got %v", err)
+	}
+
+	// Signer errors keep their HTTP status, so only a 429 or 5xx is retried as transient
+	unauthorized, _ := NewRemoteEVMSigner(RemoteSignerConfig{URL: server.URL, Token: "wrong"}, address.Hex())
+	_, err = unauthorized.SignHash(context.Background(), make([]byte, 32))
+	if err == nil || !strings.Contains(err.Error(), "HTTP 401") || IsTransientRPCError(err) {
+		t.Errorf("expected a permanent 401 error, got %v", err)
+	}
+
+	if _, err := NewRemoteEVMSigner(RemoteSignerConfig{URL: "kms.example"}, address.Hex()); err == nil {
+		t.Error("expected a URL without an http(s) scheme to be rejected")
+	}
+	if _, err := NewRemoteEVMSigner(RemoteSignerConfig{URL: server.URL}, "0x1234"); err == nil {
+		t.Error("expected a malformed signer address to be rejected")
+	}
+}
+
+func TestSignSolanaTransaction(t *testing.T) {
+	payer := solana.NewWallet().PrivateKey
+	authority := solana.NewWallet().PrivateKey
+	mock := &mockRemoteSigner{solanaKeys: map[string]solana.PrivateKey{payer.PublicKey().String(): payer}}
+	server := mock.start(t)
+
+	remotePayer, err := NewRemoteSolanaSigner(RemoteSignerConfig{URL: server.URL}, payer.PublicKey().String())
+	if err != nil {
+		t.Fatalf("NewRemoteSolanaSigner failed: %v", err)
+	}
+
+	// The remote payer and a local nonce authority both sign
+	newTx := func() *solana.Transaction {
+		instruction := solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+			{PublicKey: payer.PublicKey(), IsSigner: true, IsWritable: true},
+			{PublicKey: authority.PublicKey(), IsSigner: true},
+		}, []byte{4, 0, 0, 0})
+		tx, err := solana.NewTransaction([]solana.Instruction{instruction}, solana.Hash{1}, solana.TransactionPayer(payer.PublicKey()))
+		if err != nil {
+			t.Fatalf("failed to build transaction: %v", err)
+		}
+		return tx
+	}
+	tx := newTx()
+	if err := signSolanaTransaction(context.Background(), tx, remotePayer, NewLocalSolanaSigner(authority)); err != nil {
+		t.Fatalf("signSolanaTransaction failed: %v", err)
+	}
+	if len(tx.Signatures) != 2 || tx.VerifySignatures() != nil {
+		t.Errorf("expected two valid signatures, got %d (err %v)", len(tx.Signatures), tx.VerifySignatures())
+	}
+	if mock.requests != 1 {
+		t.Errorf("expected one request to the remote signer, got %d", mock.requests)
+	}
+
+	if err := signSolanaTransaction(context.Background(), newTx(), remotePayer); err == nil || !strings.Contains(err.Error(), authority.PublicKey().String()) {
+		t.Errorf("expected a missing signer to be reported, got %v", err)
+	}
+
+	// A signature that does not verify against the account is refused
+	mock.solanaKeys[payer.PublicKey().String()] = authority
+	if _, err := remotePayer.Sign(context.Background(), []byte("message")); err == nil || !strings.Contains(err.Error(), "does not verify") {
+		t.Errorf("expected a signature from the wrong key to be refused, got %v", err)
+	}
+}
+
+func TestNewClientsWithSigner(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	_, err := NewEVMClient(zap.NewNop(), EVMClientConfig{
+		RPCURL:     "http://127.0.0.1:1",
+		Signer:     NewLocalEVMSigner(key),
+		PrivateKey: hex.EncodeToString(crypto.FromECDSA(key)),
+	})
+	if err == nil || !strings.Contains(err.Error(), "use only one") {
+		t.Errorf("expected a signer with a private key to be rejected, got %v", err)
+	}
+
+	payer := solana.NewWallet().PrivateKey
+	client, err := NewSolanaClient(zap.NewNop(), SolanaClientConfig{
+		RPCURL:    newSolanaRPCServer(t, "ok").URL,
+		Signer:    NewLocalSolanaSigner(payer),
+		ProgramID: solana.SystemProgramID.String(),
+	})
+	if err != nil {
+		t.Fatalf("NewSolanaClient failed: %v", err)
+	}
+	if !client.GetPayerAddress().Equals(payer.PublicKey()) {
+		t.Errorf("expected the signer to be the payer, got %s", client.GetPayerAddress())
+	}
+}
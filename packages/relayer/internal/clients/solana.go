@@ -1,32 +1,68 @@
 package clients
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gagliardetto/solana-go"
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
 	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
 )
 
 // Default Wormhole devnet program ID
 var DefaultWormholeProgramID = solana.MustPublicKeyFromBase58("3u8hJUVTA4jH1wYAyUur7FFZVQ8H635K3tSHHF4ssjQ5")
 
+// SolanaNetwork holds the well-known public RPC endpoint and Wormhole Core Bridge of a Solana cluster
+type SolanaNetwork struct {
+	RPCURL            string
+	WormholeProgramID string // Empty if Wormhole has no well-known Core Bridge deployment on the cluster
+}
+
+// SolanaNetworks maps cluster names to their well-known settings. Wormhole's testnet deployment
+// lives on Solana devnet, so testnet has no default Core Bridge.
+var SolanaNetworks = map[string]SolanaNetwork{
+	"devnet": {
+		RPCURL:            "https://api.devnet.solana.com",
+		WormholeProgramID: DefaultWormholeProgramID.String(),
+	},
+	"testnet": {
+		RPCURL: "https://api.testnet.solana.com",
+	},
+	"mainnet-beta": {
+		RPCURL:            "https://api.mainnet-beta.solana.com",
+		WormholeProgramID: "worm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth",
+	},
+}
+
+// LookupSolanaNetwork returns the well-known settings of the named Solana cluster
+func LookupSolanaNetwork(name string) (SolanaNetwork, error) {
+	network, ok := SolanaNetworks[name]
+	if !ok {
+		return SolanaNetwork{}, fmt.Errorf("unknown Solana network %q (valid: devnet, testnet, mainnet-beta)", name)
+	}
+	return network, nil
+}
+
 // PDA seeds for our MessageBridge program
 var (
-	SeedConfig       = []byte("config")
-	SeedCurrentValue = []byte("current_value")
-	SeedEmitter      = []byte("emitter")
+	SeedConfig         = []byte("config")
+	SeedCurrentValue   = []byte("current_value")
+	SeedEmitter        = []byte("emitter")
 	SeedForeignEmitter = []byte("foreign_emitter")
-	SeedReceived     = []byte("received")
+	SeedReceived       = []byte("received")
 )
 
 // Wormhole PDA seeds
@@ -34,72 +70,360 @@ var (
 	SeedPostedVAA = []byte("PostedVAA")
 )
 
-// Instruction discriminators (from Anchor IDL)
+// ParseBlockhashCommitment parses the commitment level transaction blockhashes are fetched at.
+// Only finalized and confirmed are accepted: a processed blockhash may belong to a fork that is
+// skipped, making the transaction unlandable. Empty selects finalized.
+func ParseBlockhashCommitment(commitment string) (rpc.CommitmentType, error) {
+	switch rpc.CommitmentType(commitment) {
+	case "", rpc.CommitmentFinalized:
+		return rpc.CommitmentFinalized, nil
+	case rpc.CommitmentConfirmed:
+		return rpc.CommitmentConfirmed, nil
+	default:
+		return "", fmt.Errorf("invalid blockhash commitment %q (valid: finalized, confirmed)", commitment)
+	}
+}
+
+// solanaHealthTimeout bounds the RPC health check performed when creating the client
+const solanaHealthTimeout = 10 * time.Second
+
+// Solana transaction fees: every required signature pays a fixed base fee, and compute budget
+// instructions may add a priority fee of the unit price (in micro-lamports) times the unit limit
+const (
+	LamportsPerSOL             = 1_000_000_000
+	solanaLamportsPerSignature = 5000
+	solanaDefaultComputeUnits  = 200_000 // Limit per instruction when no compute unit limit is set
+	solanaMaxComputeUnits      = 1_400_000
+	computeBudgetSetUnitLimit  = 2 // SetComputeUnitLimit(u32) discriminator
+	computeBudgetSetUnitPrice  = 3 // SetComputeUnitPrice(u64) discriminator
+	microLamportsPerLamport    = 1_000_000
+)
+
+// ErrInsufficientBalance is returned when the payer cannot afford a transaction's estimated fee
+// or holds less than the configured minimum balance
+var ErrInsufficientBalance = errors.New("insufficient payer balance")
+
+// ErrVAANotPosted is returned when a VAA's posted VAA account does not exist yet, so receive_value
+// cannot read it; waiting for the guardians' post, or posting it again, may fix it
+var ErrVAANotPosted = errors.New("VAA not yet posted to Wormhole")
+
+// DiscriminatorReceiveValue is the built-in Anchor discriminator of the receive_value
+// instruction (see DefaultReceiveValueLayout)
 var DiscriminatorReceiveValue = []byte{131, 101, 246, 45, 2, 139, 81, 21}
 
 // SolanaClient handles interactions with Solana blockchain
 type SolanaClient struct {
 	client            *rpc.Client
-	payer             solana.PrivateKey
+	payer             SolanaSigner
 	programID         solana.PublicKey
 	wormholeProgramID solana.PublicKey
-	vaaServiceURL     string // URL of the VAA posting service
+	vaaServiceURL     string             // URL of the VAA posting service
+	preflight         bool               // Simulate transactions before sending them
+	commitment        rpc.CommitmentType // Commitment for blockhashes, preflight and simulation
+	confirmation      rpc.CommitmentType // Commitment a sent tx must reach to count as delivered (empty = none)
+	accounts          *accountCache
 	httpClient        *http.Client
+	gzip              *gzipRequests // VAA service request compression (nil = never compress)
+	vaaServiceMaxBody int64         // Largest VAA service response body read (0 = DefaultMaxResponseBytes)
+	vaaServiceAuth    ServiceAuth   // Credentials attached to every VAA service request
 	logger            *zap.Logger
+	minBalance        uint64            // Lamports the payer must hold before a transaction is sent
+	feesSpent         atomic.Uint64     // Estimated fees of the transactions sent so far, in lamports
+	programErrors     map[uint32]string // Names of custom program error codes, for readable failures
+	nonce             *durableNonce     // Durable nonce replacing the recent blockhash (nil = recent blockhash)
+	reads             *ReadLimiter      // Bounds the account reads in flight (nil = unlimited)
+	// Encoding of the receive_value instruction data (nil = DefaultReceiveValueLayout)
+	receiveValue *ReceiveValueLayout
+}
+
+// SolanaClientConfig holds the settings for a SolanaClient
+type SolanaClientConfig struct {
+	RPCURL            string // RPC URL for Solana, or comma-separated URLs to fail over between
+	PrivateKey        string // Base58-encoded payer secret key (mutually exclusive with KeypairFile and Signer)
+	KeypairFile       string // Path to a Solana CLI JSON keypair file (mutually exclusive with PrivateKey and Signer)
+	ProgramID         string // MessageBridge program ID
+	WormholeProgramID string // Wormhole Core Bridge program ID (empty = DefaultWormholeProgramID, devnet)
+	VAAServiceURL     string // If set, VAAs are posted via this service before calling receive_value
+	Preflight         bool   // Simulate transactions before sending them
+	// Commitment the transaction blockhash is fetched at: "finalized" (default) or "confirmed"
+	BlockhashCommitment string
+	// Commitment a sent transaction must reach before it counts as delivered: "processed",
+	// "confirmed" (default), "finalized" or "none" (see ParseSolanaConfirmation)
+	ConfirmationCommitment string
+	// Transport for requests to the VAA posting service (nil = default transport, honouring
+	// the HTTP_PROXY and HTTPS_PROXY environment variables)
+	HTTPTransport *http.Transport
+	// Gzip requests to the VAA posting service once it advertises support with Accept-Encoding: gzip
+	VAAServiceGzip bool
+	// Largest VAA posting service response body read, in bytes (0 = DefaultMaxResponseBytes)
+	VAAServiceMaxResponseBytes int64
+	// Bearer token and static headers sent with every VAA posting service request
+	VAAServiceAuth ServiceAuth
+	// Minimum payer balance, in lamports, for a transaction to be sent (0 = only its estimated fee is required)
+	MinBalanceLamports uint64
+	// Names of custom program error codes, added to or overriding DefaultProgramErrors (see ParseProgramErrors)
+	ProgramErrors map[uint32]string
+	// Signs as the payer in place of a local key, e.g. a remote signer (see NewRemoteSolanaSigner)
+	Signer SolanaSigner
+	// Durable nonce account whose stored nonce replaces the recent blockhash, so transactions do not
+	// expire while they wait to land (empty = a recent blockhash is fetched for each transaction)
+	NonceAccount string
+	// Solana CLI JSON keypair file of the nonce account's authority (empty = the payer is the authority)
+	NonceAuthorityKeypairFile string
+	// Maximum account reads (getAccountInfo) in flight, across all VAAs (0 = unlimited)
+	ReadConcurrency int
+	// Anchor IDL JSON file the receive_value instruction is built from (empty = DefaultReceiveValueLayout)
+	IDLFile string
 }
 
-// NewSolanaClient creates a new Solana client
-// If wormholeProgramID is empty, uses DefaultWormholeProgramID (devnet)
-// If vaaServiceURL is provided, VAAs will be posted via that service before calling receive_value
-func NewSolanaClient(logger *zap.Logger, rpcURL string, privateKeyBase58 string, programID string, wormholeProgramID string, vaaServiceURL string) (*SolanaClient, error) {
+// NewSolanaClient creates a new Solana client.
+// Each key and program ID is validated separately, and the RPC endpoint must report healthy.
+func NewSolanaClient(logger *zap.Logger, config SolanaClientConfig) (*SolanaClient, error) {
+	rpcURLs, err := ParseRPCURLs(config.RPCURL)
+	if err != nil {
+		return nil, err
+	}
+	programID := config.ProgramID
+	wormholeProgramID := config.WormholeProgramID
 	client := &SolanaClient{
-		logger:        logger.With(zap.String("component", "SolanaClient")),
-		vaaServiceURL: vaaServiceURL,
-		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
-		},
+		logger:            logger.With(zap.String("component", "SolanaClient")),
+		vaaServiceURL:     config.VAAServiceURL,
+		preflight:         config.Preflight,
+		accounts:          newAccountCache(DefaultAccountCacheTTL),
+		httpClient:        newHTTPClient(60*time.Second, config.HTTPTransport),
+		gzip:              newGzipRequests(config.VAAServiceGzip),
+		vaaServiceMaxBody: config.VAAServiceMaxResponseBytes,
+		vaaServiceAuth:    config.VAAServiceAuth,
+		minBalance:        config.MinBalanceLamports,
+		programErrors:     programErrorNames(config.ProgramErrors),
+	}
+
+	commitment, err := ParseBlockhashCommitment(config.BlockhashCommitment)
+	if err != nil {
+		return nil, err
 	}
+	client.commitment = commitment
 
-	client.logger.Info("Connecting to Solana", zap.String("rpcURL", rpcURL))
+	confirmation, err := ParseSolanaConfirmation(config.ConfirmationCommitment)
+	if err != nil {
+		return nil, err
+	}
+	client.confirmation = confirmation
 
-	// Create RPC client
-	rpcClient := rpc.New(rpcURL)
-	client.client = rpcClient
+	if client.reads, err = NewReadLimiter("solana", config.ReadConcurrency); err != nil {
+		return nil, err
+	}
 
-	// Parse private key from base58
-	privKey, err := solana.PrivateKeyFromBase58(privateKeyBase58)
-	if err != nil {
-		return nil, fmt.Errorf("invalid private key: %v", err)
+	// Use the configured signer, or load the payer key from base58 or from a keypair file
+	if config.Signer != nil {
+		if config.PrivateKey != "" || config.KeypairFile != "" {
+			return nil, fmt.Errorf("both a signer and a private key source were provided; use only one")
+		}
+		client.payer = config.Signer
+	} else {
+		privKey, err := loadSolanaPrivateKey(config.PrivateKey, config.KeypairFile)
+		if err != nil {
+			return nil, err
+		}
+		client.payer = NewLocalSolanaSigner(privKey)
+	}
+
+	// Load the durable nonce account and its authority, if configured
+	if config.NonceAccount != "" {
+		nonceAccount, err := solana.PublicKeyFromBase58(config.NonceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonce account %q (expected a base58 public key): %v", config.NonceAccount, err)
+		}
+		authority := client.payer
+		if config.NonceAuthorityKeypairFile != "" {
+			authorityKey, err := readSolanaKeypairFile(config.NonceAuthorityKeypairFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid nonce authority: %v", err)
+			}
+			authority = NewLocalSolanaSigner(authorityKey)
+		}
+		client.nonce = &durableNonce{account: nonceAccount, authority: authority, advanceTimeout: DefaultNonceAdvanceTimeout}
+	} else if config.NonceAuthorityKeypairFile != "" {
+		return nil, fmt.Errorf("a nonce authority was provided without a nonce account")
 	}
-	client.payer = privKey
 
 	// Parse program ID
 	progID, err := solana.PublicKeyFromBase58(programID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid program ID: %v", err)
+		return nil, fmt.Errorf("invalid program ID %q (expected a base58 public key): %v", programID, err)
 	}
 	client.programID = progID
 
+	// Build receive_value from the program's IDL, if one is given
+	if config.IDLFile != "" {
+		layout, err := LoadReceiveValueLayout(config.IDLFile)
+		if err != nil {
+			return nil, err
+		}
+		if layout.Address != "" && layout.Address != progID.String() {
+			client.logger.Warn("IDL was generated for another program address; check it matches the deployed program",
+				zap.String("idlAddress", layout.Address),
+				zap.String("programID", progID.String()))
+		}
+		client.logger.Info("Loaded receive_value layout from IDL",
+			zap.String("idlFile", config.IDLFile),
+			zap.String("discriminator", fmt.Sprintf("%x", layout.Discriminator)))
+		client.receiveValue = &layout
+	}
+
 	// Parse Wormhole program ID or use default
 	if wormholeProgramID != "" {
 		whProgID, err := solana.PublicKeyFromBase58(wormholeProgramID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid wormhole program ID: %v", err)
+			return nil, fmt.Errorf("invalid wormhole program ID %q (expected a base58 public key): %v", wormholeProgramID, err)
 		}
 		client.wormholeProgramID = whProgID
 	} else {
 		client.wormholeProgramID = DefaultWormholeProgramID
 	}
 
+	client.logger.Info("Connecting to Solana", zap.Strings("rpcURLs", rpcURLs))
+
+	// Create RPC client and make sure the node is reachable and healthy
+	if client.client, err = newSolanaRPC(client.logger, rpcURLs); err != nil {
+		return nil, err
+	}
+	healthCtx, cancel := context.WithTimeout(context.Background(), solanaHealthTimeout)
+	defer cancel()
+	if err := client.CheckHealth(healthCtx); err != nil {
+		return nil, fmt.Errorf("Solana RPC %s is not usable: %v", config.RPCURL, err)
+	}
+
 	client.logger.Info("Solana client initialized",
 		zap.String("payer", client.payer.PublicKey().String()),
 		zap.String("programID", client.programID.String()),
 		zap.String("wormholeProgramID", client.wormholeProgramID.String()),
-		zap.String("vaaServiceURL", client.vaaServiceURL))
+		zap.String("vaaServiceURL", client.vaaServiceURL),
+		zap.String("blockhashCommitment", string(client.commitment)),
+		zap.String("minBalanceSOL", FormatSOL(client.minBalance)),
+		zap.Bool("durableNonce", client.nonce != nil))
+	if client.nonce != nil {
+		client.logger.Info("Using durable nonce instead of recent blockhashes",
+			zap.String("nonceAccount", client.nonce.account.String()),
+			zap.String("nonceAuthority", client.nonce.authority.PublicKey().String()))
+	}
 
 	return client, nil
 }
 
+// newSolanaRPC creates an RPC client for the only URL of rpcURLs, or for the first URL with
+// failover to the others
+func newSolanaRPC(logger *zap.Logger, rpcURLs []string) (*rpc.Client, error) {
+	if len(rpcURLs) == 1 {
+		return rpc.New(rpcURLs[0]), nil
+	}
+	failover, err := newRPCFailover(logger, rpcURLs, http.DefaultTransport.(*http.Transport).Clone())
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewWithCustomRPCClient(jsonrpc.NewClientWithOpts(rpcURLs[0], &jsonrpc.RPCClientOpts{
+		HTTPClient: &http.Client{Transport: failover},
+	})), nil
+}
+
+// CheckHealth verifies the RPC endpoint is reachable and reports itself healthy
+func (c *SolanaClient) CheckHealth(ctx context.Context) error {
+	health, err := c.client.GetHealth(ctx)
+	if err != nil {
+		return fmt.Errorf("health check failed: %v", err)
+	}
+	if health != rpc.HealthOk {
+		return fmt.Errorf("node reports %q instead of %q", health, rpc.HealthOk)
+	}
+	return nil
+}
+
+// Balance returns the payer's balance in lamports
+func (c *SolanaClient) Balance(ctx context.Context) (uint64, error) {
+	result, err := c.client.GetBalance(ctx, c.payer.PublicKey(), rpc.CommitmentConfirmed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get balance: %v", err)
+	}
+	return result.Value, nil
+}
+
+// SolanaFeeEstimate is the fee a transaction is expected to pay, in lamports
+type SolanaFeeEstimate struct {
+	Signatures       int    // Required signatures, each paying the base fee
+	BaseFee          uint64 // Lamports for the signatures
+	ComputeUnitLimit uint32 // Compute units the priority fee is charged for
+	ComputeUnitPrice uint64 // Micro-lamports per compute unit (0 = no priority fee)
+	PriorityFee      uint64 // Lamports for the compute units
+}
+
+// Total returns the estimated fee in lamports
+func (f SolanaFeeEstimate) Total() uint64 {
+	return f.BaseFee + f.PriorityFee
+}
+
+// EstimateFee returns the fee tx is expected to pay: the base fee for each required signature,
+// plus the priority fee requested by its compute budget instructions, if any. Without a
+// SetComputeUnitLimit instruction, the runtime's default limit per instruction is assumed.
+func (c *SolanaClient) EstimateFee(tx *solana.Transaction) SolanaFeeEstimate {
+	estimate := SolanaFeeEstimate{Signatures: int(tx.Message.Header.NumRequiredSignatures)}
+	estimate.BaseFee = uint64(estimate.Signatures) * solanaLamportsPerSignature
+
+	var unitLimit uint32
+	limitSet := false
+	instructions := 0
+	for _, ix := range tx.Message.Instructions {
+		programID, err := tx.ResolveProgramIDIndex(ix.ProgramIDIndex)
+		if err != nil || !programID.Equals(solana.ComputeBudget) {
+			instructions++
+			continue
+		}
+		switch {
+		case len(ix.Data) >= 5 && ix.Data[0] == computeBudgetSetUnitLimit:
+			unitLimit = binary.LittleEndian.Uint32(ix.Data[1:5])
+			limitSet = true
+		case len(ix.Data) >= 9 && ix.Data[0] == computeBudgetSetUnitPrice:
+			estimate.ComputeUnitPrice = binary.LittleEndian.Uint64(ix.Data[1:9])
+		}
+	}
+	if !limitSet {
+		unitLimit = uint32(min(instructions*solanaDefaultComputeUnits, solanaMaxComputeUnits))
+	}
+	estimate.ComputeUnitLimit = min(unitLimit, solanaMaxComputeUnits)
+
+	// The priority fee is rounded up to whole lamports
+	microLamports := estimate.ComputeUnitPrice * uint64(estimate.ComputeUnitLimit)
+	estimate.PriorityFee = (microLamports + microLamportsPerLamport - 1) / microLamportsPerLamport
+	return estimate
+}
+
+// checkBalance returns an error wrapping ErrInsufficientBalance if the payer cannot afford fee
+// or holds less than the configured minimum balance. A failed balance lookup is only logged,
+// since the node rejects a transaction the payer cannot afford anyway.
+func (c *SolanaClient) checkBalance(ctx context.Context, fee SolanaFeeEstimate) error {
+	balance, err := c.Balance(ctx)
+	if err != nil {
+		c.logger.Warn("Could not check payer balance before sending", zap.Error(err))
+		return nil
+	}
+
+	required := max(fee.Total(), c.minBalance)
+	if balance < required {
+		return fmt.Errorf("%w: payer %s has %s SOL, needs at least %s SOL (estimated fee %d lamports, minimum balance %s SOL)",
+			ErrInsufficientBalance, c.payer.PublicKey(), FormatSOL(balance), FormatSOL(required), fee.Total(), FormatSOL(c.minBalance))
+	}
+	return nil
+}
+
+// FeesSpent returns the estimated fees of the transactions sent so far, in lamports
+func (c *SolanaClient) FeesSpent() uint64 {
+	return c.feesSpent.Load()
+}
+
+// FormatSOL formats an amount of lamports in SOL
+func FormatSOL(lamports uint64) string {
+	return strconv.FormatFloat(float64(lamports)/LamportsPerSOL, 'f', -1, 64)
+}
+
 // GetPayerAddress returns the payer's public key
 func (c *SolanaClient) GetPayerAddress() solana.PublicKey {
 	return c.payer.PublicKey()
@@ -127,6 +451,25 @@ func (c *SolanaClient) DeriveForeignEmitterPDA(chainID uint16) (solana.PublicKey
 	return solana.FindProgramAddress([][]byte{SeedForeignEmitter, chainIDBytes}, c.programID)
 }
 
+// foreignEmitterAccountSize is the size of a MessageBridge ForeignEmitter account:
+// 8 (discriminator) + 2 (chain_id) + 32 (address) + 1 (is_default_payload)
+const foreignEmitterAccountSize = 8 + 2 + 32 + 1
+
+// VerifyEmitterRegistered reads the foreign_emitter PDA of chainID and checks it holds emitter.
+// It returns an error wrapping ErrEmitterNotRegistered if the account is missing or holds another
+// address, since receive_value would then fail; other errors mean the lookup itself failed.
+func (c *SolanaClient) VerifyEmitterRegistered(ctx context.Context, chainID uint16, emitter [32]byte) error {
+	registered, _, err := c.ForeignEmitter(ctx, chainID)
+	if err != nil {
+		return err
+	}
+	if registered.Address != emitter {
+		return fmt.Errorf("%w: chain %d has emitter %x registered, VAA is from %x",
+			ErrEmitterNotRegistered, chainID, registered.Address, emitter)
+	}
+	return nil
+}
+
 // DeriveReceivedMessagePDA derives the received message PDA for replay protection
 func (c *SolanaClient) DeriveReceivedMessagePDA(emitterChain uint16, sequence uint64) (solana.PublicKey, uint8, error) {
 	chainIDBytes := make([]byte, 2)
@@ -196,22 +539,23 @@ func (c *SolanaClient) BuildReceiveValueInstruction(
 		return nil, fmt.Errorf("failed to derive received message PDA: %v", err)
 	}
 
-	// Build instruction data: discriminator + vaa_hash (32 bytes) + emitter_chain (u16) + sequence (u64)
-	data := make([]byte, 8+32+2+8)
-	copy(data[0:8], DiscriminatorReceiveValue)
-	copy(data[8:40], vaaHash[:])
-	binary.LittleEndian.PutUint16(data[40:42], emitterChain)
-	binary.LittleEndian.PutUint64(data[42:50], sequence)
+	// Build instruction data: the discriminator, then vaa_hash (32 bytes), emitter_chain (u16) and
+	// sequence (u64) in the order of the layout
+	layout := DefaultReceiveValueLayout
+	if c.receiveValue != nil {
+		layout = *c.receiveValue
+	}
+	data := layout.encode(vaaHash, emitterChain, sequence)
 
 	// Build accounts list
 	accounts := []*solana.AccountMeta{
-		{PublicKey: c.payer.PublicKey(), IsSigner: true, IsWritable: true},   // payer
-		{PublicKey: configPDA, IsSigner: false, IsWritable: false},           // config
-		{PublicKey: currentValuePDA, IsSigner: false, IsWritable: true},      // current_value
-		{PublicKey: c.wormholeProgramID, IsSigner: false, IsWritable: false}, // wormhole_program
-		{PublicKey: postedVAA, IsSigner: false, IsWritable: false},           // posted_vaa
-		{PublicKey: foreignEmitterPDA, IsSigner: false, IsWritable: false},   // foreign_emitter
-		{PublicKey: receivedMessagePDA, IsSigner: false, IsWritable: true},   // received_message
+		{PublicKey: c.payer.PublicKey(), IsSigner: true, IsWritable: true},      // payer
+		{PublicKey: configPDA, IsSigner: false, IsWritable: false},              // config
+		{PublicKey: currentValuePDA, IsSigner: false, IsWritable: true},         // current_value
+		{PublicKey: c.wormholeProgramID, IsSigner: false, IsWritable: false},    // wormhole_program
+		{PublicKey: postedVAA, IsSigner: false, IsWritable: false},              // posted_vaa
+		{PublicKey: foreignEmitterPDA, IsSigner: false, IsWritable: false},      // foreign_emitter
+		{PublicKey: receivedMessagePDA, IsSigner: false, IsWritable: true},      // received_message
 		{PublicKey: solana.SystemProgramID, IsSigner: false, IsWritable: false}, // system_program
 	}
 
@@ -236,16 +580,26 @@ func (c *SolanaClient) SendReceiveValueTransaction(
 		zap.Uint64("sequence", sequence),
 		zap.Int("vaaLength", len(vaaBytes)))
 
+	ix, err := c.prepareReceiveValue(ctx, vaaBytes, emitterChain, sequence)
+	if err != nil {
+		return "", err
+	}
+	return c.sendTransaction(ctx, []solana.Instruction{ix})
+}
+
+// prepareReceiveValue builds the receive_value instruction for a VAA, checking the VAA is
+// posted to Wormhole first
+func (c *SolanaClient) prepareReceiveValue(ctx context.Context, vaaBytes []byte, emitterChain uint16, sequence uint64) (solana.Instruction, error) {
 	// Compute VAA hash
 	vaaHash, err := ComputeVAAHash(vaaBytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to compute VAA hash: %v", err)
+		return nil, fmt.Errorf("failed to compute VAA hash: %v", err)
 	}
 
 	// Derive posted VAA PDA
 	postedVAA, _, err := c.DerivePostedVAAPDA(vaaHash)
 	if err != nil {
-		return "", fmt.Errorf("failed to derive posted VAA PDA: %v", err)
+		return nil, fmt.Errorf("failed to derive posted VAA PDA: %v", err)
 	}
 
 	c.logger.Debug("Derived PDAs",
@@ -253,30 +607,50 @@ func (c *SolanaClient) SendReceiveValueTransaction(
 		zap.String("vaaHash", fmt.Sprintf("%x", vaaHash)))
 
 	// Check if VAA is already posted
-	postedVAAInfo, err := c.client.GetAccountInfo(ctx, postedVAA)
+	posted, err := c.accountExists(ctx, postedVAA)
 	if err != nil {
 		c.logger.Warn("Could not check posted VAA account", zap.Error(err))
 	}
-	if postedVAAInfo == nil || postedVAAInfo.Value == nil {
-		return "", fmt.Errorf("VAA not yet posted to Wormhole. PostedVAA account %s does not exist. Please ensure the VAA is posted via Wormhole first", postedVAA.String())
+	if !posted {
+		return nil, fmt.Errorf("%w. PostedVAA account %s does not exist. Please ensure the VAA is posted via Wormhole first", ErrVAANotPosted, postedVAA.String())
 	}
 
 	// Build receive_value instruction
 	ix, err := c.BuildReceiveValueInstruction(vaaHash, emitterChain, sequence, postedVAA)
 	if err != nil {
-		return "", fmt.Errorf("failed to build instruction: %v", err)
+		return nil, fmt.Errorf("failed to build instruction: %v", err)
 	}
+	return ix, nil
+}
 
-	// Get recent blockhash
-	recentBlockhash, err := c.client.GetLatestBlockhash(ctx, rpc.CommitmentFinalized)
-	if err != nil {
-		return "", fmt.Errorf("failed to get recent blockhash: %v", err)
+// sendTransaction signs and sends a transaction of the given instructions, preceded by the
+// nonce advance when a durable nonce is configured, and waits for it to be confirmed
+func (c *SolanaClient) sendTransaction(ctx context.Context, instructions []solana.Instruction) (string, error) {
+	var blockhash solana.Hash
+	var err error
+	if c.nonce != nil {
+		// The durable nonce never expires; transactions using it are sent one at a time
+		c.nonce.mu.Lock()
+		defer c.nonce.mu.Unlock()
+		blockhash, err = c.nextNonce(ctx)
+		if err != nil {
+			return "", err
+		}
+		instructions = append([]solana.Instruction{c.advanceNonceInstruction()}, instructions...)
+	} else {
+		// Get recent blockhash. A confirmed blockhash is available sooner than a finalized one,
+		// leaving more of its ~150-block (~60-90s) validity window for the transaction to land.
+		recentBlockhash, err := c.client.GetLatestBlockhash(ctx, c.commitment)
+		if err != nil {
+			return "", fmt.Errorf("failed to get recent blockhash: %v", err)
+		}
+		blockhash = recentBlockhash.Value.Blockhash
 	}
 
 	// Build transaction
 	tx, err := solana.NewTransaction(
-		[]solana.Instruction{ix},
-		recentBlockhash.Value.Blockhash,
+		instructions,
+		blockhash,
 		solana.TransactionPayer(c.payer.PublicKey()),
 	)
 	if err != nil {
@@ -284,27 +658,145 @@ func (c *SolanaClient) SendReceiveValueTransaction(
 	}
 
 	// Sign transaction
-	_, err = tx.Sign(func(key solana.PublicKey) *solana.PrivateKey {
-		if key.Equals(c.payer.PublicKey()) {
-			return &c.payer
+	signers := []SolanaSigner{c.payer}
+	if c.nonce != nil {
+		signers = append(signers, c.nonce.authority)
+	}
+	if err := signSolanaTransaction(ctx, tx, signers...); err != nil {
+		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	// Make sure the payer can afford the transaction before spending an RPC round trip on it
+	fee := c.EstimateFee(tx)
+	if err := c.checkBalance(ctx, fee); err != nil {
+		return "", err
+	}
+
+	// Simulate first if requested, so a doomed transaction is never paid for
+	if c.preflight {
+		if err := c.simulateTransaction(ctx, tx); err != nil {
+			return "", err
 		}
-		return nil
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to sign transaction: %v", err)
 	}
 
 	// Send transaction
-	sig, err := c.client.SendTransaction(ctx, tx)
+	// Preflight must see the blockhash at the commitment it was fetched at, or it is not found
+	sig, err := c.client.SendTransactionWithOpts(ctx, tx, rpc.TransactionOpts{PreflightCommitment: c.commitment})
 	if err != nil {
-		return "", fmt.Errorf("failed to send transaction: %v", err)
+		reason := describeSendError(err, c.programErrors)
+		c.logger.Error("Transaction rejected", zap.String("reason", reason))
+		return "", fmt.Errorf("failed to send transaction: %s", reason)
 	}
 
-	c.logger.Info("Transaction sent", zap.String("signature", sig.String()))
+	if c.nonce != nil {
+		c.nonce.lastUsed, c.nonce.lastSent = blockhash, time.Now()
+	}
+
+	// The fee is charged once the transaction lands, even if it fails; count it as spent on sending
+	spent := c.feesSpent.Add(fee.Total())
+	metrics.SolanaFees.Add(float64(fee.Total()))
+	c.logger.Info("Transaction sent",
+		zap.String("signature", sig.String()),
+		zap.Uint64("feeLamports", fee.Total()),
+		zap.Uint64("priorityFeeLamports", fee.PriorityFee),
+		zap.Uint64("totalFeesLamports", spent))
+
+	if err := c.waitForSignature(ctx, sig); err != nil {
+		return "", err
+	}
+	if c.confirmation != "" {
+		c.logger.Debug("Transaction confirmed",
+			zap.String("signature", sig.String()),
+			zap.String("commitment", string(c.confirmation)))
+	}
 
 	return sig.String(), nil
 }
 
+// simulateTransaction runs tx through simulateTransaction and returns an error describing
+// the failure (decoded Anchor error, compute units, program logs) if it would revert
+func (c *SolanaClient) simulateTransaction(ctx context.Context, tx *solana.Transaction) error {
+	result, err := c.client.SimulateTransactionWithOpts(ctx, tx, &rpc.SimulateTransactionOpts{Commitment: c.commitment})
+	if err != nil {
+		return fmt.Errorf("failed to simulate transaction: %v", err)
+	}
+	if result == nil || result.Value == nil {
+		return fmt.Errorf("failed to simulate transaction: empty response")
+	}
+
+	sim := result.Value
+	var unitsConsumed uint64
+	if sim.UnitsConsumed != nil {
+		unitsConsumed = *sim.UnitsConsumed
+	}
+
+	if sim.Err == nil {
+		c.logger.Debug("Transaction simulation succeeded", zap.Uint64("computeUnits", unitsConsumed))
+		return nil
+	}
+
+	reason := describeSimulationError(sim.Err, sim.Logs, c.programErrors)
+	c.logger.Error("Transaction simulation failed",
+		zap.String("reason", reason),
+		zap.Uint64("computeUnits", unitsConsumed),
+		zap.Strings("logs", sim.Logs))
+	return fmt.Errorf("transaction simulation failed: %s (compute units: %d)", reason, unitsConsumed)
+}
+
+// anchorErrorLogPrefix marks the program log line Anchor emits for a failed constraint or require!
+const anchorErrorLogPrefix = "Program log: AnchorError"
+
+// describeSimulationError turns a simulation error and its logs into a readable reason.
+// It prefers Anchor's own error log line (error name, number and message) or the system
+// program's "already in use" line, and falls back to the custom program error code from
+// the InstructionError, named from names when known, then to the raw error.
+func describeSimulationError(simErr interface{}, logs []string, names map[uint32]string) string {
+	for _, line := range logs {
+		if strings.HasPrefix(line, anchorErrorLogPrefix) {
+			return strings.TrimPrefix(line, "Program log: ")
+		}
+		// The system program refuses to re-create an existing account, e.g. the received-message
+		// PDA of a VAA that was already delivered
+		if IsAccountInUse(line) {
+			return line
+		}
+	}
+
+	if code, ok := customErrorCode(simErr); ok {
+		return describeProgramError(code, names)
+	}
+
+	raw, err := json.Marshal(simErr)
+	if err != nil {
+		return fmt.Sprintf("%v", simErr)
+	}
+	return string(raw)
+}
+
+// customErrorCode extracts N from an error of the form {"InstructionError": [idx, {"Custom": N}]}
+func customErrorCode(simErr interface{}) (uint32, bool) {
+	errMap, ok := simErr.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	instructionErr, ok := errMap["InstructionError"].([]interface{})
+	if !ok || len(instructionErr) != 2 {
+		return 0, false
+	}
+	detail, ok := instructionErr[1].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	switch code := detail["Custom"].(type) {
+	case float64:
+		return uint32(code), true
+	case json.Number:
+		n, err := code.Int64()
+		return uint32(n), err == nil
+	}
+	return 0, false
+}
+
 // PostVAAToWormhole posts a VAA to the Wormhole bridge for verification.
 // If vaaServiceURL is configured, it calls the external VAA posting service.
 // Otherwise, it just checks if the VAA is already posted.
@@ -320,19 +812,19 @@ func (c *SolanaClient) PostVAAToWormhole(ctx context.Context, vaaBytes []byte) (
 	}
 
 	// Check if VAA is already posted
-	info, err := c.client.GetAccountInfo(ctx, postedVAA)
+	posted, err := c.accountExists(ctx, postedVAA)
 	if err != nil {
 		c.logger.Warn("Failed to check posted VAA account", zap.Error(err))
 	}
 
-	if info != nil && info.Value != nil {
+	if posted {
 		c.logger.Info("VAA already posted to Wormhole", zap.String("postedVAA", postedVAA.String()))
 		return postedVAA, nil
 	}
 
 	// VAA not posted - try to post it via the VAA service
 	if c.vaaServiceURL == "" {
-		return solana.PublicKey{}, fmt.Errorf("VAA not yet posted to Wormhole at %s and no VAA service URL configured", postedVAA.String())
+		return solana.PublicKey{}, fmt.Errorf("%w at %s and no VAA service URL configured", ErrVAANotPosted, postedVAA.String())
 	}
 
 	c.logger.Info("Posting VAA via VAA service",
@@ -346,19 +838,58 @@ func (c *SolanaClient) PostVAAToWormhole(ctx context.Context, vaaBytes []byte) (
 
 	// Verify the VAA is now posted
 	for i := 0; i < 10; i++ {
-		time.Sleep(2 * time.Second)
-		info, err = c.client.GetAccountInfo(ctx, postedVAA)
-		if err == nil && info != nil && info.Value != nil {
+		select {
+		case <-ctx.Done():
+			return solana.PublicKey{}, fmt.Errorf("VAA posted via service but not yet found on chain: %w", ctx.Err())
+		case <-time.After(2 * time.Second):
+		}
+		if posted, _ := c.accountExists(ctx, postedVAA); posted {
 			c.logger.Info("VAA successfully posted to Wormhole", zap.String("postedVAA", postedVAA.String()))
 			return postedVAA, nil
 		}
 		c.logger.Debug("Waiting for VAA to be posted...", zap.Int("attempt", i+1))
 	}
 
-	return solana.PublicKey{}, fmt.Errorf("VAA was posted but not found on chain after 20 seconds")
+	return solana.PublicKey{}, fmt.Errorf("%w: posted via service but not found on chain after 20 seconds", ErrVAANotPosted)
+}
+
+// getAccountInfo reads account under the read concurrency limit, at opts' commitment (nil = the
+// RPC's default)
+func (c *SolanaClient) getAccountInfo(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error) {
+	return limitRead(ctx, c.reads, func() (*rpc.GetAccountInfoResult, error) {
+		if opts == nil {
+			return c.client.GetAccountInfo(ctx, account)
+		}
+		return c.client.GetAccountInfoWithOpts(ctx, account, opts)
+	})
+}
+
+// accountExists reports whether account exists on chain. Accounts seen to exist are cached
+// for a short time; missing accounts are always looked up again. A failed lookup returns
+// false along with the error.
+func (c *SolanaClient) accountExists(ctx context.Context, account solana.PublicKey) (bool, error) {
+	if c.accounts.exists(account) {
+		return true, nil
+	}
+
+	info, err := c.getAccountInfo(ctx, account, nil)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info == nil || info.Value == nil {
+		return false, nil
+	}
+
+	c.accounts.markExists(account)
+	return true, nil
 }
 
-// callVAAService posts a VAA to the external VAA posting service
+// callVAAService posts a VAA to the external VAA posting service. Any non-2xx status is a
+// failure, reported as an HTTPStatusError carrying the service's error message or the start of
+// the raw body, and so is a response without "success": true.
 func (c *SolanaClient) callVAAService(ctx context.Context, vaaBytes []byte) error {
 	// Prepare request body
 	reqBody := map[string]string{
@@ -371,11 +902,12 @@ func (c *SolanaClient) callVAAService(ctx context.Context, vaaBytes []byte) erro
 
 	// Create HTTP request
 	url := c.vaaServiceURL + "/post-vaa"
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqJSON))
+	req, compressed, err := c.gzip.newRequest(ctx, "POST", url, reqJSON)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	c.vaaServiceAuth.apply(req)
 
 	// Send request
 	resp, err := c.httpClient.Do(req)
@@ -384,25 +916,45 @@ func (c *SolanaClient) callVAAService(ctx context.Context, vaaBytes []byte) erro
 	}
 	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	// Read response, refusing to buffer an oversized body
+	body, err := readResponseBody(resp.Body, c.vaaServiceMaxBody)
+	if c.gzip.observe(resp, compressed) {
+		c.logger.Info("VAA service rejected a compressed request, sending uncompressed from now on")
+		return c.callVAAService(ctx, vaaBytes)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return fmt.Errorf("failed to read response (HTTP %d): %w", resp.StatusCode, err)
 	}
 
-	// Parse response
+	// Parse response; error statuses may carry a JSON error or an HTML/plain-text page
 	var result struct {
 		Success   bool   `json:"success"`
 		Signature string `json:"signature"`
 		Error     string `json:"error"`
 		Message   string `json:"message"`
 	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return fmt.Errorf("failed to parse response: %w (body: %s)", err, string(body))
+	parseErr := json.Unmarshal(body, &result)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := truncateBody(body)
+		if parseErr == nil {
+			if detail := vaaServiceErrorDetail(result.Error, result.Message); detail != "" {
+				message = detail
+			}
+		}
+		return fmt.Errorf("VAA service request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: message})
+	}
+
+	if parseErr != nil {
+		return fmt.Errorf("failed to parse response (HTTP %d, body %q): %v", resp.StatusCode, truncateBody(body), parseErr)
 	}
 
-	if !result.Success && result.Error != "" {
-		return fmt.Errorf("VAA service error: %s", result.Error)
+	if !result.Success {
+		detail := vaaServiceErrorDetail(result.Error, result.Message)
+		if detail == "" {
+			detail = fmt.Sprintf("no error given (body %q)", truncateBody(body))
+		}
+		return fmt.Errorf("VAA service error (HTTP %d): %s", resp.StatusCode, detail)
 	}
 
 	c.logger.Info("VAA posted via service",
@@ -411,3 +963,15 @@ func (c *SolanaClient) callVAAService(ctx context.Context, vaaBytes []byte) erro
 
 	return nil
 }
+
+// vaaServiceErrorDetail joins the error and message fields of a VAA service response, either
+// of which may be empty
+func vaaServiceErrorDetail(errText, message string) string {
+	switch {
+	case errText == "":
+		return message
+	case message == "" || message == errText:
+		return errText
+	}
+	return errText + ": " + message
+}
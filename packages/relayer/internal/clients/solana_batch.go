@@ -0,0 +1,187 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+)
+
+// Limits on packing receive_value instructions into one transaction
+const (
+	// solanaMaxTransactionSize is the largest serialized transaction a validator accepts
+	solanaMaxTransactionSize = 1232
+	// solanaMaxInstructionsPerTx is how many instructions fit in the compute budget the runtime
+	// grants a transaction without a SetComputeUnitLimit instruction
+	solanaMaxInstructionsPerTx = solanaMaxComputeUnits / solanaDefaultComputeUnits
+)
+
+// ErrInstructionTooLarge is returned for a VAA whose receive_value instruction does not fit in
+// a transaction even on its own
+var ErrInstructionTooLarge = errors.New("receive_value instruction does not fit in a transaction")
+
+// ReceiveValueRequest is one VAA to deliver with receive_value
+type ReceiveValueRequest struct {
+	VAABytes     []byte
+	EmitterChain uint16
+	Sequence     uint64
+}
+
+// ReceiveValueResult is the outcome of one ReceiveValueRequest of a batch
+type ReceiveValueResult struct {
+	Signature       string // Transaction that delivered the VAA, shared with the requests packed with it
+	AlreadyReceived bool   // The program had received the VAA before, so it was not sent again
+	Err             error  // Why the VAA was not delivered
+}
+
+// SendReceiveValueBatch delivers several VAAs, each of a distinct sequence, packing as many
+// receive_value instructions into each transaction as fit its size and compute limits, and
+// splitting the rest into further transactions. VAAs whose received message account already
+// exists are skipped, since one replayed instruction would fail its whole transaction.
+//
+// It returns one result per request, in order; a failed transaction fails every request
+// packed into it, and later transactions are still sent. An instruction too large for any
+// transaction fails only its request, with ErrInstructionTooLarge. An error is returned only
+// for a batch that cannot be sent at all, such as one requesting a sequence twice.
+func (c *SolanaClient) SendReceiveValueBatch(ctx context.Context, requests []ReceiveValueRequest) ([]ReceiveValueResult, error) {
+	type messageKey struct {
+		chain    uint16
+		sequence uint64
+	}
+	seen := make(map[messageKey]bool, len(requests))
+	for _, req := range requests {
+		key := messageKey{req.EmitterChain, req.Sequence}
+		if seen[key] {
+			return nil, fmt.Errorf("sequence %d of chain %d is requested more than once", req.Sequence, req.EmitterChain)
+		}
+		seen[key] = true
+	}
+
+	results := make([]ReceiveValueResult, len(requests))
+	var pending []int // Requests to send, by index
+	var instructions []solana.Instruction
+	for i, req := range requests {
+		receivedMessage, _, err := c.DeriveReceivedMessagePDA(req.EmitterChain, req.Sequence)
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to derive received message PDA: %v", err)
+			continue
+		}
+		received, err := c.accountExists(ctx, receivedMessage)
+		if err != nil {
+			c.logger.Warn("Could not check received message account; sending anyway",
+				zap.Uint64("sequence", req.Sequence), zap.Error(err))
+		}
+		if received {
+			results[i].AlreadyReceived = true
+			continue
+		}
+
+		ix, err := c.prepareReceiveValue(ctx, req.VAABytes, req.EmitterChain, req.Sequence)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+		instructions = append(instructions, ix)
+	}
+
+	var prefix []solana.Instruction
+	if c.nonce != nil {
+		prefix = []solana.Instruction{c.advanceNonceInstruction()}
+	}
+	// An instruction too large to send fails only its own request
+	groups, packErrs := packInstructions(c.payer.PublicKey(), prefix, instructions)
+	for k, err := range packErrs {
+		if err != nil {
+			results[pending[k]].Err = err
+			c.logger.Error("receive_value instruction does not fit in a transaction",
+				zap.Uint64("sequence", requests[pending[k]].Sequence), zap.Error(err))
+		}
+	}
+
+	c.logger.Info("Sending receive_value batch",
+		zap.Int("requests", len(requests)),
+		zap.Int("instructions", len(instructions)),
+		zap.Int("transactions", len(groups)))
+
+	for _, group := range groups {
+		indexes := make([]int, len(group))
+		groupInstructions := make([]solana.Instruction, len(group))
+		for j, k := range group {
+			indexes[j], groupInstructions[j] = pending[k], instructions[k]
+		}
+		sig, err := c.sendTransaction(ctx, groupInstructions)
+
+		sequences := make([]uint64, len(indexes))
+		for j, i := range indexes {
+			results[i].Signature, results[i].Err = sig, err
+			sequences[j] = requests[i].Sequence
+		}
+		if err != nil {
+			c.logger.Error("receive_value batch transaction failed", zap.Uint64s("sequences", sequences), zap.Error(err))
+		} else {
+			c.logger.Info("receive_value batch transaction delivered", zap.Uint64s("sequences", sequences), zap.String("signature", sig))
+		}
+	}
+	return results, nil
+}
+
+// packInstructions splits instructions, in order, into groups that each fit in one transaction
+// paid by payer after the prefix instructions, and returns the indexes of the instructions in
+// each group. An instruction that does not fit in a transaction on its own is left out, with
+// an error wrapping ErrInstructionTooLarge at its index in errs.
+func packInstructions(payer solana.PublicKey, prefix, instructions []solana.Instruction) (groups [][]int, errs []error) {
+	fits := func(indexes []int) (bool, error) {
+		tx := append([]solana.Instruction{}, prefix...)
+		for _, k := range indexes {
+			tx = append(tx, instructions[k])
+		}
+		size, err := solanaTransactionSize(payer, tx)
+		return err == nil && size <= solanaMaxTransactionSize, err
+	}
+
+	errs = make([]error, len(instructions))
+	var group []int
+	for k := range instructions {
+		if len(group) > 0 && len(prefix)+len(group) < solanaMaxInstructionsPerTx {
+			if ok, _ := fits(append(group[:len(group):len(group)], k)); ok {
+				group = append(group, k)
+				continue
+			}
+		}
+		ok, err := fits([]int{k})
+		if err != nil {
+			errs[k] = fmt.Errorf("%w: %v", ErrInstructionTooLarge, err)
+			continue
+		}
+		if !ok {
+			errs[k] = fmt.Errorf("%w: over %d bytes even on its own", ErrInstructionTooLarge, solanaMaxTransactionSize)
+			continue
+		}
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+		group = []int{k}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups, errs
+}
+
+// solanaTransactionSize returns the serialized size of a signed transaction of instructions
+// paid by payer. The blockhash does not change the size, so a zero one is used.
+func solanaTransactionSize(payer solana.PublicKey, instructions []solana.Instruction) (int, error) {
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, solana.TransactionPayer(payer))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create transaction: %v", err)
+	}
+	tx.Signatures = make([]solana.Signature, tx.Message.Header.NumRequiredSignatures)
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode transaction: %v", err)
+	}
+	return len(data), nil
+}
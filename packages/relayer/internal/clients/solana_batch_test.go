@@ -0,0 +1,224 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// newBatchTestClient returns a client for a fresh program and payer, sending to rpcURL
+func newBatchTestClient(rpcURL string) *SolanaClient {
+	return &SolanaClient{
+		client:            rpc.New(rpcURL),
+		payer:             NewLocalSolanaSigner(solana.NewWallet().PrivateKey),
+		programID:         solana.NewWallet().PublicKey(),
+		wormholeProgramID: DefaultWormholeProgramID,
+		accounts:          newAccountCache(0),
+		logger:            zap.NewNop(),
+	}
+}
+
+// batchTestVAA returns a VAA without signatures whose body is unique to sequence
+func batchTestVAA(sequence uint64) []byte {
+	vaa := make([]byte, 6+51)
+	vaa[0] = 1
+	binary.BigEndian.PutUint64(vaa[6+42:6+50], sequence)
+	return vaa
+}
+
+func TestPackInstructions(t *testing.T) {
+	client := newBatchTestClient("http://127.0.0.1:1")
+
+	var instructions []solana.Instruction
+	for seq := uint64(1); seq <= 12; seq++ {
+		vaaHash, _ := ComputeVAAHash(batchTestVAA(seq))
+		postedVAA, _, _ := client.DerivePostedVAAPDA(vaaHash)
+		ix, err := client.BuildReceiveValueInstruction(vaaHash, 2, seq, postedVAA)
+		if err != nil {
+			t.Fatalf("failed to build instruction: %v", err)
+		}
+		instructions = append(instructions, ix)
+	}
+
+	prefix := []solana.Instruction{solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{
+		{PublicKey: client.payer.PublicKey(), IsSigner: true, IsWritable: true},
+	}, []byte{4, 0, 0, 0})}
+	// One instruction too large for any transaction is left out, and the rest still packed
+	oversized := solana.NewInstruction(client.programID, nil, make([]byte, solanaMaxTransactionSize))
+	instructions = append(instructions[:5], append([]solana.Instruction{oversized}, instructions[5:]...)...)
+	groups, errs := packInstructions(client.payer.PublicKey(), prefix, instructions)
+	if len(groups) < 2 {
+		t.Fatalf("expected 12 instructions to be split over several transactions, got %v", groups)
+	}
+	for k, err := range errs {
+		if (k == 5) != errors.Is(err, ErrInstructionTooLarge) {
+			t.Errorf("instruction %d: expected only the oversized instruction to fail, got %v", k, err)
+		}
+	}
+
+	next := 0
+	for _, indexes := range groups {
+		group := append([]solana.Instruction{}, prefix...)
+		for _, k := range indexes {
+			if next == 5 {
+				next++ // The oversized instruction
+			}
+			if k != next {
+				t.Fatalf("expected instruction %d next in order, got %d", next, k)
+			}
+			group = append(group, instructions[k])
+			next++
+		}
+		size, err := solanaTransactionSize(client.payer.PublicKey(), group)
+		if err != nil {
+			t.Fatalf("failed to size transaction: %v", err)
+		}
+		if size > solanaMaxTransactionSize || len(group) > solanaMaxInstructionsPerTx {
+			t.Errorf("group of %d instructions is %d bytes, over the limits", len(group), size)
+		}
+	}
+	if next != len(instructions) {
+		t.Errorf("expected every other instruction packed, got %d of %d", next-1, len(instructions)-1)
+	}
+	instructions = append(instructions[:5], instructions[6:]...)
+
+	// Each instruction writes the received message account of its own sequence
+	seen := make(map[solana.PublicKey]bool)
+	for i, ix := range instructions {
+		want, _, _ := client.DeriveReceivedMessagePDA(2, uint64(i+1))
+		accounts := ix.Accounts()
+		if got := accounts[6].PublicKey; got != want || seen[got] {
+			t.Errorf("instruction %d: expected received message account %s, got %s", i, want, got)
+		}
+		seen[accounts[6].PublicKey] = true
+	}
+}
+
+// batchRPCServer is a JSON-RPC node holding the given accounts and recording sent transactions
+type batchRPCServer struct {
+	mu       sync.Mutex
+	accounts map[solana.PublicKey]bool
+	sent     []*solana.Transaction
+}
+
+func (s *batchRPCServer) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		var param string
+		if len(req.Params) > 0 {
+			_ = json.Unmarshal(req.Params[0], &param)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var result interface{}
+		rpcContext := map[string]interface{}{"slot": 1}
+		switch req.Method {
+		case "getAccountInfo":
+			var value interface{}
+			if s.accounts[solana.MustPublicKeyFromBase58(param)] {
+				value = map[string]interface{}{"data": []string{"", "base64"}, "executable": false, "lamports": 1, "owner": solana.SystemProgramID.String(), "rentEpoch": 0}
+			}
+			result = map[string]interface{}{"context": rpcContext, "value": value}
+		case "getLatestBlockhash":
+			result = map[string]interface{}{"context": rpcContext, "value": map[string]interface{}{"blockhash": solana.Hash{7}.String(), "lastValidBlockHeight": 100}}
+		case "getBalance":
+			result = map[string]interface{}{"context": rpcContext, "value": LamportsPerSOL}
+		case "sendTransaction":
+			data, _ := base64.StdEncoding.DecodeString(param)
+			tx, err := solana.TransactionFromBytes(data)
+			if err != nil {
+				t.Errorf("failed to decode sent transaction: %v", err)
+				return
+			}
+			s.sent = append(s.sent, tx)
+			result = solana.Signature{byte(len(s.sent))}.String()
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSolanaClientSendReceiveValueBatch(t *testing.T) {
+	node := &batchRPCServer{accounts: make(map[solana.PublicKey]bool)}
+	client := newBatchTestClient(node.start(t).URL)
+
+	var requests []ReceiveValueRequest
+	for seq := uint64(10); seq < 20; seq++ {
+		vaa := batchTestVAA(seq)
+		requests = append(requests, ReceiveValueRequest{VAABytes: vaa, EmitterChain: 2, Sequence: seq})
+		if seq == 15 {
+			continue // Not posted to Wormhole
+		}
+		vaaHash, _ := ComputeVAAHash(vaa)
+		postedVAA, _, _ := client.DerivePostedVAAPDA(vaaHash)
+		node.accounts[postedVAA] = true
+	}
+	received, _, _ := client.DeriveReceivedMessagePDA(2, 12)
+	node.accounts[received] = true
+
+	results, err := client.SendReceiveValueBatch(context.Background(), requests)
+	if err != nil {
+		t.Fatalf("SendReceiveValueBatch failed: %v", err)
+	}
+	if len(results) != len(requests) {
+		t.Fatalf("expected %d results, got %d", len(requests), len(results))
+	}
+
+	if !results[2].AlreadyReceived || results[2].Signature != "" {
+		t.Errorf("expected sequence 12 to be skipped as already received, got %+v", results[2])
+	}
+	if results[5].Err == nil || !strings.Contains(results[5].Err.Error(), "not yet posted") {
+		t.Errorf("expected sequence 15 to fail as not posted, got %+v", results[5])
+	}
+
+	// The other 8 sequences are delivered in fewer transactions than one each
+	sent := 0
+	signatures := make(map[string]bool)
+	for i, result := range results {
+		if i == 2 || i == 5 {
+			continue
+		}
+		if result.Err != nil || result.Signature == "" {
+			t.Errorf("sequence %d: expected a delivery, got %+v", requests[i].Sequence, result)
+		}
+		signatures[result.Signature] = true
+	}
+	for _, tx := range node.sent {
+		sent += len(tx.Message.Instructions)
+		if err := tx.VerifySignatures(); err != nil {
+			t.Errorf("sent transaction is not signed: %v", err)
+		}
+	}
+	if sent != 8 || len(node.sent) != len(signatures) || len(node.sent) >= 8 {
+		t.Errorf("expected 8 instructions over fewer than 8 transactions, got %d over %d", sent, len(node.sent))
+	}
+
+	if _, err := client.SendReceiveValueBatch(context.Background(), append(requests, requests[0])); err == nil {
+		t.Error("expected a batch requesting a sequence twice to be rejected")
+	}
+}
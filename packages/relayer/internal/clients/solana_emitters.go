@@ -0,0 +1,128 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// DiscriminatorRegisterEmitter is the Anchor discriminator of the register_emitter instruction
+var DiscriminatorRegisterEmitter = []byte{217, 153, 40, 34, 190, 121, 144, 105}
+
+// configAccountMinSize covers the MessageBridge Config account up to its owner:
+// 8 (discriminator) + 32 (owner)
+const configAccountMinSize = 8 + 32
+
+// SolanaForeignEmitter is the content of a MessageBridge foreign_emitter account
+type SolanaForeignEmitter struct {
+	ChainID uint16
+	Address [32]byte
+	// Payload layout the program decodes: true = 18-byte default payload, false = 50-byte Aztec payload
+	DefaultPayload bool
+}
+
+// ForeignEmitter reads the foreign_emitter PDA of chainID and returns its content and address.
+// It returns an error wrapping ErrEmitterNotRegistered if the account does not exist.
+func (c *SolanaClient) ForeignEmitter(ctx context.Context, chainID uint16) (SolanaForeignEmitter, solana.PublicKey, error) {
+	foreignEmitter, _, err := c.DeriveForeignEmitterPDA(chainID)
+	if err != nil {
+		return SolanaForeignEmitter{}, solana.PublicKey{}, fmt.Errorf("failed to derive foreign emitter PDA: %v", err)
+	}
+
+	info, err := c.getAccountInfo(ctx, foreignEmitter, nil)
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && (info == nil || info.Value == nil)) {
+		return SolanaForeignEmitter{}, foreignEmitter, fmt.Errorf("%w: program %s has no foreign_emitter account %s for chain %d",
+			ErrEmitterNotRegistered, c.programID, foreignEmitter, chainID)
+	}
+	if err != nil {
+		return SolanaForeignEmitter{}, foreignEmitter, fmt.Errorf("failed to read foreign_emitter account %s: %v", foreignEmitter, err)
+	}
+
+	data := info.Value.Data.GetBinary()
+	if len(data) < foreignEmitterAccountSize {
+		return SolanaForeignEmitter{}, foreignEmitter, fmt.Errorf("foreign_emitter account %s is %d bytes, expected %d",
+			foreignEmitter, len(data), foreignEmitterAccountSize)
+	}
+	registered := SolanaForeignEmitter{
+		ChainID:        binary.LittleEndian.Uint16(data[8:10]),
+		DefaultPayload: data[42] != 0,
+	}
+	copy(registered.Address[:], data[10:42])
+	return registered, foreignEmitter, nil
+}
+
+// ProgramOwner reads the MessageBridge owner, the only key allowed to register emitters, from
+// the program's config account
+func (c *SolanaClient) ProgramOwner(ctx context.Context) (solana.PublicKey, error) {
+	configPDA, _, err := c.DeriveConfigPDA()
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to derive config PDA: %v", err)
+	}
+
+	info, err := c.getAccountInfo(ctx, configPDA, nil)
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && (info == nil || info.Value == nil)) {
+		return solana.PublicKey{}, fmt.Errorf("program %s has no config account %s; is it initialized?", c.programID, configPDA)
+	}
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("failed to read config account %s: %v", configPDA, err)
+	}
+
+	data := info.Value.Data.GetBinary()
+	if len(data) < configAccountMinSize {
+		return solana.PublicKey{}, fmt.Errorf("config account %s is %d bytes, expected at least %d", configPDA, len(data), configAccountMinSize)
+	}
+	return solana.PublicKeyFromBytes(data[8:40]), nil
+}
+
+// BuildRegisterEmitterInstruction builds the register_emitter instruction creating the
+// foreign_emitter PDA of chainID, with the payer signing as the program owner
+func (c *SolanaClient) BuildRegisterEmitterInstruction(registration SolanaForeignEmitter) (*solana.GenericInstruction, error) {
+	configPDA, _, err := c.DeriveConfigPDA()
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive config PDA: %v", err)
+	}
+
+	foreignEmitterPDA, _, err := c.DeriveForeignEmitterPDA(registration.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive foreign emitter PDA: %v", err)
+	}
+
+	// Build instruction data: discriminator + chain_id (u16) + emitter_address (32 bytes) + is_default_payload (bool)
+	data := make([]byte, 8+2+32+1)
+	copy(data[0:8], DiscriminatorRegisterEmitter)
+	binary.LittleEndian.PutUint16(data[8:10], registration.ChainID)
+	copy(data[10:42], registration.Address[:])
+	if registration.DefaultPayload {
+		data[42] = 1
+	}
+
+	accounts := []*solana.AccountMeta{
+		{PublicKey: c.payer.PublicKey(), IsSigner: true, IsWritable: true},      // owner
+		{PublicKey: configPDA, IsSigner: false, IsWritable: false},              // config
+		{PublicKey: foreignEmitterPDA, IsSigner: false, IsWritable: true},       // foreign_emitter
+		{PublicKey: solana.SystemProgramID, IsSigner: false, IsWritable: false}, // system_program
+	}
+
+	return solana.NewInstruction(c.programID, accounts, data), nil
+}
+
+// RegisterEmitter sends a register_emitter transaction and returns its signature. The payer must
+// be the program owner, and chainID must not have an emitter registered yet: the program only
+// creates foreign_emitter accounts, it cannot change one.
+func (c *SolanaClient) RegisterEmitter(ctx context.Context, registration SolanaForeignEmitter) (string, error) {
+	c.logger.Debug("Building register_emitter transaction",
+		zap.Uint16("chainID", registration.ChainID),
+		zap.String("emitter", fmt.Sprintf("%x", registration.Address)),
+		zap.Bool("defaultPayload", registration.DefaultPayload))
+
+	ix, err := c.BuildRegisterEmitterInstruction(registration)
+	if err != nil {
+		return "", fmt.Errorf("failed to build instruction: %v", err)
+	}
+	return c.sendTransaction(ctx, []solana.Instruction{ix})
+}
@@ -0,0 +1,95 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSolanaClientForeignEmitter(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xaa
+	data := make([]byte, foreignEmitterAccountSize)
+	binary.LittleEndian.PutUint16(data[8:10], 56)
+	copy(data[10:42], emitter[:])
+
+	for _, defaultPayload := range []bool{false, true} {
+		if defaultPayload {
+			data[42] = 1
+		}
+		client := &SolanaClient{client: rpc.New(newSolanaAccountServer(t, data).URL), programID: solana.SystemProgramID}
+		got, pda, err := client.ForeignEmitter(context.Background(), 56)
+		if err != nil {
+			t.Fatalf("ForeignEmitter failed: %v", err)
+		}
+		want := SolanaForeignEmitter{ChainID: 56, Address: emitter, DefaultPayload: defaultPayload}
+		if got != want {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+		if expected, _, _ := client.DeriveForeignEmitterPDA(56); !pda.Equals(expected) {
+			t.Errorf("expected PDA %s, got %s", expected, pda)
+		}
+	}
+
+	client := &SolanaClient{client: rpc.New(newSolanaAccountServer(t, nil).URL), programID: solana.SystemProgramID}
+	if _, _, err := client.ForeignEmitter(context.Background(), 56); !errors.Is(err, ErrEmitterNotRegistered) {
+		t.Errorf("expected a missing account to be ErrEmitterNotRegistered, got %v", err)
+	}
+}
+
+func TestSolanaClientProgramOwner(t *testing.T) {
+	owner := solana.NewWallet().PublicKey()
+	data := make([]byte, 8+32*6+2+4)
+	copy(data[8:40], owner[:])
+
+	client := &SolanaClient{client: rpc.New(newSolanaAccountServer(t, data).URL), programID: solana.SystemProgramID}
+	got, err := client.ProgramOwner(context.Background())
+	if err != nil || !got.Equals(owner) {
+		t.Errorf("expected owner %s, got %s (%v)", owner, got, err)
+	}
+
+	client = &SolanaClient{client: rpc.New(newSolanaAccountServer(t, nil).URL), programID: solana.SystemProgramID}
+	if _, err := client.ProgramOwner(context.Background()); err == nil {
+		t.Error("expected an uninitialized program to be reported")
+	}
+}
+
+func TestBuildRegisterEmitterInstruction(t *testing.T) {
+	client := newBatchTestClient("http://localhost")
+	var emitter [32]byte
+	emitter[0], emitter[31] = 0x01, 0xff
+
+	ix, err := client.BuildRegisterEmitterInstruction(SolanaForeignEmitter{ChainID: 10003, Address: emitter, DefaultPayload: true})
+	if err != nil {
+		t.Fatalf("BuildRegisterEmitterInstruction failed: %v", err)
+	}
+
+	data, _ := ix.Data()
+	want := append(append(append([]byte{}, DiscriminatorRegisterEmitter...), 0x13, 0x27), emitter[:]...)
+	want = append(want, 1)
+	if !bytes.Equal(data, want) {
+		t.Errorf("expected data %x, got %x", want, data)
+	}
+
+	configPDA, _, _ := client.DeriveConfigPDA()
+	foreignEmitterPDA, _, _ := client.DeriveForeignEmitterPDA(10003)
+	accounts := ix.Accounts()
+	if len(accounts) != 4 || !ix.ProgramID().Equals(client.programID) {
+		t.Fatalf("expected 4 accounts of program %s, got %d of %s", client.programID, len(accounts), ix.ProgramID())
+	}
+	for i, want := range []solana.AccountMeta{
+		{PublicKey: client.payer.PublicKey(), IsSigner: true, IsWritable: true},
+		{PublicKey: configPDA},
+		{PublicKey: foreignEmitterPDA, IsWritable: true},
+		{PublicKey: solana.SystemProgramID},
+	} {
+		if *accounts[i] != want {
+			t.Errorf("account %d: expected %+v, got %+v", i, want, *accounts[i])
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package clients
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// accountInUsePattern matches the system program refusing to create an account that exists,
+// e.g. "Allocate: account Address { address: <key>, base: None } already in use"
+var accountInUsePattern = regexp.MustCompile(`account Address \{[^}]*\} already in use`)
+
+// IsAccountInUse reports whether msg carries the system program's refusal to create an existing
+// account, as when receive_value re-creates the received-message PDA of a delivered VAA
+func IsAccountInUse(msg string) bool {
+	return accountInUsePattern.MatchString(msg)
+}
+
+// DefaultProgramErrors names the custom error codes a receive_value transaction can fail with:
+// the MessageBridge program's MessageBridgeError variants, numbered by Anchor from 6000 in
+// declaration order, and the Anchor account and constraint errors its instructions can raise
+var DefaultProgramErrors = map[uint32]string{
+	// MessageBridgeError (programs/message_bridge/src/error.rs)
+	6000: "OwnerOnly: Only the owner can perform this action",
+	6001: "InvalidWormholeConfig: Invalid Wormhole configuration",
+	6002: "InvalidForeignEmitter: Invalid foreign emitter",
+	6003: "InvalidDestinationChainId: Invalid destination chain ID",
+	6004: "CannotRegisterSolanaEmitter: Cannot register emitter for Solana chain",
+	6005: "ZeroEmitterAddress: Emitter address cannot be zero",
+	6006: "InvalidPayload: Invalid message payload",
+	6007: "AlreadyProcessed: Message already processed",
+	6008: "InsufficientFee: Insufficient fee for Wormhole message",
+
+	// Anchor framework errors
+	2000: "ConstraintMut: A mut constraint was violated",
+	2001: "ConstraintHasOne: A has one constraint was violated",
+	2002: "ConstraintSigner: A signer constraint was violated",
+	2003: "ConstraintRaw: A raw constraint was violated",
+	2004: "ConstraintOwner: An owner constraint was violated",
+	2006: "ConstraintSeeds: A seeds constraint was violated",
+	3001: "AccountDiscriminatorNotFound: No discriminator was found on the account",
+	3002: "AccountDiscriminatorMismatch: Account discriminator did not match what was expected",
+	3003: "AccountDidNotDeserialize: Failed to deserialize the account",
+	3007: "AccountOwnedByWrongProgram: The given account is owned by a different program than expected",
+	3012: "AccountNotInitialized: The program expected this account to be already initialized",
+}
+
+// ParseProgramErrors parses extra program error names keyed by error code, in decimal or 0x hex,
+// e.g. {"6009": "NewError", "0x177a": "OtherError"}
+func ParseProgramErrors(entries map[string]string) (map[uint32]string, error) {
+	names := make(map[uint32]string, len(entries))
+	for key, name := range entries {
+		code, err := strconv.ParseUint(strings.TrimSpace(key), 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid program error code %q (expected decimal or 0x hex): %v", key, err)
+		}
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("program error code %s has no name", key)
+		}
+		names[uint32(code)] = strings.TrimSpace(name)
+	}
+	return names, nil
+}
+
+// programErrorNames merges extra over DefaultProgramErrors
+func programErrorNames(extra map[uint32]string) map[uint32]string {
+	names := make(map[uint32]string, len(DefaultProgramErrors)+len(extra))
+	for code, name := range DefaultProgramErrors {
+		names[code] = name
+	}
+	for code, name := range extra {
+		names[code] = name
+	}
+	return names
+}
+
+// describeProgramError names a custom program error code, keeping the code for reference
+func describeProgramError(code uint32, names map[uint32]string) string {
+	if name, ok := names[code]; ok {
+		return fmt.Sprintf("%s (custom program error %d, 0x%x)", name, code, code)
+	}
+	return fmt.Sprintf("custom program error %d (0x%x)", code, code)
+}
+
+// describeSendError turns a sendTransaction error into a readable reason. When the node's own
+// preflight simulation rejected the transaction, the error carries the simulation result, which
+// is decoded like a simulateTransaction failure instead of being dumped as a raw RPC error.
+func describeSendError(err error, names map[uint32]string) string {
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) {
+		return err.Error()
+	}
+	data, ok := rpcErr.Data.(map[string]interface{})
+	if !ok || data["err"] == nil {
+		return rpcErr.Message
+	}
+
+	var logs []string
+	if rawLogs, ok := data["logs"].([]interface{}); ok {
+		for _, line := range rawLogs {
+			if s, ok := line.(string); ok {
+				logs = append(logs, s)
+			}
+		}
+	}
+	return fmt.Sprintf("%s: %s", rpcErr.Message, describeSimulationError(data["err"], logs, names))
+}
@@ -0,0 +1,59 @@
+package clients
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func TestParseProgramErrors(t *testing.T) {
+	names, err := ParseProgramErrors(map[string]string{"6009": " NewError ", "0x177a": "OtherError"})
+	if err != nil {
+		t.Fatalf("ParseProgramErrors failed: %v", err)
+	}
+	if names[6009] != "NewError" || names[6010] != "OtherError" {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	for _, entries := range []map[string]string{{"abc": "Name"}, {"0x1ffffffff": "Name"}, {"6009": " "}} {
+		if _, err := ParseProgramErrors(entries); err == nil {
+			t.Errorf("expected %v to be rejected", entries)
+		}
+	}
+
+	merged := programErrorNames(map[uint32]string{6002: "Renamed", 6100: "Extra"})
+	if merged[6002] != "Renamed" || merged[6100] != "Extra" || merged[6007] != DefaultProgramErrors[6007] {
+		t.Errorf("expected extra names over the defaults, got %v", merged)
+	}
+	if DefaultProgramErrors[6002] == "Renamed" {
+		t.Error("expected the defaults to be left unchanged")
+	}
+}
+
+func TestDescribeSendError(t *testing.T) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(`{"err":{"InstructionError":[0,{"Custom":6002}]},"logs":["Program log: Instruction: ReceiveValue"]}`), &data); err != nil {
+		t.Fatalf("failed to decode data: %v", err)
+	}
+	preflightErr := fmt.Errorf("rpc call sendTransaction(): %w", &jsonrpc.RPCError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: Error processing Instruction 0: custom program error: 0x1772",
+		Data:    data,
+	})
+
+	want := "Transaction simulation failed: Error processing Instruction 0: custom program error: 0x1772: " +
+		"InvalidForeignEmitter: Invalid foreign emitter (custom program error 6002, 0x1772)"
+	if got := describeSendError(preflightErr, DefaultProgramErrors); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := describeSendError(&jsonrpc.RPCError{Code: -32005, Message: "Node is behind"}, DefaultProgramErrors); got != "Node is behind" {
+		t.Errorf("expected the RPC message, got %q", got)
+	}
+	if got := describeSendError(errors.New("connection refused"), DefaultProgramErrors); got != "connection refused" {
+		t.Errorf("expected the plain error, got %q", got)
+	}
+}
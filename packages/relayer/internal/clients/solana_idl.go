@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReceiveValueLayout is how the receive_value instruction data is encoded: its discriminator,
+// then its arguments in order, Borsh-encoded. The relayer supplies the arguments by name, so a
+// layout can reorder them but not add ones the relayer has no value for.
+type ReceiveValueLayout struct {
+	Discriminator []byte
+	Args          []ReceiveValueArg
+	Address       string // Program address the IDL was generated for (empty = unknown)
+}
+
+// ReceiveValueArg is one argument of receive_value
+type ReceiveValueArg struct {
+	Name string // vaa_hash, emitter_chain or sequence
+	Type string // The IDL type: [u8;32], u16 or u64
+}
+
+// receiveValueArgTypes are the arguments the relayer can supply, with the type each must have
+var receiveValueArgTypes = map[string]string{
+	"vaa_hash":      "[u8;32]",
+	"emitter_chain": "u16",
+	"sequence":      "u64",
+}
+
+// receiveValueAccounts are the accounts BuildReceiveValueInstruction passes, in order
+var receiveValueAccounts = []string{
+	"payer", "config", "current_value", "wormhole_program", "posted_vaa", "foreign_emitter", "received_message", "system_program",
+}
+
+// DefaultReceiveValueLayout is the receive_value layout of the MessageBridge program built with
+// this relayer
+var DefaultReceiveValueLayout = ReceiveValueLayout{
+	Discriminator: DiscriminatorReceiveValue,
+	Args: []ReceiveValueArg{
+		{Name: "vaa_hash", Type: "[u8;32]"},
+		{Name: "emitter_chain", Type: "u16"},
+		{Name: "sequence", Type: "u64"},
+	},
+}
+
+// anchorIDL is the part of an Anchor IDL describing instructions. Anchor 0.30+ IDLs carry each
+// discriminator; older ones leave it to be derived from the instruction name.
+type anchorIDL struct {
+	Address      string `json:"address"`
+	Instructions []struct {
+		Name          string          `json:"name"`
+		Discriminator []int           `json:"discriminator"`
+		Args          []anchorIDLArg  `json:"args"`
+		Accounts      []anchorIDLName `json:"accounts"`
+	} `json:"instructions"`
+}
+
+type anchorIDLArg struct {
+	Name string          `json:"name"`
+	Type json.RawMessage `json:"type"`
+}
+
+type anchorIDLName struct {
+	Name string `json:"name"`
+}
+
+// LoadReceiveValueLayout reads the receive_value layout from the Anchor IDL JSON file at path.
+// It fails unless the instruction takes exactly the arguments and accounts the relayer supplies.
+func LoadReceiveValueLayout(path string) (ReceiveValueLayout, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ReceiveValueLayout{}, fmt.Errorf("failed to read IDL: %v", err)
+	}
+	layout, err := parseReceiveValueLayout(raw)
+	if err != nil {
+		return ReceiveValueLayout{}, fmt.Errorf("IDL %s: %v", path, err)
+	}
+	return layout, nil
+}
+
+// parseReceiveValueLayout extracts the receive_value layout from an Anchor IDL
+func parseReceiveValueLayout(raw []byte) (ReceiveValueLayout, error) {
+	var idl anchorIDL
+	if err := json.Unmarshal(raw, &idl); err != nil {
+		return ReceiveValueLayout{}, fmt.Errorf("invalid IDL JSON: %v", err)
+	}
+
+	for _, ix := range idl.Instructions {
+		if ix.Name != "receive_value" && ix.Name != "receiveValue" {
+			continue
+		}
+		layout := ReceiveValueLayout{Address: idl.Address}
+
+		// Without one in the IDL, the discriminator is Anchor's: sha256("global:<name>")[:8]
+		if ix.Discriminator == nil {
+			sum := sha256.Sum256([]byte("global:receive_value"))
+			layout.Discriminator = sum[:8]
+		} else {
+			if len(ix.Discriminator) != 8 {
+				return ReceiveValueLayout{}, fmt.Errorf("receive_value discriminator is %d bytes, want 8", len(ix.Discriminator))
+			}
+			for _, b := range ix.Discriminator {
+				if b < 0 || b > 255 {
+					return ReceiveValueLayout{}, fmt.Errorf("receive_value discriminator byte %d is out of range", b)
+				}
+				layout.Discriminator = append(layout.Discriminator, byte(b))
+			}
+		}
+
+		seen := make(map[string]bool)
+		for _, arg := range ix.Args {
+			name := anchorSnakeCase(strings.TrimPrefix(arg.Name, "_"))
+			want, ok := receiveValueArgTypes[name]
+			if !ok {
+				return ReceiveValueLayout{}, fmt.Errorf("receive_value argument %q is not one the relayer can supply (vaa_hash, emitter_chain, sequence)", arg.Name)
+			}
+			if got := idlTypeName(arg.Type); got != want {
+				return ReceiveValueLayout{}, fmt.Errorf("receive_value argument %q has type %s, want %s", arg.Name, got, want)
+			}
+			if seen[name] {
+				return ReceiveValueLayout{}, fmt.Errorf("receive_value argument %q appears twice", arg.Name)
+			}
+			seen[name] = true
+			layout.Args = append(layout.Args, ReceiveValueArg{Name: name, Type: want})
+		}
+		if len(layout.Args) != len(receiveValueArgTypes) {
+			return ReceiveValueLayout{}, fmt.Errorf("receive_value takes %d of the %d arguments the relayer supplies", len(layout.Args), len(receiveValueArgTypes))
+		}
+
+		var accounts []string
+		for _, account := range ix.Accounts {
+			accounts = append(accounts, anchorSnakeCase(account.Name))
+		}
+		if strings.Join(accounts, ",") != strings.Join(receiveValueAccounts, ",") {
+			return ReceiveValueLayout{}, fmt.Errorf("receive_value accounts are %v, want %v", accounts, receiveValueAccounts)
+		}
+		return layout, nil
+	}
+	return ReceiveValueLayout{}, fmt.Errorf("no receive_value instruction")
+}
+
+// idlTypeName renders an IDL type as u16, u64 or [u8;32], the forms receiveValueArgTypes uses.
+// Anchor writes fixed arrays as {"array": ["u8", 32]}.
+func idlTypeName(raw json.RawMessage) string {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		return name
+	}
+	var array struct {
+		Array [2]json.RawMessage `json:"array"`
+	}
+	if json.Unmarshal(raw, &array) == nil && array.Array[0] != nil {
+		return fmt.Sprintf("[%s;%s]", idlTypeName(array.Array[0]), array.Array[1])
+	}
+	return string(raw)
+}
+
+// anchorSnakeCase converts the camelCase names of Anchor IDLs before 0.30 to snake_case
+func anchorSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// encode builds receive_value instruction data for the given arguments
+func (l ReceiveValueLayout) encode(vaaHash [32]byte, emitterChain uint16, sequence uint64) []byte {
+	data := append([]byte{}, l.Discriminator...)
+	for _, arg := range l.Args {
+		switch arg.Name {
+		case "vaa_hash":
+			data = append(data, vaaHash[:]...)
+		case "emitter_chain":
+			data = binary.LittleEndian.AppendUint16(data, emitterChain)
+		case "sequence":
+			data = binary.LittleEndian.AppendUint64(data, sequence)
+		}
+	}
+	return data
+}
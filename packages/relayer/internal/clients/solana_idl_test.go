@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// receiveValueIDL returns an Anchor 0.30 IDL whose receive_value has discriminator, args and accounts
+func receiveValueIDL(discriminator, args, accounts string) string {
+	return `{"address": "6eLf6j8YhfhB95kYPRpeUDdF1JWTdECNnsKmUpxwdAjp", "instructions": [
+		{"name": "initialize", "discriminator": [175, 175, 109, 31, 13, 152, 155, 237], "args": [], "accounts": []},
+		{"name": "receive_value", "discriminator": ` + discriminator + `, "args": ` + args + `, "accounts": ` + accounts + `}
+	]}`
+}
+
+const (
+	idlDiscriminator = `[131, 101, 246, 45, 2, 139, 81, 21]`
+	idlArgs          = `[{"name": "_vaa_hash", "type": {"array": ["u8", 32]}}, {"name": "emitter_chain", "type": "u16"}, {"name": "sequence", "type": "u64"}]`
+	idlAccounts      = `[{"name": "payer"}, {"name": "config"}, {"name": "current_value"}, {"name": "wormhole_program"},
+		{"name": "posted_vaa"}, {"name": "foreign_emitter"}, {"name": "received_message"}, {"name": "system_program"}]`
+)
+
+func TestParseReceiveValueLayout(t *testing.T) {
+	var vaaHash [32]byte
+	vaaHash[0], vaaHash[31] = 0xaa, 0xbb
+	builtIn := DefaultReceiveValueLayout.encode(vaaHash, 2, 7)
+
+	tests := []struct {
+		name    string
+		idl     string
+		want    []byte // Encoded instruction data
+		wantErr string
+	}{
+		{name: "current IDL", idl: receiveValueIDL(idlDiscriminator, idlArgs, idlAccounts), want: builtIn},
+		{
+			// IDLs before Anchor 0.30 are camelCase and leave the discriminator to the name
+			name: "legacy IDL",
+			idl: `{"instructions": [{"name": "receiveValue",
+				"args": [{"name": "vaaHash", "type": {"array": ["u8", 32]}}, {"name": "emitterChain", "type": "u16"}, {"name": "sequence", "type": "u64"}],
+				"accounts": [{"name": "payer"}, {"name": "config"}, {"name": "currentValue"}, {"name": "wormholeProgram"},
+					{"name": "postedVaa"}, {"name": "foreignEmitter"}, {"name": "receivedMessage"}, {"name": "systemProgram"}]}]}`,
+			want: builtIn,
+		},
+		{
+			name: "redeployed with another discriminator and order",
+			idl: receiveValueIDL(`[1, 2, 3, 4, 5, 6, 7, 8]`,
+				`[{"name": "sequence", "type": "u64"}, {"name": "emitter_chain", "type": "u16"}, {"name": "vaa_hash", "type": {"array": ["u8", 32]}}]`, idlAccounts),
+			want: append(append([]byte{1, 2, 3, 4, 5, 6, 7, 8}, 7, 0, 0, 0, 0, 0, 0, 0, 2, 0), vaaHash[:]...),
+		},
+		{name: "short discriminator", idl: receiveValueIDL(`[1, 2, 3, 4, 5, 6, 7]`, idlArgs, idlAccounts), wantErr: "7 bytes, want 8"},
+		{name: "discriminator byte out of range", idl: receiveValueIDL(`[1, 2, 3, 4, 5, 6, 7, 256]`, idlArgs, idlAccounts), wantErr: "out of range"},
+		{
+			name:    "additional argument",
+			idl:     receiveValueIDL(idlDiscriminator, strings.TrimSuffix(idlArgs, "]")+`, {"name": "fee", "type": "u64"}]`, idlAccounts),
+			wantErr: `argument "fee" is not one the relayer can supply`,
+		},
+		{
+			name:    "changed argument type",
+			idl:     receiveValueIDL(idlDiscriminator, strings.Replace(idlArgs, `"sequence", "type": "u64"`, `"sequence", "type": "u32"`, 1), idlAccounts),
+			wantErr: `"sequence" has type u32, want u64`,
+		},
+		{
+			name:    "missing argument",
+			idl:     receiveValueIDL(idlDiscriminator, `[{"name": "emitter_chain", "type": "u16"}, {"name": "sequence", "type": "u64"}]`, idlAccounts),
+			wantErr: "takes 2 of the 3 arguments",
+		},
+		{
+			name:    "changed accounts",
+			idl:     receiveValueIDL(idlDiscriminator, idlArgs, `[{"name": "payer"}, {"name": "config"}]`),
+			wantErr: "accounts are [payer config]",
+		},
+		{name: "no receive_value", idl: `{"instructions": [{"name": "initialize", "args": [], "accounts": []}]}`, wantErr: "no receive_value instruction"},
+		{name: "not JSON", idl: `{`, wantErr: "invalid IDL JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := parseReceiveValueLayout([]byte(tt.idl))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReceiveValueLayout failed: %v", err)
+			}
+			if got := layout.encode(vaaHash, 2, 7); !bytes.Equal(got, tt.want) {
+				t.Errorf("expected instruction data %x, got %x", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReceiveValueLayoutFromIDLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "message_bridge.json")
+	if err := os.WriteFile(path, []byte(receiveValueIDL(`[1, 2, 3, 4, 5, 6, 7, 8]`, idlArgs, idlAccounts)), 0o600); err != nil {
+		t.Fatalf("write IDL: %v", err)
+	}
+	layout, err := LoadReceiveValueLayout(path)
+	if err != nil {
+		t.Fatalf("LoadReceiveValueLayout failed: %v", err)
+	}
+	if layout.Address != "6eLf6j8YhfhB95kYPRpeUDdF1JWTdECNnsKmUpxwdAjp" {
+		t.Errorf("expected the IDL's program address, got %q", layout.Address)
+	}
+	if _, err := LoadReceiveValueLayout(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected a missing IDL file to fail")
+	}
+
+	// The client builds receive_value with the loaded layout, and the built-in one without
+	client := newBatchTestClient("http://127.0.0.1:1")
+	var vaaHash [32]byte
+	ix, err := client.BuildReceiveValueInstruction(vaaHash, 2, 7, client.programID)
+	if err != nil {
+		t.Fatalf("failed to build instruction: %v", err)
+	}
+	data, _ := ix.Data()
+	if !bytes.Equal(data[:8], DiscriminatorReceiveValue) || len(data) != 50 {
+		t.Errorf("expected the built-in layout, got %x", data)
+	}
+
+	client.receiveValue = &layout
+	ix, err = client.BuildReceiveValueInstruction(vaaHash, 2, 7, client.programID)
+	if err != nil {
+		t.Fatalf("failed to build instruction: %v", err)
+	}
+	data, _ = ix.Data()
+	if !bytes.Equal(data[:8], []byte{1, 2, 3, 4, 5, 6, 7, 8}) || len(data) != 50 {
+		t.Errorf("expected the IDL's discriminator, got %x", data)
+	}
+}
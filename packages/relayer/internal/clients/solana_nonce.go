@@ -0,0 +1,133 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// Defaults for waiting until a durable nonce advances after a transaction used it
+const (
+	DefaultNonceAdvanceTimeout = 30 * time.Second
+	nonceAdvancePollInterval   = time.Second
+)
+
+// System program nonce account layout: version (u32), state (u32), authority (32), nonce (32),
+// lamports per signature (u64)
+const (
+	nonceAccountSize      = 4 + 4 + 32 + 32 + 8
+	nonceStateInitialized = 1
+	nonceAuthorityOffset  = 8
+	nonceValueOffset      = nonceAuthorityOffset + 32
+)
+
+// ErrInvalidNonceAccount is returned when the configured durable nonce account cannot be used:
+// it does not exist, is not initialized, or is controlled by another authority
+var ErrInvalidNonceAccount = errors.New("invalid durable nonce account")
+
+// durableNonce is a nonce account whose stored nonce replaces the recent blockhash of each
+// transaction, so a transaction stays valid however long it takes to land. The runtime advances
+// the nonce when a transaction using it lands, so transactions sharing the account are sent one
+// at a time, each waiting for the previous one's nonce to advance.
+type durableNonce struct {
+	account        solana.PublicKey
+	authority      SolanaSigner
+	advanceTimeout time.Duration // How long to wait for the last used nonce to advance before reusing it
+
+	mu       sync.Mutex  // Held from reading the nonce until the transaction using it is sent
+	lastUsed solana.Hash // Nonce of the last transaction sent
+	lastSent time.Time
+}
+
+// nonceAccountState is the part of a nonce account a transaction needs
+type nonceAccountState struct {
+	authority solana.PublicKey
+	nonce     solana.Hash
+}
+
+// parseNonceAccount decodes an initialized system program nonce account
+func parseNonceAccount(data []byte) (nonceAccountState, error) {
+	if len(data) < nonceAccountSize {
+		return nonceAccountState{}, fmt.Errorf("account is %d bytes, expected a %d-byte nonce account", len(data), nonceAccountSize)
+	}
+	if state := binary.LittleEndian.Uint32(data[4:8]); state != nonceStateInitialized {
+		return nonceAccountState{}, fmt.Errorf("nonce account is not initialized (state %d)", state)
+	}
+	return nonceAccountState{
+		authority: solana.PublicKeyFromBytes(data[nonceAuthorityOffset:nonceValueOffset]),
+		nonce:     solana.HashFromBytes(data[nonceValueOffset : nonceValueOffset+32]),
+	}, nil
+}
+
+// readNonceAccount fetches and decodes the nonce account at the client's commitment, returning
+// an error wrapping ErrInvalidNonceAccount if it cannot be used with the configured authority
+func (c *SolanaClient) readNonceAccount(ctx context.Context) (nonceAccountState, error) {
+	info, err := c.getAccountInfo(ctx, c.nonce.account, &rpc.GetAccountInfoOpts{Commitment: c.commitment})
+	if errors.Is(err, rpc.ErrNotFound) || (err == nil && (info == nil || info.Value == nil)) {
+		return nonceAccountState{}, fmt.Errorf("%w: account %s does not exist", ErrInvalidNonceAccount, c.nonce.account)
+	}
+	if err != nil {
+		return nonceAccountState{}, fmt.Errorf("failed to read nonce account %s: %v", c.nonce.account, err)
+	}
+	if !info.Value.Owner.Equals(solana.SystemProgramID) {
+		return nonceAccountState{}, fmt.Errorf("%w: account %s is owned by %s, not the system program",
+			ErrInvalidNonceAccount, c.nonce.account, info.Value.Owner)
+	}
+
+	state, err := parseNonceAccount(info.Value.Data.GetBinary())
+	if err != nil {
+		return nonceAccountState{}, fmt.Errorf("%w: %s: %v", ErrInvalidNonceAccount, c.nonce.account, err)
+	}
+	if !state.authority.Equals(c.nonce.authority.PublicKey()) {
+		return nonceAccountState{}, fmt.Errorf("%w: account %s has authority %s, not %s",
+			ErrInvalidNonceAccount, c.nonce.account, state.authority, c.nonce.authority.PublicKey())
+	}
+	return state, nil
+}
+
+// nextNonce returns the nonce for the next transaction. If it is still the one the previous
+// transaction used, that transaction has not landed yet, so the nonce is polled until it advances.
+// After advanceTimeout the previous transaction is assumed dropped and its nonce is reused; should
+// it land after all, the new transaction fails with an invalid nonce and is retried.
+// The caller holds c.nonce.mu.
+func (c *SolanaClient) nextNonce(ctx context.Context) (solana.Hash, error) {
+	for {
+		state, err := c.readNonceAccount(ctx)
+		if err != nil {
+			return solana.Hash{}, err
+		}
+		if !state.nonce.Equals(c.nonce.lastUsed) {
+			return state.nonce, nil
+		}
+		if time.Since(c.nonce.lastSent) >= c.nonce.advanceTimeout {
+			c.logger.Warn("Durable nonce did not advance; the previous transaction may have been dropped, reusing it",
+				zap.String("nonceAccount", c.nonce.account.String()),
+				zap.Duration("waited", time.Since(c.nonce.lastSent)))
+			return state.nonce, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return solana.Hash{}, fmt.Errorf("gave up waiting for durable nonce %s to advance: %w", c.nonce.account, ctx.Err())
+		case <-time.After(nonceAdvancePollInterval):
+		}
+	}
+}
+
+// advanceNonceInstruction builds the AdvanceNonceAccount instruction that must come first in a
+// transaction using the durable nonce
+func (c *SolanaClient) advanceNonceInstruction() solana.Instruction {
+	return system.NewAdvanceNonceAccountInstruction(
+		c.nonce.account,
+		solana.SysVarRecentBlockHashesPubkey,
+		c.nonce.authority.PublicKey(),
+	).Build()
+}
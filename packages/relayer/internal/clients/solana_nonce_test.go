@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// nonceAccountData encodes an initialized nonce account
+func nonceAccountData(authority solana.PublicKey, nonce solana.Hash) []byte {
+	data := make([]byte, nonceAccountSize)
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	binary.LittleEndian.PutUint32(data[4:8], nonceStateInitialized)
+	copy(data[nonceAuthorityOffset:], authority[:])
+	copy(data[nonceValueOffset:], nonce[:])
+	return data
+}
+
+func TestParseNonceAccount(t *testing.T) {
+	authority := solana.NewWallet().PublicKey()
+	nonce := solana.Hash{1, 2, 3}
+
+	state, err := parseNonceAccount(nonceAccountData(authority, nonce))
+	if err != nil || !state.authority.Equals(authority) || !state.nonce.Equals(nonce) {
+		t.Fatalf("expected authority %s and nonce %s, got %+v (err %v)", authority, nonce, state, err)
+	}
+
+	uninitialized := nonceAccountData(authority, nonce)
+	binary.LittleEndian.PutUint32(uninitialized[4:8], 0)
+	if _, err := parseNonceAccount(uninitialized); err == nil {
+		t.Error("expected an uninitialized nonce account to be rejected")
+	}
+	if _, err := parseNonceAccount(make([]byte, 40)); err == nil {
+		t.Error("expected a truncated nonce account to be rejected")
+	}
+}
+
+func TestSolanaClientNextNonce(t *testing.T) {
+	authority := solana.NewWallet().PrivateKey
+	nonce := solana.Hash{7}
+	newClient := func(data []byte) *SolanaClient {
+		return &SolanaClient{
+			client: rpc.New(newSolanaAccountServer(t, data).URL),
+			logger: zap.NewNop(),
+			nonce:  &durableNonce{account: solana.NewWallet().PublicKey(), authority: NewLocalSolanaSigner(authority), advanceTimeout: time.Second},
+		}
+	}
+
+	client := newClient(nonceAccountData(authority.PublicKey(), nonce))
+	if got, err := client.nextNonce(context.Background()); err != nil || !got.Equals(nonce) {
+		t.Fatalf("expected nonce %s, got %s (err %v)", nonce, got, err)
+	}
+
+	// The nonce of a transaction just sent has not advanced yet: wait for it until cancelled
+	client.nonce.lastUsed, client.nonce.lastSent = nonce, time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := client.nextNonce(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected to wait for the nonce to advance, got %v", err)
+	}
+
+	// Once advanceTimeout has passed, the previous transaction is assumed dropped
+	client.nonce.lastSent = time.Now().Add(-time.Minute)
+	if got, err := client.nextNonce(context.Background()); err != nil || !got.Equals(nonce) {
+		t.Errorf("expected the unadvanced nonce to be reused, got %s (err %v)", got, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"missing account": nil,
+		"other authority": nonceAccountData(solana.NewWallet().PublicKey(), nonce),
+	} {
+		if _, err := newClient(data).nextNonce(context.Background()); !errors.Is(err, ErrInvalidNonceAccount) {
+			t.Errorf("%s: expected ErrInvalidNonceAccount, got %v", name, err)
+		}
+	}
+}
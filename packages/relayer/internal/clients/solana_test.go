@@ -0,0 +1,447 @@
+package clients
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+	"go.uber.org/zap"
+)
+
+// newSolanaRPCServer starts a JSON-RPC server answering getHealth with the given result
+func newSolanaRPCServer(t *testing.T, health string) *httptest.Server {
+	t.Helper()
+	return newJSONRPCServer(t, func(method string) interface{} {
+		if method != "getHealth" {
+			t.Errorf("unexpected method %s", method)
+		}
+		if health != "ok" {
+			return &jsonRPCError{Code: -32005, Message: "Node is " + health}
+		}
+		return "ok"
+	})
+}
+
+func TestNewSolanaClient(t *testing.T) {
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	programID := solana.SystemProgramID.String()
+	healthy := newSolanaRPCServer(t, "ok")
+
+	client, err := NewSolanaClient(zap.NewNop(), SolanaClientConfig{RPCURL: healthy.URL, PrivateKey: payer.String(), ProgramID: programID})
+	if err != nil {
+		t.Fatalf("NewSolanaClient failed: %v", err)
+	}
+	if client.GetPayerAddress() != payer.PublicKey() {
+		t.Errorf("expected payer %s, got %s", payer.PublicKey(), client.GetPayerAddress())
+	}
+	if client.wormholeProgramID != DefaultWormholeProgramID {
+		t.Errorf("expected default wormhole program ID, got %s", client.wormholeProgramID)
+	}
+
+	tests := []struct {
+		name              string
+		rpcURL            string
+		privateKey        string
+		programID         string
+		wormholeProgramID string
+		wantErr           string
+	}{
+		{name: "empty private key", rpcURL: healthy.URL, privateKey: "", programID: programID, wantErr: "invalid private key"},
+		{name: "malformed private key", rpcURL: healthy.URL, privateKey: "not-base58!", programID: programID, wantErr: "invalid private key"},
+		{name: "short private key", rpcURL: healthy.URL, privateKey: programID, programID: programID, wantErr: "invalid private key"},
+		{name: "malformed program ID", rpcURL: healthy.URL, privateKey: payer.String(), programID: "bad", wantErr: "invalid program ID"},
+		{name: "malformed wormhole program ID", rpcURL: healthy.URL, privateKey: payer.String(), programID: programID, wormholeProgramID: "bad", wantErr: "invalid wormhole program ID"},
+		{name: "unreachable RPC", rpcURL: "http://127.0.0.1:1", privateKey: payer.String(), programID: programID, wantErr: "not usable"},
+		{name: "unhealthy RPC", rpcURL: newSolanaRPCServer(t, "behind").URL, privateKey: payer.String(), programID: programID, wantErr: "not usable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSolanaClient(zap.NewNop(), SolanaClientConfig{
+				RPCURL:            tt.rpcURL,
+				PrivateKey:        tt.privateKey,
+				ProgramID:         tt.programID,
+				WormholeProgramID: tt.wormholeProgramID,
+			})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if tt.privateKey != "" && strings.Contains(err.Error(), payer.String()) {
+				t.Fatal("error must not leak the private key")
+			}
+		})
+	}
+}
+
+func TestDescribeSimulationError(t *testing.T) {
+	var customErr interface{}
+	if err := json.Unmarshal([]byte(`{"InstructionError":[0,{"Custom":6001}]}`), &customErr); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+
+	anchorLog := "Program log: AnchorError caused by account: foreign_emitter. Error Code: AccountNotInitialized. Error Number: 3012. Error Message: The program expected this account to be already initialized."
+	logs := []string{"Program 11111111111111111111111111111111 invoke [1]", anchorLog}
+
+	if got := describeSimulationError(customErr, logs, DefaultProgramErrors); got != strings.TrimPrefix(anchorLog, "Program log: ") {
+		t.Errorf("expected the Anchor log line, got %q", got)
+	}
+	inUseLog := "Allocate: account Address { address: 9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin, base: None } already in use"
+	if got := describeSimulationError(customErr, []string{"Program 11111111111111111111111111111111 invoke [2]", inUseLog}, DefaultProgramErrors); got != inUseLog {
+		t.Errorf("expected the already-in-use log line, got %q", got)
+	}
+	if got := describeSimulationError(customErr, nil, DefaultProgramErrors); got != "InvalidWormholeConfig: Invalid Wormhole configuration (custom program error 6001, 0x1771)" {
+		t.Errorf("expected the named custom error, got %q", got)
+	}
+	if got := describeSimulationError(customErr, nil, nil); got != "custom program error 6001 (0x1771)" {
+		t.Errorf("expected the custom error code, got %q", got)
+	}
+	if got := describeSimulationError("AccountNotFound", nil, DefaultProgramErrors); got != `"AccountNotFound"` {
+		t.Errorf("expected the raw error, got %q", got)
+	}
+}
+
+func TestParseBlockhashCommitment(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    rpc.CommitmentType
+		wantErr bool
+	}{
+		{input: "", want: rpc.CommitmentFinalized},
+		{input: "finalized", want: rpc.CommitmentFinalized},
+		{input: "confirmed", want: rpc.CommitmentConfirmed},
+		{input: "processed", wantErr: true},
+		{input: "Finalized", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseBlockhashCommitment(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expected an error for %q, got %s", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBlockhashCommitment(%q) = %s, %v; want %s", tt.input, got, err, tt.want)
+		}
+	}
+
+	payer, err := solana.NewRandomPrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	_, err = NewSolanaClient(zap.NewNop(), SolanaClientConfig{
+		RPCURL:              newSolanaRPCServer(t, "ok").URL,
+		PrivateKey:          payer.String(),
+		ProgramID:           solana.SystemProgramID.String(),
+		BlockhashCommitment: "processed",
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid blockhash commitment") {
+		t.Errorf("expected NewSolanaClient to reject an invalid commitment, got %v", err)
+	}
+}
+
+func TestEstimateFee(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	program := solana.NewInstruction(solana.SystemProgramID, solana.AccountMetaSlice{solana.Meta(payer).WRITE().SIGNER()}, []byte{1})
+	setLimit := func(units uint32) solana.Instruction {
+		data := binary.LittleEndian.AppendUint32([]byte{computeBudgetSetUnitLimit}, units)
+		return solana.NewInstruction(solana.ComputeBudget, nil, data)
+	}
+	setPrice := func(microLamports uint64) solana.Instruction {
+		data := binary.LittleEndian.AppendUint64([]byte{computeBudgetSetUnitPrice}, microLamports)
+		return solana.NewInstruction(solana.ComputeBudget, nil, data)
+	}
+
+	tests := []struct {
+		name         string
+		instructions []solana.Instruction
+		wantLimit    uint32
+		wantPriority uint64
+	}{
+		{name: "no compute budget", instructions: []solana.Instruction{program}, wantLimit: 200_000, wantPriority: 0},
+		{name: "price with default limit", instructions: []solana.Instruction{setPrice(10), program, program}, wantLimit: 400_000, wantPriority: 4},
+		{name: "price and limit", instructions: []solana.Instruction{setLimit(300_000), setPrice(1_000), program}, wantLimit: 300_000, wantPriority: 300},
+		{name: "priority fee rounds up", instructions: []solana.Instruction{setLimit(1), setPrice(1), program}, wantLimit: 1, wantPriority: 1},
+		{name: "limit is capped", instructions: []solana.Instruction{setLimit(5_000_000), setPrice(1_000_000), program}, wantLimit: 1_400_000, wantPriority: 1_400_000},
+	}
+
+	client := &SolanaClient{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := solana.NewTransaction(tt.instructions, solana.Hash{}, solana.TransactionPayer(payer))
+			if err != nil {
+				t.Fatalf("NewTransaction failed: %v", err)
+			}
+			fee := client.EstimateFee(tx)
+			if fee.Signatures != 1 || fee.BaseFee != 5000 {
+				t.Errorf("expected one signature paying 5000 lamports, got %+v", fee)
+			}
+			if fee.ComputeUnitLimit != tt.wantLimit || fee.PriorityFee != tt.wantPriority {
+				t.Errorf("expected limit %d and priority fee %d, got %+v", tt.wantLimit, tt.wantPriority, fee)
+			}
+			if fee.Total() != fee.BaseFee+fee.PriorityFee {
+				t.Errorf("unexpected total %d", fee.Total())
+			}
+		})
+	}
+}
+
+// newSolanaBalanceServer starts a JSON-RPC server answering getBalance with lamports
+func newSolanaBalanceServer(t *testing.T, lamports uint64) *httptest.Server {
+	t.Helper()
+	return newJSONRPCServer(t, func(method string) interface{} {
+		if method != "getBalance" {
+			t.Errorf("unexpected method %s", method)
+		}
+		return map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": lamports}
+	})
+}
+
+func TestSolanaClientCheckBalance(t *testing.T) {
+	fee := SolanaFeeEstimate{BaseFee: 5000, PriorityFee: 300}
+
+	tests := []struct {
+		name       string
+		balance    uint64
+		minBalance uint64
+		wantErr    bool
+	}{
+		{name: "affords the fee", balance: 5300, wantErr: false},
+		{name: "cannot afford the fee", balance: 5299, wantErr: true},
+		{name: "above the minimum balance", balance: LamportsPerSOL, minBalance: LamportsPerSOL / 10, wantErr: false},
+		{name: "below the minimum balance", balance: LamportsPerSOL / 20, minBalance: LamportsPerSOL / 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &SolanaClient{
+				client:     rpc.New(newSolanaBalanceServer(t, tt.balance).URL),
+				payer:      NewLocalSolanaSigner(solana.NewWallet().PrivateKey),
+				minBalance: tt.minBalance,
+				logger:     zap.NewNop(),
+			}
+			err := client.checkBalance(context.Background(), fee)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error = %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInsufficientBalance) {
+				t.Errorf("expected ErrInsufficientBalance, got %v", err)
+			}
+		})
+	}
+
+	// A failed lookup does not hold back the submission
+	client := &SolanaClient{client: rpc.New("http://127.0.0.1:1"), payer: NewLocalSolanaSigner(solana.NewWallet().PrivateKey), logger: zap.NewNop()}
+	if err := client.checkBalance(context.Background(), fee); err != nil {
+		t.Errorf("expected a failed balance lookup to be ignored, got %v", err)
+	}
+}
+
+func TestFormatSOL(t *testing.T) {
+	for lamports, want := range map[uint64]string{0: "0", 5000: "0.000005", LamportsPerSOL: "1", 1_500_000_000: "1.5"} {
+		if got := FormatSOL(lamports); got != want {
+			t.Errorf("FormatSOL(%d) = %q, want %q", lamports, got, want)
+		}
+	}
+}
+
+func TestLookupSolanaNetwork(t *testing.T) {
+	for name, network := range SolanaNetworks {
+		if network.RPCURL == "" {
+			t.Errorf("%s: expected an RPC URL", name)
+		}
+		if network.WormholeProgramID == "" {
+			continue
+		}
+		if _, err := solana.PublicKeyFromBase58(network.WormholeProgramID); err != nil {
+			t.Errorf("%s: invalid Wormhole program ID %q: %v", name, network.WormholeProgramID, err)
+		}
+	}
+
+	devnet, err := LookupSolanaNetwork("devnet")
+	if err != nil || devnet.WormholeProgramID != DefaultWormholeProgramID.String() {
+		t.Errorf("expected devnet to use the default Wormhole program, got %+v (%v)", devnet, err)
+	}
+	if _, err := LookupSolanaNetwork("localnet"); err == nil {
+		t.Error("expected an unknown network to be rejected")
+	}
+}
+
+// newSolanaAccountServer starts a JSON-RPC server answering getAccountInfo with data, or with no
+// account if data is nil
+func newSolanaAccountServer(t *testing.T, data []byte) *httptest.Server {
+	t.Helper()
+	return newJSONRPCServer(t, func(method string) interface{} {
+		if method != "getAccountInfo" {
+			t.Errorf("unexpected method %s", method)
+		}
+		return solanaAccountInfo(data)
+	})
+}
+
+// solanaAccountInfo is the getAccountInfo result for an account holding data, or for a
+// missing account if data is nil
+func solanaAccountInfo(data []byte) interface{} {
+	var value interface{}
+	if data != nil {
+		value = map[string]interface{}{
+			"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+			"executable": false,
+			"lamports":   1,
+			"owner":      solana.SystemProgramID.String(),
+			"rentEpoch":  0,
+		}
+	}
+	return map[string]interface{}{"context": map[string]interface{}{"slot": 1}, "value": value}
+}
+
+func TestSolanaClientVerifyEmitterRegistered(t *testing.T) {
+	var emitter, other [32]byte
+	emitter[31], other[31] = 0xaa, 0xbb
+	account := func(address [32]byte) []byte {
+		data := make([]byte, foreignEmitterAccountSize)
+		binary.LittleEndian.PutUint16(data[8:10], 10003)
+		copy(data[10:42], address[:])
+		return data
+	}
+
+	tests := []struct {
+		name             string
+		data             []byte
+		wantErr          bool
+		wantUnregistered bool
+	}{
+		{name: "registered", data: account(emitter)},
+		{name: "another emitter registered", data: account(other), wantErr: true, wantUnregistered: true},
+		{name: "no account", data: nil, wantErr: true, wantUnregistered: true},
+		{name: "truncated account", data: account(emitter)[:20], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &SolanaClient{client: rpc.New(newSolanaAccountServer(t, tt.data).URL), programID: solana.SystemProgramID}
+			err := client.VerifyEmitterRegistered(context.Background(), 10003, emitter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error = %v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, ErrEmitterNotRegistered) != tt.wantUnregistered {
+				t.Errorf("expected ErrEmitterNotRegistered = %v, got %v", tt.wantUnregistered, err)
+			}
+		})
+	}
+}
+
+func TestSolanaClientCallVAAService(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantErr  []string // Substrings of the error (nil = success)
+		wantCode int      // Expected HTTPStatusError status (0 = none)
+	}{
+		{name: "success", status: http.StatusOK, body: `{"success":true,"signature":"5xyz","message":"posted"}`},
+		{
+			name: "failure with an error", status: http.StatusOK, body: `{"success":false,"error":"guardian set expired"}`,
+			wantErr: []string{"HTTP 200", "guardian set expired"},
+		},
+		{
+			name: "failure with only a message", status: http.StatusOK, body: `{"success":false,"message":"insufficient funds for rent"}`,
+			wantErr: []string{"HTTP 200", "insufficient funds for rent"},
+		},
+		{
+			name: "failure with an error and a message", status: http.StatusOK, body: `{"success":false,"error":"post failed","message":"simulation failed"}`,
+			wantErr: []string{"post failed: simulation failed"},
+		},
+		{
+			name: "failure without details", status: http.StatusOK, body: `{"success":false}`,
+			wantErr: []string{"no error given", `{\"success\":false}`},
+		},
+		{
+			name: "success field missing", status: http.StatusOK, body: `{"signature":"5xyz"}`,
+			wantErr: []string{"VAA service error"},
+		},
+		{
+			name: "500 without a body", status: http.StatusInternalServerError, body: "",
+			wantErr: []string{"HTTP 500"}, wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "502 with an HTML page", status: http.StatusBadGateway, body: "<html><h1>502 Bad Gateway</h1></html>",
+			wantErr: []string{"HTTP 502", "<h1>502 Bad Gateway</h1>"}, wantCode: http.StatusBadGateway,
+		},
+		{
+			name: "400 with a JSON message", status: http.StatusBadRequest, body: `{"success":false,"message":"invalid VAA hex"}`,
+			wantErr: []string{"HTTP 400", "invalid VAA hex"}, wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "non-2xx fails even if success is set", status: http.StatusNotFound, body: `{"success":true}`,
+			wantErr: []string{"HTTP 404"}, wantCode: http.StatusNotFound,
+		},
+		{
+			name: "200 with a non-JSON body", status: http.StatusOK, body: "OK",
+			wantErr: []string{"HTTP 200", `body "OK"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/post-vaa" {
+					http.Error(w, "unexpected request", http.StatusMethodNotAllowed)
+					return
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			client := &SolanaClient{vaaServiceURL: server.URL, httpClient: server.Client(), logger: zap.NewNop()}
+
+			err := client.callVAAService(context.Background(), []byte{1, 2, 3})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("expected success, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error, got success")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected the error to contain %q, got %v", want, err)
+				}
+			}
+			var statusErr *HTTPStatusError
+			if gotStatus := errors.As(err, &statusErr); gotStatus != (tt.wantCode != 0) || (gotStatus && statusErr.StatusCode != tt.wantCode) {
+				t.Errorf("expected HTTPStatusError with status %d, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestCallVAAServiceLimitsResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":true,"message":"` + strings.Repeat("x", 4096) + `"}`))
+	}))
+	defer server.Close()
+	client := &SolanaClient{vaaServiceURL: server.URL, httpClient: server.Client(), vaaServiceMaxBody: 1024, logger: zap.NewNop()}
+
+	err := client.callVAAService(context.Background(), []byte{1, 2, 3})
+	if !errors.Is(err, ErrResponseTooLarge) || !strings.Contains(err.Error(), "over 1024 bytes") {
+		t.Fatalf("expected ErrResponseTooLarge naming the limit, got %v", err)
+	}
+}
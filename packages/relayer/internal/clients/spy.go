@@ -2,26 +2,94 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	publicrpcv1 "github.com/certusone/wormhole/node/pkg/proto/publicrpc/v1"
 	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
+// ErrSpyFatal marks spy errors that retrying cannot fix, such as a subscription the endpoint
+// rejects. Transient ones (Unavailable, DeadlineExceeded, dropped connections) are left unwrapped.
+var ErrSpyFatal = errors.New("fatal spy error")
+
+// fatalSpyCodes are the gRPC status codes that mean the spy will keep rejecting the subscription,
+// with the likely misconfiguration behind each
+var fatalSpyCodes = map[codes.Code]string{
+	codes.Unauthenticated:  "the endpoint requires credentials the relayer does not send",
+	codes.PermissionDenied: "the endpoint refused the subscription",
+	codes.Unimplemented:    "the endpoint does not serve the spy API; check it points at a guardian spy",
+}
+
+// ClassifySpyError wraps err in ErrSpyFatal, with a hint at the cause, if its gRPC status code
+// means the spy will keep failing the same way; any other error is returned unchanged
+func ClassifySpyError(err error) error {
+	if hint, ok := fatalSpyCodes[status.Code(err)]; ok {
+		return fmt.Errorf("%w: %s: %v", ErrSpyFatal, hint, err)
+	}
+	return err
+}
+
+// Spy subscription modes, choosing which VAAs the spy streams to the relayer
+const (
+	// SpySubscriptionAll streams every signed VAA the spy sees
+	SpySubscriptionAll = "all"
+	// SpySubscriptionEmitters streams only the VAAs of the configured chains and emitters,
+	// filtered by the spy itself
+	SpySubscriptionEmitters = "emitters"
+)
+
+// SpyEmitter is an emitter on one chain whose VAAs a filtered subscription asks the spy for
+type SpyEmitter struct {
+	ChainID uint16
+	Address string // Hex-encoded 32-byte emitter address
+}
+
+// VAAStream is a subscription to signed VAAs, as opened by SpyClient.SubscribeSignedVAA.
+// Recv blocks until the next VAA arrives, returning an error once the stream breaks or its
+// context is done.
+type VAAStream interface {
+	Recv() (*spyv1.SubscribeSignedVAAResponse, error)
+}
+
 // SpyClient handles connections to the Wormhole spy service
 type SpyClient struct {
-	conn   *grpc.ClientConn
-	client spyv1.SpyRPCServiceClient
-	logger *zap.Logger
+	endpoint   string
+	conn       *grpc.ClientConn // Used for one-off checks; subscriptions dial their own
+	client     spyv1.SpyRPCServiceClient
+	maxBackoff time.Duration        // Cap on the delay between reconnect attempts
+	filters    []*spyv1.FilterEntry // Sent with every subscription; empty subscribes to all VAAs
+	logger     *zap.Logger
+
+	// dial opens a subscription connection (dialSpy, replaced in tests)
+	dial    func(ctx context.Context, endpoint string) (*grpc.ClientConn, error)
+	subMu   sync.Mutex
+	subConn *grpc.ClientConn // Connection of the current subscription
 }
 
 // NewSpyClient creates a new client for the Wormhole spy service
 func NewSpyClient(logger *zap.Logger, endpoint string) (*SpyClient, error) {
+	return NewSpyClientWithBackoff(logger, endpoint, DefaultSpyMaxBackoff)
+}
+
+// NewSpyClientWithBackoff creates a spy client whose reconnect delays grow exponentially
+// from DefaultSpyInitialBackoff up to maxBackoff
+func NewSpyClientWithBackoff(logger *zap.Logger, endpoint string, maxBackoff time.Duration) (*SpyClient, error) {
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultSpyMaxBackoff
+	}
 	client := &SpyClient{
-		logger: logger.With(zap.String("component", "SpyClient")),
+		endpoint:   endpoint,
+		maxBackoff: maxBackoff,
+		dial:       dialSpy,
+		logger:     logger.With(zap.String("component", "SpyClient")),
 	}
 
 	client.logger.Info("Connecting to spy service", zap.String("endpoint", endpoint))
@@ -36,53 +104,87 @@ func NewSpyClient(logger *zap.Logger, endpoint string) (*SpyClient, error) {
 	return client, nil
 }
 
-// Close closes the connection to the spy service
+// Close closes the connections to the spy service, ending any subscription
 func (c *SpyClient) Close() {
+	c.closeSubscriptionConn()
 	if c.conn != nil {
 		c.conn.Close()
 	}
 }
 
-// SubscribeSignedVAA subscribes to all signed VAAs with retry logic
-func (c *SpyClient) SubscribeSignedVAA(ctx context.Context) (spyv1.SpyRPCService_SubscribeSignedVAAClient, error) {
-	const maxRetries = 5
-	const retryDelay = 2 * time.Second
+// SetEmitterFilter makes later subscriptions ask the spy for the VAAs of emitters only, so
+// the spy drops the rest before sending them. An empty list subscribes to every VAA.
+func (c *SpyClient) SetEmitterFilter(emitters []SpyEmitter) {
+	filters := make([]*spyv1.FilterEntry, len(emitters))
+	for i, emitter := range emitters {
+		filters[i] = &spyv1.FilterEntry{Filter: &spyv1.FilterEntry_EmitterFilter{
+			EmitterFilter: &spyv1.EmitterFilter{
+				ChainId:        publicrpcv1.ChainID(emitter.ChainID),
+				EmitterAddress: emitter.Address,
+			},
+		}}
+	}
+	c.filters = filters
+}
 
-	c.logger.Debug("Subscribing to signed VAAs")
+// subscribeRequest returns the request opening a subscription with the configured filters
+func (c *SpyClient) subscribeRequest() *spyv1.SubscribeSignedVAARequest {
+	return &spyv1.SubscribeSignedVAARequest{Filters: c.filters}
+}
 
-	var stream spyv1.SpyRPCService_SubscribeSignedVAAClient
-	var err error
+// NewReconnectBackoff returns a fresh backoff for reconnecting to the spy
+func (c *SpyClient) NewReconnectBackoff() *Backoff {
+	return NewBackoff(DefaultSpyInitialBackoff, c.maxBackoff)
+}
 
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		// Create a fresh connection for each attempt
-		endpoint := c.conn.Target()
-		conn, err := grpc.DialContext(ctx, endpoint,
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithBlock())
-		if err != nil {
-			if attempt < maxRetries {
-				c.logger.Warn("Connection attempt failed",
-					zap.Int("attempt", attempt),
-					zap.Error(err),
-					zap.Duration("retryIn", retryDelay))
-				time.Sleep(retryDelay)
-				continue
-			}
-			return nil, fmt.Errorf("failed to create connection after %d attempts: %v", maxRetries, err)
-		}
+// spySubscriptionGrace is how long CheckSubscription waits for the spy to reject a subscription
+const spySubscriptionGrace = 2 * time.Second
 
-		client := spyv1.NewSpyRPCServiceClient(conn)
-		stream, err = client.SubscribeSignedVAA(ctx, &spyv1.SubscribeSignedVAARequest{})
-		if err == nil {
-			c.logger.Info("Successfully subscribed to VAA stream")
-			return stream, nil
+// CheckSubscription opens a single VAA subscription and closes it again. Unlike
+// SubscribeSignedVAA it does not retry, so it suits one-off reachability checks.
+func (c *SpyClient) CheckSubscription(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Closes the stream
+
+	stream, err := c.client.SubscribeSignedVAA(ctx, c.subscribeRequest(), grpc.WaitForReady(true))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %v", err)
+	}
+
+	// The spy stays silent until a VAA arrives, so an error within the grace period
+	// (e.g. Unimplemented) means the subscription was rejected and silence means it was accepted
+	recvErr := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		recvErr <- err
+	}()
+	select {
+	case err := <-recvErr:
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("subscription rejected: %w", ClassifySpyError(err))
 		}
+	case <-time.After(spySubscriptionGrace):
+	case <-ctx.Done():
+	}
+	return nil
+}
 
-		conn.Close() // Close the failed connection
+// SubscribeSignedVAA subscribes to all signed VAAs, retrying with exponential backoff.
+// Each subscription gets its own connection; the previous subscription's connection is
+// closed first, so a resubscribe after a stream error does not leak it. An error wrapping
+// ErrSpyFatal is returned at once, without retrying.
+func (c *SpyClient) SubscribeSignedVAA(ctx context.Context) (VAAStream, error) {
+	const maxRetries = 5
+	backoff := c.NewReconnectBackoff()
+
+	c.logger.Debug("Subscribing to signed VAAs", zap.Int("emitterFilters", len(c.filters)))
 
-		if attempt < maxRetries {
+	var err error
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if attempt > 1 {
+			retryDelay := backoff.Next()
 			c.logger.Warn("Subscribe attempt failed",
-				zap.Int("attempt", attempt),
+				zap.Int("attempt", attempt-1),
 				zap.Error(err),
 				zap.Duration("retryIn", retryDelay))
 
@@ -93,7 +195,52 @@ func (c *SpyClient) SubscribeSignedVAA(ctx context.Context) (spyv1.SpyRPCService
 				return nil, fmt.Errorf("context cancelled during retry: %v", ctx.Err())
 			}
 		}
+
+		// Create a fresh connection for each attempt
+		c.closeSubscriptionConn()
+		var conn *grpc.ClientConn
+		conn, err = c.dial(ctx, c.endpoint)
+		if err != nil {
+			err = fmt.Errorf("failed to connect: %v", err)
+			continue
+		}
+		c.setSubscriptionConn(conn)
+
+		var stream spyv1.SpyRPCService_SubscribeSignedVAAClient
+		stream, err = spyv1.NewSpyRPCServiceClient(conn).SubscribeSignedVAA(ctx, c.subscribeRequest())
+		if err == nil {
+			c.logger.Info("Successfully subscribed to VAA stream")
+			return stream, nil
+		}
+		c.closeSubscriptionConn()
+		if err = ClassifySpyError(err); errors.Is(err, ErrSpyFatal) {
+			return nil, err
+		}
 	}
 
 	return nil, fmt.Errorf("failed to subscribe after %d attempts: %v", maxRetries, err)
 }
+
+// dialSpy opens a connection to the spy, blocking until it is ready or ctx is done
+func dialSpy(ctx context.Context, endpoint string) (*grpc.ClientConn, error) {
+	return grpc.DialContext(ctx, endpoint,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock())
+}
+
+// setSubscriptionConn records conn as the connection of the current subscription
+func (c *SpyClient) setSubscriptionConn(conn *grpc.ClientConn) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	c.subConn = conn
+}
+
+// closeSubscriptionConn closes the current subscription's connection, if any
+func (c *SpyClient) closeSubscriptionConn() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.subConn != nil {
+		c.subConn.Close()
+		c.subConn = nil
+	}
+}
@@ -0,0 +1,192 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// spyServer accepts subscriptions; streams fail only once a client calls Recv
+type spyServer struct {
+	spyv1.UnimplementedSpyRPCServiceServer
+}
+
+// failingSpyServer ends every subscription with a gRPC status error of code
+type failingSpyServer struct {
+	spyv1.UnimplementedSpyRPCServiceServer
+	code codes.Code
+}
+
+func (s failingSpyServer) SubscribeSignedVAA(*spyv1.SubscribeSignedVAARequest, spyv1.SpyRPCService_SubscribeSignedVAAServer) error {
+	return status.Error(s.code, "stream failed")
+}
+
+// startSpyServer serves spy on a local port, returning its address
+func startSpyServer(t *testing.T, spy spyv1.SpyRPCServiceServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	spyv1.RegisterSpyRPCServiceServer(server, spy)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestClassifySpyError(t *testing.T) {
+	tests := []struct {
+		code  codes.Code
+		fatal bool
+	}{
+		{code: codes.Unavailable, fatal: false},
+		{code: codes.DeadlineExceeded, fatal: false},
+		{code: codes.Internal, fatal: false},
+		{code: codes.Unauthenticated, fatal: true},
+		{code: codes.PermissionDenied, fatal: true},
+		{code: codes.Unimplemented, fatal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			endpoint := startSpyServer(t, failingSpyServer{code: tt.code})
+			client := &SpyClient{endpoint: endpoint, maxBackoff: time.Second, dial: dialSpy, logger: zap.NewNop()}
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			stream, err := client.SubscribeSignedVAA(ctx)
+			if err != nil {
+				t.Fatalf("subscribe failed: %v", err)
+			}
+			_, err = stream.Recv()
+			if status.Code(err) != tt.code {
+				t.Fatalf("expected a %s stream error, got %v", tt.code, err)
+			}
+
+			classified := ClassifySpyError(err)
+			if errors.Is(classified, ErrSpyFatal) != tt.fatal {
+				t.Errorf("expected fatal=%v, got %v", tt.fatal, classified)
+			}
+			if status.Code(classified) != tt.code && !tt.fatal {
+				t.Errorf("expected a transient error to be returned unchanged, got %v", classified)
+			}
+		})
+	}
+
+	if err := ClassifySpyError(errors.New("EOF")); errors.Is(err, ErrSpyFatal) {
+		t.Errorf("expected a non-gRPC error not to be fatal, got %v", err)
+	}
+}
+
+func TestSubscribeSignedVAAClosesPreviousConnection(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	spyv1.RegisterSpyRPCServiceServer(server, spyServer{})
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	// Record every connection the client dials
+	var conns []*grpc.ClientConn
+	client := &SpyClient{endpoint: lis.Addr().String(), maxBackoff: time.Second, logger: zap.NewNop()}
+	client.dial = func(ctx context.Context, endpoint string) (*grpc.ClientConn, error) {
+		conn, err := dialSpy(ctx, endpoint)
+		if err == nil {
+			conns = append(conns, conn)
+		}
+		return conn, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if _, err := client.SubscribeSignedVAA(ctx); err != nil {
+			t.Fatalf("subscribe %d failed: %v", i+1, err)
+		}
+	}
+
+	if len(conns) != 2 {
+		t.Fatalf("expected 2 dialed connections, got %d", len(conns))
+	}
+	if state := conns[0].GetState(); state != connectivity.Shutdown {
+		t.Errorf("expected the first subscription's connection to be closed on resubscribe, got %s", state)
+	}
+	if state := conns[1].GetState(); state == connectivity.Shutdown {
+		t.Error("expected the current subscription's connection to stay open")
+	}
+
+	client.Close()
+	if state := conns[1].GetState(); state != connectivity.Shutdown {
+		t.Errorf("expected Close to close the subscription's connection, got %s", state)
+	}
+}
+
+// filterRecordingSpyServer reports the filters of each subscription and keeps it open
+type filterRecordingSpyServer struct {
+	spyv1.UnimplementedSpyRPCServiceServer
+	filters chan []*spyv1.FilterEntry
+}
+
+func (s filterRecordingSpyServer) SubscribeSignedVAA(req *spyv1.SubscribeSignedVAARequest, stream spyv1.SpyRPCService_SubscribeSignedVAAServer) error {
+	s.filters <- req.Filters
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestSubscribeSignedVAAEmitterFilter(t *testing.T) {
+	spy := filterRecordingSpyServer{filters: make(chan []*spyv1.FilterEntry, 2)}
+	endpoint := startSpyServer(t, spy)
+	client := &SpyClient{endpoint: endpoint, maxBackoff: time.Second, dial: dialSpy, logger: zap.NewNop()}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	receive := func() []*spyv1.FilterEntry {
+		t.Helper()
+		select {
+		case filters := <-spy.filters:
+			return filters
+		case <-ctx.Done():
+			t.Fatal("the spy received no subscription")
+			return nil
+		}
+	}
+
+	// Without a filter, every VAA is requested
+	if _, err := client.SubscribeSignedVAA(ctx); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	if filters := receive(); len(filters) != 0 {
+		t.Fatalf("expected an unfiltered subscription, got %v", filters)
+	}
+
+	emitter := strings.Repeat("0", 24) + strings.Repeat("ab", 20)
+	client.SetEmitterFilter([]SpyEmitter{{ChainID: 10003, Address: emitter}, {ChainID: 56, Address: emitter}})
+	if _, err := client.SubscribeSignedVAA(ctx); err != nil {
+		t.Fatalf("subscribe failed: %v", err)
+	}
+	filters := receive()
+	if len(filters) != 2 {
+		t.Fatalf("expected 2 emitter filters, got %v", filters)
+	}
+	for i, want := range []uint16{10003, 56} {
+		got := filters[i].GetEmitterFilter()
+		if got == nil || uint16(got.ChainId) != want || got.EmitterAddress != emitter {
+			t.Errorf("filter %d: expected chain %d emitter %s, got %v", i, want, emitter, filters[i])
+		}
+	}
+}
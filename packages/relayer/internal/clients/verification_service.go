@@ -1,22 +1,28 @@
 package clients
 
 import (
-	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"go.uber.org/zap"
 )
 
+// IdempotencyKeyHeader carries the idempotency key of a verification request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 // ADD: HTTP verification service types
 type VerificationRequest struct {
 	VAABytes string `json:"vaaBytes"`
+	// IdempotencyKey identifies the VAA and is also sent in the Idempotency-Key header. A service
+	// that has already handled the key should return the original result instead of submitting
+	// again; services that ignore it keep working unchanged.
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
 }
 
 type VerificationResponse struct {
@@ -29,32 +35,72 @@ type VerificationResponse struct {
 type VerificationServiceClient struct {
 	baseURL    string
 	httpClient *http.Client
+	retry      RetryConfig   // Retry policy for 5xx responses, timeouts and dropped connections
+	gzip       *gzipRequests // Request compression negotiated with the service (nil = never compress)
+	maxBody    int64         // Largest response body read (0 = DefaultMaxResponseBytes)
+	auth       ServiceAuth   // Credentials attached to every request, including health checks
 	logger     *zap.Logger
 }
 
+// VerificationServiceConfig holds the settings for a VerificationServiceClient
+type VerificationServiceConfig struct {
+	URL       string          // Base URL of the verification service
+	Transport *http.Transport // nil = default transport, honouring HTTP_PROXY and HTTPS_PROXY
+	Retry     RetryConfig     // Zero value = DefaultRetryConfig()
+	// Gzip request bodies once the service advertises support with Accept-Encoding: gzip
+	GzipRequests bool
+	// Largest response body read, in bytes; larger responses fail (0 = DefaultMaxResponseBytes)
+	MaxResponseBytes int64
+	// Bearer token and static headers sent with every request, for services behind an auth gateway
+	Auth ServiceAuth
+}
+
 // ADD: Create new verification service client
 func NewVerificationServiceClient(logger *zap.Logger, baseURL string) *VerificationServiceClient {
+	return NewVerificationServiceClientWithConfig(logger, VerificationServiceConfig{URL: baseURL})
+}
+
+// NewVerificationServiceClientWithTransport creates a verification service client that sends its
+// requests through transport (proxy, TLS config, connection pooling). A nil transport behaves like
+// NewVerificationServiceClient, honouring the HTTP_PROXY and HTTPS_PROXY environment variables.
+func NewVerificationServiceClientWithTransport(logger *zap.Logger, baseURL string, transport *http.Transport) *VerificationServiceClient {
+	return NewVerificationServiceClientWithConfig(logger, VerificationServiceConfig{URL: baseURL, Transport: transport})
+}
+
+// NewVerificationServiceClientWithConfig creates a verification service client from config
+func NewVerificationServiceClientWithConfig(logger *zap.Logger, config VerificationServiceConfig) *VerificationServiceClient {
+	retry := config.Retry
+	if retry == (RetryConfig{}) {
+		retry = DefaultRetryConfig()
+	}
 	return &VerificationServiceClient{
-		baseURL: strings.TrimSuffix(baseURL, "/"),
-		httpClient: &http.Client{
-			Timeout: 300 * time.Second,
-		},
-		logger: logger.With(zap.String("component", "VerificationServiceClient")),
+		baseURL:    strings.TrimSuffix(config.URL, "/"),
+		httpClient: newHTTPClient(300*time.Second, config.Transport),
+		retry:      retry,
+		gzip:       newGzipRequests(config.GzipRequests),
+		maxBody:    config.MaxResponseBytes,
+		auth:       config.Auth,
+		logger:     logger.With(zap.String("component", "VerificationServiceClient")),
 	}
 }
 
 // ADD: Verify VAA via HTTP service
 func (c *VerificationServiceClient) VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error) {
-	c.logger.Debug("Sending VAA to verification service", zap.Int("vaaLength", len(vaaBytes)))
-
 	// Prepare request
 	vaaHex := hex.EncodeToString(vaaBytes)
 	if !strings.HasPrefix(vaaHex, "0x") {
 		vaaHex = "0x" + vaaHex
 	}
 
+	// The key stays the same across retries here and replays of the VAA from the spy
+	idempotencyKey := VAAIdempotencyKey(vaaBytes)
+	c.logger.Debug("Sending VAA to verification service",
+		zap.Int("vaaLength", len(vaaBytes)),
+		zap.String("idempotencyKey", idempotencyKey))
+
 	request := VerificationRequest{
-		VAABytes: vaaHex,
+		VAABytes:       vaaHex,
+		IdempotencyKey: idempotencyKey,
 	}
 
 	jsonData, err := json.Marshal(request)
@@ -62,34 +108,74 @@ func (c *VerificationServiceClient) VerifyVAA(ctx context.Context, vaaBytes []by
 		return "", fmt.Errorf("failed to marshal verification request: %v", err)
 	}
 
+	// 5xx responses, timeouts and dropped connections are retried with backoff
+	return retryCall(ctx, c.retry, c.logger, "VerifyVAA", func() (string, error) {
+		return c.verifyOnce(ctx, jsonData, idempotencyKey)
+	})
+}
+
+// VAAIdempotencyKey returns the hex digest identifying a VAA: the double keccak256 of its body,
+// as used by the Wormhole contracts for replay protection. Unlike a hash of the raw bytes, it
+// does not change when the VAA carries a different set of guardian signatures. Bytes too short
+// to hold a VAA body are hashed whole.
+func VAAIdempotencyKey(vaaBytes []byte) string {
+	body := vaaBytes
+	// Header: version(1) | guardianSetIndex(4) | signatureCount(1) | signatures(66 each)
+	if len(vaaBytes) >= 6 {
+		if bodyStart := 6 + 66*int(vaaBytes[5]); bodyStart < len(vaaBytes) {
+			body = vaaBytes[bodyStart:]
+		}
+	}
+	return hex.EncodeToString(crypto.Keccak256(crypto.Keccak256(body)))
+}
+
+// verifyOnce sends a single verification request. Any non-2xx status is a failure, reported as
+// an HTTPStatusError carrying the service's error message or the start of the raw body.
+func (c *VerificationServiceClient) verifyOnce(ctx context.Context, jsonData []byte, idempotencyKey string) (string, error) {
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/verify", bytes.NewBuffer(jsonData))
+	req, compressed, err := c.gzip.newRequest(ctx, "POST", c.baseURL+"/verify", jsonData)
 	if err != nil {
 		return "", fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
+	c.auth.apply(req)
 
 	// Send request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to send verification request: %v", err)
+		return "", fmt.Errorf("failed to send verification request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
+	// Read response, refusing to buffer an oversized body
+	body, err := readResponseBody(resp.Body, c.maxBody)
+	if c.gzip.observe(resp, compressed) {
+		c.logger.Info("Verification service rejected a compressed request, sending uncompressed from now on")
+		return c.verifyOnce(ctx, jsonData, idempotencyKey)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to read verification response: %v", err)
+		return "", fmt.Errorf("failed to read verification response (HTTP %d): %w", resp.StatusCode, err)
 	}
 
 	c.logger.Debug("Received response from verification service",
 		zap.Int("statusCode", resp.StatusCode))
 
-	// Parse response
+	// Parse response; error statuses may carry a JSON error or an HTML/plain-text page
 	var response VerificationResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("failed to unmarshal verification response: %v", err)
+	parseErr := json.Unmarshal(body, &response)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message := truncateBody(body)
+		if parseErr == nil && response.Error != "" {
+			message = response.Error
+		}
+		return "", fmt.Errorf("verification service request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: message})
+	}
+
+	if parseErr != nil {
+		return "", fmt.Errorf("failed to unmarshal verification response (HTTP %d, body %q): %v", resp.StatusCode, truncateBody(body), parseErr)
 	}
 
 	if !response.Success {
@@ -105,12 +191,14 @@ func (c *VerificationServiceClient) CheckHealth(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create health check request: %v", err)
 	}
+	c.auth.apply(req)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("health check failed: %v", err)
 	}
 	defer resp.Body.Close()
+	c.gzip.observe(resp, false)
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("verification service unhealthy: status %d", resp.StatusCode)
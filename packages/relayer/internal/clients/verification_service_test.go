@@ -0,0 +1,320 @@
+package clients
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// fastRetry retries quickly so tests do not wait on real backoff delays
+var fastRetry = RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+type verificationResponse struct {
+	status int
+	body   string
+}
+
+// newVerificationServer replies to /verify with responses in order, repeating the last one
+func newVerificationServer(t *testing.T, responses ...verificationResponse) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(calls.Add(1)) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		w.WriteHeader(responses[i].status)
+		_, _ = w.Write([]byte(responses[i].body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestVerificationServiceClientVerifyVAA(t *testing.T) {
+	badGateway := verificationResponse{http.StatusBadGateway, "<html><body><h1>502 Bad Gateway</h1></body></html>"}
+	ok := verificationResponse{http.StatusOK, `{"success":true,"txHash":"0xabc"}`}
+
+	tests := []struct {
+		name      string
+		responses []verificationResponse
+		wantTx    string
+		wantCalls int32
+		wantErr   []string // Substrings of the error (nil = success)
+		wantCode  int      // Expected HTTPStatusError status (0 = none)
+	}{
+		{name: "success", responses: []verificationResponse{ok}, wantTx: "0xabc", wantCalls: 1},
+		{name: "502 then success", responses: []verificationResponse{badGateway, ok}, wantTx: "0xabc", wantCalls: 2},
+		{
+			name:      "always 503",
+			responses: []verificationResponse{{http.StatusServiceUnavailable, "upstream connect error"}},
+			wantCalls: 3, wantErr: []string{"HTTP 503", "upstream connect error"}, wantCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:      "HTML 502 body is kept",
+			responses: []verificationResponse{badGateway},
+			wantCalls: 3, wantErr: []string{"HTTP 502", "<h1>502 Bad Gateway</h1>"}, wantCode: http.StatusBadGateway,
+		},
+		{
+			name:      "400 is not retried",
+			responses: []verificationResponse{{http.StatusBadRequest, `{"success":false,"error":"invalid VAA"}`}},
+			wantCalls: 1, wantErr: []string{"HTTP 400", "invalid VAA"}, wantCode: http.StatusBadRequest,
+		},
+		{
+			name:      "non-2xx fails even if success is set",
+			responses: []verificationResponse{{http.StatusNotFound, `{"success":true,"txHash":"0xabc"}`}},
+			wantCalls: 1, wantErr: []string{"HTTP 404"}, wantCode: http.StatusNotFound,
+		},
+		{
+			name:      "200 with a non-JSON body",
+			responses: []verificationResponse{{http.StatusOK, "OK"}},
+			wantCalls: 1, wantErr: []string{"HTTP 200", `body "OK"`},
+		},
+		{
+			name:      "service reports failure",
+			responses: []verificationResponse{{http.StatusOK, `{"success":false,"error":"proof failed"}`}},
+			wantCalls: 1, wantErr: []string{"verification failed: proof failed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := newVerificationServer(t, tt.responses...)
+			client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry})
+
+			txHash, err := client.VerifyVAA(context.Background(), []byte{1, 2, 3})
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d requests, got %d", tt.wantCalls, got)
+			}
+			if tt.wantErr == nil {
+				if err != nil || txHash != tt.wantTx {
+					t.Fatalf("expected tx %s, got %q (err %v)", tt.wantTx, txHash, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error, got tx %q", txHash)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected the error to contain %q, got %v", want, err)
+				}
+			}
+			var statusErr *HTTPStatusError
+			if gotStatus := errors.As(err, &statusErr); gotStatus != (tt.wantCode != 0) || (gotStatus && statusErr.StatusCode != tt.wantCode) {
+				t.Errorf("expected HTTPStatusError with status %d, got %v", tt.wantCode, err)
+			}
+		})
+	}
+}
+
+func TestVerificationServiceClientTruncatesRawBody(t *testing.T) {
+	server, _ := newVerificationServer(t, verificationResponse{http.StatusBadRequest, strings.Repeat("x", 10*maxErrorBodyLength)})
+	client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry})
+
+	_, err := client.VerifyVAA(context.Background(), []byte{1})
+	if err == nil || !strings.Contains(err.Error(), "(truncated)") {
+		t.Fatalf("expected a truncated body in the error, got %v", err)
+	}
+	if len(err.Error()) > 2*maxErrorBodyLength {
+		t.Errorf("expected the error to stay short, got %d bytes", len(err.Error()))
+	}
+}
+
+func TestVerificationServiceClientLimitsResponseSize(t *testing.T) {
+	huge := `{"success":true,"txHash":"0x` + strings.Repeat("ab", 1024) + `"}`
+	server, calls := newVerificationServer(t, verificationResponse{http.StatusOK, huge})
+	client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry, MaxResponseBytes: 1024})
+
+	_, err := client.VerifyVAA(context.Background(), []byte{1})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected an oversized response not to be retried, got %d calls", calls.Load())
+	}
+
+	// The same response fits under the default limit
+	client = NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry})
+	if _, err := client.VerifyVAA(context.Background(), []byte{1}); err != nil {
+		t.Fatalf("expected success under the default limit, got %v", err)
+	}
+}
+
+func TestVerificationServiceClientRetriesTimeouts(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond) // Longer than the client timeout below
+		}
+		_, _ = w.Write([]byte(`{"success":true,"txHash":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry})
+	client.httpClient.Timeout = 50 * time.Millisecond
+
+	txHash, err := client.VerifyVAA(context.Background(), []byte{1})
+	if err != nil || txHash != "0xabc" {
+		t.Fatalf("expected the retry after a timeout to succeed, got %q (err %v)", txHash, err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 requests, got %d", calls.Load())
+	}
+}
+
+func TestVAAIdempotencyKey(t *testing.T) {
+	vaa := &vaaLib.VAA{
+		Version:          vaaLib.SupportedVAAVersion,
+		GuardianSetIndex: 1,
+		Timestamp:        time.Unix(1700000000, 0),
+		Nonce:            7,
+		Sequence:         42,
+		ConsistencyLevel: 1,
+		EmitterChain:     vaaLib.ChainIDArbitrumSepolia,
+		Payload:          []byte{0x00, 0x38, 0x01},
+		Signatures:       []*vaaLib.Signature{{Index: 0}},
+	}
+	oneSignature, err := vaa.Marshal()
+	if err != nil {
+		t.Fatalf("marshal VAA: %v", err)
+	}
+	vaa.Signatures = append(vaa.Signatures, &vaaLib.Signature{Index: 1, Signature: [65]byte{1}})
+	twoSignatures, err := vaa.Marshal()
+	if err != nil {
+		t.Fatalf("marshal VAA: %v", err)
+	}
+
+	key := VAAIdempotencyKey(oneSignature)
+	if key != vaa.HexDigest() {
+		t.Errorf("expected the VAA digest %s, got %s", vaa.HexDigest(), key)
+	}
+	if other := VAAIdempotencyKey(twoSignatures); other != key {
+		t.Errorf("expected the key to ignore signatures, got %s and %s", key, other)
+	}
+
+	vaa.Sequence++
+	next, _ := vaa.Marshal()
+	if VAAIdempotencyKey(next) == key {
+		t.Error("expected a different key for a different VAA")
+	}
+	if VAAIdempotencyKey([]byte{1, 2}) == "" {
+		t.Error("expected a key for malformed bytes")
+	}
+}
+
+func TestVerificationServiceClientSendsIdempotencyKey(t *testing.T) {
+	var headers, bodies []string
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request VerificationRequest
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		headers = append(headers, r.Header.Get(IdempotencyKeyHeader))
+		bodies = append(bodies, request.IdempotencyKey)
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"txHash":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, Retry: fastRetry})
+	vaaBytes := []byte{1, 0, 0, 0, 0, 0, 0xaa, 0xbb}
+	if _, err := client.VerifyVAA(context.Background(), vaaBytes); err != nil {
+		t.Fatalf("VerifyVAA failed: %v", err)
+	}
+
+	want := VAAIdempotencyKey(vaaBytes)
+	if len(headers) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(headers))
+	}
+	for i := range headers {
+		if headers[i] != want || bodies[i] != want {
+			t.Errorf("request %d: expected key %s in header and body, got %q and %q", i+1, want, headers[i], bodies[i])
+		}
+	}
+}
+
+func TestVerificationServiceClientGzipRequests(t *testing.T) {
+	var encodings []string
+	advertise, reject := false, false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		if advertise {
+			w.Header().Set("Accept-Encoding", "gzip")
+		}
+		if r.URL.Path == "/health" {
+			return
+		}
+		body := io.Reader(r.Body)
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			if reject {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body = zr
+		}
+		var request VerificationRequest
+		if err := json.NewDecoder(body).Decode(&request); err != nil || len(request.VAABytes) != 2+2*2000 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"success":false,"error":"bad request body"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true,"txHash":"0xabc"}`))
+	}))
+	defer server.Close()
+
+	client := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL, GzipRequests: true})
+	vaaBytes := make([]byte, 2000) // A padded Aztec payload
+	verify := func() {
+		t.Helper()
+		if _, err := client.VerifyVAA(context.Background(), vaaBytes); err != nil {
+			t.Fatalf("VerifyVAA failed: %v", err)
+		}
+	}
+
+	// Nothing is compressed until the service advertises support
+	verify()
+	advertise = true
+	if err := client.CheckHealth(context.Background()); err != nil {
+		t.Fatalf("CheckHealth failed: %v", err)
+	}
+	verify()
+
+	// A 415 for a compressed body falls back to sending it uncompressed
+	reject = true
+	verify()
+	want := []string{"", "", "gzip", "gzip", ""}
+	if strings.Join(encodings, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected Content-Encoding %q, got %q", want, encodings)
+	}
+
+	// Without the option bodies are never compressed
+	encodings = nil
+	plain := NewVerificationServiceClientWithConfig(zap.NewNop(), VerificationServiceConfig{URL: server.URL})
+	reject = false
+	for i := 0; i < 2; i++ {
+		if _, err := plain.VerifyVAA(context.Background(), vaaBytes); err != nil {
+			t.Fatalf("VerifyVAA failed: %v", err)
+		}
+	}
+	if strings.Join(encodings, ",") != "," {
+		t.Fatalf("expected no compression without the option, got %q", encodings)
+	}
+}
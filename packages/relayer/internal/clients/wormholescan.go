@@ -0,0 +1,106 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultWormholescanURL is the public Wormholescan API
+const DefaultWormholescanURL = "https://api.wormholescan.io"
+
+// ErrVAANotFound is returned when Wormholescan has no signed VAA for the requested sequence
+var ErrVAANotFound = errors.New("VAA not found")
+
+// WormholescanConfig holds the settings for a WormholescanClient
+type WormholescanConfig struct {
+	URL       string          // Base URL of the Wormholescan API (empty = DefaultWormholescanURL)
+	Transport *http.Transport // nil = default transport, honouring HTTP_PROXY and HTTPS_PROXY
+	Retry     RetryConfig     // Zero value = DefaultRetryConfig()
+}
+
+// WormholescanClient fetches signed VAAs from the Wormholescan API, e.g. to backfill
+// sequences the spy delivered while the relayer was down
+type WormholescanClient struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryConfig // Retry policy for 429 and 5xx responses, timeouts and dropped connections
+	logger     *zap.Logger
+}
+
+// wormholescanVAAResponse is the body of GET /api/v1/vaas/{chain}/{emitter}/{sequence}
+type wormholescanVAAResponse struct {
+	Data struct {
+		VAA []byte `json:"vaa"` // Base64 encoded
+	} `json:"data"`
+}
+
+// NewWormholescanClient creates a Wormholescan client from config
+func NewWormholescanClient(logger *zap.Logger, config WormholescanConfig) *WormholescanClient {
+	baseURL := config.URL
+	if baseURL == "" {
+		baseURL = DefaultWormholescanURL
+	}
+	retry := config.Retry
+	if retry == (RetryConfig{}) {
+		retry = DefaultRetryConfig()
+	}
+	return &WormholescanClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: newHTTPClient(30*time.Second, config.Transport),
+		retry:      retry,
+		logger:     logger.With(zap.String("component", "WormholescanClient")),
+	}
+}
+
+// GetVAA fetches the signed VAA emitted by emitter (64-char hex, no 0x prefix) on chainID with
+// the given sequence. It returns an error wrapping ErrVAANotFound if Wormholescan has none.
+func (c *WormholescanClient) GetVAA(ctx context.Context, chainID uint16, emitter string, sequence uint64) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/v1/vaas/%d/%s/%d", c.baseURL, chainID, emitter, sequence)
+	return retryCall(ctx, c.retry, c.logger, "GetVAA", func() ([]byte, error) {
+		return c.getVAAOnce(ctx, url)
+	})
+}
+
+// getVAAOnce sends a single VAA request
+func (c *WormholescanClient) getVAAOnce(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch VAA: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read VAA response (HTTP %d): %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrVAANotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("wormholescan request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: truncateBody(body)})
+	}
+
+	var response wormholescanVAAResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal VAA response (body %q): %v", truncateBody(body), err)
+	}
+	if len(response.Data.VAA) == 0 {
+		return nil, ErrVAANotFound
+	}
+	return response.Data.VAA, nil
+}
@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestWormholescanClientGetVAA(t *testing.T) {
+	var gotPath string
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		gotPath = r.URL.Path
+		switch r.URL.Path {
+		case "/api/v1/vaas/2/0000000000000000000000001111111111111111111111111111111111111111/7":
+			w.Write([]byte(`{"data":{"vaa":"AQID","emitterChain":2}}`))
+		case "/api/v1/vaas/2/0000000000000000000000001111111111111111111111111111111111111111/8":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":5,"message":"NOT FOUND"}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := NewWormholescanClient(zap.NewNop(), WormholescanConfig{URL: server.URL + "/", Retry: fastRetry})
+	emitter := "0000000000000000000000001111111111111111111111111111111111111111"
+
+	vaaBytes, err := client.GetVAA(context.Background(), 2, emitter, 7)
+	if err != nil {
+		t.Fatalf("GetVAA failed: %v", err)
+	}
+	if string(vaaBytes) != "\x01\x02\x03" {
+		t.Errorf("expected the decoded VAA, got %x", vaaBytes)
+	}
+
+	// A missing sequence is not retried
+	calls = 0
+	if _, err := client.GetVAA(context.Background(), 2, emitter, 8); !errors.Is(err, ErrVAANotFound) {
+		t.Errorf("expected ErrVAANotFound, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request for a missing VAA, got %d", calls)
+	}
+
+	// Gateway errors are retried, then reported with their status
+	calls = 0
+	_, err = client.GetVAA(context.Background(), 3, emitter, 1)
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected an HTTP 502 error, got %v", err)
+	}
+	if calls != fastRetry.MaxAttempts {
+		t.Errorf("expected %d attempts, got %d (last path %s)", fastRetry.MaxAttempts, calls, gotPath)
+	}
+}
@@ -0,0 +1,152 @@
+package internal
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// States of a VAA in the dedup state
+const (
+	DedupeStateInflight  = "inflight"  // Being processed; replays wait for the outcome
+	DedupeStateProcessed = "processed" // Settled recently; replays are skipped until it expires
+	DedupeStateDelivered = "delivered" // Recorded in the delivery cache; replays are skipped across restarts
+)
+
+// DedupeEntry is a VAA the relayer currently skips if the spy sends it again
+type DedupeEntry struct {
+	Key       string     `json:"key"` // VAA hash, logged as vaaHash
+	State     string     `json:"state"`
+	Since     time.Time  `json:"since"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // Not set for in-flight VAAs
+	TxHash    string     `json:"txHash,omitempty"`    // Delivered VAAs only
+}
+
+// DedupeEntries returns the in-flight, processed and delivered VAAs, newest first. A VAA both
+// processed and in the delivery cache is listed once per state.
+func (r *Relayer) DedupeEntries() []DedupeEntry {
+	r.dedupeMu.Lock()
+	entries := []DedupeEntry{}
+	for key, since := range r.inflightVAAs {
+		entries = append(entries, DedupeEntry{Key: key, State: DedupeStateInflight, Since: since})
+	}
+	for key, since := range r.processedVAAs {
+		if expiresAt := since.Add(r.dedupeTTL); time.Now().Before(expiresAt) {
+			entries = append(entries, DedupeEntry{Key: key, State: DedupeStateProcessed, Since: since, ExpiresAt: &expiresAt})
+		}
+	}
+	r.dedupeMu.Unlock()
+
+	if r.deliveries != nil {
+		for _, delivery := range r.deliveries.List() {
+			expiresAt := delivery.DeliveredAt.Add(r.deliveries.ttl)
+			entries = append(entries, DedupeEntry{
+				Key:       delivery.Key,
+				State:     DedupeStateDelivered,
+				Since:     delivery.DeliveredAt,
+				ExpiresAt: &expiresAt,
+				TxHash:    delivery.TxHash,
+			})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Since.After(entries[j].Since) })
+	return entries
+}
+
+// ForgetVAA drops the VAA with key from the processed VAAs and the delivery cache, so the next
+// replay from the spy is relayed again. An in-flight VAA is left alone. It reports whether the
+// VAA was found.
+func (r *Relayer) ForgetVAA(key string) (bool, error) {
+	r.dedupeMu.Lock()
+	_, found := r.processedVAAs[key]
+	delete(r.processedVAAs, key)
+	r.dedupeMu.Unlock()
+
+	if r.deliveries != nil {
+		delivered, err := r.deliveries.Forget(key)
+		if err != nil {
+			return found, err
+		}
+		found = found || delivered
+	}
+	if found {
+		r.logger.Warn("Forgot VAA; a replay will be relayed again", zap.String("vaaHash", key))
+	}
+	return found, nil
+}
+
+// ResetDedupe drops every processed VAA and clears the delivery cache, returning how many entries
+// were dropped. In-flight VAAs are left alone.
+func (r *Relayer) ResetDedupe() (int, error) {
+	r.dedupeMu.Lock()
+	cleared := len(r.processedVAAs)
+	r.processedVAAs = make(map[string]time.Time)
+	r.dedupeMu.Unlock()
+
+	if r.deliveries != nil {
+		delivered, err := r.deliveries.Clear()
+		cleared += delivered
+		if err != nil {
+			return cleared, err
+		}
+	}
+	r.logger.Warn("Reset dedup state; replays will be relayed again", zap.Int("cleared", cleared))
+	return cleared, nil
+}
+
+// DedupeHandler serves the dedup state for incident response:
+//   - GET /dedupe lists the VAAs replays of which are skipped (see DedupeEntries)
+//   - DELETE /dedupe/{key} forgets one VAA, so its next replay is relayed again
+//   - DELETE /dedupe forgets every processed and delivered VAA
+//
+// Every request must carry "Authorization: Bearer <token>"; with an empty token, all are refused.
+func (r *Relayer) DedupeHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /dedupe", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, http.StatusOK, r.DedupeEntries())
+	})
+	mux.HandleFunc("DELETE /dedupe/{key}", func(w http.ResponseWriter, req *http.Request) {
+		key := strings.ToLower(req.PathValue("key"))
+		found, err := r.ForgetVAA(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.Error(w, "VAA not in the dedup state", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"forgot": key})
+	})
+	mux.HandleFunc("DELETE /dedupe", func(w http.ResponseWriter, req *http.Request) {
+		cleared, err := r.ResetDedupe()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int{"cleared": cleared})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		presented, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, req)
+	})
+}
+
+// writeJSON writes body as a JSON response with status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestDedupeHandler(t *testing.T) {
+	relayer, _ := NewRelayer(zap.NewNop(), nil, nil)
+	cache, err := OpenDeliveryCache(filepath.Join(t.TempDir(), "deliveries.jsonl"), 10, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	defer cache.Close()
+	relayer.SetDeliveryCache(cache)
+
+	relayer.beginProcessingVAA("inflight")
+	relayer.beginProcessingVAA("processed")
+	relayer.finishProcessingVAA("processed", true)
+	if err := cache.Record(Delivery{Key: "delivered", TxHash: "0xabc", DeliveredAt: time.Now()}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	handler := relayer.DedupeHandler("secret")
+	serve := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		if code := serve(http.MethodGet, "/dedupe", token).Code; code != http.StatusUnauthorized {
+			t.Errorf("expected 401 with token %q, got %d", token, code)
+		}
+	}
+
+	var entries []DedupeEntry
+	if err := json.Unmarshal(serve(http.MethodGet, "/dedupe", "secret").Body.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode entries: %v", err)
+	}
+	states := make(map[string]string)
+	for _, entry := range entries {
+		states[entry.Key] = entry.State
+	}
+	want := map[string]string{"inflight": DedupeStateInflight, "processed": DedupeStateProcessed, "delivered": DedupeStateDelivered}
+	if len(states) != len(want) {
+		t.Fatalf("expected %v, got %+v", want, entries)
+	}
+	for key, state := range want {
+		if states[key] != state {
+			t.Errorf("expected %s to be %s, got %q", key, state, states[key])
+		}
+	}
+
+	// Forgetting a delivered VAA lets its next replay through
+	if code := serve(http.MethodDelete, "/dedupe/delivered", "secret").Code; code != http.StatusOK {
+		t.Fatalf("expected 200 forgetting a delivered VAA, got %d", code)
+	}
+	if !relayer.beginProcessingVAA("delivered") {
+		t.Error("expected a forgotten VAA to be processed again")
+	}
+	if code := serve(http.MethodDelete, "/dedupe/unknown", "secret").Code; code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown VAA, got %d", code)
+	}
+
+	// Resetting drops processed VAAs but leaves in-flight ones alone
+	recorder := serve(http.MethodDelete, "/dedupe", "secret")
+	var reset map[string]int
+	if err := json.Unmarshal(recorder.Body.Bytes(), &reset); err != nil || reset["cleared"] != 1 {
+		t.Fatalf("expected 1 entry cleared, got %s (err %v)", recorder.Body, err)
+	}
+	if !relayer.beginProcessingVAA("processed") {
+		t.Error("expected a reset VAA to be processed again")
+	}
+	if relayer.beginProcessingVAA("inflight") {
+		t.Error("expected an in-flight VAA to stay in flight after a reset")
+	}
+
+	if code := serve(http.MethodPost, "/dedupe", "secret").Code; code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", code)
+	}
+}
@@ -0,0 +1,278 @@
+package internal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Delivery cache defaults, used when no size or TTL is configured
+const (
+	DefaultDeliveryCacheSize = 10000
+	DefaultDeliveryCacheTTL  = 24 * time.Hour
+)
+
+// Delivery is a VAA the relayer delivered, as persisted in the delivery cache
+type Delivery struct {
+	Key         string    `json:"key"` // VAA identity, as used for deduplication
+	TxHash      string    `json:"txHash"`
+	DeliveredAt time.Time `json:"deliveredAt"`
+	VAA         []byte    `json:"vaa"` // Raw VAA, so an operator can inspect or re-submit it
+}
+
+// DeliveryCache persists the VAAs the relayer has delivered, so a restarted relayer recognizes
+// replays from the spy without asking the destination chain. Entries expire after the TTL and
+// the oldest are evicted beyond the size. It is safe for concurrent use.
+//
+// The file is an append-only log of one JSON delivery per line, compacted when the cache is
+// opened and whenever it holds twice as many lines as the size. Lines that cannot be decoded,
+// such as a write torn by a crash, are dropped.
+type DeliveryCache struct {
+	mu      sync.Mutex
+	path    string
+	size    int
+	ttl     time.Duration
+	entries map[string]Delivery
+	file    *os.File
+	lines   int // Lines in the file, including expired and evicted deliveries
+}
+
+// OpenDeliveryCache loads the delivery cache at path, creating it if needed. A size or TTL of
+// zero or less uses DefaultDeliveryCacheSize or DefaultDeliveryCacheTTL.
+func OpenDeliveryCache(path string, size int, ttl time.Duration) (*DeliveryCache, error) {
+	if size <= 0 {
+		size = DefaultDeliveryCacheSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultDeliveryCacheTTL
+	}
+
+	c := &DeliveryCache{
+		path:    path,
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]Delivery),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	if err := c.compact(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// load reads the unexpired deliveries from the cache file, if it exists
+func (c *DeliveryCache) load() error {
+	file, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open delivery cache: %v", err)
+	}
+	defer file.Close()
+
+	cutoff := time.Now().Add(-c.ttl)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var delivery Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &delivery); err != nil || delivery.Key == "" {
+			continue
+		}
+		if delivery.DeliveredAt.After(cutoff) {
+			c.entries[delivery.Key] = delivery
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read delivery cache: %v", err)
+	}
+	c.evict()
+	return nil
+}
+
+// compact rewrites the cache file with the current deliveries, oldest first, and reopens it
+// for appending. The file is replaced atomically, so a crash keeps either log intact.
+func (c *DeliveryCache) compact() error {
+	deliveries := make([]Delivery, 0, len(c.entries))
+	for _, delivery := range c.entries {
+		deliveries = append(deliveries, delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].DeliveredAt.Before(deliveries[j].DeliveredAt)
+	})
+
+	tmpPath := c.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("compact delivery cache: %v", err)
+	}
+	buf := bufio.NewWriter(tmp)
+	for _, delivery := range deliveries {
+		line, err := json.Marshal(delivery)
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+	if err := buf.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact delivery cache: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("compact delivery cache: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("compact delivery cache: %v", err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("compact delivery cache: %v", err)
+	}
+
+	if c.file != nil {
+		c.file.Close()
+	}
+	c.file, err = os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open delivery cache: %v", err)
+	}
+	c.lines = len(deliveries)
+	return nil
+}
+
+// evict drops the oldest deliveries until at most size remain
+func (c *DeliveryCache) evict() {
+	for len(c.entries) > c.size {
+		var oldest Delivery
+		for _, delivery := range c.entries {
+			if oldest.Key == "" || delivery.DeliveredAt.Before(oldest.DeliveredAt) {
+				oldest = delivery
+			}
+		}
+		delete(c.entries, oldest.Key)
+	}
+}
+
+// Lookup returns the delivery of the VAA with key, if it was delivered within the TTL
+func (c *DeliveryCache) Lookup(key string) (Delivery, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delivery, ok := c.entries[key]
+	if !ok {
+		return Delivery{}, false
+	}
+	if time.Since(delivery.DeliveredAt) >= c.ttl {
+		delete(c.entries, key)
+		return Delivery{}, false
+	}
+	return delivery, true
+}
+
+// Record persists delivery, compacting the file once it has grown to twice the size
+func (c *DeliveryCache) Record(delivery Delivery) error {
+	line, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return fmt.Errorf("delivery cache is closed")
+	}
+	if _, err := c.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write delivery cache: %v", err)
+	}
+	c.entries[delivery.Key] = delivery
+	c.lines++
+	c.evict()
+
+	if c.lines >= 2*c.size {
+		// Drop expired deliveries so they are not carried into the compacted file
+		cutoff := time.Now().Add(-c.ttl)
+		for key, delivery := range c.entries {
+			if !delivery.DeliveredAt.After(cutoff) {
+				delete(c.entries, key)
+			}
+		}
+		return c.compact()
+	}
+	return nil
+}
+
+// List returns the unexpired deliveries, newest first
+func (c *DeliveryCache) List() []Delivery {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cutoff := time.Now().Add(-c.ttl)
+	deliveries := make([]Delivery, 0, len(c.entries))
+	for _, delivery := range c.entries {
+		if delivery.DeliveredAt.After(cutoff) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].DeliveredAt.After(deliveries[j].DeliveredAt)
+	})
+	return deliveries
+}
+
+// Forget drops the delivery of the VAA with key, so a replay of it is delivered again, and
+// rewrites the file without it. It reports whether the VAA was in the cache.
+func (c *DeliveryCache) Forget(key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok {
+		return false, nil
+	}
+	if c.file == nil {
+		return false, fmt.Errorf("delivery cache is closed")
+	}
+	delete(c.entries, key)
+	return true, c.compact()
+}
+
+// Clear drops every delivery and truncates the file, returning how many deliveries were dropped
+func (c *DeliveryCache) Clear() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return 0, fmt.Errorf("delivery cache is closed")
+	}
+	cleared := len(c.entries)
+	c.entries = make(map[string]Delivery)
+	return cleared, c.compact()
+}
+
+// Len returns the number of deliveries held, including any that expired since the last compaction
+func (c *DeliveryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Close closes the cache file. Deliveries are written as they are recorded, so nothing is lost.
+func (c *DeliveryCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
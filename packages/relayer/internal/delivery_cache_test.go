@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeliveryCachePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	cache, err := OpenDeliveryCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	if err := cache.Record(Delivery{Key: "a", TxHash: "0xaaa", DeliveredAt: time.Now(), VAA: []byte("vaa")}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// A torn write from a crash is dropped when the cache is reopened
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("open cache file: %v", err)
+	}
+	file.WriteString(`{"key":"b","txHash":"0x`)
+	file.Close()
+
+	cache, err = OpenDeliveryCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer cache.Close()
+
+	delivery, ok := cache.Lookup("a")
+	if !ok || delivery.TxHash != "0xaaa" || string(delivery.VAA) != "vaa" {
+		t.Fatalf("expected the delivery to survive a restart, got %+v (found %v)", delivery, ok)
+	}
+	if _, ok := cache.Lookup("b"); ok {
+		t.Error("expected the torn delivery to be dropped")
+	}
+}
+
+func TestDeliveryCacheExpiresEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	var lines []string
+	for key, age := range map[string]time.Duration{"old": 2 * time.Hour, "new": time.Minute} {
+		line, _ := json.Marshal(Delivery{Key: key, TxHash: "0x" + key, DeliveredAt: time.Now().Add(-age)})
+		lines = append(lines, string(line))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatalf("write cache file: %v", err)
+	}
+
+	cache, err := OpenDeliveryCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	defer cache.Close()
+
+	if _, ok := cache.Lookup("old"); ok {
+		t.Error("expected the delivery older than the TTL to be dropped")
+	}
+	if _, ok := cache.Lookup("new"); !ok {
+		t.Error("expected the recent delivery to be kept")
+	}
+}
+
+func TestDeliveryCacheEvictsAndCompacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	cache, err := OpenDeliveryCache(path, 3, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	defer cache.Close()
+
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("vaa-%d", i)
+		if err := cache.Record(Delivery{Key: key, TxHash: "0x1", DeliveredAt: start.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("Record %d failed: %v", i, err)
+		}
+	}
+
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 deliveries, got %d", cache.Len())
+	}
+	if _, ok := cache.Lookup("vaa-6"); ok {
+		t.Error("expected the oldest deliveries to be evicted")
+	}
+	if _, ok := cache.Lookup("vaa-9"); !ok {
+		t.Error("expected the newest delivery to be kept")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cache file: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines >= 6 {
+		t.Errorf("expected the file to be compacted below twice the size, got %d lines", lines)
+	}
+}
+
+func TestDeliveryCacheForgetAndClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	cache, err := OpenDeliveryCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	now := time.Now()
+	for i, key := range []string{"a", "b", "c"} {
+		if err := cache.Record(Delivery{Key: key, DeliveredAt: now.Add(time.Duration(i) * time.Second)}); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if list := cache.List(); len(list) != 3 || list[0].Key != "c" {
+		t.Fatalf("expected 3 deliveries newest first, got %+v", list)
+	}
+
+	if forgot, err := cache.Forget("b"); err != nil || !forgot {
+		t.Fatalf("expected b to be forgotten, got %v (err %v)", forgot, err)
+	}
+	if forgot, _ := cache.Forget("b"); forgot {
+		t.Error("expected forgetting b twice to find nothing")
+	}
+	cache.Close()
+
+	// The forgotten delivery stays forgotten across a restart
+	cache, err = OpenDeliveryCache(path, 10, time.Hour)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer cache.Close()
+	if _, ok := cache.Lookup("b"); ok || cache.Len() != 2 {
+		t.Fatalf("expected only a and c after a restart, got %+v", cache.List())
+	}
+
+	if cleared, err := cache.Clear(); err != nil || cleared != 2 || cache.Len() != 0 {
+		t.Errorf("expected 2 deliveries cleared, got %d (err %v), %d left", cleared, err, cache.Len())
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EmitterFilter decides which emitters' VAAs are relayed. It combines the --emitter-address
+// flag with allowlist and denylist files; the denylist takes precedence over the allowlist,
+// and an empty allowlist accepts every emitter that is not denied.
+// The files can be reloaded while the relayer runs; it is safe for concurrent use.
+type EmitterFilter struct {
+	emitterAddress string // Normalized --emitter-address, always allowed unless denied
+	allowlistFile  string
+	denylistFile   string
+
+	mu    sync.RWMutex
+	allow map[string]struct{}
+	deny  map[string]struct{}
+}
+
+// NewEmitterFilter validates emitterAddress and loads the allowlist and denylist files.
+// Any of them may be empty.
+func NewEmitterFilter(emitterAddress, allowlistFile, denylistFile string) (*EmitterFilter, error) {
+	f := &EmitterFilter{
+		allowlistFile: allowlistFile,
+		denylistFile:  denylistFile,
+	}
+	if emitterAddress != "" {
+		addr, err := ValidateEmitterAddress(emitterAddress)
+		if err != nil {
+			return nil, err
+		}
+		f.emitterAddress = addr
+	}
+
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Reload re-reads the allowlist and denylist files. If either file is invalid the
+// previous lists are kept and an error is returned.
+func (f *EmitterFilter) Reload() error {
+	allow, err := loadEmitterList(f.allowlistFile)
+	if err != nil {
+		return fmt.Errorf("load emitter allowlist: %v", err)
+	}
+	deny, err := loadEmitterList(f.denylistFile)
+	if err != nil {
+		return fmt.Errorf("load emitter denylist: %v", err)
+	}
+	if f.emitterAddress != "" {
+		allow[f.emitterAddress] = struct{}{}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allow = allow
+	f.deny = deny
+	return nil
+}
+
+// Allows reports whether VAAs from emitter should be relayed, and if not, why
+func (f *EmitterFilter) Allows(emitter string) (bool, string) {
+	emitter = NormalizeEmitter(emitter)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if _, denied := f.deny[emitter]; denied {
+		return false, "emitter is denylisted"
+	}
+	if _, allowed := f.allow[emitter]; len(f.allow) > 0 && !allowed {
+		return false, "emitter is not allowlisted"
+	}
+	return true, ""
+}
+
+// Allowlist returns the allowlisted emitters, including the --emitter-address one, that are
+// not denylisted, sorted. It is empty when every emitter that is not denied is accepted.
+func (f *EmitterFilter) Allowlist() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	emitters := make([]string, 0, len(f.allow))
+	for emitter := range f.allow {
+		if _, denied := f.deny[emitter]; !denied {
+			emitters = append(emitters, emitter)
+		}
+	}
+	sort.Strings(emitters)
+	return emitters
+}
+
+// Counts returns the number of allowlisted and denylisted emitters
+func (f *EmitterFilter) Counts() (allowed, denied int) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return len(f.allow), len(f.deny)
+}
+
+// loadEmitterList reads one hex emitter address per line from path (empty = no list).
+// Blank lines and lines starting with # are ignored; every other line must be a valid address.
+func loadEmitterList(path string) (map[string]struct{}, error) {
+	emitters := make(map[string]struct{})
+	if path == "" {
+		return emitters, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addr, err := ValidateEmitterAddress(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNumber, err)
+		}
+		emitters[addr] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %v", path, err)
+	}
+	return emitters, nil
+}
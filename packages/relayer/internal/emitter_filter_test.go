@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+)
+
+const (
+	emitterA = "0x248EC2E5595480fF371031698ae3a4099b8dC229"
+	emitterB = "0000000000000000000000000000000000000000000000000000000000000b0b"
+	emitterC = "0x000000000000000000000000000000000000000000000000000000000000000c"
+)
+
+// writeEmitterList writes lines to a file in a test directory and returns its path
+func writeEmitterList(t *testing.T, path string, lines ...string) string {
+	t.Helper()
+	if path == "" {
+		path = filepath.Join(t.TempDir(), "emitters.txt")
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("write emitter list: %v", err)
+	}
+	return path
+}
+
+func TestEmitterFilter(t *testing.T) {
+	allowlist := writeEmitterList(t, "", "# trusted emitters", "", emitterB, "  "+emitterC+"  ")
+	denylist := writeEmitterList(t, "", emitterC)
+
+	tests := []struct {
+		name           string
+		emitterAddress string
+		allowlist      string
+		denylist       string
+		allowed        []string
+		rejected       []string
+	}{
+		{name: "no filter", allowed: []string{emitterA, emitterB}},
+		{name: "flag only", emitterAddress: emitterA, allowed: []string{emitterA}, rejected: []string{emitterB}},
+		{name: "flag merged with allowlist", emitterAddress: emitterA, allowlist: allowlist, allowed: []string{emitterA, emitterB, emitterC}},
+		{name: "denylist only", denylist: denylist, allowed: []string{emitterA, emitterB}, rejected: []string{emitterC}},
+		{name: "denylist wins over allowlist", allowlist: allowlist, denylist: denylist, allowed: []string{emitterB}, rejected: []string{emitterA, emitterC}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewEmitterFilter(tt.emitterAddress, tt.allowlist, tt.denylist)
+			if err != nil {
+				t.Fatalf("NewEmitterFilter failed: %v", err)
+			}
+			for _, emitter := range tt.allowed {
+				if ok, reason := f.Allows(emitter); !ok {
+					t.Errorf("expected %s to be allowed, got %q", emitter, reason)
+				}
+			}
+			for _, emitter := range tt.rejected {
+				if ok, _ := f.Allows(emitter); ok {
+					t.Errorf("expected %s to be rejected", emitter)
+				}
+			}
+		})
+	}
+}
+
+func TestEmitterFilterFormats(t *testing.T) {
+	solanaEmitter := "worm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth"
+	aztecEmitter := "12345678901234567890" // Decimal field element 0xab54a98ceb1f0ad2
+	allowlist := writeEmitterList(t, "", aztecEmitter)
+
+	f, err := NewEmitterFilter(solanaEmitter, allowlist, "")
+	if err != nil {
+		t.Fatalf("NewEmitterFilter failed: %v", err)
+	}
+	// VAAs carry emitters as 32 bytes, compared in hex
+	for _, emitter := range []string{
+		NormalizeEmitter(solana.MustPublicKeyFromBase58(solanaEmitter).Bytes()),
+		"0xab54a98ceb1f0ad2",
+	} {
+		if ok, reason := f.Allows(emitter); !ok {
+			t.Errorf("expected %s to be allowed, got %q", emitter, reason)
+		}
+	}
+}
+
+func TestEmitterFilterRejectsInvalidEntries(t *testing.T) {
+	list := writeEmitterList(t, "", emitterB, "0xnothex")
+	_, err := NewEmitterFilter("", list, "")
+	if err == nil {
+		t.Fatal("expected an error for an invalid entry")
+	}
+	if !strings.Contains(err.Error(), list+":2") {
+		t.Errorf("expected the error to name the file and line, got %v", err)
+	}
+
+	if _, err := NewEmitterFilter("", filepath.Join(t.TempDir(), "missing.txt"), ""); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestEmitterFilterReload(t *testing.T) {
+	denylist := writeEmitterList(t, "", emitterB)
+	f, err := NewEmitterFilter("", "", denylist)
+	if err != nil {
+		t.Fatalf("NewEmitterFilter failed: %v", err)
+	}
+	if ok, _ := f.Allows(emitterC); !ok {
+		t.Fatal("expected emitter C to be allowed before the reload")
+	}
+
+	writeEmitterList(t, denylist, emitterB, emitterC)
+	if err := f.Reload(); err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if ok, _ := f.Allows(emitterC); ok {
+		t.Fatal("expected emitter C to be denied after the reload")
+	}
+
+	// An invalid file keeps the previous lists in effect
+	writeEmitterList(t, denylist, "0xnothex")
+	if err := f.Reload(); err == nil {
+		t.Fatal("expected Reload to fail for an invalid entry")
+	}
+	if ok, _ := f.Allows(emitterC); ok {
+		t.Error("expected the previous denylist to stay in effect after a failed reload")
+	}
+}
+
+func TestEmitterFilterAllowlist(t *testing.T) {
+	allowlist := writeEmitterList(t, "", emitterC, emitterB)
+	denylist := writeEmitterList(t, "", emitterC)
+	f, err := NewEmitterFilter(emitterA, allowlist, denylist)
+	if err != nil {
+		t.Fatalf("NewEmitterFilter failed: %v", err)
+	}
+
+	// Sorted, normalized and without the denylisted emitter C
+	want := []string{NormalizeEmitter(emitterA), NormalizeEmitter(emitterB)}
+	if want[0] > want[1] {
+		want[0], want[1] = want[1], want[0]
+	}
+	if got := f.Allowlist(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected allowlist %v, got %v", want, got)
+	}
+
+	unfiltered, _ := NewEmitterFilter("", "", denylist)
+	if got := unfiltered.Allowlist(); len(got) != 0 {
+		t.Errorf("expected an empty allowlist without an emitter filter, got %v", got)
+	}
+}
@@ -0,0 +1,176 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"go.uber.org/zap"
+)
+
+// DefaultGapBackfillMaxSequences caps how many sequences of each emitter are fetched after a reconnect
+const DefaultGapBackfillMaxSequences = 100
+
+// watermark is the highest sequence seen from one emitter
+type watermark struct {
+	sequence uint64
+	seenAt   time.Time
+}
+
+// emitterWatermarks records the highest sequence seen from each monitored emitter: one with at
+// least one VAA the processor did not filter. Emitters whose VAAs are all filtered are not tracked,
+// so a busy spy does not turn every reconnect into a backfill of the whole network.
+type emitterWatermarks struct {
+	mu    sync.Mutex
+	marks map[emitterKey]watermark
+}
+
+func newEmitterWatermarks() *emitterWatermarks {
+	return &emitterWatermarks{marks: make(map[emitterKey]watermark)}
+}
+
+// observe raises the watermark of key to seq, returning the new watermark and whether it
+// changed. An untracked emitter is only tracked if monitored.
+func (w *emitterWatermarks) observe(key emitterKey, seq uint64, monitored bool) (watermark, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	mark, tracked := w.marks[key]
+	if !tracked && !monitored {
+		return watermark{}, false
+	}
+	if !tracked || seq >= mark.sequence {
+		mark = watermark{sequence: seq, seenAt: time.Now()}
+		w.marks[key] = mark
+		return mark, true
+	}
+	return mark, false
+}
+
+// restore tracks key from a watermark recorded by an earlier run, unless it is already further
+func (w *emitterWatermarks) restore(key emitterKey, mark watermark) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if current, tracked := w.marks[key]; !tracked || mark.sequence > current.sequence {
+		w.marks[key] = mark
+	}
+}
+
+// snapshot returns a copy of the current watermarks
+func (w *emitterWatermarks) snapshot() map[emitterKey]watermark {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	marks := make(map[emitterKey]watermark, len(w.marks))
+	for key, mark := range w.marks {
+		marks[key] = mark
+	}
+	return marks
+}
+
+// backfillGap relays the VAAs each emitter in marks produced after its watermark, fetching them
+// from r.gapFetcher in sequence order until one is not found yet or the per-emitter cap is hit.
+// VAAs the new stream already delivered are skipped by the usual deduplication. Backfills run
+// one at a time, so a flapping stream cannot start a storm of them. reason says what the gap
+// was, for the log, and since when.
+func (r *Relayer) backfillGap(ctx context.Context, marks map[emitterKey]watermark, reason string, since time.Time) {
+	r.gapMu.Lock()
+	defer r.gapMu.Unlock()
+
+	r.logger.Info("Backfilling VAAs missed "+reason,
+		zap.Int("emitters", len(marks)),
+		zap.Duration("gap", time.Since(since)),
+		zap.Int("maxSequencesPerEmitter", r.gapMaxSequences))
+
+	for key, mark := range marks {
+		if ctx.Err() != nil {
+			return
+		}
+		recovered := r.backfillEmitterGap(ctx, key, mark)
+		r.logger.Info("Backfilled emitter gap",
+			zap.Stringer("emitter", key),
+			zap.Uint64("lastSeenSequence", mark.sequence),
+			zap.Time("lastSeenAt", mark.seenAt),
+			zap.Int("relayed", recovered))
+	}
+}
+
+// backfillEmitterGap fetches and relays the sequences after mark for one emitter, returning how
+// many were handed to the processor
+func (r *Relayer) backfillEmitterGap(ctx context.Context, key emitterKey, mark watermark) int {
+	relayed := 0
+	for i := 0; i < r.gapMaxSequences; i++ {
+		seq := mark.sequence + uint64(i) + 1
+		if seq == 0 {
+			return relayed // The watermark was the largest sequence
+		}
+		if ctx.Err() != nil {
+			return relayed
+		}
+
+		vaaBytes, err := r.gapFetcher.GetVAA(ctx, key.chainID, key.emitter, seq)
+		if errors.Is(err, clients.ErrVAANotFound) {
+			return relayed // Caught up with the emitter
+		}
+		if err != nil {
+			r.logger.Warn("Failed to fetch VAA for gap backfill; later sequences are not recovered",
+				zap.Stringer("vaa", NewVAAIdentity(key.chainID, key.emitter, seq)),
+				zap.Error(err))
+			return relayed
+		}
+		if _, err := parseFetchedVAA(vaaBytes, key.chainID, key.emitter, seq); err != nil {
+			r.logger.Warn("Discarding VAA fetched for gap backfill",
+				zap.Stringer("vaa", NewVAAIdentity(key.chainID, key.emitter, seq)),
+				zap.Error(err))
+			return relayed
+		}
+
+		dedupeKey := computeVAAKey(vaaBytes)
+		if !r.beginProcessingVAA(dedupeKey) {
+			continue // Already delivered by the new stream
+		}
+		r.handleVAA(ctx, vaaBytes, dedupeKey)
+		relayed++
+	}
+
+	r.logger.Warn("Gap backfill capped; later sequences of this emitter are not recovered",
+		zap.Stringer("emitter", key),
+		zap.Uint64("nextSequence", mark.sequence+uint64(r.gapMaxSequences)+1),
+		zap.Int("maxSequences", r.gapMaxSequences))
+	return relayed
+}
+
+// resume restores the watermarks recorded by the previous run, logging where each emitter
+// resumes, and returns them. It returns nil without a sequence store.
+func (r *Relayer) resume() map[emitterKey]watermark {
+	if r.sequences == nil {
+		return nil
+	}
+	marks := r.sequences.watermarks()
+	if len(marks) == 0 {
+		r.logger.Info("No emitter sequences recorded yet; starting from the live stream")
+		return nil
+	}
+	for key, mark := range marks {
+		r.watermarks.restore(key, mark)
+		r.logger.Info("Resuming emitter",
+			zap.Stringer("emitter", key),
+			zap.Uint64("lastProcessedSequence", mark.sequence),
+			zap.Time("lastSeenAt", mark.seenAt))
+	}
+	return marks
+}
+
+// latestSeenAt returns when the most recently seen emitter in marks was last seen
+func latestSeenAt(marks map[emitterKey]watermark) time.Time {
+	var latest time.Time
+	for _, mark := range marks {
+		if mark.seenAt.After(latest) {
+			latest = mark.seenAt
+		}
+	}
+	return latest
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestEmitterWatermarks(t *testing.T) {
+	w := newEmitterWatermarks()
+	monitored := emitterKey{chainID: 2, emitter: "aa"}
+	other := emitterKey{chainID: 2, emitter: "bb"}
+
+	w.observe(other, 9, false)
+	w.observe(monitored, 5, true)
+	w.observe(monitored, 7, false) // Filtered VAAs still advance a tracked emitter
+	w.observe(monitored, 6, true)  // Out-of-order VAAs do not move it back
+
+	marks := w.snapshot()
+	if len(marks) != 1 || marks[monitored].sequence != 7 {
+		t.Errorf("expected only the monitored emitter at sequence 7, got %+v", marks)
+	}
+}
+
+func TestBackfillGap(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xcd
+	key := emitterKey{chainID: 2, emitter: NormalizeEmitter(emitter[:])}
+	fetcher := &fakeFetcher{vaas: make(map[uint64][]byte)}
+	for seq := uint64(3); seq <= 20; seq++ {
+		fetcher.vaas[seq] = buildV1VAA(1, 2, emitter, seq, destinationPayload(10003))
+	}
+
+	newRelayer := func(maxSequences int) (*Relayer, *sequenceSubmitter) {
+		s := &sequenceSubmitter{}
+		p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+		if err != nil {
+			t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+		}
+		r, _ := NewRelayer(zap.NewNop(), nil, p)
+		r.SetGapBackfill(fetcher, maxSequences)
+		return r, s
+	}
+
+	t.Run("relays until caught up", func(t *testing.T) {
+		r, s := newRelayer(0)
+		// Sequence 18 already arrived on the new stream
+		r.finishProcessingVAA(computeVAAKey(fetcher.vaas[18]), true)
+
+		r.backfillGap(context.Background(), map[emitterKey]watermark{key: {sequence: 15}}, "in a test", time.Now())
+		if want := []uint64{16, 17, 19, 20}; !reflect.DeepEqual(s.submitted, want) {
+			t.Errorf("expected sequences %v to be relayed, got %v", want, s.submitted)
+		}
+		if marks := r.watermarks.snapshot(); marks[key].sequence != 20 {
+			t.Errorf("expected the watermark to advance to 20, got %+v", marks[key])
+		}
+	})
+
+	t.Run("stops at the cap", func(t *testing.T) {
+		r, s := newRelayer(3)
+		r.backfillGap(context.Background(), map[emitterKey]watermark{key: {sequence: 2}}, "in a test", time.Now())
+		if want := []uint64{3, 4, 5}; !reflect.DeepEqual(s.submitted, want) {
+			t.Errorf("expected sequences %v to be relayed, got %v", want, s.submitted)
+		}
+	})
+}
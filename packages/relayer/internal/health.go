@@ -0,0 +1,251 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
+)
+
+// DefaultHealthCheckTimeout bounds a single health check when no timeout is configured
+const DefaultHealthCheckTimeout = 10 * time.Second
+
+// HealthCheck is a named check of one dependency, returning an error while it is unhealthy
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthMonitorConfig holds the settings of a HealthMonitor
+type HealthMonitorConfig struct {
+	Interval   time.Duration // Time between rounds of checks
+	Timeout    time.Duration // Deadline of each check (0 = the interval, capped at DefaultHealthCheckTimeout)
+	PauseAfter time.Duration // Pause submissions once a check has failed for this long (0 = never pause)
+}
+
+// DependencyHealth is the latest state of one checked dependency
+type DependencyHealth struct {
+	Name      string    `json:"name"`
+	Healthy   bool      `json:"healthy"`
+	Since     time.Time `json:"since"` // When the dependency last became healthy or unhealthy
+	CheckedAt time.Time `json:"checkedAt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// HealthMonitor runs health checks of the relayer's dependencies periodically, logging each
+// change of state. It serves the result as a readiness endpoint and, when configured, pauses
+// submissions while a dependency stays unhealthy, so VAAs wait for it to recover instead of
+// each failing against it.
+type HealthMonitor struct {
+	checks []HealthCheck
+	config HealthMonitorConfig
+	logger *zap.Logger
+
+	mu      sync.Mutex
+	states  []DependencyHealth // By check, in order; zero CheckedAt until first checked
+	paused  bool
+	resumed chan struct{} // Closed when a pause ends
+	now     func() time.Time
+}
+
+// NewHealthMonitor creates a monitor of checks. It reports not ready until Run has checked
+// every dependency once.
+func NewHealthMonitor(logger *zap.Logger, checks []HealthCheck, config HealthMonitorConfig) *HealthMonitor {
+	if config.Timeout <= 0 {
+		config.Timeout = config.Interval
+		if config.Timeout <= 0 || config.Timeout > DefaultHealthCheckTimeout {
+			config.Timeout = DefaultHealthCheckTimeout
+		}
+	}
+	states := make([]DependencyHealth, len(checks))
+	for i, check := range checks {
+		states[i].Name = check.Name
+	}
+	return &HealthMonitor{
+		checks: checks,
+		config: config,
+		logger: logger.With(zap.String("component", "HealthMonitor")),
+		states: states,
+		now:    time.Now,
+	}
+}
+
+// Run checks every dependency at once and then every interval, until ctx is done
+func (m *HealthMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		m.CheckAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll runs one round of checks concurrently, each with its own deadline, and updates
+// the state of every dependency
+func (m *HealthMonitor) CheckAll(ctx context.Context) {
+	errs := make([]error, len(m.checks))
+	var wg sync.WaitGroup
+	for i, check := range m.checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+			defer cancel()
+			errs[i] = check.Check(checkCtx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	// A round cut short by shutdown says nothing about the dependencies
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	for i, err := range errs {
+		m.record(&m.states[i], err, now)
+	}
+	m.updatePause(now)
+}
+
+// record updates state with the outcome of a check, logging a change of state
+func (m *HealthMonitor) record(state *DependencyHealth, err error, now time.Time) {
+	first := state.CheckedAt.IsZero()
+	healthy := err == nil
+	state.CheckedAt = now
+	state.Error = ""
+	if err != nil {
+		state.Error = err.Error()
+	}
+	if healthy {
+		metrics.DependencyHealthy.WithLabelValues(state.Name).Set(1)
+	} else {
+		metrics.DependencyHealthy.WithLabelValues(state.Name).Set(0)
+	}
+	if !first && healthy == state.Healthy {
+		return
+	}
+
+	unhealthyFor := now.Sub(state.Since)
+	state.Healthy, state.Since = healthy, now
+	switch {
+	case !healthy:
+		m.logger.Warn("Dependency unhealthy", zap.String("dependency", state.Name), zap.Error(err))
+	case !first:
+		m.logger.Info("Dependency recovered", zap.String("dependency", state.Name), zap.Duration("unhealthyFor", unhealthyFor))
+	default:
+		m.logger.Info("Dependency healthy", zap.String("dependency", state.Name))
+	}
+}
+
+// updatePause pauses submissions once a dependency has been unhealthy for PauseAfter, and
+// resumes them once every dependency is healthy again
+func (m *HealthMonitor) updatePause(now time.Time) {
+	if m.config.PauseAfter <= 0 {
+		return
+	}
+
+	var failing []string
+	pause := false
+	for _, state := range m.states {
+		if !state.Healthy {
+			failing = append(failing, state.Name)
+			pause = pause || now.Sub(state.Since) >= m.config.PauseAfter
+		}
+	}
+
+	switch {
+	case pause && !m.paused:
+		m.paused = true
+		m.resumed = make(chan struct{})
+		m.logger.Warn("Pausing submissions until dependencies recover",
+			zap.Strings("unhealthy", failing),
+			zap.Duration("pauseAfter", m.config.PauseAfter))
+	case m.paused && len(failing) == 0:
+		m.paused = false
+		close(m.resumed)
+		m.logger.Info("Dependencies recovered, resuming submissions")
+	}
+}
+
+// Ready reports whether every dependency has been checked and passed its latest check
+func (m *HealthMonitor) Ready() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.readyLocked()
+}
+
+func (m *HealthMonitor) readyLocked() bool {
+	for _, state := range m.states {
+		if state.CheckedAt.IsZero() || !state.Healthy {
+			return false
+		}
+	}
+	return true
+}
+
+// Paused reports whether submissions are paused for an unhealthy dependency
+func (m *HealthMonitor) Paused() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.paused
+}
+
+// WaitHealthy blocks while submissions are paused, returning ctx's error if it is done first
+func (m *HealthMonitor) WaitHealthy(ctx context.Context) error {
+	m.mu.Lock()
+	paused, resumed := m.paused, m.resumed
+	m.mu.Unlock()
+	if !paused {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// States returns the latest state of every dependency, in check order
+func (m *HealthMonitor) States() []DependencyHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DependencyHealth(nil), m.states...)
+}
+
+// ServeHTTP writes the state of every dependency as JSON, with status 503 unless all are ready
+func (m *HealthMonitor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	m.mu.Lock()
+	ready := m.readyLocked()
+	body := struct {
+		Ready        bool               `json:"ready"`
+		Paused       bool               `json:"paused"`
+		Dependencies []DependencyHealth `json:"dependencies"`
+	}{ready, m.paused, append([]DependencyHealth(nil), m.states...)}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeDependency is a dependency whose health is set by the test
+type fakeDependency struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (d *fakeDependency) set(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = err
+}
+
+func (d *fakeDependency) check(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.err
+}
+
+func TestHealthMonitorReadiness(t *testing.T) {
+	spy, rpc := &fakeDependency{}, &fakeDependency{}
+	monitor := NewHealthMonitor(zap.NewNop(), []HealthCheck{
+		{Name: "spy", Check: spy.check},
+		{Name: "rpc", Check: rpc.check},
+	}, HealthMonitorConfig{Interval: time.Minute})
+
+	serve := func() (int, map[string]interface{}) {
+		rec := httptest.NewRecorder()
+		monitor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var body map[string]interface{}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return rec.Code, body
+	}
+
+	if monitor.Ready() {
+		t.Fatal("expected the monitor not to be ready before the first check")
+	}
+	if code, _ := serve(); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before the first check, got %d", code)
+	}
+
+	monitor.CheckAll(context.Background())
+	if !monitor.Ready() {
+		t.Fatal("expected the monitor to be ready once every check passed")
+	}
+	if code, body := serve(); code != http.StatusOK || body["ready"] != true {
+		t.Fatalf("expected 200 and ready, got %d with %v", code, body)
+	}
+
+	rpc.set(errors.New("connection refused"))
+	monitor.CheckAll(context.Background())
+	if monitor.Ready() {
+		t.Fatal("expected the monitor not to be ready while a check fails")
+	}
+	states := monitor.States()
+	if !states[0].Healthy || states[1].Healthy || states[1].Error != "connection refused" {
+		t.Fatalf("expected only rpc to be unhealthy, got %+v", states)
+	}
+	code, body := serve()
+	if code != http.StatusServiceUnavailable || body["ready"] != false {
+		t.Fatalf("expected 503 and not ready, got %d with %v", code, body)
+	}
+
+	rpc.set(nil)
+	monitor.CheckAll(context.Background())
+	if !monitor.Ready() {
+		t.Fatal("expected the monitor to be ready again once rpc recovered")
+	}
+
+	// A round cut short by shutdown keeps the previous state
+	rpc.set(errors.New("connection refused"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	monitor.CheckAll(ctx)
+	if !monitor.Ready() {
+		t.Fatal("expected a cancelled round not to change the state")
+	}
+}
+
+func TestHealthMonitorCheckTimeout(t *testing.T) {
+	monitor := NewHealthMonitor(zap.NewNop(), []HealthCheck{
+		{Name: "hung", Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	}, HealthMonitorConfig{Interval: time.Minute, Timeout: 10 * time.Millisecond})
+
+	monitor.CheckAll(context.Background())
+	if states := monitor.States(); states[0].Healthy || states[0].Error == "" {
+		t.Fatalf("expected a check past its deadline to fail, got %+v", states[0])
+	}
+}
+
+func TestHealthMonitorPause(t *testing.T) {
+	rpc := &fakeDependency{}
+	monitor := NewHealthMonitor(zap.NewNop(), []HealthCheck{{Name: "rpc", Check: rpc.check}},
+		HealthMonitorConfig{Interval: time.Second, PauseAfter: time.Minute})
+	now := time.Unix(1700000000, 0)
+	monitor.now = func() time.Time { return now }
+
+	rpc.set(errors.New("down"))
+	monitor.CheckAll(context.Background())
+	if monitor.Paused() {
+		t.Fatal("expected no pause before the dependency was unhealthy for PauseAfter")
+	}
+	if err := monitor.WaitHealthy(context.Background()); err != nil {
+		t.Fatalf("expected WaitHealthy to return at once while not paused, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	monitor.CheckAll(context.Background())
+	if !monitor.Paused() {
+		t.Fatal("expected submissions to be paused after PauseAfter")
+	}
+
+	// WaitHealthy gives up when its context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := monitor.WaitHealthy(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected WaitHealthy to return the context error while paused, got %v", err)
+	}
+
+	// And returns once the dependency recovers
+	waited := make(chan error, 1)
+	go func() { waited <- monitor.WaitHealthy(context.Background()) }()
+	rpc.set(nil)
+	now = now.Add(time.Second)
+	monitor.CheckAll(context.Background())
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatalf("expected WaitHealthy to succeed after recovery, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected WaitHealthy to return once the dependency recovered")
+	}
+	if monitor.Paused() {
+		t.Fatal("expected submissions to resume once every dependency is healthy")
+	}
+}
+
+func TestHealthMonitorRun(t *testing.T) {
+	var mu sync.Mutex
+	checks := 0
+	monitor := NewHealthMonitor(zap.NewNop(), []HealthCheck{{Name: "spy", Check: func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+		checks++
+		return nil
+	}}}, HealthMonitorConfig{Interval: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.After(time.Second)
+	for {
+		mu.Lock()
+		n := checks
+		mu.Unlock()
+		if n >= 3 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("expected repeated checks, got %d", n)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+	if !monitor.Ready() {
+		t.Fatal("expected the monitor to be ready after passing checks")
+	}
+}
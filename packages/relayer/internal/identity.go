@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+)
+
+// VAAIdentity identifies a VAA by its Wormhole message ID: the emitter chain, the emitter
+// address and the sequence. Unlike a hash of the VAA bytes it is the same for every copy of
+// the VAA, whatever guardian signatures it carries, and matches what Wormholescan and other
+// tools use, so it is what logs and records use to name a VAA.
+type VAAIdentity struct {
+	ChainID  uint16
+	Emitter  string // Canonical emitter address (see NormalizeEmitter)
+	Sequence uint64
+}
+
+// NewVAAIdentity returns the identity of the VAA with sequence from emitter on chainID,
+// normalizing the emitter address
+func NewVAAIdentity(chainID uint16, emitter string, sequence uint64) VAAIdentity {
+	return VAAIdentity{ChainID: chainID, Emitter: NormalizeEmitter(emitter), Sequence: sequence}
+}
+
+// String returns the identity as chain/emitter/sequence, e.g. 56/0000...abcd/42
+func (id VAAIdentity) String() string {
+	return fmt.Sprintf("%d/%s/%d", id.ChainID, id.Emitter, id.Sequence)
+}
+
+// EmitterString returns the emitter part of the identity as chain/emitter
+func (id VAAIdentity) EmitterString() string {
+	return emitterKey{chainID: id.ChainID, emitter: id.Emitter}.String()
+}
+
+// NativeEmitter returns the emitter of the identity in its source chain's own format (see FormatEmitter)
+func (id VAAIdentity) NativeEmitter() string {
+	return FormatEmitter(id.ChainID, id.Emitter)
+}
+
+// evmChainIDs are the Wormhole chain IDs of EVM chains, whose emitters are 20-byte addresses
+// left-padded to 32 bytes
+var evmChainIDs = map[uint16]bool{
+	2: true, 4: true, 5: true, 6: true, 10: true, 13: true, 14: true, 16: true, // Ethereum, BSC, Polygon, Avalanche, Fantom, Klaytn, Celo, Moonbeam
+	23: true, 24: true, 25: true, 30: true, 34: true, 35: true, 36: true, 38: true, // Arbitrum, Optimism, Gnosis, Base, Scroll, Mantle, Blast, Linea
+	10002: true, 10003: true, 10004: true, 10005: true, 10006: true, 10007: true, // Sepolia, Arbitrum/Base/Optimism Sepolia, Holesky, Polygon Sepolia
+}
+
+// FormatEmitter renders a canonical emitter address (see NormalizeEmitter) the way the source
+// chain's own tools show it: a checksummed 0x address on EVM chains, base58 on Solana (1) and a
+// 0x-prefixed field element on Aztec (54, 56). Other chains, and EVM emitters that are not
+// left-padded addresses, keep the canonical hex, which is also what emitter filters take.
+func FormatEmitter(chainID uint16, emitter string) string {
+	raw, err := hex.DecodeString(emitter)
+	if err != nil || len(raw) != 32 {
+		return emitter
+	}
+	switch {
+	case chainID == 1:
+		return solana.PublicKeyFromBytes(raw).String()
+	case chainID == 54 || chainID == 56:
+		return "0x" + emitter
+	case evmChainIDs[chainID] && strings.HasPrefix(emitter, strings.Repeat("0", 24)):
+		return common.BytesToAddress(raw[12:]).Hex()
+	}
+	return emitter
+}
+
+// ParseVAAIdentity parses an identity in the chain/emitter/sequence form returned by String.
+// The emitter may be given with a 0x prefix or as a 20-byte EVM address.
+func ParseVAAIdentity(s string) (VAAIdentity, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 {
+		return VAAIdentity{}, fmt.Errorf("invalid VAA identity %q: expected chain/emitter/sequence", s)
+	}
+	chainID, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil {
+		return VAAIdentity{}, fmt.Errorf("invalid VAA identity %q: chain ID: %v", s, err)
+	}
+	emitter, err := ValidateEmitterAddress(parts[1])
+	if err != nil {
+		return VAAIdentity{}, fmt.Errorf("invalid VAA identity %q: %v", s, err)
+	}
+	sequence, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return VAAIdentity{}, fmt.Errorf("invalid VAA identity %q: sequence: %v", s, err)
+	}
+	return VAAIdentity{ChainID: uint16(chainID), Emitter: emitter, Sequence: sequence}, nil
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+)
+
+func TestVAAIdentity(t *testing.T) {
+	emitter := strings.Repeat("0", 24) + "0d8b0cbfb8f1e1cbe1fa4f3b0d0e5e1b2c3d4e5f"
+	id := NewVAAIdentity(56, "0x0D8B0CBFB8F1E1CBE1FA4F3B0D0E5E1B2C3D4E5F", 42)
+	if want := "56/" + emitter + "/42"; id.String() != want {
+		t.Fatalf("expected %s, got %s", want, id)
+	}
+	if want := "56/" + emitter; id.EmitterString() != want {
+		t.Fatalf("expected emitter %s, got %s", want, id.EmitterString())
+	}
+
+	parsed, err := ParseVAAIdentity(id.String())
+	if err != nil {
+		t.Fatalf("ParseVAAIdentity failed: %v", err)
+	}
+	if parsed != id {
+		t.Fatalf("expected %+v to round-trip, got %+v", id, parsed)
+	}
+	if parsed, err := ParseVAAIdentity("56/0x0d8b0cbfb8f1e1cbe1fa4f3b0d0e5e1b2c3d4e5f/42"); err != nil || parsed != id {
+		t.Fatalf("expected an EVM emitter to be normalized, got %+v, %v", parsed, err)
+	}
+
+	for _, invalid := range []string{"", "56/" + emitter, "70000/" + emitter + "/1", "56/xyz/1", "56/" + emitter + "/-1", "56/" + emitter + "/1/2"} {
+		if _, err := ParseVAAIdentity(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+
+	// VAAData carries the identity of its VAA
+	var address vaaLib.Address
+	address[31] = 0xab
+	data := NewVAAData(&vaaLib.VAA{EmitterChain: 2, EmitterAddress: address, Sequence: 7}, []byte("vaa"))
+	if want := "2/" + strings.Repeat("0", 62) + "ab/7"; data.ID.String() != want {
+		t.Fatalf("expected VAAData identity %s, got %s", want, data.ID)
+	}
+}
+
+func TestFormatEmitter(t *testing.T) {
+	evm := NormalizeEmitter("0x0d8b0cbfb8f1e1cbe1fa4f3b0d0e5e1b2c3d4e5f")
+	tokenBridge := NormalizeEmitter("ec7372995d5cc8732397fb0ad35c0121e0eaa90d26f828a534cab54391b3a4f5")
+	tests := []struct {
+		name    string
+		chainID uint16
+		emitter string
+		want    string
+	}{
+		{name: "arbitrum sepolia", chainID: 10003, emitter: evm, want: "0x0d8b0cbFB8f1E1CbE1fA4F3B0d0e5e1b2c3d4E5f"},
+		{name: "solana", chainID: 1, emitter: tokenBridge, want: "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk"},
+		{name: "aztec", chainID: 56, emitter: tokenBridge, want: "0x" + tokenBridge},
+		{name: "evm chain, unpadded emitter", chainID: 2, emitter: tokenBridge, want: tokenBridge},
+		{name: "unknown chain", chainID: 3104, emitter: evm, want: evm},
+		{name: "not hex", chainID: 1, emitter: "xyz", want: "xyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatEmitter(tt.chainID, tt.emitter); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if got := NewVAAIdentity(1, tokenBridge, 7).NativeEmitter(); got != "Gv1KWf8DT1jKv5pKBmGaTmVszqa56Xn8YGx2Pg7i7qAk" {
+		t.Errorf("expected the identity's emitter in base58, got %s", got)
+	}
+}
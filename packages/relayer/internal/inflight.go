@@ -0,0 +1,33 @@
+package internal
+
+import "sync"
+
+// inflightWork tracks the goroutines Start runs VAAs and backfills in. Once drain is called it
+// starts no more, so nothing begun after the shutdown signal outlives Start.
+type inflightWork struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+}
+
+// start runs fn in a goroutine drain waits for, unless draining has begun
+func (w *inflightWork) start(fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.draining {
+		return
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn()
+	}()
+}
+
+// drain stops new goroutines from starting and waits for the running ones to finish
+func (w *inflightWork) drain() {
+	w.mu.Lock()
+	w.draining = true
+	w.mu.Unlock()
+	w.wg.Wait()
+}
@@ -0,0 +1,192 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// SubmissionPhaseDuration tracks how long each phase of a VAA submission takes,
+// labelled by destination (aztec, evm, solana, cosmos) and phase
+var SubmissionPhaseDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "submission_phase_duration_seconds",
+		Help:      "Duration of each VAA submission phase by destination",
+		// Aztec proofs can take minutes, so the buckets reach up to 15 minutes
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 900},
+	},
+	[]string{"destination", "phase"},
+)
+
+// SubmissionFailures counts failed VAA submissions, labelled by destination and
+// failure class (transient, already_processed, permanent, config)
+var SubmissionFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "submission_failures_total",
+		Help:      "Failed VAA submissions by destination and failure class",
+	},
+	[]string{"destination", "class"},
+)
+
+// RateLimitWait is how long the most recent submission waited for the rate limiter,
+// labelled by destination chain ID. It stays near zero while submissions are under the limit.
+var RateLimitWait = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time the most recent submission waited for the rate limiter by destination chain",
+	},
+	[]string{"destination_chain"},
+)
+
+// VAAsReceived counts VAAs received from the spy, including duplicates
+var VAAsReceived = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "vaas_received_total",
+		Help:      "VAAs received from the spy, including duplicates",
+	},
+)
+
+// VAALag is the time from a VAA's timestamp (its source block time) to when the relayer
+// received it from the spy (stage "received") and finished handling it (stage "processed").
+// High received lag points at the spy or guardians; lag that grows between the two stages
+// points at a processing backlog.
+var VAALag = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "vaa_lag_seconds",
+		Help:      "Time from a VAA's timestamp to when it was received and processed, by stage",
+		// Finality alone takes minutes on some chains, and backlogs can reach hours
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 10800, 43200, 86400},
+	},
+	[]string{"stage"},
+)
+
+// MalformedVAAs counts VAAs from the spy too short to parse, dropped before processing
+var MalformedVAAs = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "vaas_malformed_total",
+		Help:      "VAAs received from the spy that were empty or too short to parse",
+	},
+)
+
+// VAAsHandled counts processed VAAs, labelled by decision (submitted, already_processed, filtered, failed)
+var VAAsHandled = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "vaas_handled_total",
+		Help:      "Processed VAAs by decision",
+	},
+	[]string{"decision"},
+)
+
+// VAAsFiltered counts VAAs skipped by the processor's filters, labelled by the reason of the first
+// filter each failed (see internal.FilterReason)
+var VAAsFiltered = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "vaas_filtered_total",
+		Help:      "VAAs skipped by the processor's filters by reason",
+	},
+	[]string{"reason"},
+)
+
+// PayloadRejections counts VAAs skipped because their payload violated a validation rule, labelled by rule
+var PayloadRejections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "payload_rejections_total",
+		Help:      "VAAs skipped for failing payload validation by rule",
+	},
+	[]string{"rule"},
+)
+
+// SolanaFees accumulates the estimated fees of the Solana transactions sent, in lamports
+var SolanaFees = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "solana_fees_lamports_total",
+		Help:      "Estimated fees of the Solana transactions sent, in lamports",
+	},
+)
+
+// DependencyHealthy is 1 while the latest periodic health check of a dependency passed and 0
+// while it fails, labelled by dependency
+var DependencyHealthy = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "dependency_healthy",
+		Help:      "Whether the latest health check of each dependency passed (1) or failed (0)",
+	},
+	[]string{"dependency"},
+)
+
+// CircuitBreakerState is the state of each destination's circuit breaker, labelled by
+// destination chain ID: 0 while closed, 1 while half-open and 2 while open
+var CircuitBreakerState = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "circuit_breaker_state",
+		Help:      "State of each destination's circuit breaker (0 closed, 1 half-open, 2 open)",
+	},
+	[]string{"destination_chain"},
+)
+
+// RPCReadsInFlight is the number of RPC reads holding a slot of the read concurrency limit,
+// labelled by client (evm, solana). Only clients with a limit report it.
+var RPCReadsInFlight = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "rpc_reads_in_flight",
+		Help:      "RPC reads in flight under the read concurrency limit by client",
+	},
+	[]string{"client"},
+)
+
+// RPCReadConcurrencyLimit is the configured read concurrency limit of each client, so
+// rpc_reads_in_flight can be read as a fraction of it
+var RPCReadConcurrencyLimit = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "rpc_read_concurrency_limit",
+		Help:      "Maximum RPC reads in flight by client",
+	},
+	[]string{"client"},
+)
+
+// RPCReadWaits counts RPC reads that found the read concurrency limit saturated and had to wait
+// for a slot, labelled by client. A steady rate means the limit, not the RPC, paces the reads.
+var RPCReadWaits = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "wormhole_relayer",
+		Name:      "rpc_read_waits_total",
+		Help:      "RPC reads that waited for a slot of the saturated read concurrency limit by client",
+	},
+	[]string{"client"},
+)
+
+func init() {
+	prometheus.MustRegister(SubmissionPhaseDuration, SubmissionFailures, RateLimitWait, VAAsReceived, VAALag, MalformedVAAs, VAAsHandled, VAAsFiltered, PayloadRejections, SolanaFees, DependencyHealthy, CircuitBreakerState,
+		RPCReadsInFlight, RPCReadConcurrencyLimit, RPCReadWaits)
+}
+
+// NewServer returns an HTTP server exposing the registered metrics on /metrics,
+// plus any additional handlers keyed by path (e.g. /recent)
+func NewServer(addr string, handlers map[string]http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	for path, handler := range handlers {
+		mux.Handle(path, handler)
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
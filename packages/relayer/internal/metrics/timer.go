@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PhaseTotal is the phase label used for the end-to-end duration of a submission
+const PhaseTotal = "total"
+
+// PhaseTiming is the measured duration of a single submission phase
+type PhaseTiming struct {
+	Phase    string
+	Duration time.Duration
+}
+
+// SubmissionTimer records timed phases of a single VAA submission into
+// SubmissionPhaseDuration and logs them at debug level
+type SubmissionTimer struct {
+	destination string
+	start       time.Time
+	phases      []PhaseTiming
+	logger      *zap.Logger
+}
+
+// NewSubmissionTimer starts timing a submission to the given destination
+func NewSubmissionTimer(logger *zap.Logger, destination string) *SubmissionTimer {
+	return &SubmissionTimer{
+		destination: destination,
+		start:       time.Now(),
+		logger:      logger,
+	}
+}
+
+// StartPhase starts timing a phase and returns a function that stops it.
+// A phase may be timed more than once; each run is recorded separately.
+func (t *SubmissionTimer) StartPhase(phase string) func() {
+	start := time.Now()
+	return func() {
+		t.record(phase, time.Since(start))
+	}
+}
+
+// Finish records the end-to-end duration and logs all phase durations at debug level
+func (t *SubmissionTimer) Finish() {
+	total := time.Since(t.start)
+	SubmissionPhaseDuration.WithLabelValues(t.destination, PhaseTotal).Observe(total.Seconds())
+
+	fields := make([]zap.Field, 0, len(t.phases)+2)
+	fields = append(fields, zap.String("destination", t.destination))
+	for _, p := range t.phases {
+		fields = append(fields, zap.Duration(p.Phase, p.Duration))
+	}
+	fields = append(fields, zap.Duration(PhaseTotal, total))
+	t.logger.Debug("Submission phase timings", fields...)
+}
+
+// Phases returns the phases recorded so far, in completion order
+func (t *SubmissionTimer) Phases() []PhaseTiming {
+	return t.phases
+}
+
+func (t *SubmissionTimer) record(phase string, d time.Duration) {
+	t.phases = append(t.phases, PhaseTiming{Phase: phase, Duration: d})
+	SubmissionPhaseDuration.WithLabelValues(t.destination, phase).Observe(d.Seconds())
+}
@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+func TestSubmissionTimerRecordsPhases(t *testing.T) {
+	timer := NewSubmissionTimer(zap.NewNop(), "timer_test")
+
+	stop := timer.StartPhase("first")
+	stop()
+	stop = timer.StartPhase("second")
+	stop()
+	stop = timer.StartPhase("first")
+	stop()
+	timer.Finish()
+
+	phases := timer.Phases()
+	if len(phases) != 3 {
+		t.Fatalf("expected 3 recorded phases, got %d", len(phases))
+	}
+	want := []string{"first", "second", "first"}
+	for i, p := range phases {
+		if p.Phase != want[i] {
+			t.Errorf("phase %d: expected %q, got %q", i, want[i], p.Phase)
+		}
+	}
+
+	if got := testutil.CollectAndCount(SubmissionPhaseDuration); got != 3 {
+		t.Errorf("expected 3 histogram series (first, second, total), got %d", got)
+	}
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// ErrNoVAADelivered is returned by Start in once mode when the relayer stops before delivering a VAA
+var ErrNoVAADelivered = errors.New("no VAA delivered")
+
+// onceMode stops the relayer after its first delivery, for scripted end-to-end runs. VAAs are
+// processed one at a time in this mode, so exactly one is delivered. It is only touched by the
+// goroutine running Start.
+type onceMode struct {
+	timeout   time.Duration // How long to wait for a delivery (0 = no limit)
+	stop      context.CancelFunc
+	delivered bool
+}
+
+// begin derives the context Start runs under: cancelled after the first delivery or the timeout
+func (o *onceMode) begin(ctx context.Context) context.Context {
+	if o.timeout > 0 {
+		ctx, o.stop = context.WithTimeout(ctx, o.timeout)
+	} else {
+		ctx, o.stop = context.WithCancel(ctx)
+	}
+	return ctx
+}
+
+// observe records the outcome of processing one VAA and stops the relayer if it was delivered,
+// or the destination already had it. Filtered and failed VAAs leave the relayer listening.
+func (o *onceMode) observe(txHash string, err error) bool {
+	if (err == nil && txHash != "") || errors.Is(err, submitter.ErrAlreadyProcessed) {
+		o.delivered = true
+		o.stop()
+	}
+	return o.delivered
+}
+
+// result returns the error Start reports once stopped: nil after a delivery, otherwise why none happened
+func (o *onceMode) result(ctx context.Context) error {
+	defer o.stop()
+	if o.delivered {
+		return nil
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w within %v", ErrNoVAADelivered, o.timeout)
+	}
+	return fmt.Errorf("%w before shutdown", ErrNoVAADelivered)
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+func TestOnceMode(t *testing.T) {
+	t.Run("stops after a delivery", func(t *testing.T) {
+		once := &onceMode{}
+		ctx := once.begin(context.Background())
+
+		if once.observe("", nil) || once.observe("", fmt.Errorf("reverted: %w", submitter.ErrPermanent)) {
+			t.Fatal("expected filtered and failed VAAs not to stop the relayer")
+		}
+		if ctx.Err() != nil {
+			t.Fatal("expected the relayer to keep listening")
+		}
+		if !once.observe("0xabc", nil) {
+			t.Fatal("expected a delivery to stop the relayer")
+		}
+		if ctx.Err() == nil {
+			t.Error("expected the context to be cancelled after a delivery")
+		}
+		if err := once.result(ctx); err != nil {
+			t.Errorf("expected no error after a delivery, got %v", err)
+		}
+	})
+
+	t.Run("already processed counts as delivered", func(t *testing.T) {
+		once := &onceMode{}
+		ctx := once.begin(context.Background())
+		if !once.observe("", fmt.Errorf("nonce too low: %w", submitter.ErrAlreadyProcessed)) {
+			t.Fatal("expected an already processed VAA to stop the relayer")
+		}
+		if err := once.result(ctx); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		once := &onceMode{timeout: 10 * time.Millisecond}
+		ctx := once.begin(context.Background())
+		<-ctx.Done()
+		if err := once.result(ctx); !errors.Is(err, ErrNoVAADelivered) {
+			t.Errorf("expected ErrNoVAADelivered, got %v", err)
+		}
+	})
+
+	t.Run("shut down first", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		once := &onceMode{timeout: time.Minute}
+		ctx := once.begin(parent)
+		cancel()
+		if err := once.result(ctx); !errors.Is(err, ErrNoVAADelivered) {
+			t.Errorf("expected ErrNoVAADelivered, got %v", err)
+		}
+	})
+}
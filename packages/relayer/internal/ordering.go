@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultOrderingGapTimeout is how long a VAA waits for its predecessor before being processed anyway
+const DefaultOrderingGapTimeout = 2 * time.Minute
+
+// emitterKey identifies a single Wormhole emitter
+type emitterKey struct {
+	chainID uint16
+	emitter string
+}
+
+// String returns the emitter as chain/emitter, the prefix of its VAAs' identities
+func (k emitterKey) String() string {
+	return fmt.Sprintf("%d/%s", k.chainID, k.emitter)
+}
+
+// emitterQueue tracks the ordering state of a single emitter
+type emitterQueue struct {
+	busy    bool           // A VAA from this emitter is currently being processed
+	started bool           // next is known (at least one VAA has completed)
+	next    uint64         // Next expected sequence
+	waiting map[uint64]int // Sequences currently waiting for their turn
+	changed chan struct{}  // Closed and replaced whenever the queue state changes
+}
+
+// emitterSequencer serializes VAA processing within each emitter, in sequence order,
+// while allowing different emitters to be processed in parallel.
+//
+// The first VAA seen for an emitter establishes the baseline. A VAA whose predecessor
+// has not completed waits up to gapTimeout; after that it is processed anyway (lowest
+// waiting sequence first) so a missing or dropped VAA cannot stall the emitter forever.
+// A completed VAA advances the expected sequence whether or not it succeeded.
+type emitterSequencer struct {
+	mu         sync.Mutex
+	gapTimeout time.Duration
+	queues     map[emitterKey]*emitterQueue
+}
+
+func newEmitterSequencer(gapTimeout time.Duration) *emitterSequencer {
+	if gapTimeout <= 0 {
+		gapTimeout = DefaultOrderingGapTimeout
+	}
+	return &emitterSequencer{
+		gapTimeout: gapTimeout,
+		queues:     make(map[emitterKey]*emitterQueue),
+	}
+}
+
+// queue returns the queue for key, creating it if needed. Callers must hold s.mu.
+func (s *emitterSequencer) queue(key emitterKey) *emitterQueue {
+	q, ok := s.queues[key]
+	if !ok {
+		q = &emitterQueue{
+			waiting: make(map[uint64]int),
+			changed: make(chan struct{}),
+		}
+		s.queues[key] = q
+	}
+	return q
+}
+
+// notify wakes every waiter of q. Callers must hold s.mu.
+func (q *emitterQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// lowestWaiting reports whether seq is the lowest sequence waiting on q. Callers must hold s.mu.
+func (q *emitterQueue) lowestWaiting(seq uint64) bool {
+	for s := range q.waiting {
+		if s < seq {
+			return false
+		}
+	}
+	return true
+}
+
+// acquire blocks until the VAA with the given sequence may be processed, or ctx is done.
+// Every successful acquire must be paired with a release.
+func (s *emitterSequencer) acquire(ctx context.Context, key emitterKey, seq uint64) error {
+	gapExpired := false
+	gapTimer := time.NewTimer(s.gapTimeout)
+	defer gapTimer.Stop()
+
+	s.mu.Lock()
+	q := s.queue(key)
+	q.waiting[seq]++
+	defer func() {
+		s.mu.Lock()
+		if q.waiting[seq]--; q.waiting[seq] == 0 {
+			delete(q.waiting, seq)
+		}
+		q.notify()
+		s.mu.Unlock()
+	}()
+
+	for {
+		if !q.busy && (!q.started || seq <= q.next || (gapExpired && q.lowestWaiting(seq))) {
+			q.busy = true
+			s.mu.Unlock()
+			return nil
+		}
+		changed := q.changed
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-gapTimer.C:
+			gapExpired = true
+		case <-changed:
+		}
+
+		s.mu.Lock()
+	}
+}
+
+// release marks the VAA with the given sequence as completed and lets its successor proceed
+func (s *emitterSequencer) release(key emitterKey, seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.queue(key)
+	q.busy = false
+	if !q.started || seq >= q.next {
+		q.next = seq + 1
+		q.started = true
+	}
+	q.notify()
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEmitterSequencerOrdersWithinEmitter(t *testing.T) {
+	s := newEmitterSequencer(time.Minute)
+	key := emitterKey{chainID: 2, emitter: "aa"}
+
+	// Establish the baseline with sequence 1
+	if err := s.acquire(context.Background(), key, 1); err != nil {
+		t.Fatalf("acquire(1) failed: %v", err)
+	}
+	s.release(key, 1)
+
+	var mu sync.Mutex
+	var order []uint64
+	var wg sync.WaitGroup
+
+	// Sequence 3 arrives before 2 and must wait for it
+	for _, seq := range []uint64{3, 2} {
+		wg.Add(1)
+		go func(seq uint64) {
+			defer wg.Done()
+			if err := s.acquire(context.Background(), key, seq); err != nil {
+				t.Errorf("acquire(%d) failed: %v", seq, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, seq)
+			mu.Unlock()
+			s.release(key, seq)
+		}(seq)
+		time.Sleep(20 * time.Millisecond)
+	}
+	wg.Wait()
+
+	if len(order) != 2 || order[0] != 2 || order[1] != 3 {
+		t.Fatalf("expected processing order [2 3], got %v", order)
+	}
+}
+
+func TestEmitterSequencerParallelAcrossEmitters(t *testing.T) {
+	s := newEmitterSequencer(time.Minute)
+	a := emitterKey{chainID: 2, emitter: "aa"}
+	b := emitterKey{chainID: 2, emitter: "bb"}
+
+	if err := s.acquire(context.Background(), a, 10); err != nil {
+		t.Fatalf("acquire(a) failed: %v", err)
+	}
+	// A different emitter is not blocked by a busy one
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.acquire(ctx, b, 5); err != nil {
+		t.Fatalf("acquire(b) blocked by another emitter: %v", err)
+	}
+	s.release(b, 5)
+	s.release(a, 10)
+}
+
+func TestEmitterSequencerGapTimeout(t *testing.T) {
+	s := newEmitterSequencer(50 * time.Millisecond)
+	key := emitterKey{chainID: 2, emitter: "aa"}
+
+	if err := s.acquire(context.Background(), key, 1); err != nil {
+		t.Fatalf("acquire(1) failed: %v", err)
+	}
+	s.release(key, 1)
+
+	// Sequence 2 never arrives; 3 proceeds once the gap timeout elapses
+	start := time.Now()
+	if err := s.acquire(context.Background(), key, 3); err != nil {
+		t.Fatalf("acquire(3) failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Fatalf("expected acquire(3) to wait for the gap timeout, returned after %v", elapsed)
+	}
+	s.release(key, 3)
+}
+
+func TestEmitterSequencerContextCancelled(t *testing.T) {
+	s := newEmitterSequencer(time.Minute)
+	key := emitterKey{chainID: 2, emitter: "aa"}
+
+	if err := s.acquire(context.Background(), key, 1); err != nil {
+		t.Fatalf("acquire(1) failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.acquire(ctx, key, 2); err == nil {
+		t.Fatal("expected an error when the context is cancelled while waiting")
+	}
+
+	// The cancelled waiter must not leave the emitter blocked
+	s.release(key, 1)
+	if err := s.acquire(context.Background(), key, 2); err != nil {
+		t.Fatalf("acquire(2) after release failed: %v", err)
+	}
+	s.release(key, 2)
+}
@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PayloadRule is a check a decoded payload must pass before the VAA is submitted.
+// Check returns a descriptive error for a payload that violates the rule.
+type PayloadRule struct {
+	Name  string // Short identifier, used as the metrics label
+	Check func(vaaData *VAAData) error
+}
+
+// checkPayloadRules runs vaaData through rules in order and returns the first violation
+func checkPayloadRules(rules []PayloadRule, vaaData *VAAData) (PayloadRule, error) {
+	for _, rule := range rules {
+		if err := rule.Check(vaaData); err != nil {
+			return rule, err
+		}
+	}
+	return PayloadRule{}, nil
+}
+
+// RequireDestinationIn rejects payloads whose destination chain is not one of chainIDs
+func RequireDestinationIn(chainIDs ...uint16) PayloadRule {
+	return PayloadRule{
+		Name: "known_destination",
+		Check: func(vaaData *VAAData) error {
+			if !vaaData.HasDestination {
+				return fmt.Errorf("payload (%d bytes) has no destination chain", len(vaaData.VAA.Payload))
+			}
+			if !slices.Contains(chainIDs, vaaData.DestinationChainID) {
+				return fmt.Errorf("destination chain %d is not one of the known chains %v", vaaData.DestinationChainID, chainIDs)
+			}
+			return nil
+		},
+	}
+}
+
+// RequireNonZeroValue rejects payloads that carry no value or a value of zero
+func RequireNonZeroValue() PayloadRule {
+	return PayloadRule{
+		Name: "non_zero_value",
+		Check: func(vaaData *VAAData) error {
+			if vaaData.Value == nil {
+				return fmt.Errorf("payload (%d bytes) has no value field", len(vaaData.VAA.Payload))
+			}
+			if vaaData.Value.Sign() == 0 {
+				return fmt.Errorf("payload value is zero")
+			}
+			return nil
+		},
+	}
+}
+
+// RequirePayloadLength rejects payloads whose length is not exactly one of lengths, e.g. 18 and 50
+// for the default and Aztec layouts. Longer payloads otherwise decode as the shorter layout.
+func RequirePayloadLength(lengths ...int) PayloadRule {
+	return PayloadRule{
+		Name: "payload_length",
+		Check: func(vaaData *VAAData) error {
+			if length := len(vaaData.VAA.Payload); !slices.Contains(lengths, length) {
+				return fmt.Errorf("payload length %d is not one of %v", length, lengths)
+			}
+			return nil
+		},
+	}
+}
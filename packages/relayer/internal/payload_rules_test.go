@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestPayloadRules(t *testing.T) {
+	toArbitrum := valuePayload(big.NewInt(5), false)
+	toArbitrum[0], toArbitrum[1] = 0x27, 0x13 // Destination 10003
+	zeroToArbitrum := make([]byte, 18)
+	zeroToArbitrum[0], zeroToArbitrum[1] = 0x27, 0x13
+
+	tests := []struct {
+		name       string
+		rule       PayloadRule
+		payload    []byte
+		wantReason string // empty = the payload passes
+	}{
+		{name: "known destination", rule: RequireDestinationIn(10003, 10004), payload: toArbitrum},
+		{name: "unknown destination", rule: RequireDestinationIn(10004), payload: toArbitrum, wantReason: "destination chain 10003 is not one of the known chains [10004]"},
+		{name: "missing destination", rule: RequireDestinationIn(10003), payload: make([]byte, 10), wantReason: "payload (10 bytes) has no destination chain"},
+		{name: "non-zero value", rule: RequireNonZeroValue(), payload: toArbitrum},
+		{name: "zero value", rule: RequireNonZeroValue(), payload: zeroToArbitrum, wantReason: "payload value is zero"},
+		{name: "missing value", rule: RequireNonZeroValue(), payload: make([]byte, 10), wantReason: "payload (10 bytes) has no value field"},
+		{name: "accepted length", rule: RequirePayloadLength(18, 50), payload: toArbitrum},
+		{name: "unexpected length", rule: RequirePayloadLength(18, 50), payload: make([]byte, 20), wantReason: "payload length 20 is not one of [18 50]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaaData := testVAADataWithPayload(tt.payload)
+			err := tt.rule.Check(&vaaData)
+			switch {
+			case tt.wantReason == "" && err != nil:
+				t.Fatalf("expected the payload to pass, got %v", err)
+			case tt.wantReason != "" && (err == nil || err.Error() != tt.wantReason):
+				t.Fatalf("expected reason %q, got %v", tt.wantReason, err)
+			}
+		})
+	}
+}
+
+func TestProcessVAASkipsPayloadRuleViolations(t *testing.T) {
+	s := &countingSubmitter{}
+	config := VAAProcessorConfig{PayloadRules: []PayloadRule{RequirePayloadLength(18), RequireNonZeroValue()}}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), config, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	for _, payload := range [][]byte{make([]byte, 50), make([]byte, 18)} {
+		if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(payload)); err != nil {
+			t.Fatalf("ProcessVAA failed: %v", err)
+		}
+	}
+	if s.calls != 0 {
+		t.Fatalf("expected both VAAs to be skipped, got %d submissions", s.calls)
+	}
+
+	if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(valuePayload(big.NewInt(1), false))); err != nil {
+		t.Fatalf("ProcessVAA failed: %v", err)
+	}
+	if s.calls != 1 {
+		t.Fatal("expected a payload passing every rule to be submitted")
+	}
+
+	vaaData := testVAADataWithPayload(make([]byte, 50))
+	rule, err := checkPayloadRules(config.PayloadRules, &vaaData)
+	if rule.Name != "payload_length" || err == nil || !strings.Contains(err.Error(), "50") {
+		t.Errorf("expected the first violated rule to be reported, got %q: %v", rule.Name, err)
+	}
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/relayertest"
+)
+
+// TestPipelineInMemory runs VAAs from an in-memory source through the relayer and the default
+// processor into a recording submitter, checking the filters, dedup and ordering together
+func TestPipelineInMemory(t *testing.T) {
+	source := relayertest.NewSource()
+	recorder := relayertest.NewSubmitter(nil)
+	processor, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{
+		ChainIDs:           []uint16{10004},
+		DestinationChainID: 10003,
+	}, recorder)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	relayer, _ := NewRelayerWithOrdering(zap.NewNop(), source, processor, time.Minute)
+	recent := NewRecentVAAs(16)
+	relayer.SetRecentVAAs(recent)
+	stop := startRelayer(t, relayer)
+
+	var base, solana [32]byte
+	base[31], solana[31] = 0xba, 0x50
+	vaa := func(chain uint16, emitter [32]byte, sequence uint64, payload []byte) []byte {
+		return relayertest.VAA{EmitterChain: chain, Emitter: emitter, Sequence: sequence, Payload: payload}.Bytes()
+	}
+	first := vaa(10004, base, 1, destinationPayload(10003))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The first VAA sets where the emitter's sequence stands
+	source.Push(first)
+	if err := recorder.WaitForSubmissions(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	source.Push(
+		vaa(10004, base, 3, destinationPayload(10003)), // Waits for sequence 2
+		vaa(10004, base, 2, destinationPayload(10003)),
+		first, // Replay of a delivered VAA
+		vaa(1, solana, 1, destinationPayload(10003)),   // Source chain not relayed
+		vaa(10004, base, 4, destinationPayload(10002)), // Another destination
+		vaa(10004, base, 5, []byte{0x27, 0x13}),        // Too short to carry a destination
+	)
+	if err := source.WaitDrained(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := recorder.WaitForSubmissions(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	// Every VAA but the replay is handled, whether submitted or filtered
+	for len(recent.List()) < 6 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the VAAs to be handled, got %v", recent.List())
+		case <-time.After(time.Millisecond):
+		}
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+
+	// Only the VAAs for this destination were submitted, once each, in sequence order
+	var submitted []uint64
+	for _, submission := range recorder.Submissions() {
+		parsed, err := ParseVAAPermissive(submission.VAA)
+		if err != nil {
+			t.Fatalf("submitted VAA does not parse: %v", err)
+		}
+		submitted = append(submitted, parsed.Sequence)
+	}
+	if len(submitted) != 3 || submitted[0] != 1 || submitted[1] != 2 || submitted[2] != 3 {
+		t.Errorf("expected sequences [1 2 3] to be submitted in order, got %v", submitted)
+	}
+
+	decisions := make(map[string]string)
+	for _, entry := range recent.List() {
+		decisions[entry.VAA] = entry.Decision
+	}
+	identity := func(chain uint16, emitter [32]byte, sequence uint64) string {
+		return NewVAAIdentity(chain, hex.EncodeToString(emitter[:]), sequence).String()
+	}
+	for vaa, want := range map[string]string{
+		identity(10004, base, 1): DecisionSubmitted,
+		identity(10004, base, 2): DecisionSubmitted,
+		identity(10004, base, 3): DecisionSubmitted,
+		identity(1, solana, 1):   DecisionFiltered,
+		identity(10004, base, 4): DecisionFiltered,
+		identity(10004, base, 5): DecisionFiltered,
+	} {
+		if decisions[vaa] != want {
+			t.Errorf("%s: expected %s, got %q", vaa, want, decisions[vaa])
+		}
+	}
+	if relayer.summary.received != 7 || relayer.summary.duplicates != 1 {
+		t.Errorf("expected 7 VAAs received and 1 duplicate, got %d and %d", relayer.summary.received, relayer.summary.duplicates)
+	}
+}
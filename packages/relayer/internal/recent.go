@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRecentVAAsSize is the number of recent VAAs kept when no size is configured
+const DefaultRecentVAAsSize = 100
+
+// Decisions recorded for a handled VAA
+const (
+	DecisionFiltered         = "filtered"
+	DecisionSubmitted        = "submitted"
+	DecisionAlreadyProcessed = "already_processed"
+	DecisionFailed           = "failed"
+)
+
+// RecentVAA is the outcome of handling a single VAA
+type RecentVAA struct {
+	HandledAt          time.Time `json:"handledAt"`
+	VAA                string    `json:"vaa"` // Identity of the VAA as chain/emitter/sequence (see VAAIdentity)
+	ChainID            uint16    `json:"chainId"`
+	Emitter            string    `json:"emitter"`
+	Sequence           uint64    `json:"sequence"`
+	SourceTxID         string    `json:"sourceTxId,omitempty"`
+	PayloadLength      int       `json:"payloadLength"`
+	DestinationChainID uint16    `json:"destinationChainId"`
+	Decision           string    `json:"decision"`
+	TxHash             string    `json:"txHash,omitempty"`
+	Error              string    `json:"error,omitempty"`
+}
+
+// RecentVAAs is a bounded ring buffer of the most recently handled VAAs.
+// It is safe for concurrent use and serves its contents as JSON over HTTP.
+type RecentVAAs struct {
+	mu      sync.Mutex
+	entries []RecentVAA
+	next    int  // Index the next entry is written to
+	full    bool // entries has wrapped at least once
+}
+
+// NewRecentVAAs creates a ring buffer holding up to size VAAs (DefaultRecentVAAsSize if size <= 0)
+func NewRecentVAAs(size int) *RecentVAAs {
+	if size <= 0 {
+		size = DefaultRecentVAAsSize
+	}
+	return &RecentVAAs{entries: make([]RecentVAA, size)}
+}
+
+// Add records a handled VAA, evicting the oldest entry when the buffer is full
+func (r *RecentVAAs) Add(entry RecentVAA) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// List returns the recorded VAAs, newest first
+func (r *RecentVAAs) List() []RecentVAA {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+
+	list := make([]RecentVAA, 0, count)
+	for i := 1; i <= count; i++ {
+		list = append(list, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return list
+}
+
+// ServeHTTP writes the recorded VAAs as a JSON array, newest first
+func (r *RecentVAAs) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(r.List())
+}
@@ -0,0 +1,54 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecentVAAsWrapsNewestFirst(t *testing.T) {
+	recent := NewRecentVAAs(3)
+	if got := recent.List(); len(got) != 0 {
+		t.Fatalf("expected an empty buffer, got %d entries", len(got))
+	}
+
+	for seq := uint64(1); seq <= 5; seq++ {
+		recent.Add(RecentVAA{Sequence: seq, Decision: DecisionSubmitted})
+	}
+
+	got := recent.List()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	for i, want := range []uint64{5, 4, 3} {
+		if got[i].Sequence != want {
+			t.Errorf("entry %d: expected sequence %d, got %d", i, want, got[i].Sequence)
+		}
+	}
+}
+
+func TestRecentVAAsServeHTTP(t *testing.T) {
+	recent := NewRecentVAAs(0)
+	recent.Add(RecentVAA{ChainID: 56, Sequence: 7, Decision: DecisionFailed, Error: "boom"})
+
+	rec := httptest.NewRecorder()
+	recent.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/recent", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+
+	var entries []RecentVAA
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Sequence != 7 || entries[0].Decision != DecisionFailed || entries[0].Error != "boom" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	rec = httptest.NewRecorder()
+	recent.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/recent", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status 405 for POST, got %d", rec.Code)
+	}
+}
@@ -2,38 +2,250 @@ package internal
 
 import (
 	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
 
 	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
+	"github.com/wormhole-demo/relayer/internal/submitter"
 	"go.uber.org/zap"
 )
 
+// VAASource opens the VAA streams the relayer consumes (*clients.SpyClient). It is an
+// interface so the relayer loop can be tested against scripted streams.
+type VAASource interface {
+	// SubscribeSignedVAA opens a stream of signed VAAs, retrying as the source sees fit. An
+	// error wrapping clients.ErrSpyFatal means retrying cannot help.
+	SubscribeSignedVAA(ctx context.Context) (clients.VAAStream, error)
+	// NewReconnectBackoff returns the backoff between reconnects after stream errors
+	NewReconnectBackoff() *clients.Backoff
+	Close()
+}
+
+var _ VAASource = (*clients.SpyClient)(nil)
+
 type Relayer struct {
-	spyClient    *clients.SpyClient
+	spy          VAASource
 	vaaProcessor VAAProcessor
 	logger       *zap.Logger
 	// Protect against duplicate deliveries from the spy service (at-least-once semantics).
 	dedupeMu      sync.Mutex
-	inflightVAAs  map[string]struct{}
+	inflightVAAs  map[string]time.Time // Start of processing, by VAA key
 	processedVAAs map[string]time.Time
 	dedupeTTL     time.Duration
+	// Optional ordered delivery: serializes processing per emitter in sequence order
+	sequencer *emitterSequencer
+	// Optional ring buffer of recently handled VAAs for live debugging
+	recentVAAs *RecentVAAs
+	// Optional audit trail of every handled VAA
+	eventSink EventSink
+	// Optional on-disk record of delivered VAAs, recognizing replays across restarts
+	deliveries *DeliveryCache
+	// Optional recovery of the VAAs missed while the spy stream was down
+	watermarks      *emitterWatermarks
+	gapFetcher      VAAFetcher
+	gapMaxSequences int
+	gapMu           sync.Mutex // Serializes gap backfills
+	// Optional on-disk record of the watermarks, resuming them (and backfilling from them) on start
+	sequences      *SequenceStore
+	resumeBackfill bool
+	// Optional full dump, including the raw hex, of every VAA that fails
+	logVAAOnFailure bool
+	// Optional stop after the first delivery
+	once *onceMode
+	// Counters for the summary logged on shutdown
+	summary *runSummary
+}
+
+// DefaultDedupeTTL is how long replays of a settled VAA are skipped when no TTL is set
+const DefaultDedupeTTL = 15 * time.Minute
+
+// ErrDedupeTTLTooShort reports a dedup TTL shorter than a single submission may take
+var ErrDedupeTTLTooShort = errors.New("dedup TTL is shorter than the submission timeout")
+
+// ValidateDedupeTTL checks ttl is positive and at least longestSubmission, the largest
+// submission timeout across destinations. Copies of a VAA the spy streams while one copy is
+// being confirmed (re-observations, reconnects) can arrive up to that long apart, so a shorter
+// TTL can expire a settled VAA before its last copy arrives, which is then submitted again.
+// A short TTL returns an error wrapping ErrDedupeTTLTooShort.
+func ValidateDedupeTTL(ttl, longestSubmission time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("dedup TTL must be positive, got %v", ttl)
+	}
+	if ttl < longestSubmission {
+		return fmt.Errorf("%w: %v < %v", ErrDedupeTTLTooShort, ttl, longestSubmission)
+	}
+	return nil
 }
 
 // NewRelayer creates a new relayer instance
-func NewRelayer(logger *zap.Logger, spyClient *clients.SpyClient, processor VAAProcessor) (*Relayer, error) {
+func NewRelayer(logger *zap.Logger, spy VAASource, processor VAAProcessor) (*Relayer, error) {
 
 	return &Relayer{
 		logger:        logger.With(zap.String("component", "Relayer")),
-		spyClient:     spyClient,
+		spy:           spy,
 		vaaProcessor:  processor,
-		inflightVAAs:  make(map[string]struct{}),
+		inflightVAAs:  make(map[string]time.Time),
 		processedVAAs: make(map[string]time.Time),
-		dedupeTTL:     15 * time.Minute,
+		dedupeTTL:     DefaultDedupeTTL,
+		watermarks:    newEmitterWatermarks(),
+		summary:       newRunSummary(),
 	}, nil
 }
 
+// NewRelayerWithOrdering creates a relayer that processes VAAs from each emitter one at a time,
+// in sequence order. Out-of-order VAAs are buffered until their predecessor completes or
+// gapTimeout elapses; VAAs from different emitters are still processed in parallel.
+func NewRelayerWithOrdering(logger *zap.Logger, spy VAASource, processor VAAProcessor, gapTimeout time.Duration) (*Relayer, error) {
+	r, err := NewRelayer(logger, spy, processor)
+	if err != nil {
+		return nil, err
+	}
+	r.sequencer = newEmitterSequencer(gapTimeout)
+	return r, nil
+}
+
+// SetDedupeTTL sets how long replays of a settled VAA are skipped (DefaultDedupeTTL by default;
+// see ValidateDedupeTTL). It must be called before Start.
+func (r *Relayer) SetDedupeTTL(ttl time.Duration) {
+	r.dedupeTTL = ttl
+}
+
+// SetRecentVAAs makes the relayer record the outcome of every handled VAA into recent.
+// It must be called before Start.
+func (r *Relayer) SetRecentVAAs(recent *RecentVAAs) {
+	r.recentVAAs = recent
+}
+
+// SetEventSink makes the relayer record an audit event for every handled VAA into sink.
+// It must be called before Start; the caller closes the sink after Start returns.
+func (r *Relayer) SetEventSink(sink EventSink) {
+	r.eventSink = sink
+}
+
+// SetDeliveryCache makes the relayer skip VAAs found in cache and record each delivered VAA into it.
+// It must be called before Start; the caller closes the cache after Start returns.
+func (r *Relayer) SetDeliveryCache(cache *DeliveryCache) {
+	r.deliveries = cache
+}
+
+// SetGapBackfill makes the relayer fetch, after each spy reconnect, the VAAs its monitored emitters
+// produced while the stream was down, up to maxSequences per emitter (0 = DefaultGapBackfillMaxSequences).
+// It must be called before Start.
+func (r *Relayer) SetGapBackfill(fetcher VAAFetcher, maxSequences int) {
+	if maxSequences <= 0 {
+		maxSequences = DefaultGapBackfillMaxSequences
+	}
+	r.gapFetcher = fetcher
+	r.gapMaxSequences = maxSequences
+}
+
+// SetSequenceStore makes the relayer record the watermark of each monitored emitter into store
+// and, on Start, log the position recorded by the previous run and resume from it. With backfill,
+// the VAAs those emitters produced since then are fetched and relayed as after a spy reconnect,
+// which requires SetGapBackfill. It must be called before Start.
+func (r *Relayer) SetSequenceStore(store *SequenceStore, backfill bool) {
+	r.sequences = store
+	r.resumeBackfill = backfill
+}
+
+// SetLogVAAOnFailure makes the relayer log every field and the raw hex of each VAA that fails,
+// so it can be inspected and replayed later. VAAs that succeed are not dumped.
+// It must be called before Start.
+func (r *Relayer) SetLogVAAOnFailure(enabled bool) {
+	r.logVAAOnFailure = enabled
+}
+
+// SetOnce makes Start process VAAs one at a time and return after the first one is delivered (or
+// found already processed by the destination), or with an error wrapping ErrNoVAADelivered once
+// timeout elapses (0 = no limit) or ctx is cancelled first. It must be called before Start.
+func (r *Relayer) SetOnce(timeout time.Duration) {
+	r.once = &onceMode{timeout: timeout}
+}
+
+// recordDelivery persists a delivered VAA, if a delivery cache is configured
+func (r *Relayer) recordDelivery(key string, vaaBytes []byte, txHash string) {
+	if r.deliveries == nil {
+		return
+	}
+
+	delivery := Delivery{Key: key, TxHash: txHash, DeliveredAt: time.Now(), VAA: vaaBytes}
+	if err := r.deliveries.Record(delivery); err != nil {
+		r.logger.Error("Failed to record delivery", zap.String("vaaHash", key), zap.Error(err))
+	}
+}
+
+// recordEvent writes the audit event for processing vaaData, if a sink is configured
+func (r *Relayer) recordEvent(vaaData *VAAData, started time.Time, txHash string, err error) {
+	if r.eventSink == nil {
+		return
+	}
+
+	event := RelayEvent{
+		HandledAt:  time.Now(),
+		VAA:        vaaData.ID.String(),
+		ChainID:    vaaData.ChainID,
+		Emitter:    vaaData.EmitterHex,
+		Sequence:   vaaData.Sequence,
+		SourceTxID: vaaData.TxID,
+		Decision:   vaaDecision(txHash, err),
+		TxHash:     txHash,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if vaaData.HasDestination {
+		destination := vaaData.DestinationChainID
+		event.DestinationChainID = &destination
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if sinkErr := r.eventSink.Record(event); sinkErr != nil {
+		r.logger.Error("Failed to record audit event",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Error(sinkErr))
+	}
+}
+
+// recordRecentVAA records the outcome of processing vaaData, if a ring buffer is configured
+func (r *Relayer) recordRecentVAA(vaaData *VAAData, txHash string, err error) {
+	if r.recentVAAs == nil {
+		return
+	}
+
+	entry := RecentVAA{
+		HandledAt:          time.Now(),
+		VAA:                vaaData.ID.String(),
+		ChainID:            vaaData.ChainID,
+		Emitter:            vaaData.EmitterHex,
+		Sequence:           vaaData.Sequence,
+		SourceTxID:         vaaData.TxID,
+		PayloadLength:      len(vaaData.VAA.Payload),
+		DestinationChainID: vaaData.DestinationChainID,
+		Decision:           vaaDecision(txHash, err),
+		TxHash:             txHash,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	r.recentVAAs.Add(entry)
+}
+
+// dropMalformedVAA reports whether vaaBytes is too short to be a VAA, counting and logging it if so.
+// It only checks the length, so it stays cheap for every VAA the spy streams.
+func (r *Relayer) dropMalformedVAA(vaaBytes []byte) bool {
+	if len(vaaBytes) >= minVAALength {
+		return false
+	}
+	r.summary.recordMalformed()
+	r.logger.Warn("Dropping malformed VAA from spy",
+		zap.Int("length", len(vaaBytes)),
+		zap.Int("minLength", minVAALength))
+	return true
+}
+
 // beginProcessingVAA checks if we should process a VAA (returns false if duplicate)
 func (r *Relayer) beginProcessingVAA(key string) bool {
 	r.dedupeMu.Lock()
@@ -47,15 +259,33 @@ func (r *Relayer) beginProcessingVAA(key string) bool {
 		delete(r.processedVAAs, key)
 	}
 
+	// Drop if an earlier run delivered this VAA; the destination already has it
+	if r.deliveries != nil {
+		if delivery, ok := r.deliveries.Lookup(key); ok {
+			r.logger.Info("Skipping VAA delivered by an earlier run",
+				zap.String("vaaHash", key),
+				zap.String("txHash", delivery.TxHash),
+				zap.Time("deliveredAt", delivery.DeliveredAt))
+			return false
+		}
+	}
+
 	// Another goroutine is already working on this VAA; let it finish.
 	if _, ok := r.inflightVAAs[key]; ok {
 		return false
 	}
 
-	r.inflightVAAs[key] = struct{}{}
+	r.inflightVAAs[key] = time.Now()
 	return true
 }
 
+// isSettled reports whether a VAA needs no further attempts: it was delivered, the destination had
+// already consumed it, or it failed permanently. Transient and configuration failures are left
+// uncached so a replay from the spy retries them.
+func isSettled(err error) bool {
+	return err == nil || errors.Is(err, submitter.ErrAlreadyProcessed) || errors.Is(err, submitter.ErrPermanent)
+}
+
 // finishProcessingVAA marks a VAA as done processing
 func (r *Relayer) finishProcessingVAA(key string, success bool) {
 	r.dedupeMu.Lock()
@@ -79,8 +309,8 @@ func (r *Relayer) finishProcessingVAA(key string, success bool) {
 
 // Close cleans up resources used by the relayer
 func (r *Relayer) Close() {
-	if r.spyClient != nil {
-		r.spyClient.Close()
+	if r.spy != nil {
+		r.spy.Close()
 	}
 }
 
@@ -93,76 +323,174 @@ func (r *Relayer) Start(ctx context.Context) error {
 	// 	zap.Uint16("arbitrumChain", r.config.DestChainID),
 	// 	zap.String("verificationServiceURL", r.config.VerificationServiceURL)) // ADD
 
-	// Create a wait group to track goroutines
-	var wg sync.WaitGroup
+	// Log what this run handled however it ends; in-flight VAAs have finished by then
+	defer func() {
+		r.logger.Info("Relayer summary", r.summary.fields()...)
+	}()
+
+	// In once mode, stop after the first delivery or the timeout
+	if r.once != nil {
+		ctx = r.once.begin(ctx)
+	}
+
+	// Pick up where the previous run stopped, if its position was recorded
+	resumeMarks := r.resume()
+
+	// Track the goroutines started for VAAs and backfills, so shutdown can drain them
+	work := &inflightWork{}
 
 	// Subscribe to VAAs
-	stream, err := r.spyClient.SubscribeSignedVAA(ctx)
+	stream, err := r.spy.SubscribeSignedVAA(ctx)
 	if err != nil {
-		return fmt.Errorf("subscribe to VAA stream: %v", err)
+		return fmt.Errorf("subscribe to VAA stream: %w", err)
 	}
 
 	r.logger.Info("Listening for VAAs")
 
-	// Create a separate context for graceful shutdown
-	processingCtx, cancelProcessing := context.WithCancel(context.Background())
+	// Processing is cancelled on shutdown, before waiting for in-flight VAAs to finish
+	processingCtx, cancelProcessing := context.WithCancel(ctx)
 	defer cancelProcessing()
 
+	// Relay what the recorded emitters produced while the relayer was stopped, if requested
+	if r.resumeBackfill && r.gapFetcher != nil && len(resumeMarks) > 0 {
+		work.start(func() {
+			r.backfillGap(processingCtx, resumeMarks, "while the relayer was stopped", latestSeenAt(resumeMarks))
+		})
+	}
+
+	// Consecutive stream errors back off exponentially; a received VAA resets the delay
+	reconnect := r.spy.NewReconnectBackoff()
+
 	for {
 		select {
 		case <-ctx.Done():
 			r.logger.Info("Shutting down relayer")
 			// Cancel all processing
 			cancelProcessing()
-			// Wait for all processing goroutines to complete
+			// Drain: start nothing new and wait for the in-flight VAAs and backfills
 			r.logger.Info("Waiting for all VAA processing to complete")
-			wg.Wait()
+			work.drain()
 			r.logger.Info("Shutdown complete")
+			if r.once != nil {
+				return r.once.result(ctx)
+			}
 			return nil
 		default:
 			// Receive the next VAA
 			resp, err := stream.Recv()
+			// Shutdown began while Recv waited: whatever it returned, a VAA the stream had
+			// buffered or the cancellation, is not handled, and no reconnect is attempted
+			if ctx.Err() != nil {
+				if err == nil {
+					r.logger.Debug("Discarding VAA received after shutdown began",
+						zap.String("vaaHash", computeVAAKey(resp.VaaBytes)))
+				}
+				continue // Shut down at the top of the loop
+			}
 			if err != nil {
-				r.logger.Warn("Stream error, retrying in 5s", zap.Error(err))
-				time.Sleep(5 * time.Second)
-				stream, err = r.spyClient.SubscribeSignedVAA(ctx)
+				// Fail fast on errors a reconnect cannot fix, rather than retrying forever
+				if err = clients.ClassifySpyError(err); errors.Is(err, clients.ErrSpyFatal) {
+					r.logger.Error("VAA stream failed and cannot recover by reconnecting", zap.Error(err))
+					cancelProcessing()
+					work.drain()
+					return fmt.Errorf("VAA stream: %w", err)
+				}
+
+				// Remember where each emitter stood before VAAs from the new stream move it on
+				disconnectedAt := time.Now()
+				marks := r.watermarks.snapshot()
+				retryDelay := reconnect.Next()
+				r.logger.Warn("Stream error, reconnecting", zap.Duration("retryIn", retryDelay), zap.Error(err))
+				select {
+				case <-time.After(retryDelay):
+				case <-ctx.Done():
+					continue // Shut down at the top of the loop
+				}
+				stream, err = r.spy.SubscribeSignedVAA(ctx)
 				if err != nil {
 					// Cancel all processing before returning
 					cancelProcessing()
 					// Wait for all processing goroutines to complete
-					wg.Wait()
-					return fmt.Errorf("subscribe to VAA stream after retry: %v", err)
+					work.drain()
+					return fmt.Errorf("subscribe to VAA stream after retry: %w", err)
+				}
+				if r.gapFetcher != nil && len(marks) > 0 {
+					work.start(func() {
+						r.backfillGap(processingCtx, marks, "while disconnected from the spy", disconnectedAt)
+					})
 				}
 				continue
 			}
+			reconnect.Reset()
+
+			// Drop empty or truncated VAAs before any dedup bookkeeping or goroutine
+			if r.dropMalformedVAA(resp.VaaBytes) {
+				continue
+			}
 
 			// Check for duplicates before processing
 			key := computeVAAKey(resp.VaaBytes)
-			if !r.beginProcessingVAA(key) {
+			isNew := r.beginProcessingVAA(key)
+			r.summary.recordReceived(!isNew)
+			if !isNew {
 				r.logger.Debug("Skipping duplicate VAA", zap.String("vaaHash", key))
 				continue
 			}
 
-			// Process the VAA in a goroutine, but track it with the WaitGroup
-			wg.Add(1)
-			go func(vaaBytes []byte, dedupeKey string) {
-				defer wg.Done()
-				if err := r.processVAA(processingCtx, vaaBytes); err != nil {
-					r.finishProcessingVAA(dedupeKey, false)
-				} else {
-					r.finishProcessingVAA(dedupeKey, true)
+			// In once mode, process VAAs one at a time so exactly one is delivered
+			if r.once != nil {
+				txHash, err := r.handleVAA(processingCtx, resp.VaaBytes, key)
+				if r.once.observe(txHash, err) {
+					r.logger.Info("VAA delivered, stopping (once mode)", zap.String("txHash", txHash))
 				}
-			}(resp.VaaBytes, key)
+				continue
+			}
+
+			// Process the VAA in a goroutine tracked for draining
+			vaaBytes := resp.VaaBytes
+			work.start(func() {
+				r.handleVAA(processingCtx, vaaBytes, key)
+			})
 		}
 	}
 }
 
-func (r *Relayer) processVAA(ctx context.Context, vaaBytes []byte) error {
+// handleVAA processes a VAA claimed with beginProcessingVAA, then releases it and records its delivery
+func (r *Relayer) handleVAA(ctx context.Context, vaaBytes []byte, dedupeKey string) (string, error) {
+	txHash, err := r.processVAA(ctx, vaaBytes)
+	r.finishProcessingVAA(dedupeKey, isSettled(err))
+	if err == nil && txHash != "" {
+		r.recordDelivery(dedupeKey, vaaBytes, txHash)
+	}
+	return txHash, err
+}
+
+// Stages of the VAA lag metric
+const (
+	lagStageReceived  = "received"
+	lagStageProcessed = "processed"
+)
+
+// observeLag records the time from vaaData's timestamp to at in the VAA lag metric for stage.
+// A timestamp ahead of the relayer's clock counts as no lag.
+func observeLag(stage string, vaaData *VAAData, at time.Time) {
+	lag := at.Sub(vaaData.VAA.Timestamp)
+	if lag < 0 {
+		lag = 0
+	}
+	metrics.VAALag.WithLabelValues(stage).Observe(lag.Seconds())
+}
+
+// processVAA parses and processes a single VAA, returning the delivery transaction hash
+// (empty if it was filtered)
+func (r *Relayer) processVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	received := time.Now()
+
 	// Check for context cancellation first
 	select {
 	case <-ctx.Done():
 		r.logger.Debug("Processing cancelled for VAA")
-		return ctx.Err()
+		return "", ctx.Err()
 	default:
 		// Continue processing
 	}
@@ -170,41 +498,59 @@ func (r *Relayer) processVAA(ctx context.Context, vaaBytes []byte) error {
 	// Parse the VAA (using permissive parser that handles v1 and v2)
 	wormholeVAA, err := ParseVAAPermissive(vaaBytes)
 	if err != nil {
-		r.logger.Error("Failed to parse VAA", zap.Error(err))
-		return err
-	}
-
-	// Extract the txID from the payload (first 32 bytes)
-	txID := ""
-	if len(wormholeVAA.Payload) >= 32 {
-		txIDBytes := wormholeVAA.Payload[:32]
-		txID = fmt.Sprintf("0x%x", txIDBytes)
-		r.logger.Debug("Extracted txID from payload", zap.String("txID", txID))
-	} else {
-		r.logger.Debug("Payload too short to contain txID", zap.Int("payload_length", len(wormholeVAA.Payload)))
+		fields := []zap.Field{zap.Error(err)}
+		if r.logVAAOnFailure {
+			fields = append(fields, zap.String("rawHex", hex.EncodeToString(vaaBytes)))
+		}
+		r.logger.Error("Failed to parse VAA", fields...)
+		r.summary.recordFailed()
+		return "", err
 	}
 
-	// Create VAA data with essential information
-	vaaData := &VAAData{
-		VAA:        wormholeVAA,
-		RawBytes:   vaaBytes,
-		ChainID:    uint16(wormholeVAA.EmitterChain),
-		EmitterHex: fmt.Sprintf("%064x", wormholeVAA.EmitterAddress),
-		Sequence:   wormholeVAA.Sequence,
-		TxID:       txID,
-	}
+	// Create VAA data with essential information decoded from the VAA and its payload
+	vaaData := NewVAAData(wormholeVAA, vaaBytes)
+	observeLag(lagStageReceived, vaaData, received)
 
 	r.logger.Debug("Processing VAA",
-		zap.Uint16("chain", vaaData.ChainID),
-		zap.Uint64("sequence", vaaData.Sequence),
-		zap.String("emitter", vaaData.EmitterHex),
-		zap.String("sourceTxID", vaaData.TxID))
+		zap.Stringer("vaa", vaaData.ID),
+		zap.String("sourceTxID", vaaData.TxID),
+		zap.Uint16("destinationChainID", vaaData.DestinationChainID),
+		zap.Stringer("value", vaaData.Value))
+
+	// In ordered mode, wait for this emitter's previous sequence to complete
+	if r.sequencer != nil {
+		key := emitterKey{chainID: vaaData.ChainID, emitter: vaaData.EmitterHex}
+		if err := r.sequencer.acquire(ctx, key, vaaData.Sequence); err != nil {
+			r.logger.Debug("Processing cancelled while waiting for predecessor",
+				zap.Stringer("vaa", vaaData.ID))
+			return "", err
+		}
+		defer r.sequencer.release(key, vaaData.Sequence)
+	}
 
 	// Use the passed context when calling the processor
-	if _, err := r.vaaProcessor.ProcessVAA(ctx, *vaaData); err != nil {
-		r.logger.Error("Error processing VAA", zap.Error(err))
-		return err
+	started := time.Now()
+	txHash, err := r.vaaProcessor.ProcessVAA(ctx, *vaaData)
+	r.recordRecentVAA(vaaData, txHash, err)
+	r.recordEvent(vaaData, started, txHash, err)
+	decision := vaaDecision(txHash, err)
+	r.summary.recordHandled(vaaData, decision)
+	if decision != DecisionFiltered {
+		observeLag(lagStageProcessed, vaaData, time.Now())
+	}
+	emitter := emitterKey{chainID: vaaData.ChainID, emitter: vaaData.EmitterHex}
+	if mark, advanced := r.watermarks.observe(emitter, vaaData.Sequence, decision != DecisionFiltered); advanced && r.sequences != nil {
+		if err := r.sequences.record(emitter, mark); err != nil {
+			r.logger.Warn("Failed to record emitter sequence", zap.Stringer("vaa", vaaData.ID), zap.Error(err))
+		}
+	}
+	if err != nil {
+		r.logger.Error("Error processing VAA", zap.Stringer("vaa", vaaData.ID), zap.Error(err))
+		if r.logVAAOnFailure && decision == DecisionFailed {
+			LogVAAFull(r.logger.With(zap.Error(err)), wormholeVAA, vaaBytes)
+		}
+		return "", err
 	}
 
-	return nil
+	return txHash, nil
 }
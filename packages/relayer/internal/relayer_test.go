@@ -0,0 +1,463 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDropMalformedVAA(t *testing.T) {
+	relayer, _ := NewRelayer(zap.NewNop(), nil, nil)
+
+	for _, vaaBytes := range [][]byte{nil, {}, {1}, make([]byte, minVAALength-1)} {
+		if !relayer.dropMalformedVAA(vaaBytes) {
+			t.Errorf("expected a %d-byte VAA to be dropped", len(vaaBytes))
+		}
+	}
+	if relayer.dropMalformedVAA(loadVAAFixture(t, "devnet_token_transfer.hex")) {
+		t.Error("expected a valid VAA to be kept")
+	}
+	// The shortest parseable VAA is kept; the parser decides what to do with it
+	if relayer.dropMalformedVAA(append([]byte{1}, make([]byte, minVAALength-1)...)) {
+		t.Error("expected a VAA of the minimum length to be kept")
+	}
+
+	if relayer.summary.received != 4 || relayer.summary.malformed != 4 {
+		t.Errorf("expected 4 malformed VAAs received, got %d received and %d malformed", relayer.summary.received, relayer.summary.malformed)
+	}
+	if len(relayer.inflightVAAs) != 0 || len(relayer.processedVAAs) != 0 {
+		t.Error("expected malformed VAAs to skip dedup bookkeeping")
+	}
+}
+
+func TestBeginProcessingVAASkipsEarlierDeliveries(t *testing.T) {
+	cache, err := OpenDeliveryCache(filepath.Join(t.TempDir(), "deliveries.jsonl"), 0, 0)
+	if err != nil {
+		t.Fatalf("OpenDeliveryCache failed: %v", err)
+	}
+	defer cache.Close()
+
+	relayer, _ := NewRelayer(zap.NewNop(), nil, nil)
+	relayer.SetDeliveryCache(cache)
+	relayer.recordDelivery("delivered", []byte("vaa"), "0xabc")
+
+	if relayer.beginProcessingVAA("delivered") {
+		t.Error("expected a VAA delivered by an earlier run to be skipped")
+	}
+	if !relayer.beginProcessingVAA("new") {
+		t.Error("expected a new VAA to be processed")
+	}
+	if delivery, ok := cache.Lookup("delivered"); !ok || delivery.TxHash != "0xabc" || time.Since(delivery.DeliveredAt) > time.Minute {
+		t.Errorf("unexpected recorded delivery %+v", delivery)
+	}
+}
+
+func TestValidateDedupeTTL(t *testing.T) {
+	tests := []struct {
+		name              string
+		ttl               time.Duration
+		longestSubmission time.Duration
+		wantErr           bool
+		wantTooShort      bool
+	}{
+		{name: "default ttl, aztec timeout", ttl: DefaultDedupeTTL, longestSubmission: 15 * time.Minute},
+		{name: "ttl longer than the timeout", ttl: time.Hour, longestSubmission: time.Minute},
+		{name: "ttl shorter than the timeout", ttl: time.Minute, longestSubmission: 15 * time.Minute, wantErr: true, wantTooShort: true},
+		{name: "zero ttl", ttl: 0, longestSubmission: time.Minute, wantErr: true},
+		{name: "negative ttl", ttl: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDedupeTTL(tt.ttl, tt.longestSubmission)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if errors.Is(err, ErrDedupeTTLTooShort) != tt.wantTooShort {
+				t.Errorf("expected ErrDedupeTTLTooShort %v, got %v", tt.wantTooShort, err)
+			}
+		})
+	}
+
+	// The TTL set on the relayer decides how long a settled VAA's replays are skipped
+	relayer, _ := NewRelayer(zap.NewNop(), nil, nil)
+	relayer.SetDedupeTTL(time.Millisecond)
+	relayer.beginProcessingVAA("vaa")
+	relayer.finishProcessingVAA("vaa", true)
+	time.Sleep(5 * time.Millisecond)
+	if !relayer.beginProcessingVAA("vaa") {
+		t.Error("expected a replay to be processed again once the TTL expired")
+	}
+}
+
+func TestUnconfirmedDeliveryIsRetried(t *testing.T) {
+	var emitter [32]byte
+	vaaBytes := buildV1VAA(1, 2, emitter, 7, destinationPayload(10003))
+	// The submitter wraps a transaction that was sent but never confirmed as transient
+	unconfirmed := fmt.Errorf("%w: transaction 0xabc included in block 12 but not 3 blocks deep", submitter.ErrTransient)
+	s := &sequenceSubmitter{failures: map[uint64]error{7: unconfirmed}}
+	processor, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	relayer, _ := NewRelayer(zap.NewNop(), nil, processor)
+
+	if !relayer.beginProcessingVAA("vaa") {
+		t.Fatal("expected a new VAA to be processed")
+	}
+	if _, err := relayer.handleVAA(context.Background(), vaaBytes, "vaa"); !errors.Is(err, submitter.ErrTransient) {
+		t.Fatalf("expected the unconfirmed delivery to fail as transient, got %v", err)
+	}
+	if _, ok := relayer.processedVAAs["vaa"]; ok {
+		t.Fatal("expected an unconfirmed delivery not to be marked processed")
+	}
+
+	// The spy replays the VAA once the destination has recovered
+	delete(s.failures, 7)
+	if !relayer.beginProcessingVAA("vaa") {
+		t.Fatal("expected the replayed VAA to be retried")
+	}
+	if txHash, err := relayer.handleVAA(context.Background(), vaaBytes, "vaa"); err != nil || txHash != "0x7" {
+		t.Fatalf("expected the retry to be delivered, got %q (err %v)", txHash, err)
+	}
+	if _, ok := relayer.processedVAAs["vaa"]; !ok {
+		t.Error("expected the confirmed delivery to be marked processed")
+	}
+	if relayer.beginProcessingVAA("vaa") {
+		t.Error("expected a replay after the confirmed delivery to be skipped")
+	}
+}
+
+func TestLogVAAOnFailure(t *testing.T) {
+	var emitter [32]byte
+	failing := buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))
+	succeeding := buildV1VAA(1, 2, emitter, 2, destinationPayload(10003))
+	s := &sequenceSubmitter{failures: map[uint64]error{1: errors.New("transaction reverted")}}
+	processor, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	for _, enabled := range []bool{false, true} {
+		core, logs := observer.New(zapcore.InfoLevel)
+		relayer, _ := NewRelayer(zap.New(core), nil, processor)
+		relayer.SetLogVAAOnFailure(enabled)
+
+		relayer.processVAA(context.Background(), succeeding)
+		relayer.processVAA(context.Background(), failing)
+		relayer.processVAA(context.Background(), []byte{1, 2, 3})
+
+		dumps := logs.FilterMessage("=== Full VAA Details ===").All()
+		if !enabled {
+			if len(dumps) != 0 {
+				t.Errorf("expected no VAA dump when disabled, got %d", len(dumps))
+			}
+			continue
+		}
+		if len(dumps) != 1 {
+			t.Fatalf("expected only the failed VAA to be dumped, got %d dumps", len(dumps))
+		}
+		fields := dumps[0].ContextMap()
+		if fields["rawHex"] != hex.EncodeToString(failing) || fields["sequence"] != uint64(1) || fields["error"] != "transaction failed: transaction reverted" {
+			t.Errorf("unexpected dump fields %v", fields)
+		}
+		parseFailures := logs.FilterMessage("Failed to parse VAA").All()
+		if len(parseFailures) != 1 || parseFailures[0].ContextMap()["rawHex"] != "010203" {
+			t.Errorf("expected the unparseable VAA's raw hex to be logged, got %v", parseFailures)
+		}
+	}
+}
+
+// fakeRecv is one scripted result of fakeVAAStream.Recv: a VAA, or err if set
+type fakeRecv struct {
+	vaa []byte
+	err error
+}
+
+// fakeSpy is a VAASource serving one scripted stream per subscription. Once its script runs
+// out, or if no script is left for a subscription, a stream blocks until its context is done.
+type fakeSpy struct {
+	mu            sync.Mutex
+	scripts       [][]fakeRecv
+	subscriptions int
+	// Returned by the first stream's Recv once its context is done, like a VAA the stream
+	// buffered before the cancellation (nil = fail with the cancellation)
+	late []byte
+}
+
+func (s *fakeSpy) SubscribeSignedVAA(ctx context.Context) (clients.VAAStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions++
+	stream := &fakeVAAStream{ctx: ctx}
+	stream.late, s.late = s.late, nil
+	if len(s.scripts) > 0 {
+		stream.script, s.scripts = s.scripts[0], s.scripts[1:]
+	}
+	return stream, nil
+}
+
+func (s *fakeSpy) NewReconnectBackoff() *clients.Backoff {
+	return clients.NewBackoff(time.Millisecond, time.Millisecond)
+}
+
+func (s *fakeSpy) Close() {}
+
+func (s *fakeSpy) subscriptionCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions
+}
+
+// fakeVAAStream yields its script, then blocks until ctx is done like a cancelled gRPC stream
+type fakeVAAStream struct {
+	ctx    context.Context
+	script []fakeRecv
+	late   []byte
+}
+
+func (s *fakeVAAStream) Recv() (*spyv1.SubscribeSignedVAAResponse, error) {
+	if len(s.script) == 0 {
+		<-s.ctx.Done()
+		if late := s.late; late != nil {
+			s.late = nil
+			return &spyv1.SubscribeSignedVAAResponse{VaaBytes: late}, nil
+		}
+		return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+	}
+	next := s.script[0]
+	s.script = s.script[1:]
+	if next.err != nil {
+		return nil, next.err
+	}
+	return &spyv1.SubscribeSignedVAAResponse{VaaBytes: next.vaa}, nil
+}
+
+// gatedProcessor reports each VAA it starts and delivers on started and delivered. If release
+// is set, each VAA waits for it to be closed, and counts as cancelled if its context ends first.
+type gatedProcessor struct {
+	started   chan uint64
+	delivered chan uint64
+	release   chan struct{}
+
+	mu        sync.Mutex
+	cancelled int
+}
+
+func newGatedProcessor(release chan struct{}) *gatedProcessor {
+	return &gatedProcessor{started: make(chan uint64, 16), delivered: make(chan uint64, 16), release: release}
+}
+
+func (p *gatedProcessor) ProcessVAA(ctx context.Context, vaaData VAAData) (string, error) {
+	p.started <- vaaData.Sequence
+	if p.release != nil {
+		select {
+		case <-p.release:
+		case <-ctx.Done():
+			p.mu.Lock()
+			p.cancelled++
+			p.mu.Unlock()
+			return "", ctx.Err()
+		}
+	}
+	p.delivered <- vaaData.Sequence
+	return fmt.Sprintf("0x%d", vaaData.Sequence), nil
+}
+
+// expectSequences waits for the given sequences, in any order, on ch
+func expectSequences(t *testing.T, ch <-chan uint64, what string, want ...uint64) {
+	t.Helper()
+	got := make(map[uint64]bool)
+	for range want {
+		select {
+		case seq := <-ch:
+			got[seq] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s sequences %v, got %v", what, want, got)
+		}
+	}
+	for _, seq := range want {
+		if !got[seq] {
+			t.Fatalf("expected %s sequences %v, got %v", what, want, got)
+		}
+	}
+}
+
+// startRelayer runs r.Start in the background, returning a function that stops it and returns
+// its error. The function may be called more than once.
+func startRelayer(t *testing.T, r *Relayer) func() error {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Start(ctx) }()
+
+	var once sync.Once
+	var err error
+	return func() error {
+		once.Do(func() {
+			cancel()
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				t.Error("timed out waiting for the relayer to stop")
+			}
+		})
+		return err
+	}
+}
+
+func TestRelayerStartSkipsDuplicates(t *testing.T) {
+	var emitter [32]byte
+	first := buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))
+	second := buildV1VAA(1, 2, emitter, 2, destinationPayload(10003))
+	spy := &fakeSpy{scripts: [][]fakeRecv{{{vaa: first}, {vaa: first}, {vaa: second}}}}
+	processor := newGatedProcessor(nil)
+	relayer, _ := NewRelayer(zap.NewNop(), spy, processor)
+
+	stop := startRelayer(t, relayer)
+	// The second VAA is received after the replay of the first, so both are settled by then
+	expectSequences(t, processor.delivered, "delivered", 1, 2)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+
+	if len(processor.started) != 2 {
+		t.Errorf("expected each VAA to be processed once, got %d starts", len(processor.started))
+	}
+	if relayer.summary.received != 3 || relayer.summary.duplicates != 1 {
+		t.Errorf("expected 3 VAAs received and 1 duplicate, got %d and %d", relayer.summary.received, relayer.summary.duplicates)
+	}
+}
+
+func TestRelayerStartTracksInflightVAAs(t *testing.T) {
+	var emitter [32]byte
+	first := buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))
+	second := buildV1VAA(1, 2, emitter, 2, destinationPayload(10003))
+	release := make(chan struct{})
+	spy := &fakeSpy{scripts: [][]fakeRecv{{{vaa: first}, {vaa: first}, {vaa: second}}}}
+	processor := newGatedProcessor(release)
+	relayer, _ := NewRelayer(zap.NewNop(), spy, processor)
+
+	stop := startRelayer(t, relayer)
+	defer stop()
+	// The second VAA is received after the replay of the first, which is still in flight
+	expectSequences(t, processor.started, "started", 1, 2)
+	key := computeVAAKey(first)
+	relayer.dedupeMu.Lock()
+	_, inflight := relayer.inflightVAAs[key]
+	relayer.dedupeMu.Unlock()
+	if !inflight {
+		t.Fatal("expected the first VAA to be tracked in flight")
+	}
+
+	close(release)
+	expectSequences(t, processor.delivered, "delivered", 1, 2)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	if len(processor.started) != 0 {
+		t.Error("expected the replay of an in-flight VAA to be skipped")
+	}
+	if _, ok := relayer.processedVAAs[key]; !ok || len(relayer.inflightVAAs) != 0 {
+		t.Errorf("expected delivered VAAs to move from in flight to processed, got %d in flight", len(relayer.inflightVAAs))
+	}
+}
+
+func TestRelayerStartWaitsForInflightVAAsOnShutdown(t *testing.T) {
+	var emitter [32]byte
+	spy := &fakeSpy{scripts: [][]fakeRecv{{{vaa: buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))}}}}
+	processor := newGatedProcessor(make(chan struct{}))
+	relayer, _ := NewRelayer(zap.NewNop(), spy, processor)
+
+	stop := startRelayer(t, relayer)
+	expectSequences(t, processor.started, "started", 1)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+
+	// Start returns only once the in-flight VAA has seen its context cancelled and returned
+	processor.mu.Lock()
+	defer processor.mu.Unlock()
+	if processor.cancelled != 1 {
+		t.Errorf("expected the in-flight VAA to be cancelled before Start returned, got %d", processor.cancelled)
+	}
+}
+
+func TestRelayerStartDrainsWithoutStartingNewWork(t *testing.T) {
+	var emitter [32]byte
+	late := buildV1VAA(1, 2, emitter, 2, destinationPayload(10003))
+	spy := &fakeSpy{
+		scripts: [][]fakeRecv{{{vaa: buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))}}},
+		late:    late,
+	}
+	processor := newGatedProcessor(nil)
+	relayer, _ := NewRelayer(zap.NewNop(), spy, processor)
+
+	stop := startRelayer(t, relayer)
+	expectSequences(t, processor.delivered, "delivered", 1)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+
+	// The VAA Recv returned after shutdown began is dropped before it is counted, claimed for
+	// dedup or processed, so a restarted relayer relays it
+	if len(processor.started) != 1 || relayer.summary.received != 1 {
+		t.Errorf("expected only the VAA received before shutdown to be handled, got %d processed and %d received",
+			len(processor.started), relayer.summary.received)
+	}
+	key := computeVAAKey(late)
+	_, inflight := relayer.inflightVAAs[key]
+	_, processed := relayer.processedVAAs[key]
+	if inflight || processed {
+		t.Errorf("expected no dedup claim for the VAA received after shutdown, got in flight %v, processed %v", inflight, processed)
+	}
+	if n := spy.subscriptionCount(); n != 1 {
+		t.Errorf("expected no reconnect during shutdown, got %d subscriptions", n)
+	}
+}
+
+func TestRelayerStartReconnects(t *testing.T) {
+	var emitter [32]byte
+	spy := &fakeSpy{scripts: [][]fakeRecv{
+		{{vaa: buildV1VAA(1, 2, emitter, 1, destinationPayload(10003))}, {err: status.Error(codes.Unavailable, "connection reset")}},
+		{{vaa: buildV1VAA(1, 2, emitter, 2, destinationPayload(10003))}},
+	}}
+	processor := newGatedProcessor(nil)
+	relayer, _ := NewRelayer(zap.NewNop(), spy, processor)
+
+	stop := startRelayer(t, relayer)
+	expectSequences(t, processor.delivered, "delivered", 1, 2)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	if n := spy.subscriptionCount(); n != 2 {
+		t.Errorf("expected one resubscription after the stream error, got %d subscriptions", n)
+	}
+}
+
+func TestRelayerStartStopsOnFatalStreamError(t *testing.T) {
+	spy := &fakeSpy{scripts: [][]fakeRecv{{{err: status.Error(codes.Unimplemented, "unknown service spy.v1.SpyRPCService")}}}}
+	relayer, _ := NewRelayer(zap.NewNop(), spy, newGatedProcessor(nil))
+
+	err := relayer.Start(context.Background())
+	if !errors.Is(err, clients.ErrSpyFatal) {
+		t.Fatalf("expected a fatal stream error, got %v", err)
+	}
+	if n := spy.subscriptionCount(); n != 1 {
+		t.Errorf("expected no resubscription after a fatal error, got %d subscriptions", n)
+	}
+}
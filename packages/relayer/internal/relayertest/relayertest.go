@@ -0,0 +1,228 @@
+// Package relayertest provides in-memory stand-ins for the spy and the destination, so tests can
+// run the relayer pipeline (processor filters, dedup, ordering and the per-VAA goroutines) end to
+// end: push crafted VAAs into a Source and assert what reached the Submitter, and in which order.
+// Only tests import it, so it never ships in the relayer binary.
+package relayertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	spyv1 "github.com/certusone/wormhole/node/pkg/proto/spy/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// VAA describes a v1 VAA to craft. Its single guardian signature is not a valid one, which the
+// relayer never checks.
+type VAA struct {
+	Timestamp        time.Time // Zero = 2023-11-14, a fixed time so the VAA bytes are stable
+	Nonce            uint32
+	EmitterChain     uint16
+	Emitter          [32]byte
+	Sequence         uint64
+	ConsistencyLevel uint8
+	Payload          []byte
+}
+
+// Bytes encodes the VAA as the spy streams it
+func (v VAA) Bytes() []byte {
+	timestamp := uint32(1700000000)
+	if !v.Timestamp.IsZero() {
+		timestamp = uint32(v.Timestamp.Unix())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(1)                                    // version
+	_ = binary.Write(&buf, binary.BigEndian, uint32(0)) // guardian set index
+	buf.WriteByte(1)                                    // signature count
+	buf.WriteByte(0)                                    // guardian index
+	buf.Write(bytes.Repeat([]byte{1}, 65))              // signature
+	_ = binary.Write(&buf, binary.BigEndian, timestamp)
+	_ = binary.Write(&buf, binary.BigEndian, v.Nonce)
+	_ = binary.Write(&buf, binary.BigEndian, v.EmitterChain)
+	buf.Write(v.Emitter[:])
+	_ = binary.Write(&buf, binary.BigEndian, v.Sequence)
+	buf.WriteByte(v.ConsistencyLevel)
+	buf.Write(v.Payload)
+	return buf.Bytes()
+}
+
+// Source is an in-memory VAA source (internal.VAASource). Every subscription reads from the same
+// queue, filled with Push; once it is empty, streams block until more is pushed or their context
+// is done, like an idle spy stream.
+type Source struct {
+	mu            sync.Mutex
+	queue         [][]byte      // Pushed VAAs not received yet
+	changed       chan struct{} // Closed and replaced whenever queue changes
+	subscriptions int
+}
+
+// NewSource returns an empty Source
+func NewSource() *Source {
+	return &Source{changed: make(chan struct{})}
+}
+
+// Push queues vaas, streamed in order
+func (s *Source) Push(vaas ...[]byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queue = append(s.queue, vaas...)
+	s.notify()
+}
+
+// notify wakes every stream and WaitDrained waiting on s. Callers must hold s.mu.
+func (s *Source) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// WaitDrained blocks until every pushed VAA has been received, or ctx is done. A received VAA
+// may still be in processing.
+func (s *Source) WaitDrained(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Subscriptions returns how many streams were opened, 1 plus the relayer's reconnects
+func (s *Source) Subscriptions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscriptions
+}
+
+// SubscribeSignedVAA opens a stream of the queued VAAs
+func (s *Source) SubscribeSignedVAA(ctx context.Context) (clients.VAAStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions++
+	return &stream{source: s, ctx: ctx}, nil
+}
+
+// NewReconnectBackoff returns a 1ms backoff, so a reconnect does not slow a test down
+func (s *Source) NewReconnectBackoff() *clients.Backoff {
+	return clients.NewBackoff(time.Millisecond, time.Millisecond)
+}
+
+// Close does nothing; the queue stays readable
+func (s *Source) Close() {}
+
+// stream receives from the queue of its Source until ctx is done
+type stream struct {
+	source *Source
+	ctx    context.Context
+}
+
+// Recv returns the next queued VAA, waiting for one to be pushed. When ctx is done it fails with
+// codes.Canceled, as a gRPC stream does.
+func (s *stream) Recv() (*spyv1.SubscribeSignedVAAResponse, error) {
+	for {
+		s.source.mu.Lock()
+		if len(s.source.queue) > 0 {
+			next := s.source.queue[0]
+			s.source.queue = s.source.queue[1:]
+			s.source.notify()
+			s.source.mu.Unlock()
+			return &spyv1.SubscribeSignedVAAResponse{VaaBytes: next}, nil
+		}
+		changed := s.source.changed
+		s.source.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-s.ctx.Done():
+			return nil, status.Error(codes.Canceled, s.ctx.Err().Error())
+		}
+	}
+}
+
+// Submission is one VAA a Submitter was asked to submit, and what it answered
+type Submission struct {
+	VAA    []byte
+	TxHash string
+	Err    error
+}
+
+// Submitter is a submitter.VAASubmitter recording every submission in order. It answers with
+// the function it was created with, or delivers every VAA without one.
+type Submitter struct {
+	respond func(ctx context.Context, vaaBytes []byte) (string, error)
+
+	mu          sync.Mutex
+	submissions []Submission
+	changed     chan struct{} // Closed and replaced on every submission
+}
+
+var _ submitter.VAASubmitter = (*Submitter)(nil)
+
+// NewSubmitter returns a Submitter answering with respond, e.g. to fail some VAAs with a
+// classified submitter error. A nil respond delivers every VAA in a transaction of its own.
+func NewSubmitter(respond func(ctx context.Context, vaaBytes []byte) (string, error)) *Submitter {
+	return &Submitter{respond: respond, changed: make(chan struct{})}
+}
+
+// SubmitVAA records vaaBytes and answers it
+func (s *Submitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	// respond runs unlocked, so a respond that blocks does not serialize the submissions
+	var txHash string
+	var err error
+	if s.respond != nil {
+		txHash, err = s.respond(ctx, vaaBytes)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.respond == nil {
+		txHash = fmt.Sprintf("0x%064x", len(s.submissions)+1)
+	}
+	s.submissions = append(s.submissions, Submission{VAA: vaaBytes, TxHash: txHash, Err: err})
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return txHash, err
+}
+
+// Submissions returns the submissions so far, in the order they were made
+func (s *Submitter) Submissions() []Submission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Submission(nil), s.submissions...)
+}
+
+// WaitForSubmissions blocks until at least n submissions were made, or ctx is done
+func (s *Submitter) WaitForSubmissions(ctx context.Context, n int) error {
+	for {
+		s.mu.Lock()
+		if len(s.submissions) >= n {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("%d of %d submissions made: %w", len(s.Submissions()), n, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DecodeVAAHex decodes a VAA copied out of the logs, such as the rawHex field of LogVAAFull.
+// Whitespace anywhere (line wraps, indentation), surrounding quotes and a 0x prefix are
+// tolerated; anything else that is not hex is rejected with its position.
+func DecodeVAAHex(s string) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	s = strings.Trim(s, `"'`)
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+
+	if s == "" {
+		return nil, fmt.Errorf("empty VAA hex")
+	}
+	for i, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return nil, fmt.Errorf("invalid VAA hex: %q at offset %d is not a hex digit", r, i)
+		}
+	}
+	if len(s)%2 != 0 {
+		return nil, fmt.Errorf("invalid VAA hex: odd number of digits (%d); was it cut off?", len(s))
+	}
+	return hex.DecodeString(s)
+}
+
+// ParseVAAHex decodes a VAA copied out of the logs (see DecodeVAAHex) and parses it
+func ParseVAAHex(s string) (*VAAData, error) {
+	vaaBytes, err := DecodeVAAHex(s)
+	if err != nil {
+		return nil, err
+	}
+	wormholeVAA, err := ParseVAAPermissive(vaaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse VAA: %w", err)
+	}
+	return NewVAAData(wormholeVAA, vaaBytes), nil
+}
+
+// ReplayResult is the outcome of replaying a single VAA
+type ReplayResult struct {
+	ID       VAAIdentity
+	Decision string // One of the Decision* values
+	TxHash   string
+	Err      error
+}
+
+// ReplayVAA runs vaaData through processor, which applies its filters and submission timeout
+// like for a VAA from the spy. A VAA the destination already processed is not a failure.
+func ReplayVAA(ctx context.Context, processor VAAProcessor, vaaData VAAData) ReplayResult {
+	txHash, err := processor.ProcessVAA(ctx, vaaData)
+	result := ReplayResult{ID: vaaData.ID, Decision: vaaDecision(txHash, err), TxHash: txHash}
+	if result.Decision == DecisionFailed {
+		result.Err = err
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDecodeVAAHex(t *testing.T) {
+	want := []byte{0x01, 0xab, 0xcd}
+	for name, input := range map[string]string{
+		"plain":         "01abcd",
+		"0x prefix":     "0x01ABCD",
+		"wrapped":       "  0x01ab\n\tcd \r\n",
+		"quoted":        `"01abcd"`,
+		"quoted prefix": `'0x01abcd'`,
+	} {
+		got, err := DecodeVAAHex(input)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %x, got %x (%v)", name, want, got, err)
+		}
+	}
+
+	for name, tt := range map[string]struct {
+		input, want string
+	}{
+		"empty":        {"  0x ", "empty"},
+		"odd length":   {"01abc", "odd number of digits"},
+		"not hex":      {"01abzz", `'z' at offset 4`},
+		"log ellipsis": {"01ab...", `'.' at offset 4`},
+	} {
+		if _, err := DecodeVAAHex(tt.input); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error mentioning %q, got %v", name, tt.want, err)
+		}
+	}
+}
+
+func TestReplayVAAFromLogs(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xcd
+	vaaBytes := buildV1VAA(1, 2, emitter, 42, destinationPayload(10003))
+	wormholeVAA, err := ParseVAAPermissive(vaaBytes)
+	if err != nil {
+		t.Fatalf("ParseVAAPermissive failed: %v", err)
+	}
+
+	// Log the VAA as a failure would, then copy its hex back out of the log line
+	core, logs := observer.New(zap.InfoLevel)
+	LogVAAFull(zap.New(core), wormholeVAA, vaaBytes)
+	entries := logs.FilterMessage("=== Full VAA Details ===").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected one full VAA log line, got %d", len(entries))
+	}
+	rawHex, _ := entries[0].ContextMap()["rawHex"].(string)
+	copied := "0x" + rawHex[:40] + "\n  " + rawHex[40:] + "\n"
+
+	vaaData, err := ParseVAAHex(copied)
+	if err != nil {
+		t.Fatalf("ParseVAAHex failed: %v", err)
+	}
+	if !reflect.DeepEqual(vaaData.RawBytes, vaaBytes) || vaaData.Sequence != 42 {
+		t.Fatalf("expected the logged VAA back, got %s", vaaData.ID)
+	}
+
+	s := &sequenceSubmitter{}
+	processor, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	result := ReplayVAA(context.Background(), processor, *vaaData)
+	if result.Decision != DecisionSubmitted || result.Err != nil || result.ID != vaaData.ID {
+		t.Errorf("expected %s to be submitted, got %+v", vaaData.ID, result)
+	}
+	if want := []uint64{42}; !reflect.DeepEqual(s.submitted, want) {
+		t.Errorf("expected sequences %v to be submitted, got %v", want, s.submitted)
+	}
+}
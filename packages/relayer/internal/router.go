@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+	"go.uber.org/zap"
+)
+
+// Route sends VAAs whose payload targets DestinationChainID to Submitter
+type Route struct {
+	DestinationChainID uint16
+	Submitter          submitter.VAASubmitter
+	// Source filters and submission limits for this route. Its DestinationChainID is
+	// overwritten with the route's, so only VAAs for this destination are submitted.
+	Config VAAProcessorConfig
+}
+
+// Router dispatches each VAA to the route for its payload's destination chain. VAAs whose
+// destination has no route are logged and dropped. It is safe for concurrent use.
+type Router struct {
+	logger     *zap.Logger
+	processors map[uint16]*DefaultVAAProcessor
+	// Union of the routes' source chains, used to tell stray VAAs from misrouted ones (nil = any)
+	sourceChains []uint16
+}
+
+// NewRouter creates a router over routes, each with its own filters, rate limit and submission
+// deadline. Destinations must be non-zero and unique.
+func NewRouter(logger *zap.Logger, routes []Route) (*Router, error) {
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("at least one route is required")
+	}
+
+	router := &Router{
+		logger:     logger.With(zap.String("component", "Router")),
+		processors: make(map[uint16]*DefaultVAAProcessor, len(routes)),
+	}
+	anySource := false
+	for _, route := range routes {
+		if route.DestinationChainID == 0 {
+			return nil, fmt.Errorf("route destination chain ID must not be 0")
+		}
+		if _, ok := router.processors[route.DestinationChainID]; ok {
+			return nil, fmt.Errorf("duplicate route for destination chain %d", route.DestinationChainID)
+		}
+
+		config := route.Config
+		config.DestinationChainID = route.DestinationChainID
+		processor, err := NewDefaultVAAProcessor(logger.With(zap.Uint16("route", route.DestinationChainID)), config, route.Submitter)
+		if err != nil {
+			return nil, fmt.Errorf("route for destination chain %d: %v", route.DestinationChainID, err)
+		}
+		router.processors[route.DestinationChainID] = processor
+
+		if len(config.ChainIDs) == 0 {
+			anySource = true
+		}
+		router.sourceChains = append(router.sourceChains, config.ChainIDs...)
+	}
+	if anySource {
+		router.sourceChains = nil
+	}
+
+	return router, nil
+}
+
+// Destinations returns the destination chain IDs with a route, in ascending order
+func (r *Router) Destinations() []uint16 {
+	destinations := make([]uint16, 0, len(r.processors))
+	for chainID := range r.processors {
+		destinations = append(destinations, chainID)
+	}
+	slices.Sort(destinations)
+	return destinations
+}
+
+// ProcessVAA hands vaaData to the route for its destination chain
+func (r *Router) ProcessVAA(ctx context.Context, vaaData VAAData) (string, error) {
+	if vaaData.HasDestination {
+		if processor, ok := r.processors[vaaData.DestinationChainID]; ok {
+			return processor.ProcessVAA(ctx, vaaData)
+		}
+	}
+
+	// The spy streams every VAA, so only those from a routed source chain are worth an Info line
+	log := r.logger.Debug
+	if r.sourceChains == nil || containsChainID(r.sourceChains, vaaData.ChainID) {
+		log = r.logger.Info
+	}
+	if !vaaData.HasDestination {
+		// Without a destination no route's ShortPayloads policy applies
+		log("Dropping VAA (payload too short to carry a destination chain)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Int("payloadLength", len(vaaData.VAA.Payload)),
+			zap.Int("clients.MinPayloadLength", clients.MinPayloadLength(vaaData.ChainID)))
+	} else {
+		log("Dropping VAA (no route for destination chain)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Uint16("destinationChain", vaaData.DestinationChainID))
+	}
+	return "", nil
+}
+
+// ReloadEmitterLists re-reads the emitter lists of every route. Routes that fail to reload
+// keep their previous lists; the errors are joined.
+func (r *Router) ReloadEmitterLists() error {
+	var errs []error
+	for _, chainID := range r.Destinations() {
+		if err := r.processors[chainID].ReloadEmitterLists(); err != nil {
+			errs = append(errs, fmt.Errorf("route for destination chain %d: %w", chainID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,84 @@
+package internal
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// destinationPayload builds a default-layout payload for destination chainID
+func destinationPayload(chainID uint16) []byte {
+	payload := make([]byte, 18)
+	payload[0], payload[1] = byte(chainID>>8), byte(chainID)
+	return payload
+}
+
+func TestRouterDispatchesByDestination(t *testing.T) {
+	arbitrum, solana := &countingSubmitter{}, &countingSubmitter{}
+	router, err := NewRouter(zap.NewNop(), []Route{
+		{DestinationChainID: 10003, Submitter: arbitrum},
+		{DestinationChainID: 1, Submitter: solana, Config: VAAProcessorConfig{ChainIDs: []uint16{10003}}},
+	})
+	if err != nil {
+		t.Fatalf("NewRouter failed: %v", err)
+	}
+	if got := router.Destinations(); !slices.Equal(got, []uint16{1, 10003}) {
+		t.Fatalf("expected destinations [1 10003], got %v", got)
+	}
+
+	tests := []struct {
+		name    string
+		payload []byte
+		want    *countingSubmitter // nil = dropped
+	}{
+		{name: "to arbitrum", payload: destinationPayload(10003), want: arbitrum},
+		{name: "to solana from a filtered source", payload: destinationPayload(1), want: nil},
+		{name: "unknown destination", payload: destinationPayload(10004), want: nil},
+		{name: "no destination", payload: make([]byte, 10), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arbitrum.calls, solana.calls = 0, 0
+			txHash, err := router.ProcessVAA(context.Background(), testVAADataWithPayload(tt.payload))
+			if err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+			for _, s := range []*countingSubmitter{arbitrum, solana} {
+				if want := s == tt.want; (s.calls == 1) != want {
+					t.Errorf("expected submitted = %v, got %d calls", want, s.calls)
+				}
+			}
+			if (txHash != "") != (tt.want != nil) {
+				t.Errorf("unexpected tx hash %q", txHash)
+			}
+		})
+	}
+}
+
+func TestNewRouterRejectsInvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+	}{
+		{name: "no routes", routes: nil},
+		{name: "destination 0", routes: []Route{{DestinationChainID: 0, Submitter: &countingSubmitter{}}}},
+		{name: "duplicate destination", routes: []Route{
+			{DestinationChainID: 1, Submitter: &countingSubmitter{}},
+			{DestinationChainID: 1, Submitter: &countingSubmitter{}},
+		}},
+		{name: "invalid route config", routes: []Route{
+			{DestinationChainID: 1, Submitter: &countingSubmitter{}, Config: VAAProcessorConfig{EmitterAddress: "not-hex"}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewRouter(zap.NewNop(), tt.routes); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+)
+
+// selfTestCase is a synthetic VAA with known fields, and the payload fields it must decode to
+type selfTestCase struct {
+	name               string
+	vaa                *vaaLib.VAA
+	destinationChainID uint16
+	value              *big.Int
+	txID               string // Empty for payloads without a source tx ID
+}
+
+// selfTestCases returns one VAA per payload layout. The fields use distinct, asymmetric byte
+// patterns so an off-by-one offset or swapped field reads a different value.
+func selfTestCases() []selfTestCase {
+	var emitter vaaLib.Address
+	for i := range emitter {
+		emitter[i] = byte(0xa0 + i)
+	}
+	signatures := make([]*vaaLib.Signature, 3)
+	for i := range signatures {
+		signatures[i] = &vaaLib.Signature{Index: uint8(2*i + 1)}
+		for j := range signatures[i].Signature {
+			signatures[i].Signature[j] = byte(i*65 + j)
+		}
+	}
+	newVAA := func(chain vaaLib.ChainID, sequence uint64, payload []byte) *vaaLib.VAA {
+		return &vaaLib.VAA{
+			Version:          vaaLib.SupportedVAAVersion,
+			GuardianSetIndex: 0x01020304,
+			Signatures:       signatures,
+			Timestamp:        time.Unix(0x65a1b2c3, 0),
+			Nonce:            0x0badf00d,
+			Sequence:         sequence,
+			ConsistencyLevel: 200,
+			EmitterChain:     chain,
+			EmitterAddress:   emitter,
+			Payload:          payload,
+		}
+	}
+
+	value, _ := new(big.Int).SetString("0x0123456789abcdef0fedcba987654321", 0)
+	var txID [32]byte
+	for i := range txID {
+		txID[i] = byte(0x10 + i)
+	}
+	valueBytes := value.FillBytes(make([]byte, 16))
+	aztecPayload := append(append(append([]byte{}, txID[:]...), 0x27, 0x14), valueBytes...)
+	defaultPayload := append([]byte{0x27, 0x13}, valueBytes...)
+
+	return []selfTestCase{
+		{
+			name:               "aztec payload",
+			vaa:                newVAA(56, 0x0102030405060708, aztecPayload), // Aztec emits the Aztec layout
+			destinationChainID: 0x2714,
+			value:              value,
+			txID:               fmt.Sprintf("0x%x", txID),
+		},
+		{
+			name:               "default payload",
+			vaa:                newVAA(0x0138, 0x1112131415161718, defaultPayload),
+			destinationChainID: 0x2713,
+			value:              value,
+		},
+	}
+}
+
+// SelfTest serializes synthetic VAAs with known fields, parses them back with ParseVAAPermissive
+// and decodes their payloads, and returns an error naming every field that did not round-trip.
+// It guards the hand-rolled offset math against regressions at startup.
+func SelfTest(logger *zap.Logger) error {
+	for _, tc := range selfTestCases() {
+		data, err := runSelfTestCase(tc)
+		if err != nil {
+			return fmt.Errorf("self-test %s: %v", tc.name, err)
+		}
+		logger.Debug("Self-test passed",
+			zap.String("case", tc.name),
+			zap.Int("vaaLength", len(data.RawBytes)),
+			zap.Stringer("vaa", data.ID),
+			zap.Uint16("destinationChainID", data.DestinationChainID),
+			zap.String("value", data.Value.String()),
+			zap.String("txID", data.TxID))
+	}
+	return nil
+}
+
+// runSelfTestCase round-trips the VAA of tc, returning its decoded data when every field matches
+func runSelfTestCase(tc selfTestCase) (*VAAData, error) {
+	want := tc.vaa
+	raw, err := want.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize VAA: %v", err)
+	}
+	got, err := ParseVAAPermissive(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse VAA: %v", err)
+	}
+	data := NewVAAData(got, raw)
+
+	var mismatches []string
+	check := func(field string, ok bool, gotValue, wantValue interface{}) {
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: got %v, want %v", field, gotValue, wantValue))
+		}
+	}
+	check("version", got.Version == want.Version, got.Version, want.Version)
+	check("guardianSetIndex", got.GuardianSetIndex == want.GuardianSetIndex, got.GuardianSetIndex, want.GuardianSetIndex)
+	check("signatureCount", len(got.Signatures) == len(want.Signatures), len(got.Signatures), len(want.Signatures))
+	for i := 0; i < len(got.Signatures) && i < len(want.Signatures); i++ {
+		check(fmt.Sprintf("signature[%d].index", i), got.Signatures[i].Index == want.Signatures[i].Index, got.Signatures[i].Index, want.Signatures[i].Index)
+		check(fmt.Sprintf("signature[%d]", i), got.Signatures[i].Signature == want.Signatures[i].Signature,
+			fmt.Sprintf("%x", got.Signatures[i].Signature), fmt.Sprintf("%x", want.Signatures[i].Signature))
+	}
+	check("timestamp", got.Timestamp.Equal(want.Timestamp), got.Timestamp.Unix(), want.Timestamp.Unix())
+	check("nonce", got.Nonce == want.Nonce, got.Nonce, want.Nonce)
+	check("emitterChain", got.EmitterChain == want.EmitterChain, uint16(got.EmitterChain), uint16(want.EmitterChain))
+	check("emitterAddress", got.EmitterAddress == want.EmitterAddress, got.EmitterAddress, want.EmitterAddress)
+	check("sequence", got.Sequence == want.Sequence, got.Sequence, want.Sequence)
+	check("consistencyLevel", got.ConsistencyLevel == want.ConsistencyLevel, got.ConsistencyLevel, want.ConsistencyLevel)
+	check("payload", bytes.Equal(got.Payload, want.Payload), fmt.Sprintf("%x", got.Payload), fmt.Sprintf("%x", want.Payload))
+	check("destinationChainID", data.HasDestination && data.DestinationChainID == tc.destinationChainID, data.DestinationChainID, tc.destinationChainID)
+	check("value", data.Value != nil && data.Value.Cmp(tc.value) == 0, data.Value, tc.value)
+	check("txID", data.TxID == tc.txID, data.TxID, tc.txID)
+
+	if len(mismatches) > 0 {
+		return nil, fmt.Errorf("round-trip mismatch: %s", strings.Join(mismatches, "; "))
+	}
+	return data, nil
+}
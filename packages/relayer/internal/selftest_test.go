@@ -0,0 +1,26 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := SelfTest(zap.NewNop()); err != nil {
+		t.Fatalf("expected the self-test to pass, got %v", err)
+	}
+
+	// A field that does not decode as expected is reported by name
+	tc := selfTestCases()[0]
+	tc.destinationChainID++
+	tc.txID = ""
+	_, err := runSelfTestCase(tc)
+	if err == nil || !strings.Contains(err.Error(), "destinationChainID") || !strings.Contains(err.Error(), "txID") {
+		t.Fatalf("expected the destination and tx ID mismatches to be reported, got %v", err)
+	}
+	if strings.Contains(err.Error(), "sequence") {
+		t.Errorf("expected only the mismatched fields to be reported, got %v", err)
+	}
+}
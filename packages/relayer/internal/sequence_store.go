@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SequenceStoreSuffix is appended to the delivery cache path to place the sequence store beside it
+// when no path of its own is configured
+const SequenceStoreSuffix = ".sequences"
+
+// EmitterSequence is the last sequence processed from one emitter, as persisted in the sequence store
+type EmitterSequence struct {
+	ChainID  uint16    `json:"chainId"`
+	Emitter  string    `json:"emitter"` // Normalized emitter address (64 hex chars)
+	Sequence uint64    `json:"sequence"`
+	SeenAt   time.Time `json:"seenAt"`
+}
+
+// SequenceStore persists the highest sequence processed from each monitored emitter, so a
+// restarted relayer knows where it stopped and can backfill what was signed meanwhile. It
+// records the emitter watermarks of gap backfill and is safe for concurrent use.
+//
+// The file is a single JSON document rewritten atomically whenever a watermark advances, so a
+// crash keeps either the previous or the new positions. It holds one entry per emitter, which
+// keeps the rewrite cheap.
+type SequenceStore struct {
+	mu    sync.Mutex
+	path  string
+	marks map[emitterKey]watermark
+}
+
+// sequenceStoreFile is the on-disk layout of a SequenceStore
+type sequenceStoreFile struct {
+	Emitters []EmitterSequence `json:"emitters"`
+}
+
+// OpenSequenceStore loads the sequence store at path. A missing file is an empty store, created
+// on the first record.
+func OpenSequenceStore(path string) (*SequenceStore, error) {
+	s := &SequenceStore{path: path, marks: make(map[emitterKey]watermark)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open sequence store: %v", err)
+	}
+	var file sequenceStoreFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("decode sequence store %s: %v", path, err)
+	}
+	for _, entry := range file.Emitters {
+		key := emitterKey{chainID: entry.ChainID, emitter: entry.Emitter}
+		if mark, ok := s.marks[key]; !ok || entry.Sequence > mark.sequence {
+			s.marks[key] = watermark{sequence: entry.Sequence, seenAt: entry.SeenAt}
+		}
+	}
+	return s, nil
+}
+
+// Sequences returns the recorded position of every emitter, sorted by chain and emitter
+func (s *SequenceStore) Sequences() []EmitterSequence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sequences()
+}
+
+// sequences returns the recorded positions, sorted. Callers must hold s.mu.
+func (s *SequenceStore) sequences() []EmitterSequence {
+	entries := make([]EmitterSequence, 0, len(s.marks))
+	for key, mark := range s.marks {
+		entries = append(entries, EmitterSequence{
+			ChainID:  key.chainID,
+			Emitter:  key.emitter,
+			Sequence: mark.sequence,
+			SeenAt:   mark.seenAt,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ChainID != entries[j].ChainID {
+			return entries[i].ChainID < entries[j].ChainID
+		}
+		return entries[i].Emitter < entries[j].Emitter
+	})
+	return entries
+}
+
+// watermarks returns the recorded positions keyed by emitter
+func (s *SequenceStore) watermarks() map[emitterKey]watermark {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	marks := make(map[emitterKey]watermark, len(s.marks))
+	for key, mark := range s.marks {
+		marks[key] = mark
+	}
+	return marks
+}
+
+// record raises the position of key to mark and rewrites the file. A mark at or below the
+// recorded sequence is ignored.
+func (s *SequenceStore) record(key emitterKey, mark watermark) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if current, ok := s.marks[key]; ok && mark.sequence <= current.sequence {
+		return nil
+	}
+	s.marks[key] = mark
+	return s.write()
+}
+
+// write replaces the file with the current positions. Callers must hold s.mu.
+func (s *SequenceStore) write() error {
+	data, err := json.MarshalIndent(sequenceStoreFile{Emitters: s.sequences()}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("write sequence store: %v", err)
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write sequence store: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write sequence store: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write sequence store: %v", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("write sequence store: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestSequenceStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relayer.sequences")
+	store, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("OpenSequenceStore failed: %v", err)
+	}
+	if len(store.Sequences()) != 0 {
+		t.Fatalf("expected a missing file to be an empty store, got %+v", store.Sequences())
+	}
+
+	seenAt := time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC)
+	first := emitterKey{chainID: 2, emitter: "aa"}
+	second := emitterKey{chainID: 1, emitter: "bb"}
+	for _, record := range []struct {
+		key emitterKey
+		seq uint64
+	}{{first, 5}, {second, 9}, {first, 7}, {first, 6}} {
+		if err := store.record(record.key, watermark{sequence: record.seq, seenAt: seenAt}); err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+
+	reopened, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("reopening the store failed: %v", err)
+	}
+	want := []EmitterSequence{
+		{ChainID: 1, Emitter: "bb", Sequence: 9, SeenAt: seenAt},
+		{ChainID: 2, Emitter: "aa", Sequence: 7, SeenAt: seenAt}, // A lower sequence does not move it back
+	}
+	if got := reopened.Sequences(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenSequenceStore(path); err == nil {
+		t.Error("expected a corrupt store to be rejected")
+	}
+}
+
+func TestRelayerResumesFromSequenceStore(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xcd
+	key := emitterKey{chainID: 2, emitter: NormalizeEmitter(emitter[:])}
+	fetcher := &fakeFetcher{vaas: make(map[uint64][]byte)}
+	for seq := uint64(1); seq <= 18; seq++ {
+		fetcher.vaas[seq] = buildV1VAA(1, 2, emitter, seq, destinationPayload(10003))
+	}
+
+	// The previous run processed up to sequence 15 before it stopped
+	path := filepath.Join(t.TempDir(), "relayer.sequences")
+	previous, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("OpenSequenceStore failed: %v", err)
+	}
+	if err := previous.record(key, watermark{sequence: 15, seenAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+
+	store, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("reopening the store failed: %v", err)
+	}
+	processor := newGatedProcessor(nil)
+	relayer, _ := NewRelayer(zap.NewNop(), &fakeSpy{}, processor)
+	relayer.SetGapBackfill(fetcher, 0)
+	relayer.SetSequenceStore(store, true)
+
+	stop := startRelayer(t, relayer)
+	expectSequences(t, processor.delivered, "delivered", 16, 17, 18)
+	if err := stop(); err != nil {
+		t.Fatalf("expected a clean shutdown, got %v", err)
+	}
+	if len(processor.started) != 3 {
+		t.Errorf("expected only the sequences after the recorded one to be relayed, got %d", len(processor.started))
+	}
+
+	// The next run resumes after the backfilled sequences
+	next, err := OpenSequenceStore(path)
+	if err != nil {
+		t.Fatalf("reopening the store failed: %v", err)
+	}
+	if got := next.Sequences(); len(got) != 1 || got[0].Sequence != 18 {
+		t.Errorf("expected the store to resume after sequence 18, got %+v", got)
+	}
+}
@@ -2,48 +2,76 @@ package submitter
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
 
 	"go.uber.org/zap"
 
 	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
 )
 
 type AztecSubmitter struct {
 	targetContract     string
-	pxeClient          *clients.AztecPXEClient
-	verificationClient *clients.VerificationServiceClient
-	logger             *zap.Logger
+	pxeClient          AztecRelayer
+	verificationClient AztecVerifier
+	// When set, SubmitVAA only succeeds once the tx is included in a block (requires the PXE client)
+	confirmation *clients.AztecConfirmationConfig
+	logger       *zap.Logger
 }
 
-func NewAztecSubmitter(logger *zap.Logger, targetContract string, pxeClient *clients.AztecPXEClient, verificationClient *clients.VerificationServiceClient) *AztecSubmitter {
+func NewAztecSubmitter(logger *zap.Logger, targetContract string, pxeClient AztecRelayer, verificationClient AztecVerifier) *AztecSubmitter {
 	return &AztecSubmitter{
 		targetContract:     targetContract,
-		pxeClient:          pxeClient,
-		verificationClient: verificationClient,
+		pxeClient:          nilClient(pxeClient),
+		verificationClient: nilClient(verificationClient),
 		logger:             logger.With(zap.String("component", "AztecSubmitter")),
 	}
 }
 
-func (s *AztecSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
-	// Create a context with timeout for submission operations (15 minutes for Aztec)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
-	defer cancel()
+// NewAztecSubmitterWithConfirmation creates an Aztec submitter that waits, via the PXE node,
+// for each transaction to be included in a block before reporting success
+func NewAztecSubmitterWithConfirmation(logger *zap.Logger, targetContract string, pxeClient AztecRelayer, verificationClient AztecVerifier, confirmation clients.AztecConfirmationConfig) *AztecSubmitter {
+	s := NewAztecSubmitter(logger, targetContract, pxeClient, verificationClient)
+	s.confirmation = &confirmation
+	return s
+}
+
+func (s *AztecSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (txHash string, err error) {
+	defer func() { recordFailure("aztec", err) }()
+
+	if s.verificationClient == nil && s.pxeClient == nil {
+		return "", fmt.Errorf("%w: Aztec submitter has neither a verification service nor a PXE client", ErrNilClient)
+	}
+	if s.confirmation != nil && s.pxeClient == nil {
+		return "", fmt.Errorf("%w: Aztec inclusion confirmation requires a PXE client", ErrNilClient)
+	}
 
 	s.logger.Info("Submitting VAA to Aztec",
 		zap.Int("vaaLength", len(vaaBytes)),
 		zap.String("targetContract", s.targetContract))
 
-	var txHash string
-	var err error
+	timer := metrics.NewSubmissionTimer(s.logger, "aztec")
+	defer timer.Finish()
 
 	// Try verification service first, fallback to direct PXE if available
-	txHash, err = s.verificationClient.VerifyVAA(ctx, vaaBytes)
-	if err != nil {
+	if s.verificationClient != nil {
+		stopVerification := timer.StartPhase("verification_service")
+		txHash, err = s.verificationClient.VerifyVAA(ctx, vaaBytes)
+		stopVerification()
+	}
+	if s.verificationClient == nil {
+		s.logger.Debug("No verification service configured, using direct PXE")
+		stopPXE := timer.StartPhase("pxe")
+		txHash, err = s.pxeClient.SendVerifyTransaction(ctx, s.targetContract, vaaBytes)
+		stopPXE()
+	} else if err != nil {
 		if s.pxeClient != nil {
 			s.logger.Warn("Verification service failed, trying direct PXE", zap.Error(err))
 			// Fallback to direct PXE call
+			stopPXE := timer.StartPhase("pxe")
 			txHash, err = s.pxeClient.SendVerifyTransaction(ctx, s.targetContract, vaaBytes)
+			stopPXE()
 		} else {
 			s.logger.Error("Verification service failed and no PXE fallback available", zap.Error(err))
 		}
@@ -53,7 +81,18 @@ func (s *AztecSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string
 
 	if err != nil {
 		s.logger.Error("Failed to submit VAA to Aztec", zap.Error(err))
-		return "", err
+		return "", classifyDestinationError(err)
+	}
+
+	// Wait for the transaction to land in a block if confirmation is enabled
+	if s.confirmation != nil {
+		stopConfirm := timer.StartPhase("confirmation")
+		_, err = s.pxeClient.WaitForTransaction(ctx, txHash, *s.confirmation)
+		stopConfirm()
+		if err != nil {
+			s.logger.Error("Aztec transaction was not confirmed", zap.String("txHash", txHash), zap.Error(err))
+			return "", classifyConfirmationError(fmt.Errorf("failed to confirm Aztec transaction: %w", err))
+		}
 	}
 
 	s.logger.Info("VAA successfully submitted to Aztec",
@@ -62,3 +101,12 @@ func (s *AztecSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string
 
 	return txHash, nil
 }
+
+// classifyConfirmationError classifies a WaitForTransaction failure. A dropped transaction is
+// transient (the VAA was never consumed); reverts and timeouts follow classifyDestinationError.
+func classifyConfirmationError(err error) error {
+	if errors.Is(err, clients.ErrAztecTxDropped) {
+		return classify(ErrTransient, err)
+	}
+	return classifyDestinationError(err)
+}
@@ -0,0 +1,81 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestAztecSubmitterSubmitVAA(t *testing.T) {
+	vaaBytes := buildTestVAA(make([]byte, 50))
+	confirmation := clients.AztecConfirmationConfig{PollInterval: time.Millisecond, Timeout: time.Second}
+
+	t.Run("verification service", func(t *testing.T) {
+		pxe := &mockAztecRelayer{txHash: "0xpxe"}
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", pxe, &mockAztecVerifier{txHash: "0xverified"})
+		if txHash, err := s.SubmitVAA(context.Background(), vaaBytes); err != nil || txHash != "0xverified" {
+			t.Fatalf("expected tx 0xverified, got %q (err %v)", txHash, err)
+		}
+		if pxe.sent != 0 {
+			t.Error("expected the PXE not to be used when the verification service succeeds")
+		}
+	})
+
+	t.Run("verification failure", func(t *testing.T) {
+		verifier := &mockAztecVerifier{err: errors.New("verification failed: invalid guardian signature")}
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", nil, verifier)
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrPermanent) {
+			t.Errorf("expected ErrPermanent, got %v (class %s)", err, ErrorClass(err))
+		}
+		if verifier.calls != 1 {
+			t.Errorf("expected 1 verification call, got %d", verifier.calls)
+		}
+	})
+
+	t.Run("verification timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", nil, &mockAztecVerifier{block: true})
+		_, err := s.SubmitVAA(ctx, vaaBytes)
+		if !errors.Is(err, ErrTransient) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected a transient deadline error, got %v (class %s)", err, ErrorClass(err))
+		}
+	})
+
+	t.Run("falls back to PXE", func(t *testing.T) {
+		pxe := &mockAztecRelayer{txHash: "0xpxe"}
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", pxe, &mockAztecVerifier{err: errors.New("503 Service Unavailable")})
+		if txHash, err := s.SubmitVAA(context.Background(), vaaBytes); err != nil || txHash != "0xpxe" {
+			t.Fatalf("expected tx 0xpxe, got %q (err %v)", txHash, err)
+		}
+	})
+
+	t.Run("verification service only", func(t *testing.T) {
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", nil, &mockAztecVerifier{err: errors.New("simulation failed: Existing nullifier")})
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrAlreadyProcessed) {
+			t.Errorf("expected ErrAlreadyProcessed, got %v (class %s)", err, ErrorClass(err))
+		}
+	})
+
+	t.Run("dropped before inclusion", func(t *testing.T) {
+		pxe := &mockAztecRelayer{txHash: "0xpxe", confirmErr: fmt.Errorf("tx 0xpxe: %w", clients.ErrAztecTxDropped)}
+		s := NewAztecSubmitterWithConfirmation(zap.NewNop(), "0x1234", pxe, nil, confirmation)
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrTransient) {
+			t.Errorf("expected ErrTransient, got %v (class %s)", err, ErrorClass(err))
+		}
+	})
+
+	t.Run("reverted", func(t *testing.T) {
+		pxe := &mockAztecRelayer{sendErr: errors.New("Assertion failed: invalid guardian signature")}
+		s := NewAztecSubmitter(zap.NewNop(), "0x1234", pxe, nil)
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrPermanent) {
+			t.Errorf("expected ErrPermanent, got %v (class %s)", err, ErrorClass(err))
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// contextBound is how long a submitter may take to return once its context is done
+const contextBound = time.Second
+
+// blocker stands in for a node that never answers: every call waits for its context to end
+type blocker struct {
+	active atomic.Int32 // Calls still waiting
+}
+
+func (b *blocker) wait(ctx context.Context) error {
+	b.active.Add(1)
+	defer b.active.Add(-1)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+type blockingEVMRelayer struct{ *blocker }
+
+func (m blockingEVMRelayer) RelayVAA(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	return "", m.wait(ctx)
+}
+
+func (m blockingEVMRelayer) VerifyEmitterRegistered(ctx context.Context, targetContract string, chainID uint16, emitter [32]byte) error {
+	return nil
+}
+
+func (m blockingEVMRelayer) IsVAAProcessed(ctx context.Context, targetContract string, ref clients.VAAReference) (bool, error) {
+	return false, nil
+}
+
+func (m blockingEVMRelayer) GetAddress() common.Address { return common.Address{} }
+
+// blockingSolanaRelayer blocks in PostVAAToWormhole, or in SendReceiveValueTransaction once posted is set
+type blockingSolanaRelayer struct {
+	*blocker
+	posted bool
+}
+
+func (m blockingSolanaRelayer) PostVAAToWormhole(ctx context.Context, vaaBytes []byte) (solana.PublicKey, error) {
+	if m.posted {
+		return solana.PublicKey{}, nil
+	}
+	return solana.PublicKey{}, m.wait(ctx)
+}
+
+func (m blockingSolanaRelayer) SendReceiveValueTransaction(ctx context.Context, vaaBytes []byte, emitterChain uint16, sequence uint64) (string, error) {
+	return "", m.wait(ctx)
+}
+
+func (m blockingSolanaRelayer) SendReceiveValueBatch(ctx context.Context, requests []clients.ReceiveValueRequest) ([]clients.ReceiveValueResult, error) {
+	return nil, m.wait(ctx)
+}
+
+func (m blockingSolanaRelayer) VerifyEmitterRegistered(ctx context.Context, chainID uint16, emitter [32]byte) error {
+	return nil
+}
+
+func (m blockingSolanaRelayer) GetProgramID() solana.PublicKey    { return solana.SystemProgramID }
+func (m blockingSolanaRelayer) GetPayerAddress() solana.PublicKey { return solana.PublicKey{} }
+
+// blockingAztecRelayer blocks in SendVerifyTransaction, or in WaitForTransaction once sent is set
+type blockingAztecRelayer struct {
+	*blocker
+	sent bool
+}
+
+func (m blockingAztecRelayer) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	if m.sent {
+		return "0xpxe", nil
+	}
+	return "", m.wait(ctx)
+}
+
+func (m blockingAztecRelayer) WaitForTransaction(ctx context.Context, txHash string, config clients.AztecConfirmationConfig) (uint64, error) {
+	return 0, m.wait(ctx)
+}
+
+type blockingAztecVerifier struct{ *blocker }
+
+func (m blockingAztecVerifier) VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	return "", m.wait(ctx)
+}
+
+type blockingCosmosExecutor struct{ *blocker }
+
+func (m blockingCosmosExecutor) ExecuteContract(ctx context.Context, contract string, msg []byte) (string, error) {
+	return "", m.wait(ctx)
+}
+
+func (m blockingCosmosExecutor) GetAddress() string { return "wormhole1sender" }
+
+// testSubmitterHonoursContext asserts that a submitter whose node never answers returns promptly,
+// with a transient error wrapping the context's, once its parent is cancelled or its deadline
+// passes, and leaves no call or goroutine behind
+func testSubmitterHonoursContext(t *testing.T, newSubmitter func(b *blocker) VAASubmitter) {
+	t.Helper()
+	vaaBytes := buildTestVAA(make([]byte, 18))
+
+	run := func(t *testing.T, ctx context.Context, want error) {
+		t.Helper()
+		b := &blocker{}
+		s := newSubmitter(b)
+		goroutines := runtime.NumGoroutine()
+
+		started := time.Now()
+		done := make(chan error, 1)
+		go func() {
+			_, err := s.SubmitVAA(ctx, vaaBytes)
+			done <- err
+		}()
+
+		var err error
+		select {
+		case err = <-done:
+		case <-time.After(contextBound + time.Second):
+			t.Fatalf("SubmitVAA did not return within %v of its context ending", contextBound)
+		}
+		if elapsed := time.Since(started); elapsed > contextBound {
+			t.Errorf("SubmitVAA took %v to return", elapsed)
+		}
+		if !errors.Is(err, want) || !errors.Is(err, ErrTransient) {
+			t.Errorf("expected a transient error wrapping %v, got %v (class %s)", want, err, ErrorClass(err))
+		}
+		if n := b.active.Load(); n != 0 {
+			t.Errorf("%d client calls still blocked after SubmitVAA returned", n)
+		}
+		assertNoGoroutineLeak(t, goroutines)
+	}
+
+	t.Run("parent cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		defer cancel()
+		run(t, ctx, context.Canceled)
+	})
+
+	t.Run("deadline passed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		run(t, ctx, context.DeadlineExceeded)
+	})
+}
+
+// assertNoGoroutineLeak waits briefly for the goroutine count to fall back to before
+func assertNoGoroutineLeak(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(contextBound)
+	for {
+		now := runtime.NumGoroutine()
+		if now <= before {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("%d goroutines leaked", now-before)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubmittersHonourContext(t *testing.T) {
+	logger := zap.NewNop()
+	confirmation := clients.AztecConfirmationConfig{Timeout: time.Hour, PollInterval: time.Second}
+
+	tests := []struct {
+		name         string
+		newSubmitter func(b *blocker) VAASubmitter
+	}{
+		{"evm", func(b *blocker) VAASubmitter {
+			return NewEVMSubmitter(logger, "0x1234", blockingEVMRelayer{b})
+		}},
+		{"solana post", func(b *blocker) VAASubmitter {
+			return NewSolanaSubmitter(logger, blockingSolanaRelayer{blocker: b})
+		}},
+		{"solana receive_value", func(b *blocker) VAASubmitter {
+			return NewSolanaSubmitter(logger, blockingSolanaRelayer{blocker: b, posted: true})
+		}},
+		{"aztec verification service", func(b *blocker) VAASubmitter {
+			return NewAztecSubmitter(logger, "0x1234", nil, blockingAztecVerifier{b})
+		}},
+		{"aztec pxe fallback", func(b *blocker) VAASubmitter {
+			return NewAztecSubmitter(logger, "0x1234", blockingAztecRelayer{blocker: b}, blockingAztecVerifier{b})
+		}},
+		{"aztec confirmation", func(b *blocker) VAASubmitter {
+			return NewAztecSubmitterWithConfirmation(logger, "0x1234", blockingAztecRelayer{blocker: b, sent: true}, nil, confirmation)
+		}},
+		{"cosmos", func(b *blocker) VAASubmitter {
+			return NewCosmosSubmitter(logger, "wormhole1target", "", blockingCosmosExecutor{b})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testSubmitterHonoursContext(t, tt.newSubmitter)
+		})
+	}
+}
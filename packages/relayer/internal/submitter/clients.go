@@ -0,0 +1,67 @@
+package submitter
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// The submitters depend on these interfaces rather than the concrete clients so their
+// logic can be tested without a live node. Each is satisfied by the client named in its comment.
+
+// EVMRelayer sends VAAs to an EVM contract (*clients.EVMClient)
+type EVMRelayer interface {
+	// RelayVAA sends vaaBytes to targetContract, waits for inclusion and returns the transaction hash
+	RelayVAA(ctx context.Context, targetContract string, vaaBytes []byte) (string, error)
+	// VerifyEmitterRegistered checks targetContract accepts VAAs from emitter on chainID,
+	// returning an error wrapping clients.ErrEmitterNotRegistered if it does not
+	VerifyEmitterRegistered(ctx context.Context, targetContract string, chainID uint16, emitter [32]byte) error
+	// IsVAAProcessed asks targetContract whether it already consumed the VAA, returning false
+	// if no processed check is configured
+	IsVAAProcessed(ctx context.Context, targetContract string, ref clients.VAAReference) (bool, error)
+	GetAddress() common.Address
+}
+
+// SolanaRelayer posts VAAs to the Wormhole bridge and delivers them to the target program (*clients.SolanaClient)
+type SolanaRelayer interface {
+	PostVAAToWormhole(ctx context.Context, vaaBytes []byte) (solana.PublicKey, error)
+	SendReceiveValueTransaction(ctx context.Context, vaaBytes []byte, emitterChain uint16, sequence uint64) (string, error)
+	// SendReceiveValueBatch delivers several VAAs in as few transactions as fit, returning one result per request
+	SendReceiveValueBatch(ctx context.Context, requests []clients.ReceiveValueRequest) ([]clients.ReceiveValueResult, error)
+	// VerifyEmitterRegistered checks the program accepts VAAs from emitter on chainID,
+	// returning an error wrapping clients.ErrEmitterNotRegistered if it does not
+	VerifyEmitterRegistered(ctx context.Context, chainID uint16, emitter [32]byte) error
+	GetProgramID() solana.PublicKey
+	GetPayerAddress() solana.PublicKey
+}
+
+// AztecRelayer sends VAAs to an Aztec contract through a PXE and waits for inclusion (*clients.AztecPXEClient)
+type AztecRelayer interface {
+	SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error)
+	WaitForTransaction(ctx context.Context, txHash string, config clients.AztecConfirmationConfig) (uint64, error)
+}
+
+// AztecVerifier submits VAAs to Aztec through the verification service (*clients.VerificationServiceClient).
+// The client retries timeouts and 5xx responses itself; its CheckHealth is picked up by
+// AztecSubmitter.CheckHealth when present, as for the other clients.
+type AztecVerifier interface {
+	VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error)
+}
+
+// CosmosExecutor executes CosmWasm contract messages (*clients.CosmosClient)
+type CosmosExecutor interface {
+	// ExecuteContract executes msg on contract, waits for inclusion and returns the transaction hash
+	ExecuteContract(ctx context.Context, contract string, msg []byte) (string, error)
+	GetAddress() string
+}
+
+var (
+	_ EVMRelayer     = (*clients.EVMClient)(nil)
+	_ SolanaRelayer  = (*clients.SolanaClient)(nil)
+	_ AztecRelayer   = (*clients.AztecPXEClient)(nil)
+	_ AztecVerifier  = (*clients.VerificationServiceClient)(nil)
+	_ CosmosExecutor = (*clients.CosmosClient)(nil)
+)
@@ -0,0 +1,74 @@
+package submitter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
+)
+
+// DefaultCosmosExecuteMsgKey is the execute message variant used to deliver a VAA,
+// i.e. the contract is called with {"submit_vaa": {"vaa": "<base64>"}}
+const DefaultCosmosExecuteMsgKey = "submit_vaa"
+
+// CosmosSubmitter handles submission of VAAs to CosmWasm contracts (e.g. via the Wormhole gateway)
+type CosmosSubmitter struct {
+	targetContract string
+	executeMsgKey  string
+	cosmosClient   CosmosExecutor
+	logger         *zap.Logger
+}
+
+// NewCosmosSubmitter creates a new Cosmos submitter instance
+func NewCosmosSubmitter(logger *zap.Logger, targetContract string, executeMsgKey string, cosmosClient CosmosExecutor) *CosmosSubmitter {
+	if executeMsgKey == "" {
+		executeMsgKey = DefaultCosmosExecuteMsgKey
+	}
+	return &CosmosSubmitter{
+		targetContract: targetContract,
+		executeMsgKey:  executeMsgKey,
+		cosmosClient:   nilClient(cosmosClient),
+		logger:         logger.With(zap.String("component", "CosmosSubmitter")),
+	}
+}
+
+// SubmitVAA submits the given VAA bytes to the CosmWasm target contract and returns the transaction hash or an error
+func (s *CosmosSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (txHash string, err error) {
+	defer func() { recordFailure("cosmos", err) }()
+
+	if s.cosmosClient == nil {
+		return "", fmt.Errorf("%w: Cosmos client is nil", ErrNilClient)
+	}
+
+	s.logger.Info("Submitting VAA to Cosmos",
+		zap.Int("vaaLength", len(vaaBytes)),
+		zap.String("targetContract", s.targetContract),
+		zap.String("sender", s.cosmosClient.GetAddress()))
+
+	msg, err := json.Marshal(map[string]map[string]string{
+		s.executeMsgKey: {"vaa": base64.StdEncoding.EncodeToString(vaaBytes)},
+	})
+	if err != nil {
+		return "", classify(ErrPermanent, fmt.Errorf("failed to build execute message: %w", err))
+	}
+
+	timer := metrics.NewSubmissionTimer(s.logger, "cosmos")
+	defer timer.Finish()
+
+	stopTx := timer.StartPhase("transaction")
+	txHash, err = s.cosmosClient.ExecuteContract(ctx, s.targetContract, msg)
+	stopTx()
+	if err != nil {
+		return "", classifyDestinationError(fmt.Errorf("failed to submit VAA to Cosmos: %w", err))
+	}
+
+	s.logger.Info("VAA successfully submitted to Cosmos",
+		zap.String("txHash", txHash),
+		zap.String("targetContract", s.targetContract))
+
+	return txHash, nil
+}
@@ -0,0 +1,131 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
+)
+
+// Failure classes. Every error returned by a submitter's SubmitVAA matches exactly one of these
+// via errors.Is, so callers can decide whether a VAA is worth retrying without parsing messages.
+var (
+	// ErrTransient is a failure caused by the node, network or timing (rate limits, 5xx, timeouts,
+	// a VAA not yet posted); the same VAA may succeed if submitted again later
+	ErrTransient = errors.New("transient submission failure")
+
+	// ErrAlreadyProcessed means the destination has already consumed this VAA, so the delivery is complete
+	ErrAlreadyProcessed = errors.New("VAA already processed on destination")
+
+	// ErrPermanent is a failure that will recur for this VAA (reverts, malformed VAAs, missing routes)
+	ErrPermanent = errors.New("permanent submission failure")
+
+	// ErrConfig is a failure caused by the relayer's own configuration; no VAA can succeed until it is fixed
+	ErrConfig = errors.New("submitter misconfigured")
+)
+
+// ErrNilClient is returned by SubmitVAA when the submitter was built without the client it needs.
+// It is classified as ErrConfig.
+var ErrNilClient error = &classifiedError{class: ErrConfig, err: errors.New("submitter has no client configured")}
+
+// nilClient returns client, or a nil interface when it holds a nil pointer. The constructors
+// pass their clients through it so a typed-nil *clients.EVMClient fails SubmitVAA's nil check
+// with ErrNilClient instead of panicking on the first method call.
+func nilClient[T any](client T) T {
+	if v := reflect.ValueOf(client); v.Kind() == reflect.Pointer && v.IsNil() {
+		var none T
+		return none
+	}
+	return client
+}
+
+// alreadyProcessedMarkers are substrings of destination errors reporting that a VAA was consumed
+// before by the contract replay guards on EVM, CosmWasm and Aztec. On Solana it is the
+// received-message PDA already existing, matched by clients.IsAccountInUse.
+var alreadyProcessedMarkers = []string{
+	"already processed",
+	"already been processed",
+	"already received",
+	"already consumed",
+	"nullifier already exists",
+	"existing nullifier",
+}
+
+// classifiedError attaches a failure class to an error without changing its message
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string   { return e.err.Error() }
+func (e *classifiedError) Unwrap() []error { return []error{e.class, e.err} }
+
+// classify tags err with class. Errors that are already classified keep their class.
+func classify(class error, err error) error {
+	if err == nil || ErrorClass(err) != unclassified {
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// classifyDestinationError classifies an error returned by a destination chain client:
+// replay guards are ErrAlreadyProcessed; deadlines, cancellations, network failures and
+// transactions sent but not confirmed are ErrTransient; and everything else (reverts, rejected
+// transactions) is ErrPermanent
+func classifyDestinationError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case isAlreadyProcessed(err):
+		return classify(ErrAlreadyProcessed, err)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled), clients.IsTransientRPCError(err),
+		errors.Is(err, clients.ErrUnconfirmed):
+		return classify(ErrTransient, err)
+	default:
+		return classify(ErrPermanent, err)
+	}
+}
+
+// isAlreadyProcessed reports whether err is a destination's replay guard rejecting a VAA it already consumed
+func isAlreadyProcessed(err error) bool {
+	if clients.IsAccountInUse(err.Error()) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range alreadyProcessedMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// unclassified is the metrics label for errors that carry no failure class
+const unclassified = "unclassified"
+
+// ErrorClass returns the metrics label for err's failure class
+func ErrorClass(err error) string {
+	switch {
+	case errors.Is(err, ErrAlreadyProcessed):
+		return "already_processed"
+	case errors.Is(err, ErrTransient):
+		return "transient"
+	case errors.Is(err, ErrPermanent):
+		return "permanent"
+	case errors.Is(err, ErrConfig):
+		return "config"
+	default:
+		return unclassified
+	}
+}
+
+// recordFailure counts a failed submission to destination by its failure class
+func recordFailure(destination string, err error) {
+	if err != nil {
+		metrics.SubmissionFailures.WithLabelValues(destination, ErrorClass(err)).Inc()
+	}
+}
@@ -0,0 +1,71 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestClassifyDestinationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "rate limited", err: errors.New("failed to send transaction: 429 Too Many Requests"), want: ErrTransient},
+		{name: "deadline", err: fmt.Errorf("failed to submit VAA to EVM: %w", context.DeadlineExceeded), want: ErrTransient},
+		{name: "cancelled", err: fmt.Errorf("failed to submit VAA to Cosmos: %w", context.Canceled), want: ErrTransient},
+		{name: "sent but not confirmed", err: fmt.Errorf("failed to submit VAA to EVM: %w", fmt.Errorf("%w: transaction 0xabc was reorged out of block 12", clients.ErrUnconfirmed)), want: ErrTransient},
+		{name: "evm replay guard", err: errors.New("execution reverted: VAA already processed"), want: ErrAlreadyProcessed},
+		{name: "solana pda exists", err: errors.New("transaction simulation failed: Allocate: account Address { address: abc, base: None } already in use"), want: ErrAlreadyProcessed},
+		{name: "local socket in use", err: errors.New("failed to send transaction: listen tcp :8899: bind: address already in use"), want: ErrPermanent},
+		{name: "aztec nullifier", err: errors.New("Existing nullifier"), want: ErrAlreadyProcessed},
+		{name: "revert", err: errors.New("transaction 0xabc reverted in block 12"), want: ErrPermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := classifyDestinationError(tt.err)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got class %s", tt.want, ErrorClass(err))
+			}
+			if !errors.Is(err, tt.err) {
+				t.Error("classified error no longer wraps the original")
+			}
+			if err.Error() != tt.err.Error() {
+				t.Errorf("classification changed the message to %q", err.Error())
+			}
+		})
+	}
+
+	if classifyDestinationError(nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+}
+
+func TestClassifyKeepsExistingClass(t *testing.T) {
+	err := classify(ErrPermanent, classify(ErrTransient, errors.New("boom")))
+	if ErrorClass(err) != "transient" {
+		t.Errorf("expected the original transient class, got %s", ErrorClass(err))
+	}
+	if ErrorClass(fmt.Errorf("%w: EVM client is nil", ErrNilClient)) != "config" {
+		t.Error("expected ErrNilClient to be classified as config")
+	}
+	if ErrorClass(errors.New("plain")) != unclassified {
+		t.Error("expected a plain error to be unclassified")
+	}
+}
+
+func TestClassifyConfirmationError(t *testing.T) {
+	dropped := fmt.Errorf("failed to confirm Aztec transaction: %w", fmt.Errorf("%w: 0x01: nullifier conflict", clients.ErrAztecTxDropped))
+	if !errors.Is(classifyConfirmationError(dropped), ErrTransient) {
+		t.Error("expected a dropped transaction to be transient")
+	}
+	reverted := errors.New("failed to confirm Aztec transaction: aztec transaction 0x01 reverted (app_logic_reverted): assertion failed")
+	if !errors.Is(classifyConfirmationError(reverted), ErrPermanent) {
+		t.Error("expected a reverted transaction to be permanent")
+	}
+}
@@ -2,51 +2,173 @@ package submitter
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
 )
 
 // EVMSubmitter handles submission of VAAs to EVM-compatible chains
 type EVMSubmitter struct {
-	targetContract string
-	evmClient      *clients.EVMClient
+	targetContract string            // Default target contract (used when no route matches)
+	targetRoutes   map[uint16]string // Destination chain ID (from the payload) -> target contract
+	evmClient      EVMRelayer
 	logger         *zap.Logger
+	checkEmitter   bool // Verify the VAA's emitter is registered on the target before sending
 }
 
 // NewEVMSubmitter creates a new EVM submitter instance
-func NewEVMSubmitter(logger *zap.Logger, targetContract string, evmClient *clients.EVMClient) *EVMSubmitter {
+func NewEVMSubmitter(logger *zap.Logger, targetContract string, evmClient EVMRelayer) *EVMSubmitter {
 	return &EVMSubmitter{
 		targetContract: targetContract,
-		evmClient:      evmClient,
+		evmClient:      nilClient(evmClient),
 		logger:         logger.With(zap.String("component", "EVMSubmitter")),
+		checkEmitter:   true,
 	}
 }
 
+// NewEVMSubmitterWithRoutes creates an EVM submitter that picks the target contract per VAA
+// based on the destination chain ID in the payload. targetContract is used as the fallback
+// when no route matches and may be empty, in which case unrouted VAAs are rejected.
+func NewEVMSubmitterWithRoutes(logger *zap.Logger, targetContract string, routes map[uint16]string, evmClient EVMRelayer) *EVMSubmitter {
+	s := NewEVMSubmitter(logger, targetContract, evmClient)
+	s.targetRoutes = routes
+	return s
+}
+
+// SetEmitterCheck enables or disables the check, made before sending each VAA, that the target
+// contract has the VAA's emitter registered for its source chain. It is enabled by default.
+func (s *EVMSubmitter) SetEmitterCheck(enabled bool) {
+	s.checkEmitter = enabled
+}
+
+// resolveTargetContract selects the target contract for the given VAA
+func (s *EVMSubmitter) resolveTargetContract(vaaBytes []byte) (string, error) {
+	if len(s.targetRoutes) == 0 {
+		return s.targetContract, nil
+	}
+
+	payload, err := parseVAAPayload(vaaBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse VAA payload for routing: %w", err)
+	}
+	emitterChain, _, err := parseVAAEmitter(vaaBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse VAA emitter for routing: %w", err)
+	}
+
+	destChainID, ok := clients.PayloadDestinationChainID(emitterChain, payload)
+	if ok {
+		if target, found := s.targetRoutes[destChainID]; found {
+			return target, nil
+		}
+	}
+
+	if s.targetContract != "" {
+		return s.targetContract, nil
+	}
+	if !ok {
+		return "", fmt.Errorf("no target contract route: payload (%d bytes) has no destination chain ID", len(payload))
+	}
+	return "", fmt.Errorf("no target contract route for destination chain %d", destChainID)
+}
+
 // SubmitVAA submits the given VAA bytes to the EVM target contract and returns the transaction hash or an error
-func (s *EVMSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
-	// Create a context with timeout for submission operations
-	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
-	defer cancel()
+func (s *EVMSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (txHash string, err error) {
+	defer func() { recordFailure("evm", err) }()
+
+	if s.evmClient == nil {
+		return "", fmt.Errorf("%w: EVM client is nil", ErrNilClient)
+	}
+
+	// A VAA with no route can never be delivered by this relayer
+	targetContract, err := s.resolveTargetContract(vaaBytes)
+	if err != nil {
+		return "", classify(ErrPermanent, err)
+	}
 
 	s.logger.Info("Submitting VAA to EVM",
 		zap.Int("vaaLength", len(vaaBytes)),
-		zap.String("targetContract", s.targetContract),
+		zap.String("targetContract", targetContract),
 		zap.String("fromAddress", s.evmClient.GetAddress().Hex()))
 
-	// Direct submission to EVM chain
+	if processed, err := s.alreadyProcessed(ctx, targetContract, vaaBytes); processed || err != nil {
+		return "", err
+	}
+
+	if s.checkEmitter {
+		if err := s.verifyEmitter(ctx, targetContract, vaaBytes); err != nil {
+			return "", err
+		}
+	}
+
+	timer := metrics.NewSubmissionTimer(s.logger, "evm")
+	defer timer.Finish()
+
+	// Direct submission to EVM chain (send and wait for the receipt)
 	s.logger.Debug("Submitting VAA directly to EVM chain")
-	txHash, err := s.evmClient.SendVerifyTransaction(ctx, s.targetContract, vaaBytes)
+	stopTx := timer.StartPhase("transaction")
+	txHash, err = s.evmClient.RelayVAA(ctx, targetContract, vaaBytes)
+	stopTx()
 	if err != nil {
-		return "", fmt.Errorf("failed to submit VAA to EVM: %w", err)
+		return "", classifyDestinationError(fmt.Errorf("failed to submit VAA to EVM: %w", err))
 	}
 
 	s.logger.Info("VAA successfully submitted to EVM",
 		zap.String("txHash", txHash),
-		zap.String("targetContract", s.targetContract))
+		zap.String("targetContract", targetContract))
 
 	return txHash, nil
 }
+
+// alreadyProcessed asks targetContract, through the client's processed check, whether it already
+// consumed the VAA, so a send that would only revert is skipped. A consumed VAA is reported as
+// ErrAlreadyProcessed; a failed check is only logged, leaving it to the transaction to decide.
+func (s *EVMSubmitter) alreadyProcessed(ctx context.Context, targetContract string, vaaBytes []byte) (bool, error) {
+	ref, err := parseVAAReference(vaaBytes)
+	if err != nil {
+		return false, classify(ErrPermanent, fmt.Errorf("failed to parse VAA: %w", err))
+	}
+
+	processed, err := s.evmClient.IsVAAProcessed(ctx, targetContract, ref)
+	if err != nil {
+		s.logger.Warn("Could not check whether the VAA was processed; submitting anyway",
+			zap.Stringer("vaa", ref),
+			zap.Error(err))
+		return false, nil
+	}
+	if !processed {
+		return false, nil
+	}
+	s.logger.Info("VAA already processed on destination; skipping send",
+		zap.Stringer("vaa", ref),
+		zap.String("targetContract", targetContract))
+	return true, classify(ErrAlreadyProcessed, fmt.Errorf("VAA %s already processed by %s", ref, targetContract))
+}
+
+// verifyEmitter checks targetContract has the VAA's emitter registered for its source chain, so
+// a VAA the contract would revert on is not sent. An unregistered emitter is a configuration
+// error; a failed lookup is only logged, leaving it to the transaction to decide.
+func (s *EVMSubmitter) verifyEmitter(ctx context.Context, targetContract string, vaaBytes []byte) error {
+	emitterChain, emitter, err := parseVAAEmitter(vaaBytes)
+	if err != nil {
+		return classify(ErrPermanent, fmt.Errorf("failed to parse VAA emitter: %w", err))
+	}
+
+	err = s.evmClient.VerifyEmitterRegistered(ctx, targetContract, emitterChain, emitter)
+	if errors.Is(err, clients.ErrEmitterNotRegistered) {
+		s.logger.Error("Emitter not registered on destination; register it with the target contract",
+			zap.Uint16("emitterChain", emitterChain),
+			zap.String("emitter", fmt.Sprintf("%x", emitter)),
+			zap.String("targetContract", targetContract),
+			zap.Error(err))
+		return classify(ErrConfig, err)
+	}
+	if err != nil {
+		s.logger.Warn("Could not check emitter registration; submitting anyway", zap.Error(err))
+	}
+	return nil
+}
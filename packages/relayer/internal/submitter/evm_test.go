@@ -2,10 +2,13 @@ package submitter
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
-	"go.uber.org/zap"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/wormhole-demo/relayer/internal/clients"
+	"go.uber.org/zap"
 )
 
 func TestEVMSubmitterInterface(t *testing.T) {
@@ -31,8 +34,9 @@ func TestNewEVMSubmitter(t *testing.T) {
 		t.Errorf("Expected target contract %s, got %s", targetContract, submitter.targetContract)
 	}
 
-	if submitter.evmClient != evmClient {
-		t.Error("EVM client not set correctly")
+	// A typed-nil client is stored as a nil interface so SubmitVAA reports ErrNilClient
+	if submitter.evmClient != nil {
+		t.Error("expected a nil EVM client pointer to be stored as a nil interface")
 	}
 
 	if submitter.logger == nil {
@@ -50,13 +54,197 @@ func TestEVMSubmitterSubmitVAA_NoClients(t *testing.T) {
 	ctx := context.Background()
 	vaaBytes := []byte("test VAA data")
 
-	// This should panic or fail since evmClient is nil
-	// In production code, we should handle this gracefully
-	defer func() {
-		if r := recover(); r == nil {
-			t.Errorf("Expected panic when calling SubmitVAA with nil evmClient")
+	// A nil client must surface as an error rather than a panic
+	_, err := submitter.SubmitVAA(ctx, vaaBytes)
+	if !errors.Is(err, ErrNilClient) {
+		t.Errorf("Expected ErrNilClient when calling SubmitVAA with nil evmClient, got %v", err)
+	}
+}
+
+func TestSubmitVAA_NilClients(t *testing.T) {
+	logger := zap.NewNop()
+	vaaBytes := buildTestVAA(make([]byte, 18))
+
+	submitters := map[string]VAASubmitter{
+		"solana": NewSolanaSubmitter(logger, nil),
+		"aztec":  NewAztecSubmitter(logger, "0x1234", nil, nil),
+		"cosmos": NewCosmosSubmitter(logger, "wormhole1target", "", nil),
+	}
+
+	for name, s := range submitters {
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrNilClient) {
+			t.Errorf("%s: expected ErrNilClient, got %v", name, err)
 		}
-	}()
+	}
+}
+
+func TestSubmitVAA_TypedNilClients(t *testing.T) {
+	logger := zap.NewNop()
+	vaaBytes := buildTestVAA(make([]byte, 18))
+
+	submitters := map[string]VAASubmitter{
+		"evm":    NewEVMSubmitter(logger, "0x1234", (*clients.EVMClient)(nil)),
+		"solana": NewSolanaSubmitter(logger, (*clients.SolanaClient)(nil)),
+		"aztec":  NewAztecSubmitter(logger, "0x1234", (*clients.AztecPXEClient)(nil), (*clients.VerificationServiceClient)(nil)),
+		"cosmos": NewCosmosSubmitter(logger, "wormhole1target", "", (*clients.CosmosClient)(nil)),
+	}
+
+	for name, s := range submitters {
+		if _, err := s.SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrNilClient) {
+			t.Errorf("%s: expected ErrNilClient, got %v", name, err)
+		}
+	}
+}
+
+// buildTestVAA builds a minimal VAA with no signatures around the given payload
+func buildTestVAA(payload []byte) []byte {
+	vaa := []byte{1, 0, 0, 0, 0, 0}        // version, guardian set index, signature count
+	vaa = append(vaa, make([]byte, 51)...) // body header
+	return append(vaa, payload...)
+}
+
+// buildTestVAAFrom builds a minimal VAA emitted on chain around the given payload
+func buildTestVAAFrom(chain uint16, payload []byte) []byte {
+	vaa := buildTestVAA(payload)
+	vaa[6+8], vaa[6+9] = byte(chain>>8), byte(chain) // after the timestamp and nonce of the body
+	return vaa
+}
+
+func TestEVMSubmitterResolveTargetContract(t *testing.T) {
+	routes := map[uint16]string{
+		10003: "0x00000000000000000000000000000000000000aa",
+		10004: "0x00000000000000000000000000000000000000bb",
+	}
+
+	defaultPayload := append([]byte{0x27, 0x14}, make([]byte, 16)...) // dest 10004
+	aztecPayload := make([]byte, 50)
+	aztecPayload[32], aztecPayload[33] = 0x27, 0x13 // dest 10003
+	unroutedPayload := append([]byte{0x00, 0x01}, make([]byte, 16)...)
+
+	submitter := NewEVMSubmitterWithRoutes(zap.NewNop(), "", routes, nil)
+
+	if got, err := submitter.resolveTargetContract(buildTestVAA(defaultPayload)); err != nil || got != routes[10004] {
+		t.Errorf("default payload: expected %s, got %s (err %v)", routes[10004], got, err)
+	}
+	if got, err := submitter.resolveTargetContract(buildTestVAAFrom(56, aztecPayload)); err != nil || got != routes[10003] {
+		t.Errorf("aztec payload: expected %s, got %s (err %v)", routes[10003], got, err)
+	}
+	// From any other chain, 50 bytes are a default payload: the destination is in bytes 0-1
+	extendedPayload := append(append([]byte{}, defaultPayload...), aztecPayload[18:]...)
+	if got, err := submitter.resolveTargetContract(buildTestVAAFrom(1, extendedPayload)); err != nil || got != routes[10004] {
+		t.Errorf("extended default payload: expected %s, got %s (err %v)", routes[10004], got, err)
+	}
+	if _, err := submitter.resolveTargetContract(buildTestVAAFrom(56, defaultPayload)); err == nil {
+		t.Error("expected an error for an Aztec payload under 50 bytes")
+	}
+	if _, err := submitter.resolveTargetContract(buildTestVAA(unroutedPayload)); err == nil {
+		t.Error("expected error for unrouted destination")
+	}
+
+	fallback := "0x00000000000000000000000000000000000000cc"
+	submitter = NewEVMSubmitterWithRoutes(zap.NewNop(), fallback, routes, nil)
+	if got, err := submitter.resolveTargetContract(buildTestVAA(unroutedPayload)); err != nil || got != fallback {
+		t.Errorf("expected fallback %s, got %s (err %v)", fallback, got, err)
+	}
+}
+
+func TestEVMSubmitterSubmitVAA(t *testing.T) {
+	target := "0x1234567890123456789012345678901234567890"
+
+	tests := []struct {
+		name      string
+		relayErr  error
+		wantClass error
+	}{
+		{name: "success"},
+		{name: "transient", relayErr: errors.New("failed to send transaction: 503 Service Unavailable"), wantClass: ErrTransient},
+		{name: "revert", relayErr: errors.New("transaction 0xabc reverted in block 12"), wantClass: ErrPermanent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relayer := &mockEVMRelayer{txHash: "0xfeed", err: tt.relayErr}
+			submitter := NewEVMSubmitter(zap.NewNop(), target, relayer)
+
+			txHash, err := submitter.SubmitVAA(context.Background(), buildTestVAA(make([]byte, 18)))
+			if len(relayer.targets) != 1 || relayer.targets[0] != target {
+				t.Fatalf("expected one relay to %s, got %v", target, relayer.targets)
+			}
+			if tt.wantClass == nil {
+				if err != nil || txHash != "0xfeed" {
+					t.Fatalf("expected tx 0xfeed, got %q (err %v)", txHash, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantClass) || !errors.Is(err, tt.relayErr) {
+				t.Errorf("expected %v wrapping %v, got %v (class %s)", tt.wantClass, tt.relayErr, err, ErrorClass(err))
+			}
+		})
+	}
+}
+
+func TestEVMSubmitterEmitterCheck(t *testing.T) {
+	target := "0x1234567890123456789012345678901234567890"
+	vaaBytes := buildTestVAA(make([]byte, 18))
+	unregistered := fmt.Errorf("%w: no emitter registered for chain 2", clients.ErrEmitterNotRegistered)
+
+	relayer := &mockEVMRelayer{txHash: "0xfeed", emitterErr: unregistered}
+	if _, err := NewEVMSubmitter(zap.NewNop(), target, relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrConfig) {
+		t.Errorf("expected ErrConfig for an unregistered emitter, got %v (class %s)", err, ErrorClass(err))
+	}
+	if len(relayer.targets) != 0 {
+		t.Error("expected nothing to be relayed for an unregistered emitter")
+	}
+
+	// A failed lookup leaves it to the transaction
+	relayer = &mockEVMRelayer{txHash: "0xfeed", emitterErr: errors.New("execution reverted")}
+	if _, err := NewEVMSubmitter(zap.NewNop(), target, relayer).SubmitVAA(context.Background(), vaaBytes); err != nil {
+		t.Errorf("expected a failed lookup to be ignored, got %v", err)
+	}
 
-	_, _ = submitter.SubmitVAA(ctx, vaaBytes)
-}
\ No newline at end of file
+	relayer = &mockEVMRelayer{txHash: "0xfeed", emitterErr: unregistered}
+	s := NewEVMSubmitter(zap.NewNop(), target, relayer)
+	s.SetEmitterCheck(false)
+	if _, err := s.SubmitVAA(context.Background(), vaaBytes); err != nil || len(relayer.targets) != 1 {
+		t.Errorf("expected the VAA to be relayed with the check disabled, got %v", err)
+	}
+}
+
+func TestEVMSubmitterProcessedCheck(t *testing.T) {
+	target := "0x1234567890123456789012345678901234567890"
+	vaaBytes := buildTestVAA(make([]byte, 18))
+
+	relayer := &mockEVMRelayer{txHash: "0xfeed", processed: true}
+	if _, err := NewEVMSubmitter(zap.NewNop(), target, relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrAlreadyProcessed) {
+		t.Errorf("expected ErrAlreadyProcessed for a consumed VAA, got %v (class %s)", err, ErrorClass(err))
+	}
+	if len(relayer.targets) != 0 {
+		t.Error("expected nothing to be relayed for a consumed VAA")
+	}
+
+	// A failed check leaves it to the transaction
+	relayer = &mockEVMRelayer{txHash: "0xfeed", processedErr: errors.New("execution reverted")}
+	if _, err := NewEVMSubmitter(zap.NewNop(), target, relayer).SubmitVAA(context.Background(), vaaBytes); err != nil || len(relayer.targets) != 1 {
+		t.Errorf("expected a failed check to be ignored, got %v", err)
+	}
+}
+
+func TestParseVAAReference(t *testing.T) {
+	vaaBytes := buildTestVAA(make([]byte, 18))
+	ref, err := parseVAAReference(vaaBytes)
+	if err != nil {
+		t.Fatalf("parseVAAReference failed: %v", err)
+	}
+	emitterChain, emitter, _ := parseVAAEmitter(vaaBytes)
+	_, sequence, _ := parseVAAHeader(vaaBytes)
+	if ref.EmitterChain != emitterChain || ref.Emitter != emitter || ref.Sequence != sequence {
+		t.Errorf("unexpected reference %v", ref)
+	}
+	body := vaaBytes[6+int(vaaBytes[5])*66:]
+	if want := crypto.Keccak256Hash(crypto.Keccak256(body)); ref.Digest != want {
+		t.Errorf("expected digest %x, got %x", want, ref.Digest)
+	}
+	if _, err := parseVAAReference(vaaBytes[:20]); err == nil {
+		t.Error("expected a truncated VAA to be rejected")
+	}
+}
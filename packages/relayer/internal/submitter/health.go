@@ -0,0 +1,92 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HealthChecker is implemented by submitters that can check their destination is reachable
+// without submitting anything, so the relayer can watch it between VAAs
+type HealthChecker interface {
+	// CheckHealth returns an error if the destination cannot currently accept submissions
+	CheckHealth(ctx context.Context) error
+}
+
+// clientHealthChecker is implemented by the concrete clients; the client interfaces leave it
+// out so mocks need not implement it
+type clientHealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// checkClientHealth checks client if it supports health checks, and treats it as healthy otherwise
+func checkClientHealth(ctx context.Context, client interface{}) error {
+	if checker, ok := client.(clientHealthChecker); ok {
+		return checker.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// CheckHealth checks the EVM RPC endpoint is reachable
+func (s *EVMSubmitter) CheckHealth(ctx context.Context) error {
+	if s.evmClient == nil {
+		return ErrNilClient
+	}
+	return checkClientHealth(ctx, s.evmClient)
+}
+
+// CheckHealth checks the Solana RPC endpoint reports itself healthy
+func (s *SolanaSubmitter) CheckHealth(ctx context.Context) error {
+	if s.solanaClient == nil {
+		return ErrNilClient
+	}
+	return checkClientHealth(ctx, s.solanaClient)
+}
+
+// CheckHealth checks the Cosmos LCD endpoint is reachable
+func (s *CosmosSubmitter) CheckHealth(ctx context.Context) error {
+	if s.cosmosClient == nil {
+		return ErrNilClient
+	}
+	return checkClientHealth(ctx, s.cosmosClient)
+}
+
+// CheckHealth checks the verification service and the PXE. SubmitVAA falls back from one to
+// the other, so the submitter is healthy while either is, unless inclusion confirmation needs
+// the PXE.
+func (s *AztecSubmitter) CheckHealth(ctx context.Context) error {
+	if s.verificationClient == nil && s.pxeClient == nil {
+		return ErrNilClient
+	}
+
+	var verificationErr, pxeErr error
+	if s.verificationClient != nil {
+		if verificationErr = checkClientHealth(ctx, s.verificationClient); verificationErr != nil {
+			verificationErr = fmt.Errorf("verification service: %v", verificationErr)
+		}
+	}
+	if s.pxeClient != nil {
+		if pxeErr = checkClientHealth(ctx, s.pxeClient); pxeErr != nil {
+			pxeErr = fmt.Errorf("pxe: %v", pxeErr)
+		}
+	}
+
+	switch {
+	case s.confirmation != nil && pxeErr != nil:
+		return pxeErr
+	case s.verificationClient == nil:
+		return pxeErr
+	case s.pxeClient == nil:
+		return verificationErr
+	case verificationErr != nil && pxeErr != nil:
+		return errors.Join(verificationErr, pxeErr)
+	}
+	return nil
+}
+
+var (
+	_ HealthChecker = (*EVMSubmitter)(nil)
+	_ HealthChecker = (*SolanaSubmitter)(nil)
+	_ HealthChecker = (*CosmosSubmitter)(nil)
+	_ HealthChecker = (*AztecSubmitter)(nil)
+)
@@ -0,0 +1,79 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// checkedAztecVerifier is a verifier that supports health checks, like the real client
+type checkedAztecVerifier struct {
+	mockAztecVerifier
+	healthErr error
+}
+
+func (m *checkedAztecVerifier) CheckHealth(ctx context.Context) error { return m.healthErr }
+
+// checkedAztecRelayer is a PXE client that supports health checks, like the real client
+type checkedAztecRelayer struct {
+	mockAztecRelayer
+	healthErr error
+}
+
+func (m *checkedAztecRelayer) CheckHealth(ctx context.Context) error { return m.healthErr }
+
+func TestAztecSubmitterCheckHealth(t *testing.T) {
+	down := errors.New("connection refused")
+	tests := []struct {
+		name         string
+		verifierErr  error
+		pxeErr       error
+		confirmation bool
+		wantErr      string // Empty for healthy
+	}{
+		{name: "both healthy"},
+		{name: "verification service down, pxe fallback", verifierErr: down},
+		{name: "pxe down, verification service up", pxeErr: down},
+		{name: "both down", verifierErr: down, pxeErr: down, wantErr: "verification service"},
+		{name: "pxe down with confirmation", pxeErr: down, confirmation: true, wantErr: "pxe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &checkedAztecVerifier{healthErr: tt.verifierErr}
+			pxe := &checkedAztecRelayer{healthErr: tt.pxeErr}
+			s := NewAztecSubmitter(zap.NewNop(), "0xcontract", pxe, verifier)
+			if tt.confirmation {
+				s = NewAztecSubmitterWithConfirmation(zap.NewNop(), "0xcontract", pxe, verifier, clients.DefaultAztecConfirmationConfig())
+			}
+
+			err := s.CheckHealth(context.Background())
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("expected healthy, got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error mentioning %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	// Without a PXE the verification service alone decides
+	s := NewAztecSubmitter(zap.NewNop(), "0xcontract", nil, &checkedAztecVerifier{healthErr: down})
+	if err := s.CheckHealth(context.Background()); err == nil {
+		t.Fatal("expected an unhealthy verification service without a PXE to fail")
+	}
+}
+
+func TestCheckClientHealth(t *testing.T) {
+	// Clients without a health check, such as the mocks, are treated as healthy
+	if err := NewEVMSubmitter(zap.NewNop(), "0xcontract", &mockEVMRelayer{}).CheckHealth(context.Background()); err != nil {
+		t.Fatalf("expected a client without a health check to be healthy, got %v", err)
+	}
+	if err := NewEVMSubmitter(zap.NewNop(), "0xcontract", nil).CheckHealth(context.Background()); !errors.Is(err, ErrConfig) {
+		t.Fatalf("expected a submitter without a client to fail with a config error, got %v", err)
+	}
+}
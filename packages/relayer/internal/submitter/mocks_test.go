@@ -0,0 +1,140 @@
+package submitter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// mockEVMRelayer records relayed VAAs and returns a fixed result
+type mockEVMRelayer struct {
+	txHash       string
+	err          error
+	targets      []string
+	emitterErr   error // Returned by VerifyEmitterRegistered
+	processed    bool  // Returned by IsVAAProcessed
+	processedErr error // Returned by IsVAAProcessed
+}
+
+func (m *mockEVMRelayer) RelayVAA(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	m.targets = append(m.targets, targetContract)
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.txHash, nil
+}
+
+func (m *mockEVMRelayer) VerifyEmitterRegistered(ctx context.Context, targetContract string, chainID uint16, emitter [32]byte) error {
+	return m.emitterErr
+}
+
+func (m *mockEVMRelayer) IsVAAProcessed(ctx context.Context, targetContract string, ref clients.VAAReference) (bool, error) {
+	return m.processed, m.processedErr
+}
+
+func (m *mockEVMRelayer) GetAddress() common.Address {
+	return common.HexToAddress("0x00000000000000000000000000000000000000ee")
+}
+
+// mockSolanaRelayer returns fixed results for posting and delivering a VAA
+type mockSolanaRelayer struct {
+	postErr         error
+	postFailures    int // Posts failing with postErr before one succeeds (0 = every post fails if postErr is set)
+	posts           int
+	signature       string
+	receiveErr      error
+	received        []uint64         // Sequences passed to SendReceiveValueTransaction or SendReceiveValueBatch
+	alreadyReceived map[uint64]bool  // Sequences SendReceiveValueBatch reports as already received
+	emitterErr      error            // Returned by VerifyEmitterRegistered
+	batchErr        error            // Returned by SendReceiveValueBatch for the whole batch
+	receiveErrs     map[uint64]error // Per-sequence errors of SendReceiveValueBatch, over receiveErr
+}
+
+func (m *mockSolanaRelayer) PostVAAToWormhole(ctx context.Context, vaaBytes []byte) (solana.PublicKey, error) {
+	m.posts++
+	if m.postErr != nil && (m.postFailures == 0 || m.posts <= m.postFailures) {
+		return solana.PublicKey{}, m.postErr
+	}
+	return solana.PublicKey{}, nil
+}
+
+func (m *mockSolanaRelayer) SendReceiveValueTransaction(ctx context.Context, vaaBytes []byte, emitterChain uint16, sequence uint64) (string, error) {
+	m.received = append(m.received, sequence)
+	if m.receiveErr != nil {
+		return "", m.receiveErr
+	}
+	return m.signature, nil
+}
+
+func (m *mockSolanaRelayer) SendReceiveValueBatch(ctx context.Context, requests []clients.ReceiveValueRequest) ([]clients.ReceiveValueResult, error) {
+	if m.batchErr != nil {
+		return nil, m.batchErr
+	}
+	results := make([]clients.ReceiveValueResult, len(requests))
+	for i, req := range requests {
+		m.received = append(m.received, req.Sequence)
+		if m.alreadyReceived[req.Sequence] {
+			results[i].AlreadyReceived = true
+		} else if err := m.receiveErrs[req.Sequence]; err != nil {
+			results[i].Err = err
+		} else {
+			results[i].Signature, results[i].Err = m.signature, m.receiveErr
+		}
+	}
+	return results, nil
+}
+
+func (m *mockSolanaRelayer) VerifyEmitterRegistered(ctx context.Context, chainID uint16, emitter [32]byte) error {
+	return m.emitterErr
+}
+
+func (m *mockSolanaRelayer) GetProgramID() solana.PublicKey    { return solana.SystemProgramID }
+func (m *mockSolanaRelayer) GetPayerAddress() solana.PublicKey { return solana.PublicKey{} }
+
+// mockAztecRelayer returns fixed results for sending and confirming a PXE transaction
+type mockAztecRelayer struct {
+	txHash     string
+	sendErr    error
+	confirmErr error
+	sent       int
+}
+
+func (m *mockAztecRelayer) SendVerifyTransaction(ctx context.Context, targetContract string, vaaBytes []byte) (string, error) {
+	m.sent++
+	if m.sendErr != nil {
+		return "", m.sendErr
+	}
+	return m.txHash, nil
+}
+
+func (m *mockAztecRelayer) WaitForTransaction(ctx context.Context, txHash string, config clients.AztecConfirmationConfig) (uint64, error) {
+	if m.confirmErr != nil {
+		return 0, m.confirmErr
+	}
+	return 1, nil
+}
+
+// mockAztecVerifier returns a fixed result from the verification service, or with block set
+// waits for the context to end like a service that never answers
+type mockAztecVerifier struct {
+	txHash string
+	err    error
+	block  bool
+	calls  int
+}
+
+func (m *mockAztecVerifier) VerifyVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	m.calls++
+	if m.block {
+		<-ctx.Done()
+		return "", fmt.Errorf("verification service request failed: %w", ctx.Err())
+	}
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.txHash, nil
+}
@@ -0,0 +1,69 @@
+package submitter
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// parseVAAEmitter extracts the emitter chain and address from raw VAA bytes
+func parseVAAEmitter(vaaBytes []byte) (uint16, [32]byte, error) {
+	var emitter [32]byte
+	if len(vaaBytes) < 6 {
+		return 0, emitter, fmt.Errorf("VAA too short")
+	}
+
+	sigCount := int(vaaBytes[5])
+	bodyStart := 6 + (sigCount * 66)
+
+	// The emitter chain and address follow the timestamp and nonce
+	if len(vaaBytes) < bodyStart+51 {
+		return 0, emitter, fmt.Errorf("VAA body too short")
+	}
+
+	body := vaaBytes[bodyStart:]
+	copy(emitter[:], body[10:42])
+	return (uint16(body[8]) << 8) | uint16(body[9]), emitter, nil
+}
+
+// parseVAAReference extracts the emitter, sequence and digest of raw VAA bytes
+func parseVAAReference(vaaBytes []byte) (clients.VAAReference, error) {
+	var ref clients.VAAReference
+	if len(vaaBytes) < 6 {
+		return ref, fmt.Errorf("VAA too short")
+	}
+
+	sigCount := int(vaaBytes[5])
+	bodyStart := 6 + (sigCount * 66)
+	if len(vaaBytes) < bodyStart+51 {
+		return ref, fmt.Errorf("VAA body too short")
+	}
+
+	body := vaaBytes[bodyStart:]
+	ref.EmitterChain = binary.BigEndian.Uint16(body[8:10])
+	copy(ref.Emitter[:], body[10:42])
+	ref.Sequence = binary.BigEndian.Uint64(body[42:50])
+	// The digest guardians sign, and contracts key consumed VAAs by, is the double hash of the body
+	copy(ref.Digest[:], crypto.Keccak256(crypto.Keccak256(body)))
+	return ref, nil
+}
+
+// parseVAAPayload extracts the payload from raw VAA bytes
+func parseVAAPayload(vaaBytes []byte) ([]byte, error) {
+	if len(vaaBytes) < 6 {
+		return nil, fmt.Errorf("VAA too short")
+	}
+
+	sigCount := int(vaaBytes[5])
+	bodyStart := 6 + (sigCount * 66)
+
+	// Payload follows the 51-byte body header (timestamp, nonce, emitter chain/address, sequence, consistency)
+	if len(vaaBytes) < bodyStart+51 {
+		return nil, fmt.Errorf("VAA body too short")
+	}
+
+	return vaaBytes[bodyStart+51:], nil
+}
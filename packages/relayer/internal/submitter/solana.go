@@ -3,33 +3,93 @@ package submitter
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
 )
 
+// Defaults for waiting until a VAA is posted to the Wormhole program
+const (
+	DefaultSolanaPostAttempts     = 10
+	DefaultSolanaPostInitialDelay = 3 * time.Second
+	DefaultSolanaPostMaxDelay     = 15 * time.Second
+)
+
+// SolanaPostPolicy is what a Solana submitter does with a VAA it could not confirm is posted to
+// the Wormhole program once its post attempts are used up
+type SolanaPostPolicy string
+
+const (
+	// SolanaPostPolicyStrict fails the VAA as transient without sending receive_value, which
+	// would fail anyway without the posted VAA account
+	SolanaPostPolicyStrict SolanaPostPolicy = "strict"
+	// SolanaPostPolicyOptimistic sends receive_value anyway, for posting services or RPCs that
+	// lag behind: the VAA may be posted by the time the transaction lands, and if not the
+	// transaction fails and its fee is lost
+	SolanaPostPolicyOptimistic SolanaPostPolicy = "optimistic"
+)
+
+// ParseSolanaPostPolicy parses a post policy name; an empty name is SolanaPostPolicyStrict
+func ParseSolanaPostPolicy(s string) (SolanaPostPolicy, error) {
+	switch policy := SolanaPostPolicy(s); policy {
+	case "":
+		return SolanaPostPolicyStrict, nil
+	case SolanaPostPolicyStrict, SolanaPostPolicyOptimistic:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown post policy %q (want %s or %s)", s, SolanaPostPolicyStrict, SolanaPostPolicyOptimistic)
+}
+
 // SolanaSubmitter handles submission of VAAs to Solana
 type SolanaSubmitter struct {
-	solanaClient *clients.SolanaClient
+	solanaClient SolanaRelayer
 	logger       *zap.Logger
+
+	postAttempts     int           // Attempts to post the VAA before giving up
+	postInitialDelay time.Duration // Delay after the first failed attempt, doubling up to postMaxDelay
+	postMaxDelay     time.Duration
+	checkEmitter     bool // Verify the VAA's emitter is registered before posting it
+	// What to do with a VAA still not confirmed posted after postAttempts
+	postPolicy SolanaPostPolicy
 }
 
 // NewSolanaSubmitter creates a new Solana submitter instance
-func NewSolanaSubmitter(logger *zap.Logger, solanaClient *clients.SolanaClient) *SolanaSubmitter {
+func NewSolanaSubmitter(logger *zap.Logger, solanaClient SolanaRelayer) *SolanaSubmitter {
 	return &SolanaSubmitter{
-		solanaClient: solanaClient,
-		logger:       logger.With(zap.String("component", "SolanaSubmitter")),
+		solanaClient:     nilClient(solanaClient),
+		logger:           logger.With(zap.String("component", "SolanaSubmitter")),
+		postAttempts:     DefaultSolanaPostAttempts,
+		postInitialDelay: DefaultSolanaPostInitialDelay,
+		postMaxDelay:     DefaultSolanaPostMaxDelay,
+		checkEmitter:     true,
+		postPolicy:       SolanaPostPolicyStrict,
 	}
 }
 
+// SetEmitterCheck enables or disables the check, made before posting each VAA, that the
+// program has the VAA's emitter registered for its source chain. It is enabled by default.
+func (s *SolanaSubmitter) SetEmitterCheck(enabled bool) {
+	s.checkEmitter = enabled
+}
+
+// SetPostPolicy sets what to do with a VAA not confirmed posted to the Wormhole program once the
+// post attempts are used up. It is SolanaPostPolicyStrict by default.
+func (s *SolanaSubmitter) SetPostPolicy(policy SolanaPostPolicy) {
+	s.postPolicy = policy
+}
+
 // SubmitVAA submits the given VAA bytes to the Solana MessageBridge and returns the transaction signature or an error
-func (s *SolanaSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
-	// Create a context with timeout for submission operations
-	ctx, cancel := context.WithTimeout(ctx, 180*time.Second)
-	defer cancel()
+func (s *SolanaSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (signature string, err error) {
+	defer func() { recordFailure("solana", err) }()
+
+	if s.solanaClient == nil {
+		return "", fmt.Errorf("%w: Solana client is nil", ErrNilClient)
+	}
 
 	s.logger.Info("Submitting VAA to Solana",
 		zap.Int("vaaLength", len(vaaBytes)),
@@ -39,52 +99,36 @@ func (s *SolanaSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (strin
 	// Parse VAA to extract emitter chain and sequence
 	emitterChain, sequence, err := parseVAAHeader(vaaBytes)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse VAA header: %w", err)
+		return "", classify(ErrPermanent, fmt.Errorf("failed to parse VAA header: %w", err))
 	}
 
 	s.logger.Debug("Parsed VAA header",
 		zap.Uint16("emitterChain", emitterChain),
 		zap.Uint64("sequence", sequence))
 
-	// Try to post VAA and wait for it with retries
-	maxRetries := 10
-	retryDelay := 3 * time.Second
-	var lastErr error
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		// Check/post VAA to Wormhole
-		_, err = s.solanaClient.PostVAAToWormhole(ctx, vaaBytes)
-		if err == nil {
-			s.logger.Info("VAA is posted to Wormhole, proceeding with receive_value")
-			break
-		}
-
-		lastErr = err
-		if attempt < maxRetries {
-			s.logger.Info("Waiting for VAA to be posted to Wormhole",
-				zap.Int("attempt", attempt),
-				zap.Int("maxRetries", maxRetries),
-				zap.Duration("nextRetry", retryDelay))
-			select {
-			case <-ctx.Done():
-				return "", fmt.Errorf("context cancelled while waiting for VAA: %w", ctx.Err())
-			case <-time.After(retryDelay):
-				retryDelay = retryDelay * 3 / 2 // Increase delay
-				if retryDelay > 15*time.Second {
-					retryDelay = 15 * time.Second
-				}
-			}
+	if s.checkEmitter {
+		if err := s.verifyEmitter(ctx, vaaBytes); err != nil {
+			return "", err
 		}
 	}
 
-	if lastErr != nil && err != nil {
-		s.logger.Warn("VAA may not be fully posted, attempting receive_value anyway", zap.Error(lastErr))
+	timer := metrics.NewSubmissionTimer(s.logger, "solana")
+	defer timer.Finish()
+
+	// receive_value reads the posted VAA account, so the VAA must be posted first
+	stopPostWait := timer.StartPhase("post_vaa_wait")
+	err = s.waitForPostedVAA(ctx, vaaBytes)
+	stopPostWait()
+	if err != nil && !s.proceedUnposted(ctx, err) {
+		return "", err
 	}
 
 	// Submit receive_value transaction
+	stopReceiveValue := timer.StartPhase("receive_value")
 	sig, err := s.solanaClient.SendReceiveValueTransaction(ctx, vaaBytes, emitterChain, sequence)
+	stopReceiveValue()
 	if err != nil {
-		return "", fmt.Errorf("failed to submit VAA to Solana: %w", err)
+		return "", classifyReceiveValueError(err)
 	}
 
 	s.logger.Info("VAA successfully submitted to Solana",
@@ -95,6 +139,172 @@ func (s *SolanaSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (strin
 	return sig, nil
 }
 
+// SubmitVAABatch submits several VAAs to the Solana MessageBridge, posting each to Wormhole and
+// then packing their receive_value instructions into as few transactions as fit. VAAs the
+// program received before are reported as ErrAlreadyProcessed without being sent again.
+func (s *SolanaSubmitter) SubmitVAABatch(ctx context.Context, vaas [][]byte) []BatchResult {
+	results := make([]BatchResult, len(vaas))
+	if s.solanaClient == nil {
+		for i := range results {
+			results[i].Err = fmt.Errorf("%w: Solana client is nil", ErrNilClient)
+		}
+		return results
+	}
+
+	s.logger.Info("Submitting VAA batch to Solana",
+		zap.Int("vaas", len(vaas)),
+		zap.String("programID", s.solanaClient.GetProgramID().String()),
+		zap.String("payer", s.solanaClient.GetPayerAddress().String()))
+
+	// Each VAA is posted separately; only those posted are batched for receive_value. A sequence
+	// appearing twice is left out after the first, since the client rejects a batch requesting it
+	// twice; it is transient so a replay finds it received.
+	type messageKey struct {
+		chain    uint16
+		sequence uint64
+	}
+	seen := make(map[messageKey]bool, len(vaas))
+	var requests []clients.ReceiveValueRequest
+	var indexes []int
+	for i, vaaBytes := range vaas {
+		emitterChain, sequence, err := parseVAAHeader(vaaBytes)
+		if err != nil {
+			results[i].Err = classify(ErrPermanent, fmt.Errorf("failed to parse VAA header: %w", err))
+			continue
+		}
+		key := messageKey{emitterChain, sequence}
+		if seen[key] {
+			results[i].Err = classify(ErrTransient, fmt.Errorf("sequence %d of chain %d appears earlier in the batch", sequence, emitterChain))
+			continue
+		}
+		seen[key] = true
+		if s.checkEmitter {
+			if err := s.verifyEmitter(ctx, vaaBytes); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		if err := s.waitForPostedVAA(ctx, vaaBytes); err != nil && !s.proceedUnposted(ctx, err) {
+			results[i].Err = err
+			continue
+		}
+		requests = append(requests, clients.ReceiveValueRequest{VAABytes: vaaBytes, EmitterChain: emitterChain, Sequence: sequence})
+		indexes = append(indexes, i)
+	}
+
+	if len(requests) > 0 {
+		received, err := s.solanaClient.SendReceiveValueBatch(ctx, requests)
+		for j, i := range indexes {
+			switch {
+			case err != nil:
+				// The batch could not be sent at all, which says nothing about this VAA; a replay retries it
+				results[i].Err = classify(ErrConfig, fmt.Errorf("failed to submit VAA batch to Solana: %w", err))
+			case received[j].AlreadyReceived:
+				results[i].Err = classify(ErrAlreadyProcessed, fmt.Errorf("sequence %d already received by the program", requests[j].Sequence))
+			case received[j].Err != nil:
+				results[i].Err = classifyReceiveValueError(received[j].Err)
+			default:
+				results[i].TxHash = received[j].Signature
+			}
+		}
+	}
+
+	for _, result := range results {
+		recordFailure("solana", result.Err)
+	}
+	return results
+}
+
+// classifyReceiveValueError classifies an error delivering a VAA with receive_value
+func classifyReceiveValueError(err error) error {
+	if errors.Is(err, clients.ErrInstructionTooLarge) {
+		// This VAA can never be sent, but the others batched with it can
+		return classify(ErrPermanent, fmt.Errorf("failed to submit VAA to Solana: %w", err))
+	}
+	if errors.Is(err, clients.ErrInsufficientBalance) || errors.Is(err, clients.ErrInvalidNonceAccount) {
+		// Every VAA fails the same way until the payer is funded or the nonce account fixed; a replay retries it
+		return classify(ErrConfig, fmt.Errorf("failed to submit VAA to Solana: %w", err))
+	}
+	return classifyDestinationError(fmt.Errorf("failed to submit VAA to Solana: %w", err))
+}
+
+// verifyEmitter checks the program has the VAA's emitter registered for its source chain, so a
+// VAA receive_value would reject is not posted and sent at all. An unregistered emitter is a
+// configuration error; a failed lookup is only logged, leaving it to the transaction to decide.
+func (s *SolanaSubmitter) verifyEmitter(ctx context.Context, vaaBytes []byte) error {
+	emitterChain, emitter, err := parseVAAEmitter(vaaBytes)
+	if err != nil {
+		return classify(ErrPermanent, fmt.Errorf("failed to parse VAA emitter: %w", err))
+	}
+
+	err = s.solanaClient.VerifyEmitterRegistered(ctx, emitterChain, emitter)
+	if errors.Is(err, clients.ErrEmitterNotRegistered) {
+		s.logger.Error("Emitter not registered on destination; register it with the MessageBridge program",
+			zap.Uint16("emitterChain", emitterChain),
+			zap.String("emitter", fmt.Sprintf("%x", emitter)),
+			zap.String("programID", s.solanaClient.GetProgramID().String()),
+			zap.Error(err))
+		return classify(ErrConfig, err)
+	}
+	if err != nil {
+		s.logger.Warn("Could not check emitter registration; submitting anyway", zap.Error(err))
+	}
+	return nil
+}
+
+// waitForPostedVAA posts the VAA to the Wormhole program, or finds it already posted, retrying
+// with exponential backoff. It returns nil once the VAA is posted. Otherwise it returns an
+// ErrTransient error carrying the last post failure once postAttempts are used up or ctx is
+// done, whichever comes first, or an ErrPermanent one as soon as a post fails in a way waiting
+// cannot fix, such as the posting service rejecting the VAA's signatures.
+func (s *SolanaSubmitter) waitForPostedVAA(ctx context.Context, vaaBytes []byte) error {
+	backoff := clients.NewBackoff(s.postInitialDelay, s.postMaxDelay)
+	for attempt := 1; ; attempt++ {
+		_, err := s.solanaClient.PostVAAToWormhole(ctx, vaaBytes)
+		if err == nil {
+			s.logger.Info("VAA is posted to Wormhole, proceeding with receive_value", zap.Int("attempt", attempt))
+			return nil
+		}
+		if ctx.Err() == nil && !isTransientPostError(err) {
+			return classify(ErrPermanent, fmt.Errorf("failed to post VAA to Wormhole: %w", err))
+		}
+		if attempt >= s.postAttempts {
+			return classify(ErrTransient, fmt.Errorf("VAA not posted to Wormhole after %d attempts: %w", attempt, err))
+		}
+
+		delay := backoff.Next()
+		s.logger.Info("Waiting for VAA to be posted to Wormhole",
+			zap.Int("attempt", attempt),
+			zap.Int("maxAttempts", s.postAttempts),
+			zap.Duration("nextRetry", delay),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return classify(ErrTransient, fmt.Errorf("gave up waiting for VAA to be posted to Wormhole after %d attempts (last error: %v): %w", attempt, err, ctx.Err()))
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isTransientPostError reports whether a failed post may succeed if retried: the VAA is not
+// posted yet, or the node or posting service could not be reached
+func isTransientPostError(err error) bool {
+	return errors.Is(err, clients.ErrVAANotPosted) || errors.Is(classifyDestinationError(err), ErrTransient)
+}
+
+// proceedUnposted reports whether to send receive_value for a VAA waitForPostedVAA failed with
+// postErr. Only the optimistic policy does, and only once the attempts are used up on a VAA that
+// may yet be posted: a done ctx leaves no time to send anything.
+func (s *SolanaSubmitter) proceedUnposted(ctx context.Context, postErr error) bool {
+	if s.postPolicy != SolanaPostPolicyOptimistic || ctx.Err() != nil || !errors.Is(postErr, ErrTransient) {
+		return false
+	}
+	s.logger.Warn("VAA may not be fully posted, attempting receive_value anyway",
+		zap.String("postPolicy", string(s.postPolicy)),
+		zap.Error(postErr))
+	return true
+}
+
 // parseVAAHeader extracts emitter chain and sequence from VAA bytes
 func parseVAAHeader(vaaBytes []byte) (emitterChain uint16, sequence uint64, err error) {
 	// VAA structure:
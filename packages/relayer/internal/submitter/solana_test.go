@@ -0,0 +1,303 @@
+package submitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestSolanaSubmitterSubmitVAA(t *testing.T) {
+	vaaBytes := buildTestVAA(make([]byte, 18))
+	vaaBytes[6+49] = 7 // Last byte of the sequence
+
+	relayer := &mockSolanaRelayer{signature: "sig"}
+	signature, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes)
+	if err != nil || signature != "sig" {
+		t.Fatalf("expected signature sig, got %q (err %v)", signature, err)
+	}
+	if len(relayer.received) != 1 || relayer.received[0] != 7 {
+		t.Errorf("expected receive_value for sequence 7, got %v", relayer.received)
+	}
+
+	relayer = &mockSolanaRelayer{receiveErr: errors.New("Allocate: account Address { address: abc, base: None } already in use")}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrAlreadyProcessed) {
+		t.Errorf("expected ErrAlreadyProcessed, got %v (class %s)", err, ErrorClass(err))
+	}
+
+	relayer = &mockSolanaRelayer{receiveErr: fmt.Errorf("%w: payer has 0.000001 SOL", clients.ErrInsufficientBalance)}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrConfig) {
+		t.Errorf("expected ErrConfig for an unfunded payer, got %v (class %s)", err, ErrorClass(err))
+	}
+
+	relayer = &mockSolanaRelayer{receiveErr: fmt.Errorf("%w: account does not exist", clients.ErrInvalidNonceAccount)}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrConfig) {
+		t.Errorf("expected ErrConfig for an unusable nonce account, got %v (class %s)", err, ErrorClass(err))
+	}
+
+	relayer = &mockSolanaRelayer{}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), []byte{1}); !errors.Is(err, ErrPermanent) {
+		t.Errorf("expected ErrPermanent for a malformed VAA, got %v", err)
+	}
+	if len(relayer.received) != 0 {
+		t.Error("expected no receive_value for a malformed VAA")
+	}
+}
+
+func TestSolanaSubmitterCancelledWhileWaitingForPost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	relayer := &mockSolanaRelayer{postErr: clients.ErrVAANotPosted}
+	_, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(ctx, buildTestVAA(make([]byte, 18)))
+	if !errors.Is(err, ErrTransient) {
+		t.Errorf("expected ErrTransient, got %v (class %s)", err, ErrorClass(err))
+	}
+}
+
+// newFastSolanaSubmitter returns a submitter that retries posting every millisecond
+func newFastSolanaSubmitter(relayer SolanaRelayer, attempts int) *SolanaSubmitter {
+	s := NewSolanaSubmitter(zap.NewNop(), relayer)
+	s.postAttempts = attempts
+	s.postInitialDelay = time.Millisecond
+	s.postMaxDelay = time.Millisecond
+	return s
+}
+
+func TestSolanaSubmitterWaitsForPostedVAA(t *testing.T) {
+	notPosted := clients.ErrVAANotPosted
+	tests := []struct {
+		name         string
+		postErr      error
+		postFailures int
+		wantPosts    int
+		wantErr      bool
+	}{
+		{name: "posted immediately", wantPosts: 1},
+		{name: "posted after 3 failures", postErr: notPosted, postFailures: 3, wantPosts: 4},
+		{name: "never posted", postErr: notPosted, wantPosts: 5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relayer := &mockSolanaRelayer{postErr: tt.postErr, postFailures: tt.postFailures, signature: "sig"}
+			signature, err := newFastSolanaSubmitter(relayer, 5).SubmitVAA(context.Background(), buildTestVAA(make([]byte, 18)))
+
+			if relayer.posts != tt.wantPosts {
+				t.Errorf("expected %d post attempts, got %d", tt.wantPosts, relayer.posts)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrTransient) || !errors.Is(err, notPosted) {
+					t.Fatalf("expected a transient error wrapping the last post failure, got %v", err)
+				}
+				if len(relayer.received) != 0 {
+					t.Error("expected no receive_value for a VAA that was never posted")
+				}
+				return
+			}
+			if err != nil || signature != "sig" {
+				t.Fatalf("expected signature sig, got %q (err %v)", signature, err)
+			}
+			if len(relayer.received) != 1 {
+				t.Errorf("expected one receive_value, got %d", len(relayer.received))
+			}
+		})
+	}
+}
+
+func TestSolanaSubmitterStopsOnRejectedPost(t *testing.T) {
+	rejected := fmt.Errorf("failed to post VAA via service: VAA service request failed: %w",
+		&clients.HTTPStatusError{StatusCode: 400, Body: "invalid guardian signatures"})
+
+	for _, policy := range []SolanaPostPolicy{SolanaPostPolicyStrict, SolanaPostPolicyOptimistic} {
+		relayer := &mockSolanaRelayer{postErr: rejected, signature: "sig"}
+		s := newFastSolanaSubmitter(relayer, 5)
+		s.SetPostPolicy(policy)
+		_, err := s.SubmitVAA(context.Background(), buildTestVAA(make([]byte, 18)))
+
+		// Waiting does not fix a VAA the posting service rejects, so it fails for good at once
+		if !errors.Is(err, ErrPermanent) || !errors.Is(err, rejected) {
+			t.Errorf("%s: expected a permanent error wrapping the post failure, got %v", policy, err)
+		}
+		if relayer.posts != 1 || len(relayer.received) != 0 {
+			t.Errorf("%s: expected one post and no receive_value, got %d posts and %d sent", policy, relayer.posts, len(relayer.received))
+		}
+	}
+}
+
+func TestSolanaSubmitterPostWaitRespectsDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	relayer := &mockSolanaRelayer{postErr: clients.ErrVAANotPosted}
+	s := NewSolanaSubmitter(zap.NewNop(), relayer) // Default delays far exceed the deadline
+
+	start := time.Now()
+	_, err := s.SubmitVAA(ctx, buildTestVAA(make([]byte, 18)))
+	if !errors.Is(err, ErrTransient) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a transient deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to stop at the deadline, took %v", elapsed)
+	}
+	if relayer.posts != 1 {
+		t.Errorf("expected a single post attempt before the deadline, got %d", relayer.posts)
+	}
+}
+
+func TestSolanaSubmitterPostPolicy(t *testing.T) {
+	notPosted := clients.ErrVAANotPosted
+	tests := []struct {
+		name         string
+		policy       SolanaPostPolicy
+		postFailures int // 0 = never posted
+		wantReceive  bool
+	}{
+		{name: "strict, never posted", policy: SolanaPostPolicyStrict},
+		{name: "strict, posted on retry", policy: SolanaPostPolicyStrict, postFailures: 2, wantReceive: true},
+		{name: "optimistic, never posted", policy: SolanaPostPolicyOptimistic, wantReceive: true},
+		{name: "optimistic, posted on retry", policy: SolanaPostPolicyOptimistic, postFailures: 2, wantReceive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relayer := &mockSolanaRelayer{postErr: notPosted, postFailures: tt.postFailures, signature: "sig"}
+			s := newFastSolanaSubmitter(relayer, 3)
+			s.SetPostPolicy(tt.policy)
+			signature, err := s.SubmitVAA(context.Background(), buildTestVAA(make([]byte, 18)))
+
+			if !tt.wantReceive {
+				if !errors.Is(err, ErrTransient) || !errors.Is(err, notPosted) {
+					t.Fatalf("expected a transient error wrapping the post failure, got %v", err)
+				}
+				if len(relayer.received) != 0 {
+					t.Error("expected no receive_value for a VAA not confirmed posted")
+				}
+				return
+			}
+			if err != nil || signature != "sig" {
+				t.Fatalf("expected signature sig, got %q (err %v)", signature, err)
+			}
+			if len(relayer.received) != 1 {
+				t.Errorf("expected one receive_value, got %d", len(relayer.received))
+			}
+		})
+	}
+
+	// Out of time, even the optimistic policy sends nothing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	relayer := &mockSolanaRelayer{postErr: notPosted, signature: "sig"}
+	s := newFastSolanaSubmitter(relayer, 3)
+	s.SetPostPolicy(SolanaPostPolicyOptimistic)
+	if _, err := s.SubmitVAA(ctx, buildTestVAA(make([]byte, 18))); !errors.Is(err, ErrTransient) || len(relayer.received) != 0 {
+		t.Errorf("expected a transient error and no receive_value once cancelled, got %v (%d sent)", err, len(relayer.received))
+	}
+}
+
+func TestParseSolanaPostPolicy(t *testing.T) {
+	for input, want := range map[string]SolanaPostPolicy{
+		"":           SolanaPostPolicyStrict,
+		"strict":     SolanaPostPolicyStrict,
+		"optimistic": SolanaPostPolicyOptimistic,
+	} {
+		if got, err := ParseSolanaPostPolicy(input); err != nil || got != want {
+			t.Errorf("%q: expected %s, got %s (%v)", input, want, got, err)
+		}
+	}
+	if _, err := ParseSolanaPostPolicy("lenient"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
+
+func TestSolanaSubmitterEmitterCheck(t *testing.T) {
+	vaaBytes := buildTestVAA(make([]byte, 18))
+	unregistered := fmt.Errorf("%w: no foreign emitter for chain 2", clients.ErrEmitterNotRegistered)
+
+	relayer := &mockSolanaRelayer{signature: "sig", emitterErr: unregistered}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes); !errors.Is(err, ErrConfig) {
+		t.Errorf("expected ErrConfig for an unregistered emitter, got %v (class %s)", err, ErrorClass(err))
+	}
+	if relayer.posts != 0 || len(relayer.received) != 0 {
+		t.Error("expected nothing to be sent for an unregistered emitter")
+	}
+
+	// A failed lookup leaves it to the transaction
+	relayer = &mockSolanaRelayer{signature: "sig", emitterErr: errors.New("connection refused")}
+	if _, err := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAA(context.Background(), vaaBytes); err != nil {
+		t.Errorf("expected a failed lookup to be ignored, got %v", err)
+	}
+
+	relayer = &mockSolanaRelayer{signature: "sig", emitterErr: unregistered}
+	s := NewSolanaSubmitter(zap.NewNop(), relayer)
+	s.SetEmitterCheck(false)
+	if _, err := s.SubmitVAA(context.Background(), vaaBytes); err != nil || len(relayer.received) != 1 {
+		t.Errorf("expected the VAA to be sent with the check disabled, got %v", err)
+	}
+}
+
+func TestSolanaSubmitterSubmitVAABatch(t *testing.T) {
+	var _ VAABatchSubmitter = (*SolanaSubmitter)(nil)
+
+	vaas := make([][]byte, 3)
+	for i := range vaas {
+		vaas[i] = buildTestVAA(make([]byte, 18))
+		vaas[i][6+49] = byte(10 + i) // Last byte of the sequence
+	}
+	vaas = append(vaas, []byte{1}) // Malformed
+
+	relayer := &mockSolanaRelayer{signature: "sig", alreadyReceived: map[uint64]bool{11: true}}
+	results := NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAABatch(context.Background(), vaas)
+	if len(results) != len(vaas) {
+		t.Fatalf("expected %d results, got %d", len(vaas), len(results))
+	}
+	if results[0].TxHash != "sig" || results[0].Err != nil || results[2].TxHash != "sig" || results[2].Err != nil {
+		t.Errorf("expected sequences 10 and 12 delivered, got %+v and %+v", results[0], results[2])
+	}
+	if !errors.Is(results[1].Err, ErrAlreadyProcessed) {
+		t.Errorf("expected sequence 11 to be ErrAlreadyProcessed, got %v", results[1].Err)
+	}
+	if !errors.Is(results[3].Err, ErrPermanent) {
+		t.Errorf("expected ErrPermanent for a malformed VAA, got %v", results[3].Err)
+	}
+	if len(relayer.received) != 3 {
+		t.Errorf("expected the three parsed VAAs batched, got %v", relayer.received)
+	}
+
+	relayer = &mockSolanaRelayer{receiveErr: fmt.Errorf("%w: payer has 0 SOL", clients.ErrInsufficientBalance)}
+	results = NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAABatch(context.Background(), vaas[:1])
+	if !errors.Is(results[0].Err, ErrConfig) {
+		t.Errorf("expected ErrConfig for an unfunded payer, got %v", results[0].Err)
+	}
+
+	// A VAA too large to send fails for good without holding back the others
+	relayer = &mockSolanaRelayer{signature: "sig", receiveErrs: map[uint64]error{11: clients.ErrInstructionTooLarge}}
+	results = NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAABatch(context.Background(), vaas[:3])
+	if results[0].Err != nil || !errors.Is(results[1].Err, ErrPermanent) || results[2].Err != nil {
+		t.Errorf("expected only the oversized sequence 11 to fail, as ErrPermanent, got %+v", results)
+	}
+
+	// A batch that cannot be sent at all is not settled as a permanent failure of its VAAs
+	relayer = &mockSolanaRelayer{batchErr: errors.New("sequence 10 of chain 0 is requested more than once")}
+	results = NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAABatch(context.Background(), vaas[:2])
+	for i, result := range results {
+		if !errors.Is(result.Err, ErrConfig) {
+			t.Errorf("VAA %d: expected ErrConfig for an unsendable batch, got %v", i, result.Err)
+		}
+	}
+
+	// A sequence appearing twice is sent once, the repeat left for a replay
+	relayer = &mockSolanaRelayer{signature: "sig"}
+	results = NewSolanaSubmitter(zap.NewNop(), relayer).SubmitVAABatch(context.Background(), [][]byte{vaas[0], vaas[1], vaas[0]})
+	if results[0].Err != nil || results[1].Err != nil || !errors.Is(results[2].Err, ErrTransient) {
+		t.Errorf("expected the repeated sequence alone to be ErrTransient, got %+v", results)
+	}
+	if len(relayer.received) != 2 {
+		t.Errorf("expected the repeated sequence to be batched once, got %v", relayer.received)
+	}
+}
@@ -3,8 +3,21 @@ package submitter
 import "context"
 
 type VAASubmitter interface {
-	// SubmitVAA submits the given VAA bytes to the target contract and returns the transaction hash or an error
+	// SubmitVAA submits the given VAA bytes to the target contract and returns the transaction hash or an error.
+	// Errors are classified as ErrTransient, ErrAlreadyProcessed, ErrPermanent or ErrConfig.
+	// Submitters do not impose their own deadline; they honour the one on ctx.
 	SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error)
 }
 
+// BatchResult is the outcome of one VAA submitted with SubmitVAABatch
+type BatchResult struct {
+	TxHash string // Transaction that delivered the VAA, possibly shared with other VAAs of the batch
+	Err    error  // Classified like the errors of SubmitVAA
+}
 
+// VAABatchSubmitter is a VAASubmitter that can deliver several VAAs in fewer transactions than one each
+type VAABatchSubmitter interface {
+	VAASubmitter
+	// SubmitVAABatch submits vaas and returns the result of each, in order
+	SubmitVAABatch(ctx context.Context, vaas [][]byte) []BatchResult
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/metrics"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+// vaaDecision returns the Decision* for a processed VAA.
+// The processor reports a filtered VAA as an empty transaction hash without an error.
+func vaaDecision(txHash string, err error) string {
+	switch {
+	case errors.Is(err, submitter.ErrAlreadyProcessed):
+		return DecisionAlreadyProcessed
+	case err != nil:
+		return DecisionFailed
+	case txHash == "":
+		return DecisionFiltered
+	default:
+		return DecisionSubmitted
+	}
+}
+
+// runSummary accumulates what a relayer handled during one run, so a single line can be
+// logged on shutdown. Counts are mirrored to the Prometheus counters as they are recorded.
+type runSummary struct {
+	mu           sync.Mutex
+	startedAt    time.Time
+	received     int               // VAAs received from the spy, including duplicates
+	duplicates   int               // Received VAAs dropped as duplicates
+	malformed    int               // Received VAAs dropped as empty or too short to parse
+	decisions    map[string]int    // Handled VAAs by Decision*
+	destinations map[string]int    // Handled VAAs by payload destination chain ID ("none" if the payload has none)
+	lastSequence map[string]uint64 // Highest sequence handled per "chain/emitter"
+}
+
+func newRunSummary() *runSummary {
+	return &runSummary{
+		startedAt:    time.Now(),
+		decisions:    make(map[string]int),
+		destinations: make(map[string]int),
+		lastSequence: make(map[string]uint64),
+	}
+}
+
+// recordReceived counts a VAA received from the spy
+func (s *runSummary) recordReceived(duplicate bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.received++
+	metrics.VAAsReceived.Inc()
+	if duplicate {
+		s.duplicates++
+	}
+}
+
+// recordMalformed counts a VAA received from the spy that was too short to parse
+func (s *runSummary) recordMalformed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.received++
+	s.malformed++
+	metrics.VAAsReceived.Inc()
+	metrics.MalformedVAAs.Inc()
+}
+
+// recordFailed counts a VAA that failed before it could be identified (e.g. it did not parse)
+func (s *runSummary) recordFailed() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.decisions[DecisionFailed]++
+	metrics.VAAsHandled.WithLabelValues(DecisionFailed).Inc()
+}
+
+// recordHandled counts a processed VAA under decision
+func (s *runSummary) recordHandled(vaaData *VAAData, decision string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.decisions[decision]++
+	metrics.VAAsHandled.WithLabelValues(decision).Inc()
+	destination := "none"
+	if vaaData.HasDestination {
+		destination = strconv.Itoa(int(vaaData.DestinationChainID))
+	}
+	s.destinations[destination]++
+
+	emitter := vaaData.ID.EmitterString()
+	if seq, ok := s.lastSequence[emitter]; !ok || vaaData.Sequence > seq {
+		s.lastSequence[emitter] = vaaData.Sequence
+	}
+}
+
+// fields returns the summary as log fields
+func (s *runSummary) fields() []zap.Field {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	destinations := make(map[string]int, len(s.destinations))
+	for chain, count := range s.destinations {
+		destinations[chain] = count
+	}
+	lastSequence := make(map[string]uint64, len(s.lastSequence))
+	for emitter, seq := range s.lastSequence {
+		lastSequence[emitter] = seq
+	}
+
+	return []zap.Field{
+		zap.Duration("uptime", time.Since(s.startedAt).Round(time.Second)),
+		zap.Int("received", s.received),
+		zap.Int("duplicates", s.duplicates),
+		zap.Int("malformed", s.malformed),
+		zap.Int("submitted", s.decisions[DecisionSubmitted]),
+		zap.Int("alreadyProcessed", s.decisions[DecisionAlreadyProcessed]),
+		zap.Int("filtered", s.decisions[DecisionFiltered]),
+		zap.Int("failed", s.decisions[DecisionFailed]),
+		zap.Any("destinations", destinations),
+		zap.Any("lastSequence", lastSequence),
+	}
+}
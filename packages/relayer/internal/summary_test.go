@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/wormhole-demo/relayer/internal/submitter"
+)
+
+func TestVAADecision(t *testing.T) {
+	tests := []struct {
+		txHash string
+		err    error
+		want   string
+	}{
+		{txHash: "0xabc", want: DecisionSubmitted},
+		{txHash: "", want: DecisionFiltered},
+		{err: errors.New("reverted"), want: DecisionFailed},
+		{err: fmt.Errorf("relay: %w", submitter.ErrAlreadyProcessed), want: DecisionAlreadyProcessed},
+	}
+
+	for _, tt := range tests {
+		if got := vaaDecision(tt.txHash, tt.err); got != tt.want {
+			t.Errorf("vaaDecision(%q, %v) = %s, want %s", tt.txHash, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRunSummary(t *testing.T) {
+	summary := newRunSummary()
+
+	payload := make([]byte, 18)
+	payload[0], payload[1] = 0x27, 0x13 // Destination 10003
+	vaaData := testVAADataWithPayload(payload)
+	vaaData.EmitterHex, vaaData.ID.Emitter = "aa", "aa"
+
+	for _, seq := range []uint64{3, 5, 4} {
+		summary.recordReceived(false)
+		vaaData.Sequence = seq
+		summary.recordHandled(&vaaData, DecisionSubmitted)
+	}
+	summary.recordReceived(true)
+	summary.recordReceived(false)
+	summary.recordFailed()
+	summary.recordMalformed()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	zap.New(core).Info("Relayer summary", summary.fields()...)
+
+	fields := logs.All()[0].ContextMap()
+	want := map[string]interface{}{
+		"received":         int64(6),
+		"duplicates":       int64(1),
+		"malformed":        int64(1),
+		"submitted":        int64(3),
+		"alreadyProcessed": int64(0),
+		"filtered":         int64(0),
+		"failed":           int64(1),
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("expected %s=%v, got %v", key, value, fields[key])
+		}
+	}
+
+	if destinations, ok := fields["destinations"].(map[string]int); !ok || destinations["10003"] != 3 {
+		t.Errorf("expected 3 VAAs for destination 10003, got %v", fields["destinations"])
+	}
+	if lastSequence, ok := fields["lastSequence"].(map[string]uint64); !ok || lastSequence["2/aa"] != 5 {
+		t.Errorf("expected last sequence 5 for emitter 2/aa, got %v", fields["lastSequence"])
+	}
+}
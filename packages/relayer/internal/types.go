@@ -1,14 +1,44 @@
 package internal
 
 import (
+	"math/big"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
 	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
 )
 
 type VAAData struct {
-	VAA        *vaaLib.VAA // The parsed VAA
-	RawBytes   []byte      // Raw VAA bytes
-	ChainID    uint16      // Source chain ID
-	EmitterHex string      // Hex-encoded emitter address
-	Sequence   uint64      // VAA sequence number
-	TxID       string      // Source transaction ID
+	ID                 VAAIdentity // Canonical identity (chain/emitter/sequence), used to name the VAA in logs and records
+	VAA                *vaaLib.VAA // The parsed VAA
+	RawBytes           []byte      // Raw VAA bytes
+	ChainID            uint16      // Source chain ID
+	EmitterHex         string      // Hex-encoded emitter address
+	Sequence           uint64      // VAA sequence number
+	TxID               string      // Source transaction ID (empty if the payload format has none)
+	DestinationChainID uint16      // Destination chain ID from the payload (only meaningful if HasDestination)
+	HasDestination     bool        // The payload carries a destination chain ID
+	Value              *big.Int    // uint128 value from the payload (nil if the payload has none)
+	// Layout the payload was decoded with, chosen by the source chain (see clients.DetectPayloadFormat)
+	PayloadFormat clients.PayloadFormat
+}
+
+// NewVAAData builds the VAAData for a parsed VAA, decoding the source transaction ID (Aztec
+// layout only), destination chain and value from the payload. Both payload layouts are
+// supported, told apart by the source chain (see clients.DetectPayloadFormat):
+//   - Default (18 bytes): [chainId(2) | value(16)]
+//   - Aztec (50 bytes):   [txId(32) | chainId(2) | value(16)]
+func NewVAAData(vaa *vaaLib.VAA, rawBytes []byte) *VAAData {
+	vaaData := &VAAData{
+		VAA:        vaa,
+		RawBytes:   rawBytes,
+		ChainID:    uint16(vaa.EmitterChain),
+		EmitterHex: NormalizeEmitter(vaa.EmitterAddress[:]),
+		Sequence:   vaa.Sequence,
+	}
+	vaaData.ID = VAAIdentity{ChainID: vaaData.ChainID, Emitter: vaaData.EmitterHex, Sequence: vaaData.Sequence}
+	vaaData.PayloadFormat = clients.DetectPayloadFormat(vaaData.ChainID, vaa.Payload)
+	vaaData.DestinationChainID, vaaData.HasDestination = clients.ExtractDestinationChainID(vaaData.PayloadFormat, vaa.Payload)
+	vaaData.Value, _ = clients.ExtractPayloadValue(vaaData.PayloadFormat, vaa.Payload)
+	vaaData.TxID, _ = clients.ExtractSourceTxID(vaaData.PayloadFormat, vaa.Payload)
+	return vaaData
 }
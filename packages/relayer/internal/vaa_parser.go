@@ -3,6 +3,7 @@ package internal
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,20 +11,45 @@ import (
 	"go.uber.org/zap"
 )
 
-// ParseVAAPermissive parses a VAA without being strict about version.
-// It handles both v1 and v2 VAAs by extracting the fields we need.
-// The raw bytes are still passed to the on-chain contracts for proper verification.
+// batchVAAVersion is the version byte of a batch (v2) VAA
+const batchVAAVersion = 2
+
+// VAA v1 layout sizes
+const (
+	vaaHeaderLength     = 6  // version (1) + guardian set index (4) + signature count (1)
+	vaaSignatureLength  = 66 // guardian index (1) + secp256k1 signature (65)
+	vaaBodyHeaderLength = 51 // timestamp (4) + nonce (4) + emitter chain (2) + emitter address (32) + sequence (8) + consistency level (1)
+
+	// minVAALength is the size of the shortest parseable VAA: no signatures and an empty payload
+	minVAALength = vaaHeaderLength + vaaBodyHeaderLength
+)
+
+// ErrUnsupportedVAAVersion is returned when a VAA has a version the parser cannot read
+var ErrUnsupportedVAAVersion = errors.New("unsupported VAA version")
+
+// ParseVAAPermissive parses a v1 VAA without verifying its signatures.
+// It extracts the fields we need; the raw bytes are still passed to the on-chain
+// contracts for proper verification.
+//
+// Only version 1 is supported. Version 2 (batch VAAs) carries a list of observation
+// hashes and nested observations after the signatures rather than a single body, so
+// reading it with the v1 offsets would yield a bogus emitter and sequence; it is
+// rejected with ErrUnsupportedVAAVersion instead.
 func ParseVAAPermissive(data []byte) (*vaaLib.VAA, error) {
-	if len(data) < 6 {
-		return nil, fmt.Errorf("VAA too short: %d bytes", len(data))
+	if len(data) < vaaHeaderLength {
+		return nil, fmt.Errorf("VAA too short: %d bytes, need at least %d for the header", len(data), vaaHeaderLength)
 	}
 
 	version := data[0]
-	if version != 1 && version != 2 {
-		return nil, fmt.Errorf("unsupported VAA version: %d", version)
+	switch version {
+	case vaaLib.SupportedVAAVersion:
+	case batchVAAVersion:
+		return nil, fmt.Errorf("%w: version %d (batch VAA) is not supported, only version %d", ErrUnsupportedVAAVersion, version, vaaLib.SupportedVAAVersion)
+	default:
+		return nil, fmt.Errorf("%w: version %d", ErrUnsupportedVAAVersion, version)
 	}
 
-	// VAA structure (same for v1 and v2):
+	// VAA v1 structure:
 	// 0: version (1 byte)
 	// 1-4: guardian set index (4 bytes)
 	// 5: signature count (1 byte)
@@ -33,12 +59,11 @@ func ParseVAAPermissive(data []byte) (*vaaLib.VAA, error) {
 	guardianSetIndex := binary.BigEndian.Uint32(data[1:5])
 	signatureCount := int(data[5])
 
-	// Each signature is 66 bytes (1 byte index + 65 bytes signature)
-	signatureSize := 66
-	signaturesEnd := 6 + (signatureCount * signatureSize)
-
-	if len(data) < signaturesEnd {
-		return nil, fmt.Errorf("VAA too short for %d signatures", signatureCount)
+	// The declared signature count must leave room for the signatures and a full body header
+	signaturesEnd := vaaHeaderLength + signatureCount*vaaSignatureLength
+	if minLength := signaturesEnd + vaaBodyHeaderLength; len(data) < minLength {
+		return nil, fmt.Errorf("VAA length %d is inconsistent with its %d signatures: need at least %d bytes (%d header + %d×%d signatures + %d body header), %d bytes short",
+			len(data), signatureCount, minLength, vaaHeaderLength, signatureCount, vaaSignatureLength, vaaBodyHeaderLength, minLength-len(data))
 	}
 
 	// Body starts after signatures
@@ -53,10 +78,6 @@ func ParseVAAPermissive(data []byte) (*vaaLib.VAA, error) {
 	// 50: consistency level (1 byte)
 	// 51+: payload
 
-	if len(body) < 51 {
-		return nil, fmt.Errorf("VAA body too short: %d bytes", len(body))
-	}
-
 	timestamp := binary.BigEndian.Uint32(body[0:4])
 	nonce := binary.BigEndian.Uint32(body[4:8])
 	emitterChain := binary.BigEndian.Uint16(body[8:10])
@@ -67,15 +88,15 @@ func ParseVAAPermissive(data []byte) (*vaaLib.VAA, error) {
 	sequence := binary.BigEndian.Uint64(body[42:50])
 	consistencyLevel := body[50]
 
-	payload := body[51:]
+	payload := body[vaaBodyHeaderLength:]
 
 	// Parse signatures
 	signatures := make([]*vaaLib.Signature, signatureCount)
 	for i := 0; i < signatureCount; i++ {
-		sigStart := 6 + (i * signatureSize)
+		sigStart := vaaHeaderLength + i*vaaSignatureLength
 		guardianIndex := data[sigStart]
 		var sig [65]byte
-		copy(sig[:], data[sigStart+1:sigStart+66])
+		copy(sig[:], data[sigStart+1:sigStart+vaaSignatureLength])
 		signatures[i] = &vaaLib.Signature{
 			Index:     guardianIndex,
 			Signature: sig,
@@ -96,7 +117,7 @@ func ParseVAAPermissive(data []byte) (*vaaLib.VAA, error) {
 	}, nil
 }
 
-// LogVAAFull logs all fields of a VAA for debugging
+// LogVAAFull logs all fields of a VAA for debugging, with the raw bytes as hex so it can be replayed
 func LogVAAFull(logger *zap.Logger, vaa *vaaLib.VAA, rawBytes []byte) {
 	logger.Info("=== Full VAA Details ===",
 		zap.Uint8("version", vaa.Version),
@@ -108,9 +129,11 @@ func LogVAAFull(logger *zap.Logger, vaa *vaaLib.VAA, rawBytes []byte) {
 		zap.Uint8("consistencyLevel", vaa.ConsistencyLevel),
 		zap.Uint16("emitterChain", uint16(vaa.EmitterChain)),
 		zap.String("emitterAddress", hex.EncodeToString(vaa.EmitterAddress[:])),
+		zap.String("emitterNative", FormatEmitter(uint16(vaa.EmitterChain), NormalizeEmitter(vaa.EmitterAddress[:]))),
 		zap.Int("payloadLength", len(vaa.Payload)),
 		zap.String("payloadHex", hex.EncodeToString(vaa.Payload)),
 		zap.Int("rawBytesLength", len(rawBytes)),
+		zap.String("rawHex", hex.EncodeToString(rawBytes)),
 	)
 
 	// Log each signature
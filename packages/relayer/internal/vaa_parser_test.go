@@ -0,0 +1,266 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildV1VAA builds a v1 VAA with the given number of (dummy) signatures
+func buildV1VAA(signatureCount int, emitterChain uint16, emitter [32]byte, sequence uint64, payload []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(1)                                    // version
+	_ = binary.Write(&buf, binary.BigEndian, uint32(4)) // guardian set index
+	buf.WriteByte(byte(signatureCount))
+	for i := 0; i < signatureCount; i++ {
+		buf.WriteByte(byte(i))                           // guardian index
+		buf.Write(bytes.Repeat([]byte{byte(i + 1)}, 65)) // signature
+	}
+	_ = binary.Write(&buf, binary.BigEndian, uint32(1700000000)) // timestamp
+	_ = binary.Write(&buf, binary.BigEndian, uint32(42))         // nonce
+	_ = binary.Write(&buf, binary.BigEndian, emitterChain)
+	buf.Write(emitter[:])
+	_ = binary.Write(&buf, binary.BigEndian, sequence)
+	buf.WriteByte(15) // consistency level
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func TestParseVAAPermissiveV1(t *testing.T) {
+	var emitter [32]byte
+	emitter[31] = 0xab
+	payload := []byte{0x27, 0x13, 0x01}
+
+	for _, signatureCount := range []int{0, 1, 13} {
+		vaa, err := ParseVAAPermissive(buildV1VAA(signatureCount, 56, emitter, 7, payload))
+		if err != nil {
+			t.Fatalf("%d signatures: unexpected error: %v", signatureCount, err)
+		}
+		if vaa.Version != 1 || vaa.GuardianSetIndex != 4 {
+			t.Errorf("%d signatures: expected version 1 / guardian set 4, got %d / %d", signatureCount, vaa.Version, vaa.GuardianSetIndex)
+		}
+		if len(vaa.Signatures) != signatureCount {
+			t.Errorf("%d signatures: parsed %d signatures", signatureCount, len(vaa.Signatures))
+		}
+		if uint16(vaa.EmitterChain) != 56 || vaa.EmitterAddress != emitter || vaa.Sequence != 7 {
+			t.Errorf("%d signatures: wrong identity chain=%d emitter=%x sequence=%d", signatureCount, vaa.EmitterChain, vaa.EmitterAddress, vaa.Sequence)
+		}
+		if vaa.Nonce != 42 || vaa.ConsistencyLevel != 15 || vaa.Timestamp.Unix() != 1700000000 {
+			t.Errorf("%d signatures: wrong body header nonce=%d consistency=%d timestamp=%d", signatureCount, vaa.Nonce, vaa.ConsistencyLevel, vaa.Timestamp.Unix())
+		}
+		if !bytes.Equal(vaa.Payload, payload) {
+			t.Errorf("%d signatures: expected payload %x, got %x", signatureCount, payload, vaa.Payload)
+		}
+	}
+}
+
+func TestParseVAAPermissiveRejectsOtherVersions(t *testing.T) {
+	var emitter [32]byte
+
+	// A batch (v2) VAA: signatures followed by observation hashes and nested observations
+	batch := []byte{2, 0, 0, 0, 4, 0}
+	batch = append(batch, 1)                   // one observation hash
+	batch = append(batch, make([]byte, 32)...) // hash
+	batch = append(batch, 1, 0, 0, 0, 0, 0x40) // one observation: index + length
+	batch = append(batch, buildV1VAA(0, 56, emitter, 7, nil)...)
+
+	for name, data := range map[string][]byte{
+		"v2 batch": batch,
+		"v3":       append([]byte{3}, buildV1VAA(0, 56, emitter, 7, nil)[1:]...),
+	} {
+		if _, err := ParseVAAPermissive(data); !errors.Is(err, ErrUnsupportedVAAVersion) {
+			t.Errorf("%s: expected ErrUnsupportedVAAVersion, got %v", name, err)
+		}
+	}
+}
+
+func TestParseVAAPermissiveInconsistentSignatureCount(t *testing.T) {
+	var emitter [32]byte
+	data := buildV1VAA(2, 56, emitter, 7, nil)
+
+	// Claim one more signature than the VAA carries: the body header no longer fits
+	data[5] = 3
+
+	_, err := ParseVAAPermissive(data)
+	if err == nil {
+		t.Fatal("expected an error for a VAA inconsistent with its signature count")
+	}
+	want := "VAA length 189 is inconsistent with its 3 signatures: need at least 255 bytes (6 header + 3×66 signatures + 51 body header), 66 bytes short"
+	if err.Error() != want {
+		t.Fatalf("expected error %q, got %q", want, err.Error())
+	}
+
+	if _, err := ParseVAAPermissive(data[:4]); err == nil {
+		t.Fatal("expected an error for a VAA shorter than its header")
+	}
+}
+
+// loadVAAFixture reads a hex-encoded VAA from testdata/vaa
+func loadVAAFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "vaa", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	data, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		t.Fatalf("decode fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// mustDecodeHex decodes a hex string in a test table
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return data
+}
+
+// vaaFixture is a fixture VAA with every field the parser extracts
+type vaaFixture struct {
+	file             string
+	length           int
+	guardianSetIndex uint32
+	signatures       int
+	timestamp        int64
+	nonce            uint32
+	emitterChain     uint16
+	emitterAddress   string
+	sequence         uint64
+	consistencyLevel uint8
+	payload          string
+}
+
+var vaaFixtures = []vaaFixture{
+	{
+		file:             "devnet_token_transfer.hex",
+		length:           256,
+		guardianSetIndex: 0,
+		signatures:       1,
+		timestamp:        66136,
+		nonce:            1250754560,
+		emitterChain:     2,
+		emitterAddress:   "0000000000000000000000000290fb167208af455bb137780163b7b7a9a10c16",
+		sequence:         15,
+		consistencyLevel: 15,
+		payload:          "01000000000000000000000000000000000000000000000000000000002b369f40000000000000000000000000ddb64fe46a91d46ee29420539fc25fd07c5fea3e000221c175fcd8e3a19fe2e0deae96534f0f4e6a896f4df0e3ec5345fe27ac3f63f000010000000000000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		file:             "devnet_governance.hex",
+		length:           192,
+		guardianSetIndex: 1,
+		signatures:       1,
+		timestamp:        0,
+		nonce:            12,
+		emitterChain:     1,
+		emitterAddress:   "0000000000000000000000000000000000000000000000000000000000000004",
+		sequence:         38,
+		consistencyLevel: 32,
+		payload:          "000000000000000000000000000000000000000000546f6b656e42726964676501000000080102030400000000000000000000000000000000000000000000000000000000",
+	},
+	{
+		file:             "aztec_transfer_13_signatures.hex",
+		length:           965,
+		guardianSetIndex: 0,
+		signatures:       13,
+		timestamp:        1718000000,
+		nonce:            7,
+		emitterChain:     56,
+		emitterAddress:   "0d8b8a6e6f0ac2b3e9b0a1d33bd6c5aa1b41a1e2b8e1b4a1b5d0f4a6bb3c1e2f",
+		sequence:         1234,
+		consistencyLevel: 1,
+		payload:          "a0a1a2a3a4a5a6a7a8a9aaabacadaeafb0b1b2b3b4b5b6b7b8b9babbbcbdbebf271300000000000000000de0b6b3a7640000",
+	},
+}
+
+func TestParseVAAPermissiveFixtures(t *testing.T) {
+	for _, fx := range vaaFixtures {
+		t.Run(fx.file, func(t *testing.T) {
+			data := loadVAAFixture(t, fx.file)
+			if len(data) != fx.length {
+				t.Fatalf("expected a %d-byte fixture, got %d bytes", fx.length, len(data))
+			}
+
+			vaa, err := ParseVAAPermissive(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if vaa.Version != 1 || vaa.GuardianSetIndex != fx.guardianSetIndex {
+				t.Errorf("expected version 1 / guardian set %d, got %d / %d", fx.guardianSetIndex, vaa.Version, vaa.GuardianSetIndex)
+			}
+			if len(vaa.Signatures) != fx.signatures {
+				t.Fatalf("expected %d signatures, got %d", fx.signatures, len(vaa.Signatures))
+			}
+			for i, sig := range vaa.Signatures {
+				start := vaaHeaderLength + i*vaaSignatureLength
+				if sig.Index != data[start] || !bytes.Equal(sig.Signature[:], data[start+1:start+vaaSignatureLength]) {
+					t.Errorf("signature %d does not match bytes %d-%d", i, start, start+vaaSignatureLength-1)
+				}
+			}
+			if vaa.Timestamp.Unix() != fx.timestamp || vaa.Nonce != fx.nonce {
+				t.Errorf("expected timestamp %d / nonce %d, got %d / %d", fx.timestamp, fx.nonce, vaa.Timestamp.Unix(), vaa.Nonce)
+			}
+			if uint16(vaa.EmitterChain) != fx.emitterChain {
+				t.Errorf("expected emitter chain %d, got %d", fx.emitterChain, vaa.EmitterChain)
+			}
+			if got := hex.EncodeToString(vaa.EmitterAddress[:]); got != fx.emitterAddress {
+				t.Errorf("expected emitter %s, got %s", fx.emitterAddress, got)
+			}
+			if vaa.Sequence != fx.sequence || vaa.ConsistencyLevel != fx.consistencyLevel {
+				t.Errorf("expected sequence %d / consistency %d, got %d / %d", fx.sequence, fx.consistencyLevel, vaa.Sequence, vaa.ConsistencyLevel)
+			}
+			if !bytes.Equal(vaa.Payload, mustDecodeHex(t, fx.payload)) {
+				t.Errorf("expected payload %s, got %x", fx.payload, vaa.Payload)
+			}
+		})
+	}
+}
+
+// Every truncation that cuts into the header, a signature or the body header is rejected;
+// a VAA cut anywhere in its payload still parses, with the payload truncated accordingly
+func TestParseVAAPermissiveTruncatedFixtures(t *testing.T) {
+	for _, fx := range vaaFixtures {
+		t.Run(fx.file, func(t *testing.T) {
+			data := loadVAAFixture(t, fx.file)
+			bodyStart := vaaHeaderLength + fx.signatures*vaaSignatureLength
+			payloadStart := bodyStart + vaaBodyHeaderLength
+
+			for length := 0; length < len(data); length++ {
+				vaa, err := ParseVAAPermissive(data[:length])
+				if length < payloadStart {
+					if err == nil {
+						t.Fatalf("expected an error for a VAA truncated to %d bytes (payload starts at %d)", length, payloadStart)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("unexpected error for a VAA truncated to %d bytes in its payload: %v", length, err)
+				}
+				if !bytes.Equal(vaa.Payload, data[payloadStart:length]) || vaa.Sequence != fx.sequence {
+					t.Fatalf("truncated to %d bytes: expected sequence %d and payload %x, got %d and %x",
+						length, fx.sequence, data[payloadStart:length], vaa.Sequence, vaa.Payload)
+				}
+			}
+		})
+	}
+}
+
+func TestParseVAAPermissiveFixtureUnsupportedVersion(t *testing.T) {
+	for _, fx := range vaaFixtures {
+		data := loadVAAFixture(t, fx.file)
+		for _, version := range []byte{0, 2, 3, 0xff} {
+			data[0] = version
+			if _, err := ParseVAAPermissive(data); !errors.Is(err, ErrUnsupportedVAAVersion) {
+				t.Errorf("%s with version %d: expected ErrUnsupportedVAAVersion, got %v", fx.file, version, err)
+			}
+		}
+	}
+}
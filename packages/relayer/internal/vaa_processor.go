@@ -2,12 +2,17 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"math/big"
+	"strconv"
 	"time"
 
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
 	"github.com/wormhole-demo/relayer/internal/submitter"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 type VAAProcessor interface {
@@ -15,42 +20,287 @@ type VAAProcessor interface {
 	ProcessVAA(ctx context.Context, vaaData VAAData) (string, error)
 }
 
+// DefaultSubmissionTimeout is the per-submission deadline used when none is configured
+const DefaultSubmissionTimeout = 5 * time.Minute
+
+// MaxVAAClockSkew is how far in the future a VAA's timestamp may be before the clocks of the
+// source chain and the relayer are reported as out of step. Such VAAs are still relayed.
+const MaxVAAClockSkew = time.Minute
+
+// ShortPayloadPolicy decides what a processor does with a VAA whose payload is shorter than the
+// layout of its source chain (clients.PayloadFormatNone), and so carries no destination or value
+type ShortPayloadPolicy string
+
+const (
+	// ShortPayloadSkip skips the VAA as filtered, like one addressed to another destination
+	ShortPayloadSkip ShortPayloadPolicy = "skip"
+	// ShortPayloadFail fails the VAA with ErrShortPayload, a permanent error, so it is counted,
+	// dumped and audited as a failure instead of disappearing into the filtered VAAs. It only
+	// applies with an emitter allowlist: without one the VAA may come from any application on
+	// the source chain, so it is skipped.
+	ShortPayloadFail ShortPayloadPolicy = "fail"
+)
+
+// ErrShortPayload is returned for a VAA whose payload is too short under ShortPayloadFail
+var ErrShortPayload = errors.New("payload too short")
+
+// ParseShortPayloadPolicy parses a ShortPayloadPolicy name; empty is ShortPayloadSkip
+func ParseShortPayloadPolicy(s string) (ShortPayloadPolicy, error) {
+	switch policy := ShortPayloadPolicy(s); policy {
+	case "":
+		return ShortPayloadSkip, nil
+	case ShortPayloadSkip, ShortPayloadFail:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid short payload policy %q (want %s or %s)", s, ShortPayloadSkip, ShortPayloadFail)
+}
+
 type VAAProcessorConfig struct {
 	ChainIDs           []uint16 // Source chain IDs to listen for (empty = accept all)
 	EmitterAddress     string   // Hex-encoded emitter address to filter (empty = no filter)
 	DestinationChainID uint16   // Destination chain ID to filter (0 = no filter)
+	// Relay VAAs emitted on DestinationChainID itself. By default they are skipped, so a
+	// message from the destination chain addressed back to it cannot loop through the relayer.
+	AllowSameChain bool
+	// Files listing emitter addresses to accept or reject, one hex address per line (empty = no list).
+	// The allowlist is merged with EmitterAddress; the denylist takes precedence over both.
+	EmitterAllowlistFile string
+	EmitterDenylistFile  string
+	// Minimum VAA consistency level to relay (0 = no filter). Levels are chain-specific,
+	// so this is compared numerically against the level the source emitter requested.
+	MinConsistencyLevel uint8
+	// Skip VAAs whose timestamp is more than MaxVAAAge in the past (0 = no limit), e.g. to
+	// ignore the backlog of a long outage
+	MaxVAAAge time.Duration
+	// Inclusive bounds on the payload's uint128 value (nil = unbounded). When either is set,
+	// VAAs whose payload carries no value are skipped too.
+	MinValue *big.Int
+	MaxValue *big.Int
+	// Checks the decoded payload must pass before submission, in order (empty = none).
+	// VAAs that violate a rule are skipped with the rule's reason and counted per rule.
+	PayloadRules []PayloadRule
+	// Deadline for a single submission (0 = DefaultSubmissionTimeout). It is derived from the
+	// context passed to ProcessVAA, so an earlier parent deadline or a cancellation still wins.
+	SubmissionTimeout time.Duration
+	// Maximum submissions per second to the destination (0 = unlimited), with bursts of up to
+	// RateBurst submissions (0 = 1). VAAs over the limit wait, honouring the caller's context.
+	RateLimit float64
+	RateBurst int
+	// Horizontal sharding: with ShardCount > 1, only VAAs whose sequence % ShardCount equals
+	// ShardIndex are submitted, so ShardCount instances split the load (0 or 1 = no sharding).
+	// Other VAAs are skipped as filtered, after ordered delivery has seen them.
+	ShardIndex int
+	ShardCount int
+	// Circuit breaker: after BreakerThreshold consecutive failed submissions (0 = no breaker),
+	// submissions are held for BreakerCooldown (0 = DefaultBreakerCooldown) before one probes
+	// whether the destination has recovered.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// What to do with a VAA whose payload is shorter than its source chain's layout, checked after
+	// the shard, source chain and emitter filters ("" = ShortPayloadSkip)
+	ShortPayloads ShortPayloadPolicy
 }
 
 type DefaultVAAProcessor struct {
 	config    VAAProcessorConfig
 	logger    *zap.Logger
 	submitter submitter.VAASubmitter
+	limiter   *rate.Limiter   // nil when submissions are unlimited
+	breaker   *CircuitBreaker // nil when there is no circuit breaker
+	emitters  *EmitterFilter
 }
 
-func NewDefaultVAAProcessor(logger *zap.Logger, config VAAProcessorConfig, submitter submitter.VAASubmitter) *DefaultVAAProcessor {
-	// Normalize emitter address: remove 0x prefix, lowercase, pad to 64 chars
-	if config.EmitterAddress != "" {
-		addr := strings.TrimPrefix(config.EmitterAddress, "0x")
-		addr = strings.ToLower(addr)
-		// Left-pad to 64 characters (32 bytes)
-		for len(addr) < 64 {
-			addr = "0" + addr
+func NewDefaultVAAProcessor(logger *zap.Logger, config VAAProcessorConfig, submitter submitter.VAASubmitter) (*DefaultVAAProcessor, error) {
+	// Validate the emitter address and lists so a malformed entry fails at startup
+	// instead of silently producing a filter that matches nothing
+	emitters, err := NewEmitterFilter(config.EmitterAddress, config.EmitterAllowlistFile, config.EmitterDenylistFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.SubmissionTimeout <= 0 {
+		config.SubmissionTimeout = DefaultSubmissionTimeout
+	}
+
+	if err := validateShard(config.ShardIndex, config.ShardCount); err != nil {
+		return nil, err
+	}
+
+	if config.ShortPayloads, err = ParseShortPayloadPolicy(string(config.ShortPayloads)); err != nil {
+		return nil, err
+	}
+
+	if config.MaxVAAAge < 0 {
+		return nil, fmt.Errorf("maximum VAA age must not be negative, got %v", config.MaxVAAAge)
+	}
+
+	if config.MinValue != nil && config.MaxValue != nil && config.MinValue.Cmp(config.MaxValue) > 0 {
+		return nil, fmt.Errorf("minimum value %s is greater than maximum value %s", config.MinValue, config.MaxValue)
+	}
+
+	if config.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative, got %v", config.RateLimit)
+	}
+	var limiter *rate.Limiter
+	if config.RateLimit > 0 {
+		if config.RateBurst <= 0 {
+			config.RateBurst = 1
 		}
-		config.EmitterAddress = addr
+		limiter = rate.NewLimiter(rate.Limit(config.RateLimit), config.RateBurst)
+	}
+
+	if config.BreakerThreshold < 0 {
+		return nil, fmt.Errorf("circuit breaker threshold must not be negative, got %d", config.BreakerThreshold)
+	}
+	var breaker *CircuitBreaker
+	if config.BreakerThreshold > 0 {
+		breaker = NewCircuitBreaker(logger, strconv.Itoa(int(config.DestinationChainID)), config.BreakerThreshold, config.BreakerCooldown)
 	}
 
 	return &DefaultVAAProcessor{
 		config:    config,
 		logger:    logger.With(zap.String("component", "DefaultVAAProcessor")),
 		submitter: submitter,
+		limiter:   limiter,
+		breaker:   breaker,
+		emitters:  emitters,
+	}, nil
+}
+
+// ReloadEmitterLists re-reads the emitter allowlist and denylist files. On error the
+// previous lists stay in effect.
+func (p *DefaultVAAProcessor) ReloadEmitterLists() error {
+	if err := p.emitters.Reload(); err != nil {
+		return err
 	}
+	allowed, denied := p.emitters.Counts()
+	p.logger.Info("Reloaded emitter lists", zap.Int("allowed", allowed), zap.Int("denied", denied))
+	return nil
 }
 
 func (p *DefaultVAAProcessor) ProcessVAA(ctx context.Context, vaaData VAAData) (string, error) {
-	// Create a context with timeout for processing operations
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute) // 5 minute timeout for Aztec VAA verification
+	if ok, err := p.accept(&vaaData); !ok {
+		return "", err
+	}
+	if err := p.acquireBreaker(ctx, vaaData.ID); err != nil {
+		return "", err
+	}
+	if err := p.waitForLimiter(ctx, vaaData.ID); err != nil {
+		p.releaseBreaker()
+		return "", err
+	}
+
+	// The submission deadline is derived from the caller's context: whichever of the two
+	// expires first wins, and cancelling the parent (e.g. on shutdown) aborts the submission
+	submitCtx, cancel := context.WithTimeout(ctx, p.config.SubmissionTimeout)
 	defer cancel()
 
+	// Errors keep the submitter's classification (submitter.ErrTransient etc.) so callers can branch on it
+	txHash, err := p.submitter.SubmitVAA(submitCtx, vaaData.RawBytes)
+	p.settleBreaker(ctx, err)
+	return p.submissionResult(submitCtx, &vaaData, txHash, err)
+}
+
+// ProcessVAABatch processes vaas like ProcessVAA and returns the transaction hash and error of
+// each, in order. When the submitter is a submitter.VAABatchSubmitter, the VAAs that pass the
+// filters are submitted together, counting as one submission against the rate limit and sharing
+// one submission deadline; otherwise they are processed one at a time.
+func (p *DefaultVAAProcessor) ProcessVAABatch(ctx context.Context, vaas []VAAData) ([]string, []error) {
+	txHashes := make([]string, len(vaas))
+	errs := make([]error, len(vaas))
+	batcher, ok := p.submitter.(submitter.VAABatchSubmitter)
+	if !ok {
+		for i := range vaas {
+			txHashes[i], errs[i] = p.ProcessVAA(ctx, vaas[i])
+		}
+		return txHashes, errs
+	}
+
+	var accepted []int
+	var raw [][]byte
+	for i := range vaas {
+		ok, err := p.accept(&vaas[i])
+		if !ok {
+			errs[i] = err
+			continue
+		}
+		accepted = append(accepted, i)
+		raw = append(raw, vaas[i].RawBytes)
+	}
+	if len(accepted) == 0 {
+		return txHashes, errs
+	}
+	if err := p.acquireBreaker(ctx, vaas[accepted[0]].ID); err != nil {
+		for _, i := range accepted {
+			errs[i] = err
+		}
+		return txHashes, errs
+	}
+	if err := p.waitForLimiter(ctx, vaas[accepted[0]].ID); err != nil {
+		p.releaseBreaker()
+		for _, i := range accepted {
+			errs[i] = err
+		}
+		return txHashes, errs
+	}
+
+	submitCtx, cancel := context.WithTimeout(ctx, p.config.SubmissionTimeout)
+	defer cancel()
+
+	// The batch counts as one submission for the circuit breaker, failing only if every VAA did
+	results := batcher.SubmitVAABatch(submitCtx, raw)
+	batchErr := results[0].Err
+	for _, result := range results {
+		if !isBreakerFailure(result.Err) {
+			batchErr = nil
+			break
+		}
+	}
+	p.settleBreaker(ctx, batchErr)
+	for j, i := range accepted {
+		txHashes[i], errs[i] = p.submissionResult(submitCtx, &vaas[i], results[j].TxHash, results[j].Err)
+	}
+	return txHashes, errs
+}
+
+// FilterReason is why the processor skipped a VAA: the first filter it failed. Each is the
+// reason label of the vaas_filtered_total metric; a new filter adds its own.
+type FilterReason string
+
+const (
+	FilterReasonShard        FilterReason = "shard"         // Sequence belongs to another shard
+	FilterReasonSourceChain  FilterReason = "source_chain"  // Source chain not in ChainIDs
+	FilterReasonEmitter      FilterReason = "emitter"       // Emitter not allowed by the filter or lists
+	FilterReasonShortPayload FilterReason = "short_payload" // Payload too short for its layout (ShortPayloadSkip)
+	FilterReasonDestination  FilterReason = "destination"   // Payload addressed to another destination chain
+	FilterReasonSameChain    FilterReason = "same_chain"    // Emitted on the destination chain (loop prevention)
+	FilterReasonConsistency  FilterReason = "consistency"   // Consistency level below MinConsistencyLevel
+	FilterReasonAge          FilterReason = "age"           // Older than MaxVAAAge
+	FilterReasonNoValue      FilterReason = "no_value"      // No payload value while a value bound is set
+	FilterReasonValueRange   FilterReason = "value_range"   // Payload value outside MinValue-MaxValue
+	FilterReasonPayloadRule  FilterReason = "payload_rule"  // Payload failed a PayloadRules rule
+)
+
+// accept reports whether vaaData passes every filter. A filtered VAA is counted by reason and
+// logged with it at debug level, on top of the filter's own log line; a VAA rejected with an
+// error (see ShortPayloadFail) fails rather than being filtered, and is not counted.
+func (p *DefaultVAAProcessor) accept(vaaData *VAAData) (bool, error) {
+	reason, err := p.filter(vaaData)
+	if reason == "" {
+		return true, nil
+	}
+	if err == nil {
+		metrics.VAAsFiltered.WithLabelValues(string(reason)).Inc()
+		p.logger.Debug("VAA filtered",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.String("filterReason", string(reason)))
+	}
+	return false, err
+}
+
+// filter logs vaaData and returns the reason of the first filter it fails, logging why, or ""
+// if it passes them all
+func (p *DefaultVAAProcessor) filter(vaaData *VAAData) (FilterReason, error) {
 	// Log VAAs from Aztec (54 or 56) or Arbitrum Sepolia (10003) at INFO level before filtering
 	if vaaData.ChainID == 54 || vaaData.ChainID == 56 || vaaData.ChainID == 10003 {
 		chainName := "Aztec"
@@ -59,17 +309,14 @@ func (p *DefaultVAAProcessor) ProcessVAA(ctx context.Context, vaaData VAAData) (
 		}
 		p.logger.Info("Received VAA from target chain",
 			zap.String("chain", chainName),
-			zap.Uint16("chainId", vaaData.ChainID),
-			zap.String("emitter", vaaData.EmitterHex),
-			zap.Uint64("sequence", vaaData.Sequence),
+			zap.Stringer("vaa", vaaData.ID),
+			zap.String("emitter", vaaData.ID.NativeEmitter()),
 			zap.String("sourceTxID", vaaData.TxID))
 	}
 
 	// Log essential VAA information at debug level
 	p.logger.Debug("VAA Details",
-		zap.Uint16("emitterChain", vaaData.ChainID),
-		zap.String("emitterAddress", vaaData.EmitterHex),
-		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Stringer("vaa", vaaData.ID),
 		zap.Time("timestamp", vaaData.VAA.Timestamp),
 		zap.Int("payloadLength", len(vaaData.VAA.Payload)),
 		zap.String("sourceTxID", vaaData.TxID))
@@ -78,63 +325,261 @@ func (p *DefaultVAAProcessor) ProcessVAA(ctx context.Context, vaaData VAAData) (
 	p.logger.Debug("VAA Payload", zap.String("payloadHex", fmt.Sprintf("%x", vaaData.VAA.Payload)))
 
 	// Parse payload structure at debug level
-	if len(vaaData.VAA.Payload) >= 32 {
-		parseAndLogPayload(p.logger, vaaData.VAA.Payload)
+	parseAndLogPayload(p.logger, vaaData)
+
+	// Check if this VAA belongs to this instance's shard
+	if !inShard(vaaData.Sequence, p.config.ShardIndex, p.config.ShardCount) {
+		p.logger.Debug("Skipping VAA (handled by another shard)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Int("shardIndex", p.config.ShardIndex),
+			zap.Int("shardCount", p.config.ShardCount))
+		return FilterReasonShard, nil
 	}
 
 	// Check if this is a VAA from one of our configured source chains
 	if len(p.config.ChainIDs) > 0 && !containsChainID(p.config.ChainIDs, vaaData.ChainID) {
 		// Skip VAAs not from our configured chains
 		p.logger.Debug("Skipping VAA (not from configured chain)",
-			zap.Uint64("sequence", vaaData.Sequence),
-			zap.Uint16("chain", vaaData.ChainID))
-		return "", nil
+			zap.Stringer("vaa", vaaData.ID))
+		return FilterReasonSourceChain, nil
+	}
+
+	// Check if this VAA is from an allowed emitter address
+	if allowed, reason := p.emitters.Allows(vaaData.EmitterHex); !allowed {
+		p.logger.Debug("Skipping VAA (emitter filtered)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.String("emitter", vaaData.ID.NativeEmitter()),
+			zap.String("reason", reason))
+		return FilterReasonEmitter, nil
 	}
 
-	// Check if this VAA is from our configured emitter address
-	if p.config.EmitterAddress != "" && vaaData.EmitterHex != p.config.EmitterAddress {
-		p.logger.Debug("Skipping VAA (not from configured emitter)",
-			zap.Uint64("sequence", vaaData.Sequence),
-			zap.String("emitter", vaaData.EmitterHex),
-			zap.String("expectedEmitter", p.config.EmitterAddress))
-		return "", nil
+	// Check the payload is long enough to carry a destination and value
+	if vaaData.PayloadFormat == clients.PayloadFormatNone {
+		return FilterReasonShortPayload, p.shortPayload(vaaData)
 	}
 
 	// Check if this VAA is destined for our chain
-	if p.config.DestinationChainID != 0 {
-		destChainID := extractDestinationChainID(vaaData.VAA.Payload)
-		if destChainID != p.config.DestinationChainID {
-			p.logger.Debug("Skipping VAA (wrong destination chain)",
-				zap.Uint64("sequence", vaaData.Sequence),
-				zap.Uint16("destinationChain", destChainID),
-				zap.Uint16("expectedDestination", p.config.DestinationChainID))
-			return "", nil
+	if p.config.DestinationChainID != 0 && vaaData.DestinationChainID != p.config.DestinationChainID {
+		p.logger.Debug("Skipping VAA (wrong destination chain)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Uint16("destinationChain", vaaData.DestinationChainID),
+			zap.Uint16("expectedDestination", p.config.DestinationChainID))
+		return FilterReasonDestination, nil
+	}
+
+	// Check this VAA is not looping back to the chain it was emitted on
+	if p.config.DestinationChainID != 0 && vaaData.ChainID == p.config.DestinationChainID && !p.config.AllowSameChain {
+		p.logger.Debug("Skipping VAA (emitted on the destination chain; loop prevention)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Uint16("destinationChain", p.config.DestinationChainID))
+		return FilterReasonSameChain, nil
+	}
+
+	// Check if this VAA was emitted with a high enough consistency level
+	if vaaData.VAA.ConsistencyLevel < p.config.MinConsistencyLevel {
+		p.logger.Info("Skipping VAA (consistency level below minimum)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Uint8("consistencyLevel", vaaData.VAA.ConsistencyLevel),
+			zap.Uint8("minConsistencyLevel", p.config.MinConsistencyLevel))
+		return FilterReasonConsistency, nil
+	}
+
+	// Check the VAA is recent enough to still be worth relaying
+	if p.config.MaxVAAAge > 0 && !p.fresh(vaaData) {
+		return FilterReasonAge, nil
+	}
+
+	// Check if the payload value is within the configured range
+	if p.config.MinValue != nil || p.config.MaxValue != nil {
+		value := vaaData.Value
+		if value == nil {
+			p.logger.Info("Skipping VAA (payload has no value field)",
+				zap.Stringer("vaa", vaaData.ID),
+				zap.Int("payloadLength", len(vaaData.VAA.Payload)))
+			return FilterReasonNoValue, nil
 		}
+		if !valueInRange(value, p.config.MinValue, p.config.MaxValue) {
+			p.logger.Info("Skipping VAA (value out of range)",
+				zap.Stringer("vaa", vaaData.ID),
+				zap.String("value", value.String()),
+				zap.Stringer("minValue", p.config.MinValue),
+				zap.Stringer("maxValue", p.config.MaxValue))
+			return FilterReasonValueRange, nil
+		}
+	}
+
+	// Check the payload against the configured validation rules
+	if rule, err := checkPayloadRules(p.config.PayloadRules, vaaData); err != nil {
+		p.logger.Warn("Skipping VAA (payload failed validation)",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.String("rule", rule.Name),
+			zap.String("reason", err.Error()))
+		metrics.PayloadRejections.WithLabelValues(rule.Name).Inc()
+		return FilterReasonPayloadRule, nil
+	}
+
+	return "", nil
+}
+
+// shortPayload applies the ShortPayloads policy to vaaData, whose payload is too short for the
+// layout of its source chain, and returns the error to fail it with (nil = skip it)
+func (p *DefaultVAAProcessor) shortPayload(vaaData *VAAData) error {
+	fields := []zap.Field{
+		zap.Stringer("vaa", vaaData.ID),
+		zap.String("emitter", vaaData.ID.NativeEmitter()),
+		zap.Int("payloadLength", len(vaaData.VAA.Payload)),
+		zap.Int("clients.MinPayloadLength", clients.MinPayloadLength(vaaData.ChainID)),
+		zap.String("policy", string(p.config.ShortPayloads)),
+	}
+	if allowed, _ := p.emitters.Counts(); p.config.ShortPayloads != ShortPayloadFail || allowed == 0 {
+		p.logger.Info("Skipping VAA (payload too short)", fields...)
+		return nil
+	}
+	p.logger.Warn("Failing VAA (payload too short)", fields...)
+	return fmt.Errorf("%w: %w: %d bytes, need at least %d",
+		submitter.ErrPermanent, ErrShortPayload, len(vaaData.VAA.Payload), clients.MinPayloadLength(vaaData.ChainID))
+}
+
+// fresh reports whether vaaData is no older than MaxVAAAge, logging why it is not. Timestamps
+// slightly in the future, from clock skew between the source chain and the relayer, count as
+// age zero; ones further ahead are logged, but the VAA is not stale, so it is relayed.
+func (p *DefaultVAAProcessor) fresh(vaaData *VAAData) bool {
+	age := time.Since(vaaData.VAA.Timestamp)
+	if age < -MaxVAAClockSkew {
+		p.logger.Warn("VAA timestamp is in the future; check the system clock",
+			zap.Stringer("vaa", vaaData.ID),
+			zap.Time("timestamp", vaaData.VAA.Timestamp),
+			zap.Duration("ahead", -age))
+	}
+	if age <= p.config.MaxVAAAge {
+		return true
+	}
+	p.logger.Info("Skipping VAA (older than maximum age)",
+		zap.Stringer("vaa", vaaData.ID),
+		zap.Time("timestamp", vaaData.VAA.Timestamp),
+		zap.Duration("age", age.Round(time.Second)),
+		zap.Duration("maxAge", p.config.MaxVAAAge))
+	return false
+}
+
+// waitForLimiter waits for the destination's rate limiter before spending the submission deadline
+func (p *DefaultVAAProcessor) waitForLimiter(ctx context.Context, id VAAIdentity) error {
+	if p.limiter == nil {
+		return nil
+	}
+	start := time.Now()
+	if err := p.limiter.Wait(ctx); err != nil {
+		p.logger.Warn("Gave up waiting for the submission rate limiter",
+			zap.Stringer("vaa", id),
+			zap.Error(err))
+		return fmt.Errorf("rate limiter wait interrupted: %w", err)
 	}
+	waited := time.Since(start)
+	metrics.RateLimitWait.WithLabelValues(strconv.Itoa(int(p.config.DestinationChainID))).Set(waited.Seconds())
+	p.logger.Debug("Submission passed the rate limiter",
+		zap.Stringer("vaa", id),
+		zap.Duration("waited", waited))
+	return nil
+}
 
-	txHash, err := p.submitter.SubmitVAA(ctx, vaaData.RawBytes)
+// acquireBreaker waits for the circuit breaker, if any, to let a submission through
+func (p *DefaultVAAProcessor) acquireBreaker(ctx context.Context, id VAAIdentity) error {
+	if p.breaker == nil {
+		return nil
+	}
+	if err := p.breaker.Acquire(ctx); err != nil {
+		p.logger.Warn("Gave up waiting for the circuit breaker",
+			zap.Stringer("vaa", id),
+			zap.Error(err))
+		return fmt.Errorf("circuit breaker wait interrupted: %w", err)
+	}
+	return nil
+}
+
+// settleBreaker records the outcome err of a submission let through by acquireBreaker. A
+// submission cut short by ctx, the caller's context, is released without counting.
+func (p *DefaultVAAProcessor) settleBreaker(ctx context.Context, err error) {
+	if p.breaker == nil {
+		return
+	}
+	if ctx.Err() != nil {
+		p.breaker.Release()
+		return
+	}
+	p.breaker.Record(err)
+}
+
+// releaseBreaker releases a submission let through by acquireBreaker that was never sent
+func (p *DefaultVAAProcessor) releaseBreaker() {
+	if p.breaker != nil {
+		p.breaker.Release()
+	}
+}
+
+// submissionResult logs the outcome of submitting vaaData under ctx and returns it, wrapping
+// a failure with what interrupted or failed the transaction
+func (p *DefaultVAAProcessor) submissionResult(ctx context.Context, vaaData *VAAData, txHash string, err error) (string, error) {
 	if err != nil {
 		// Check if the context was cancelled or timed out
 		if ctx.Err() != nil {
 			p.logger.Warn("Transaction sending cancelled or timed out", zap.Error(ctx.Err()))
-			return "", fmt.Errorf("transaction interrupted: %v", ctx.Err())
+			return "", fmt.Errorf("transaction interrupted: %w (%w)", ctx.Err(), err)
+		}
+
+		if errors.Is(err, submitter.ErrAlreadyProcessed) {
+			p.logger.Info("VAA was already processed on the destination",
+				zap.Stringer("vaa", vaaData.ID),
+				zap.String("sourceTxID", vaaData.TxID),
+				zap.Error(err))
+			return "", err
 		}
 
 		p.logger.Error("Failed to send verify transaction",
-			zap.Uint64("sequence", vaaData.Sequence),
+			zap.Stringer("vaa", vaaData.ID),
 			zap.String("sourceTxID", vaaData.TxID),
+			zap.String("class", submitter.ErrorClass(err)),
 			zap.Error(err))
-		return "", fmt.Errorf("transaction failed: %v", err)
+		return "", fmt.Errorf("transaction failed: %w", err)
 	}
 
 	p.logger.Info("VAA verification completed",
-		zap.Uint64("sequence", vaaData.Sequence),
+		zap.Stringer("vaa", vaaData.ID),
 		zap.String("txHash", txHash),
 		zap.String("sourceTxID", vaaData.TxID))
 
 	return txHash, nil
 }
 
+// validateShard checks that index is a valid shard of count shards (count 0 or 1 = no sharding)
+func validateShard(index, count int) error {
+	if count < 0 {
+		return fmt.Errorf("shard count must not be negative, got %d", count)
+	}
+	if count <= 1 && index != 0 {
+		return fmt.Errorf("shard index %d requires a shard count greater than 1", index)
+	}
+	if count > 1 && (index < 0 || index >= count) {
+		return fmt.Errorf("shard index %d is out of range for %d shards (0-%d)", index, count, count-1)
+	}
+	return nil
+}
+
+// inShard reports whether sequence belongs to shard index of count shards
+func inShard(sequence uint64, index, count int) bool {
+	return count <= 1 || sequence%uint64(count) == uint64(index)
+}
+
+// valueInRange reports whether value lies within the inclusive bounds (nil = unbounded)
+func valueInRange(value, min, max *big.Int) bool {
+	if min != nil && value.Cmp(min) < 0 {
+		return false
+	}
+	if max != nil && value.Cmp(max) > 0 {
+		return false
+	}
+	return true
+}
+
 // containsChainID checks if a chain ID is in the list
 func containsChainID(chainIDs []uint16, target uint16) bool {
 	for _, id := range chainIDs {
@@ -0,0 +1,691 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/wormhole-demo/relayer/internal/clients"
+	"github.com/wormhole-demo/relayer/internal/metrics"
+	"github.com/wormhole-demo/relayer/internal/submitter"
+	vaaLib "github.com/wormhole-foundation/wormhole/sdk/vaa"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// contextSubmitter records the context it was called with and blocks until it is done
+type contextSubmitter struct {
+	deadline    time.Time
+	hasDeadline bool
+}
+
+func (s *contextSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	s.deadline, s.hasDeadline = ctx.Deadline()
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func testVAAData() VAAData {
+	return testVAADataWithPayload(make([]byte, 18))
+}
+
+// testVAADataWithPayload builds VAAData for a VAA from chain 2, sequence 1, carrying payload
+func testVAADataWithPayload(payload []byte) VAAData {
+	return testVAADataFromChain(2, payload)
+}
+
+// testVAADataFromChain builds the VAAData of a VAA emitted on chain, whose payload layout follows it
+func testVAADataFromChain(chain uint16, payload []byte) VAAData {
+	return *NewVAAData(&vaaLib.VAA{EmitterChain: vaaLib.ChainID(chain), Sequence: 1, Payload: payload}, []byte("vaa"))
+}
+
+func TestProcessVAASubmissionTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		submitTimeout  time.Duration
+		parentTimeout  time.Duration
+		expectedWithin time.Duration
+	}{
+		{name: "submission timeout earlier", submitTimeout: 50 * time.Millisecond, parentTimeout: time.Hour, expectedWithin: 50 * time.Millisecond},
+		{name: "parent deadline earlier", submitTimeout: time.Hour, parentTimeout: 50 * time.Millisecond, expectedWithin: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &contextSubmitter{}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{SubmissionTimeout: tt.submitTimeout}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.parentTimeout)
+			defer cancel()
+
+			start := time.Now()
+			if _, err := p.ProcessVAA(ctx, testVAAData()); err == nil {
+				t.Fatal("expected an error when the submission deadline expires")
+			}
+			if !s.hasDeadline {
+				t.Fatal("expected the submission context to carry a deadline")
+			}
+			if got := s.deadline.Sub(start); got > tt.expectedWithin+time.Second {
+				t.Fatalf("expected the earliest deadline (~%v) to win, got %v", tt.expectedWithin, got)
+			}
+		})
+	}
+}
+
+func TestProcessVAAParentCancellation(t *testing.T) {
+	s := &contextSubmitter{}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{SubmissionTimeout: time.Hour}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := p.ProcessVAA(ctx, testVAAData())
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected an error after the parent context was cancelled")
+		}
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Fatalf("expected the parent to be cancelled, got %v", ctx.Err())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("parent cancellation did not propagate to the submission")
+	}
+}
+
+func TestNewDefaultVAAProcessorDefaultSubmissionTimeout(t *testing.T) {
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{}, &contextSubmitter{})
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	if p.config.SubmissionTimeout != DefaultSubmissionTimeout {
+		t.Fatalf("expected default submission timeout %v, got %v", DefaultSubmissionTimeout, p.config.SubmissionTimeout)
+	}
+}
+
+// errorSubmitter fails every submission with err
+type errorSubmitter struct {
+	err error
+}
+
+func (s *errorSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	return "", s.err
+}
+
+func TestProcessVAAPreservesErrorClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		class   error
+		settled bool
+	}{
+		{name: "transient", class: submitter.ErrTransient, settled: false},
+		{name: "already processed", class: submitter.ErrAlreadyProcessed, settled: true},
+		{name: "permanent", class: submitter.ErrPermanent, settled: true},
+		{name: "config", class: submitter.ErrConfig, settled: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &errorSubmitter{err: fmt.Errorf("%w: submission failed", tt.class)}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+
+			_, err = p.ProcessVAA(context.Background(), testVAAData())
+			if !errors.Is(err, tt.class) {
+				t.Fatalf("expected the error to match %v, got %v", tt.class, err)
+			}
+			if got := isSettled(err); got != tt.settled {
+				t.Errorf("expected isSettled = %v, got %v", tt.settled, got)
+			}
+		})
+	}
+}
+
+// countingSubmitter succeeds every submission and counts the calls
+type countingSubmitter struct {
+	calls int
+}
+
+func (s *countingSubmitter) SubmitVAA(ctx context.Context, vaaBytes []byte) (string, error) {
+	s.calls++
+	return "0xabc", nil
+}
+
+func TestProcessVAAMinConsistencyLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		min       uint8
+		level     uint8
+		submitted bool
+	}{
+		{name: "no filter", min: 0, level: 0, submitted: true},
+		{name: "one below minimum", min: 200, level: 199, submitted: false},
+		{name: "at minimum", min: 200, level: 200, submitted: true},
+		{name: "above minimum", min: 200, level: 201, submitted: true},
+		{name: "minimum of one rejects zero", min: 1, level: 0, submitted: false},
+		{name: "maximum level", min: 255, level: 255, submitted: true},
+		{name: "below maximum", min: 255, level: 254, submitted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &countingSubmitter{}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{MinConsistencyLevel: tt.min}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+
+			vaaData := testVAAData()
+			vaaData.VAA.ConsistencyLevel = tt.level
+			txHash, err := p.ProcessVAA(context.Background(), vaaData)
+			if err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+
+			if submitted := s.calls == 1; submitted != tt.submitted {
+				t.Fatalf("expected submitted = %v, got %v", tt.submitted, submitted)
+			}
+			if tt.submitted != (txHash != "") {
+				t.Errorf("unexpected transaction hash %q", txHash)
+			}
+		})
+	}
+}
+
+func TestProcessVAARateLimit(t *testing.T) {
+	s := &countingSubmitter{}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{RateLimit: 20, RateBurst: 2}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	// The burst goes through immediately, the third submission waits ~50ms for a token
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := p.ProcessVAA(context.Background(), testVAAData()); err != nil {
+			t.Fatalf("ProcessVAA %d failed: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected the third submission to wait for the limiter, took %v", elapsed)
+	}
+	if s.calls != 3 {
+		t.Fatalf("expected 3 submissions, got %d", s.calls)
+	}
+}
+
+func TestProcessVAARateLimitHonoursContext(t *testing.T) {
+	s := &countingSubmitter{}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{RateLimit: 0.01}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	if _, err := p.ProcessVAA(context.Background(), testVAAData()); err != nil {
+		t.Fatalf("first ProcessVAA failed: %v", err)
+	}
+
+	// The next token is 100s away, so a cancelled caller must not wait for it
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := p.ProcessVAA(ctx, testVAAData()); err == nil {
+		t.Fatal("expected an error when the context ends before a token is available")
+	}
+	if s.calls != 1 {
+		t.Fatalf("expected the rate-limited VAA not to be submitted, got %d submissions", s.calls)
+	}
+}
+
+func TestNewDefaultVAAProcessorRejectsNegativeRateLimit(t *testing.T) {
+	if _, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{RateLimit: -1}, &countingSubmitter{}); err == nil {
+		t.Fatal("expected an error for a negative rate limit")
+	}
+}
+
+// valuePayload builds a payload carrying value, in the default (18-byte) or Aztec (50-byte) layout
+func valuePayload(value *big.Int, aztec bool) []byte {
+	payload := make([]byte, 18)
+	if aztec {
+		payload = make([]byte, 50)
+	}
+	value.FillBytes(payload[len(payload)-16:])
+	return payload
+}
+
+func TestProcessVAAValueRange(t *testing.T) {
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	tests := []struct {
+		name      string
+		min, max  *big.Int
+		value     *big.Int
+		submitted bool
+	}{
+		{name: "below minimum", min: big.NewInt(100), value: big.NewInt(99), submitted: false},
+		{name: "at minimum", min: big.NewInt(100), value: big.NewInt(100), submitted: true},
+		{name: "at maximum", max: big.NewInt(1000), value: big.NewInt(1000), submitted: true},
+		{name: "above maximum", max: big.NewInt(1000), value: big.NewInt(1001), submitted: false},
+		{name: "within range", min: big.NewInt(1), max: big.NewInt(10), value: big.NewInt(5), submitted: true},
+		{name: "full uint128", min: big.NewInt(1), value: maxUint128, submitted: true},
+		{name: "no bounds", value: big.NewInt(0), submitted: true},
+	}
+
+	for _, tt := range tests {
+		for _, aztec := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/aztec=%v", tt.name, aztec), func(t *testing.T) {
+				s := &countingSubmitter{}
+				p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{MinValue: tt.min, MaxValue: tt.max}, s)
+				if err != nil {
+					t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+				}
+
+				chain := uint16(2)
+				if aztec {
+					chain = 56
+				}
+				vaaData := testVAADataFromChain(chain, valuePayload(tt.value, aztec))
+				if _, err := p.ProcessVAA(context.Background(), vaaData); err != nil {
+					t.Fatalf("ProcessVAA failed: %v", err)
+				}
+				if submitted := s.calls == 1; submitted != tt.submitted {
+					t.Fatalf("expected submitted = %v, got %v", tt.submitted, submitted)
+				}
+			})
+		}
+	}
+}
+
+func TestProcessVAAValueRangeSkipsPayloadWithoutValue(t *testing.T) {
+	s := &countingSubmitter{}
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{MaxValue: big.NewInt(10)}, s)
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+
+	vaaData := testVAADataWithPayload(make([]byte, 10))
+	if _, err := p.ProcessVAA(context.Background(), vaaData); err != nil {
+		t.Fatalf("ProcessVAA failed: %v", err)
+	}
+	if s.calls != 0 {
+		t.Fatal("expected a payload without a value field to be skipped")
+	}
+}
+
+func TestNewDefaultVAAProcessorRejectsInvertedValueRange(t *testing.T) {
+	config := VAAProcessorConfig{MinValue: big.NewInt(10), MaxValue: big.NewInt(9)}
+	if _, err := NewDefaultVAAProcessor(zap.NewNop(), config, &countingSubmitter{}); err == nil {
+		t.Fatal("expected an error when the minimum value exceeds the maximum")
+	}
+}
+
+func TestNewVAAData(t *testing.T) {
+	value := new(big.Int).Lsh(big.NewInt(1), 100)
+
+	defaultPayload := valuePayload(value, false)
+	defaultPayload[0], defaultPayload[1] = 0x27, 0x13 // Destination 10003
+
+	aztecPayload := valuePayload(value, true)
+	aztecPayload[0] = 0xab                          // Source tx ID
+	aztecPayload[32], aztecPayload[33] = 0xc3, 0x1a // Destination 49946
+
+	// 50 bytes from an EVM chain are a default payload followed by other data
+	extendedPayload := append(append([]byte{}, defaultPayload...), make([]byte, 32)...)
+	extendedPayload[32], extendedPayload[33] = 0xc3, 0x1a
+
+	tests := []struct {
+		name        string
+		chain       uint16
+		payload     []byte
+		destination uint16
+		value       *big.Int
+		hasTxID     bool
+		format      clients.PayloadFormat
+	}{
+		{name: "default layout", chain: 2, payload: defaultPayload, destination: 10003, value: value, format: clients.PayloadFormatDefault},
+		{name: "aztec layout", chain: 56, payload: aztecPayload, destination: 49946, value: value, hasTxID: true, format: clients.PayloadFormatAztec},
+		{name: "default layout with trailing bytes", chain: 2, payload: append(defaultPayload, make([]byte, 20)...), destination: 10003, value: value, format: clients.PayloadFormatDefault},
+		{name: "default layout of aztec length", chain: 10004, payload: extendedPayload, destination: 10003, value: value, format: clients.PayloadFormatDefault},
+		{name: "default layout from aztec", chain: 56, payload: defaultPayload, format: clients.PayloadFormatNone},
+		{name: "short payload", chain: 2, payload: make([]byte, 10), format: clients.PayloadFormatNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vaaData := testVAADataFromChain(tt.chain, tt.payload)
+			if vaaData.PayloadFormat != tt.format {
+				t.Errorf("expected format %s, got %s", tt.format, vaaData.PayloadFormat)
+			}
+			if vaaData.DestinationChainID != tt.destination || vaaData.HasDestination != (tt.value != nil) {
+				t.Errorf("expected destination %d (present = %v), got %d (present = %v)",
+					tt.destination, tt.value != nil, vaaData.DestinationChainID, vaaData.HasDestination)
+			}
+			switch {
+			case tt.value == nil && vaaData.Value != nil:
+				t.Errorf("expected no value, got %s", vaaData.Value)
+			case tt.value != nil && (vaaData.Value == nil || vaaData.Value.Cmp(tt.value) != 0):
+				t.Errorf("expected value %s, got %v", tt.value, vaaData.Value)
+			}
+			if hasTxID := vaaData.TxID != ""; hasTxID != tt.hasTxID {
+				t.Errorf("expected a source tx ID = %v, got %q", tt.hasTxID, vaaData.TxID)
+			}
+			if tt.hasTxID && !strings.HasPrefix(vaaData.TxID, "0xab") {
+				t.Errorf("expected the source tx ID to be the first 32 payload bytes, got %q", vaaData.TxID)
+			}
+		})
+	}
+}
+
+func TestProcessVAADestinationFilter(t *testing.T) {
+	chainZero := make([]byte, 18) // Destination chain 0
+	toArbitrum := make([]byte, 18)
+	toArbitrum[0], toArbitrum[1] = 0x27, 0x13 // Destination 10003
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		submitted bool
+	}{
+		{name: "matching destination", payload: toArbitrum, submitted: true},
+		{name: "other destination", payload: chainZero, submitted: false},
+		{name: "no destination", payload: make([]byte, 17), submitted: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &countingSubmitter{}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+			if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(tt.payload)); err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+			if submitted := s.calls == 1; submitted != tt.submitted {
+				t.Fatalf("expected submitted = %v, got %v", tt.submitted, submitted)
+			}
+		})
+	}
+}
+
+func TestProcessVAAShortPayload(t *testing.T) {
+	emitter := strings.Repeat("0", 64) // The emitter of testVAADataWithPayload
+
+	tests := []struct {
+		name    string
+		config  VAAProcessorConfig
+		failed  bool
+		message string
+	}{
+		{name: "skip", config: VAAProcessorConfig{ShortPayloads: ShortPayloadSkip, EmitterAddress: emitter}, message: "Skipping VAA (payload too short)"},
+		{name: "default", config: VAAProcessorConfig{EmitterAddress: emitter}, message: "Skipping VAA (payload too short)"},
+		{name: "fail", config: VAAProcessorConfig{ShortPayloads: ShortPayloadFail, EmitterAddress: emitter}, failed: true, message: "Failing VAA (payload too short)"},
+		{name: "fail without allowlist", config: VAAProcessorConfig{ShortPayloads: ShortPayloadFail}, message: "Skipping VAA (payload too short)"},
+	}
+
+	for _, tt := range tests {
+		for _, length := range []int{0, 10} {
+			t.Run(fmt.Sprintf("%s/%d bytes", tt.name, length), func(t *testing.T) {
+				s := &countingSubmitter{}
+				core, logs := observer.New(zap.InfoLevel)
+				tt.config.DestinationChainID = 10003
+				p, err := NewDefaultVAAProcessor(zap.New(core), tt.config, s)
+				if err != nil {
+					t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+				}
+
+				txHash, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(make([]byte, length)))
+				if s.calls != 0 {
+					t.Fatal("expected a short payload not to be submitted")
+				}
+				if tt.failed {
+					if !errors.Is(err, ErrShortPayload) || !errors.Is(err, submitter.ErrPermanent) {
+						t.Fatalf("expected a permanent ErrShortPayload, got %v", err)
+					}
+				} else if err != nil {
+					t.Fatalf("expected the VAA to be skipped, got %v", err)
+				}
+				wantDecision := DecisionFiltered
+				if tt.failed {
+					wantDecision = DecisionFailed
+				}
+				if decision := vaaDecision(txHash, err); decision != wantDecision {
+					t.Errorf("expected decision %q, got %q", wantDecision, decision)
+				}
+
+				entries := logs.FilterMessage(tt.message).All()
+				if len(entries) != 1 {
+					t.Fatalf("expected one %q log line, got %d", tt.message, len(entries))
+				}
+				fields := entries[0].ContextMap()
+				if fields["payloadLength"] != int64(length) || fields["clients.MinPayloadLength"] != int64(clients.DefaultPayloadLength) {
+					t.Errorf("expected the payload and minimum length to be logged, got %v", fields)
+				}
+			})
+		}
+	}
+
+	if _, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{ShortPayloads: "drop"}, &countingSubmitter{}); err == nil {
+		t.Error("expected an unknown short payload policy to be rejected")
+	}
+}
+
+func TestProcessVAAFilterReasons(t *testing.T) {
+	toArbitrum := make([]byte, 18)
+	toArbitrum[0], toArbitrum[1] = 0x27, 0x13 // Destination 10003
+
+	tests := []struct {
+		name    string
+		config  VAAProcessorConfig
+		payload []byte
+		want    FilterReason
+	}{
+		{name: "shard", config: VAAProcessorConfig{ShardIndex: 0, ShardCount: 2}, want: FilterReasonShard},
+		{name: "source chain", config: VAAProcessorConfig{ChainIDs: []uint16{10004}}, want: FilterReasonSourceChain},
+		{name: "emitter", config: VAAProcessorConfig{EmitterAddress: strings.Repeat("1", 64)}, want: FilterReasonEmitter},
+		{name: "short payload", payload: make([]byte, 10), want: FilterReasonShortPayload},
+		{name: "destination", config: VAAProcessorConfig{DestinationChainID: 10004}, want: FilterReasonDestination},
+		{name: "same chain", config: VAAProcessorConfig{DestinationChainID: 2}, payload: []byte{0, 2, 16: 0, 17: 0}, want: FilterReasonSameChain},
+		{name: "consistency", config: VAAProcessorConfig{MinConsistencyLevel: 1}, want: FilterReasonConsistency},
+		{name: "age", config: VAAProcessorConfig{MaxVAAAge: time.Hour}, want: FilterReasonAge},
+		{name: "value range", config: VAAProcessorConfig{MinValue: big.NewInt(1)}, want: FilterReasonValueRange},
+		{name: "accepted", config: VAAProcessorConfig{DestinationChainID: 10003}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zap.DebugLevel)
+			p, err := NewDefaultVAAProcessor(zap.New(core), tt.config, &countingSubmitter{})
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+			payload := tt.payload
+			if payload == nil {
+				payload = toArbitrum
+			}
+			var before float64
+			if tt.want != "" {
+				before = testutil.ToFloat64(metrics.VAAsFiltered.WithLabelValues(string(tt.want)))
+			}
+
+			if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(payload)); err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+
+			entries := logs.FilterMessage("VAA filtered").All()
+			if tt.want == "" {
+				if len(entries) != 0 {
+					t.Fatalf("expected the VAA not to be filtered, got %v", entries[0].ContextMap())
+				}
+				return
+			}
+			if len(entries) != 1 || entries[0].ContextMap()["filterReason"] != string(tt.want) {
+				t.Fatalf("expected one VAA filtered log line with reason %s, got %d", tt.want, len(entries))
+			}
+			if got := testutil.ToFloat64(metrics.VAAsFiltered.WithLabelValues(string(tt.want))) - before; got != 1 {
+				t.Errorf("expected vaas_filtered_total{reason=%q} to grow by 1, grew by %v", tt.want, got)
+			}
+		})
+	}
+
+	// A short payload failed as an error is not filtered
+	p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{ShortPayloads: ShortPayloadFail, EmitterAddress: strings.Repeat("0", 64)}, &countingSubmitter{})
+	if err != nil {
+		t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+	}
+	before := testutil.ToFloat64(metrics.VAAsFiltered.WithLabelValues(string(FilterReasonShortPayload)))
+	if _, err := p.ProcessVAA(context.Background(), testVAADataWithPayload(nil)); !errors.Is(err, ErrShortPayload) {
+		t.Fatalf("expected ErrShortPayload, got %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.VAAsFiltered.WithLabelValues(string(FilterReasonShortPayload))); got != before {
+		t.Errorf("expected a failed short payload not to be counted as filtered")
+	}
+}
+
+func TestProcessVAAMaxAge(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration
+		submitted bool
+	}{
+		{name: "recent", age: time.Minute, submitted: true},
+		{name: "older than the maximum", age: 2 * time.Hour, submitted: false},
+		{name: "within clock skew", age: -30 * time.Second, submitted: true},
+		{name: "far in the future", age: -time.Hour, submitted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &countingSubmitter{}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{MaxVAAAge: time.Hour}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+			vaaData := testVAAData()
+			vaaData.VAA.Timestamp = time.Now().Add(-tt.age)
+			if _, err := p.ProcessVAA(context.Background(), vaaData); err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+			if submitted := s.calls == 1; submitted != tt.submitted {
+				t.Fatalf("expected submitted = %v, got %v", tt.submitted, submitted)
+			}
+		})
+	}
+
+	if _, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{MaxVAAAge: -time.Second}, &countingSubmitter{}); err == nil {
+		t.Error("expected an error for a negative maximum VAA age")
+	}
+}
+
+func TestProcessVAASameChainLoop(t *testing.T) {
+	toArbitrum := make([]byte, 18)
+	toArbitrum[0], toArbitrum[1] = 0x27, 0x13 // Destination 10003
+
+	tests := []struct {
+		name           string
+		sourceChain    uint16
+		allowSameChain bool
+		submitted      bool
+	}{
+		{name: "other source chain", sourceChain: 2, submitted: true},
+		{name: "emitted on the destination", sourceChain: 10003, submitted: false},
+		{name: "same chain allowed", sourceChain: 10003, allowSameChain: true, submitted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &countingSubmitter{}
+			p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{DestinationChainID: 10003, AllowSameChain: tt.allowSameChain}, s)
+			if err != nil {
+				t.Fatalf("NewDefaultVAAProcessor failed: %v", err)
+			}
+			vaaData := *NewVAAData(&vaaLib.VAA{EmitterChain: vaaLib.ChainID(tt.sourceChain), Sequence: 1, Payload: toArbitrum}, []byte("vaa"))
+			if _, err := p.ProcessVAA(context.Background(), vaaData); err != nil {
+				t.Fatalf("ProcessVAA failed: %v", err)
+			}
+			if submitted := s.calls == 1; submitted != tt.submitted {
+				t.Fatalf("expected submitted = %v, got %v", tt.submitted, submitted)
+			}
+		})
+	}
+}
+
+func TestProcessVAASharding(t *testing.T) {
+	const shardCount = 3
+	submitters := make([]*countingSubmitter, shardCount)
+	processors := make([]*DefaultVAAProcessor, shardCount)
+	for i := range processors {
+		submitters[i] = &countingSubmitter{}
+		p, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{ShardIndex: i, ShardCount: shardCount}, submitters[i])
+		if err != nil {
+			t.Fatalf("NewDefaultVAAProcessor for shard %d failed: %v", i, err)
+		}
+		processors[i] = p
+	}
+
+	// Every sequence is submitted by exactly one shard
+	for seq := uint64(0); seq < 9; seq++ {
+		submitted := 0
+		for i, p := range processors {
+			before := submitters[i].calls
+			vaaData := testVAAData()
+			vaaData.Sequence = seq
+			if _, err := p.ProcessVAA(context.Background(), vaaData); err != nil {
+				t.Fatalf("ProcessVAA on shard %d failed: %v", i, err)
+			}
+			if submitters[i].calls > before {
+				submitted++
+				if want := int(seq % shardCount); i != want {
+					t.Errorf("sequence %d submitted by shard %d, expected shard %d", seq, i, want)
+				}
+			}
+		}
+		if submitted != 1 {
+			t.Errorf("sequence %d submitted by %d shards, expected 1", seq, submitted)
+		}
+	}
+}
+
+func TestNewDefaultVAAProcessorRejectsInvalidShard(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+		count int
+		valid bool
+	}{
+		{name: "no sharding", index: 0, count: 0, valid: true},
+		{name: "single shard", index: 0, count: 1, valid: true},
+		{name: "last shard", index: 3, count: 4, valid: true},
+		{name: "index past count", index: 4, count: 4, valid: false},
+		{name: "negative index", index: -1, count: 4, valid: false},
+		{name: "negative count", index: 0, count: -2, valid: false},
+		{name: "index without sharding", index: 1, count: 1, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{ShardIndex: tt.index, ShardCount: tt.count}, &countingSubmitter{})
+			if (err == nil) != tt.valid {
+				t.Errorf("expected valid = %v, got err %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gagliardetto/solana-go"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+// ValidateEmitterAddress checks that a configured emitter address decodes to a 32-byte Wormhole
+// address and returns its normalized form (lowercase hex, no 0x prefix, 64 chars) used for
+// filtering. The format is detected from the input:
+//   - hex, 32 bytes or a 20-byte EVM address left-padded as Wormhole encodes EVM emitters; a 0x
+//     prefix makes the input hex only
+//   - base58, a 32-byte Solana public key
+//   - decimal, an Aztec address as a BN254 field element. A 40- or 64-digit input is always hex,
+//     as those are the lengths of hex addresses.
+//
+// An input that decodes to different addresses in two formats is rejected as ambiguous.
+func ValidateEmitterAddress(addr string) (string, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	if trimmed != addr {
+		raw, err := decodeHexEmitter(trimmed)
+		if err != nil {
+			return "", fmt.Errorf("emitter address %q %v", addr, err)
+		}
+		return NormalizeEmitter(raw), nil
+	}
+
+	decoders := []struct {
+		format string
+		decode func(string) ([]byte, error)
+	}{
+		{"hex", decodeHexEmitter},
+		{"base58", decodeBase58Emitter},
+		{"decimal", decodeFieldEmitter},
+	}
+	var format, normalized string
+	var errs []string
+	for _, d := range decoders {
+		raw, err := d.decode(addr)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", d.format, err))
+			continue
+		}
+		if normalized != "" && NormalizeEmitter(raw) != normalized {
+			return "", fmt.Errorf("emitter address %q is ambiguous: it is valid %s and valid %s; prefix hex with 0x", addr, format, d.format)
+		}
+		if normalized == "" {
+			format, normalized = d.format, NormalizeEmitter(raw)
+		}
+	}
+	if normalized == "" {
+		return "", fmt.Errorf("emitter address %q is not a valid hex, base58 or decimal address (%s)", addr, strings.Join(errs, "; "))
+	}
+	return normalized, nil
+}
+
+// decodeHexEmitter decodes an unprefixed hex emitter of 32 bytes, or 20 for an EVM address
+func decodeHexEmitter(s string) ([]byte, error) {
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("is not valid hex: %v", err)
+	}
+	if len(raw) != 32 && len(raw) != 20 {
+		return nil, fmt.Errorf("must decode to 32 bytes (or a 20-byte EVM address), got %d bytes", len(raw))
+	}
+	return raw, nil
+}
+
+// decodeBase58Emitter decodes a base58 Solana public key
+func decodeBase58Emitter(s string) ([]byte, error) {
+	key, err := solana.PublicKeyFromBase58(s)
+	if err != nil {
+		return nil, err
+	}
+	return key.Bytes(), nil
+}
+
+// bn254Modulus is the order of the BN254 scalar field, which Aztec addresses are elements of
+var bn254Modulus, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// decodeFieldEmitter decodes a decimal BN254 field element, as 32 big-endian bytes. 40- and
+// 64-digit strings are left to hex.
+func decodeFieldEmitter(s string) ([]byte, error) {
+	if len(s) == 40 || len(s) == 64 {
+		return nil, fmt.Errorf("%d digits are read as hex", len(s))
+	}
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return nil, fmt.Errorf("is not a decimal integer")
+	}
+	value, _ := new(big.Int).SetString(s, 10)
+	if value.Cmp(bn254Modulus) >= 0 {
+		return nil, fmt.Errorf("is not a BN254 field element")
+	}
+	return value.FillBytes(make([]byte, 32)), nil
+}
+
+// NormalizeEmitter returns the canonical form of an emitter address: lowercase hex without a 0x
+// prefix, left-padded with zeros to 32 bytes (64 chars). Strings are treated as hex, byte slices as
+// raw addresses. Every emitter comparison (filtering, ordering) must use this form.
+// It does not validate its input; see ValidateEmitterAddress for configured values.
+func NormalizeEmitter[T string | []byte](addr T) string {
+	var hexAddr string
+	switch v := any(addr).(type) {
+	case string:
+		hexAddr = strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(v, "0x"), "0X"))
+	case []byte:
+		hexAddr = hex.EncodeToString(v)
+	}
+
+	if len(hexAddr) < 64 {
+		hexAddr = strings.Repeat("0", 64-len(hexAddr)) + hexAddr
+	}
+	return hexAddr
+}
+
+// ValidateEVMAddress checks that addr is a 20-byte hex EVM address
+func ValidateEVMAddress(addr string) error {
+	if !common.IsHexAddress(addr) {
+		return fmt.Errorf("invalid EVM address %q: expected 20-byte hex", addr)
+	}
+	return nil
+}
+
+// ValidateSolanaAddress checks that addr is a base58-encoded 32-byte Solana public key
+func ValidateSolanaAddress(addr string) error {
+	if _, err := solana.PublicKeyFromBase58(addr); err != nil {
+		return fmt.Errorf("invalid Solana address %q: %v", addr, err)
+	}
+	return nil
+}
+
+// ValidateCosmosAddress checks that addr is a bech32 address with the expected prefix
+func ValidateCosmosAddress(addr string, prefix string) error {
+	hrp, data, err := clients.Bech32Decode(addr)
+	if err != nil {
+		return fmt.Errorf("invalid Cosmos address %q: %v", addr, err)
+	}
+	if hrp != prefix {
+		return fmt.Errorf("invalid Cosmos address %q: expected prefix %q, got %q", addr, prefix, hrp)
+	}
+	// Account addresses are 20 bytes, contract addresses 32 bytes
+	if len(data) != 20 && len(data) != 32 {
+		return fmt.Errorf("invalid Cosmos address %q: unexpected length %d bytes", addr, len(data))
+	}
+	return nil
+}
+
+// maxUint128 is the largest value a payload's uint128 value field can hold
+var maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// ParseUint128 parses a decimal or 0x-prefixed hex value that must fit in a uint128
+func ParseUint128(s string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid value %q: expected a decimal or 0x-prefixed hex integer", s)
+	}
+	if value.Sign() < 0 || value.Cmp(maxUint128) > 0 {
+		return nil, fmt.Errorf("invalid value %q: must be between 0 and 2^128-1", s)
+	}
+	return value, nil
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/gagliardetto/solana-go"
+	"go.uber.org/zap"
+
+	"github.com/wormhole-demo/relayer/internal/clients"
+)
+
+func TestValidateEmitterAddress(t *testing.T) {
+	full := "0x" + strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "32 bytes with prefix", input: full, want: strings.Repeat("ab", 32)},
+		{name: "32 bytes mixed case", input: strings.ToUpper(strings.Repeat("ab", 32)), want: strings.Repeat("ab", 32)},
+		{name: "20-byte EVM address", input: "0x248EC2E5595480fF371031698ae3a4099b8dC229", want: strings.Repeat("0", 24) + "248ec2e5595480ff371031698ae3a4099b8dc229"},
+		{name: "too short", input: "0x1234", wantErr: true},
+		{name: "too long", input: full + "00", wantErr: true},
+		{name: "non-hex", input: "0x" + strings.Repeat("zz", 32), wantErr: true},
+		{name: "odd length", input: "0x" + strings.Repeat("a", 63), wantErr: true},
+		{name: "base58 Solana key", input: "worm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth", want: hex.EncodeToString(solana.MustPublicKeyFromBase58("worm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth").Bytes())},
+		{name: "base58 with 0x prefix", input: "0xworm2ZoG2kUd4vFXhvjh93UUH596ayRfgQ2MgjNMTth", wantErr: true},
+		{name: "decimal field element", input: "12345678901234567890", want: strings.Repeat("0", 48) + "ab54a98ceb1f0ad2"},
+		{name: "decimal beyond the field", input: "21888242871839275222246405745257275088548364400416034343698204186575808495617", wantErr: true},
+		{name: "64 digits are hex", input: strings.Repeat("0", 60) + "1234", want: strings.Repeat("0", 60) + "1234"},
+		{name: "base58 or decimal", input: strings.Repeat("1", 32), wantErr: true},
+		{name: "no format", input: "not-an-address", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateEmitterAddress(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for %q, got %q", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmitter(t *testing.T) {
+	full := strings.Repeat("ab", 32)
+	evm := strings.Repeat("0", 24) + "248ec2e5595480ff371031698ae3a4099b8dc229"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "full length", input: full, want: full},
+		{name: "0x prefix", input: "0x" + full, want: full},
+		{name: "0X prefix", input: "0X" + full, want: full},
+		{name: "mixed case", input: "0x248EC2E5595480fF371031698ae3a4099b8dC229", want: evm},
+		{name: "short", input: "0x1234", want: strings.Repeat("0", 60) + "1234"},
+		{name: "already padded", input: evm, want: evm},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeEmitter(tt.input); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("bytes", func(t *testing.T) {
+		var addr [32]byte
+		addr[30], addr[31] = 0x12, 0x34
+		if got := NormalizeEmitter(addr[:]); got != strings.Repeat("0", 60)+"1234" {
+			t.Errorf("unexpected normalized bytes: %s", got)
+		}
+		if got := NormalizeEmitter([]byte{0x12, 0x34}); got != strings.Repeat("0", 60)+"1234" {
+			t.Errorf("expected short byte slices to be left-padded, got %s", got)
+		}
+	})
+
+	// The VAA's raw emitter and the configured filter must agree for every accepted input form
+	t.Run("matches validated config", func(t *testing.T) {
+		var addr [32]byte
+		copy(addr[12:], []byte{0x24, 0x8e, 0xc2, 0xe5, 0x59, 0x54, 0x80, 0xff, 0x37, 0x10,
+			0x31, 0x69, 0x8a, 0xe3, 0xa4, 0x09, 0x9b, 0x8d, 0xc2, 0x29})
+		configured, err := ValidateEmitterAddress("0x248EC2E5595480fF371031698ae3a4099b8dC229")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := NormalizeEmitter(addr[:]); got != configured {
+			t.Errorf("expected %s to match configured emitter %s", got, configured)
+		}
+	})
+}
+
+func TestValidateEVMAddress(t *testing.T) {
+	if err := ValidateEVMAddress("0x248EC2E5595480fF371031698ae3a4099b8dC229"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, addr := range []string{"0x1234", "0x248EC2E5595480fF371031698ae3a4099b8dC22900", "0xZZ8EC2E5595480fF371031698ae3a4099b8dC229"} {
+		if err := ValidateEVMAddress(addr); err == nil {
+			t.Errorf("expected error for %q", addr)
+		}
+	}
+}
+
+func TestValidateSolanaAddress(t *testing.T) {
+	if err := ValidateSolanaAddress("3u8hJUVTA4jH1wYAyUur7FFZVQ8H635K3tSHHF4ssjQ5"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for _, addr := range []string{"3u8hJUVTA4jH1wYAyUur7FF", "3u8hJUVTA4jH1wYAyUur7FFZVQ8H635K3tSHHF4ssjQ5aaaa", "0OIl0OIl0OIl0OIl0OIl0OIl0OIl0OIl0OIl0OIl0OI"} {
+		if err := ValidateSolanaAddress(addr); err == nil {
+			t.Errorf("expected error for %q", addr)
+		}
+	}
+}
+
+func TestNewDefaultVAAProcessor_InvalidEmitter(t *testing.T) {
+	_, err := NewDefaultVAAProcessor(zap.NewNop(), VAAProcessorConfig{EmitterAddress: "0xnothex"}, nil)
+	if err == nil {
+		t.Fatal("expected error for malformed emitter address")
+	}
+}
+
+func TestValidateCosmosAddress(t *testing.T) {
+	contract, err := clients.Bech32Encode("wormhole", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateCosmosAddress(contract, "wormhole"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateCosmosAddress(contract, "cosmos"); err == nil {
+		t.Error("expected prefix mismatch error")
+	}
+	corrupted := contract[:len(contract)-1] + "q"
+	if strings.HasSuffix(contract, "q") {
+		corrupted = contract[:len(contract)-1] + "p"
+	}
+	if err := ValidateCosmosAddress(corrupted, "wormhole"); err == nil {
+		t.Error("expected checksum error")
+	}
+	short, _ := clients.Bech32Encode("wormhole", make([]byte, 10))
+	if err := ValidateCosmosAddress(short, "wormhole"); err == nil {
+		t.Error("expected length error")
+	}
+}
+
+func TestParseUint128(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "0", want: "0"},
+		{input: "1000000", want: "1000000"},
+		{input: "0xff", want: "255"},
+		{input: "340282366920938463463374607431768211455", want: "340282366920938463463374607431768211455"},
+		{input: "340282366920938463463374607431768211456", wantErr: true},
+		{input: "-1", wantErr: true},
+		{input: "1.5", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseUint128(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("expected error for %q, got %v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.input, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseUint128(%q) = %v, want %s", tt.input, got, tt.want)
+		}
+	}
+}